@@ -0,0 +1,122 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/ratelimit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/rs/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newHTTPHandler returns a handler that serves gRPC-Web requests with grpcServer
+// and transcodes HTTP/JSON requests into gRPC calls made with conn.
+// Transcoding follows the google.api.http annotations of the registry protos.
+func newHTTPHandler(ctx context.Context, grpcServer *grpc.Server, conn *grpc.ClientConn, conf CORSConfig) (http.Handler, error) {
+	// These options match the print options of the transcoder in deployments/envoy/envoy.yaml.
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					Multiline:       true,
+					Indent:          "  ",
+					EmitUnpopulated: true,
+				},
+				UnmarshalOptions: protojson.UnmarshalOptions{
+					DiscardUnknown: true,
+				},
+			},
+		}),
+		// Calls are rate limited by the callers of the HTTP requests.
+		runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			return metadata.Pairs(ratelimit.PeerMetadataKey, ratelimit.HTTPPrincipal(req))
+		}),
+	)
+	if err := rpc.RegisterRegistryHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := rpc.RegisterAdminHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	webOpts := []grpcweb.Option{
+		grpcweb.WithOriginFunc(conf.allowsOrigin),
+	}
+	if len(conf.AllowedHeaders) > 0 {
+		webOpts = append(webOpts, grpcweb.WithAllowedRequestHeaders(conf.AllowedHeaders))
+	}
+	web := grpcweb.WrapServer(grpcServer, webOpts...)
+
+	rest := cors.New(cors.Options{
+		AllowedOrigins: conf.AllowedOrigins,
+		AllowedMethods: []string{
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: conf.AllowedHeaders,
+		ExposedHeaders: []string{"grpc-status", "grpc-message"},
+		MaxAge:         conf.MaxAge,
+	}).Handler(mux)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if web.IsGrpcWebRequest(r) || web.IsAcceptableGrpcCorsRequest(r) {
+			web.ServeHTTP(w, r)
+			return
+		}
+		rest.ServeHTTP(w, r)
+	}), nil
+}
+
+// newMixedHandler returns a handler that serves gRPC requests with grpcServer
+// and all other requests with httpHandler. It accepts unencrypted HTTP/2 so
+// that gRPC and HTTP can share a single port.
+func newMixedHandler(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPCRequest(r) {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	}), &http2.Server{})
+}
+
+// isGRPCRequest returns true for native gRPC requests. gRPC-Web requests,
+// which use content types like "application/grpc-web", are excluded.
+func isGRPCRequest(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return r.ProtoMajor == 2 && (contentType == "application/grpc" || strings.HasPrefix(contentType, "application/grpc+"))
+}
+
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
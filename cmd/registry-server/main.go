@@ -15,30 +15,56 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/log/interceptor"
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/apigee/registry/server/registry/ratelimit"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 	"gopkg.in/yaml.v2"
 )
 
 // ServerConfig is the top-level configuration structure.
 type ServerConfig struct {
 	// Server port. If unset or zero, an open port will be assigned.
-	Port     int            `yaml:"port"`
-	Database DatabaseConfig `yaml:"database"`
-	Logging  LoggingConfig  `yaml:"logging"`
-	Pubsub   PubsubConfig   `yaml:"pubsub"`
+	Port int `yaml:"port"`
+	// Time in seconds to wait for in-flight requests to complete when the
+	// server is stopped. If zero, the server stops immediately.
+	ShutdownTimeout int              `yaml:"shutdown_timeout"`
+	Database        DatabaseConfig   `yaml:"database"`
+	Logging         LoggingConfig    `yaml:"logging"`
+	Pubsub          PubsubConfig     `yaml:"pubsub"`
+	HTTP            HTTPConfig       `yaml:"http"`
+	TLS             TLSConfig        `yaml:"tls"`
+	RateLimits      RateLimitsConfig `yaml:"rate_limits"`
+	Quotas          QuotasConfig     `yaml:"quotas"`
+	Retention       RetentionConfig  `yaml:"retention"`
+	References      ReferencesConfig `yaml:"references"`
+	Specs           SpecsConfig      `yaml:"specs"`
+	// Locations that resources can be created in.
+	// If empty, resources can be created in any location.
+	Locations []string `yaml:"locations"`
 }
 
 // DatabaseConfig holds database configuration.
@@ -72,9 +98,119 @@ type PubsubConfig struct {
 	Project string `yaml:"project"`
 }
 
+// healthCheckInterval is the time between checks of the server's readiness.
+const healthCheckInterval = 10 * time.Second
+
+// HTTPConfig holds configuration for serving HTTP/JSON and gRPC-Web.
+type HTTPConfig struct {
+	// Enable serving of HTTP/JSON transcoding and gRPC-Web.
+	// Values: [ true, false ]
+	Enable bool `yaml:"enable"`
+	// Port for HTTP requests. If unset, zero, or equal to the server port,
+	// HTTP requests are served on the server port alongside gRPC requests.
+	Port int        `yaml:"port"`
+	CORS CORSConfig `yaml:"cors"`
+}
+
+// CORSConfig holds cross-origin resource sharing configuration.
+// It is only needed when calling the API from browser apps.
+type CORSConfig struct {
+	// Origins allowed to make cross-origin requests. "*" allows any origin.
+	// If empty, cross-origin requests are not allowed.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// Request headers allowed in cross-origin requests.
+	// If empty, "Origin", "Accept", and "Content-Type" are allowed for
+	// HTTP/JSON requests and all headers are allowed for gRPC-Web requests.
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	// Time in seconds that the results of a preflight request can be cached.
+	MaxAge int `yaml:"max_age"`
+}
+
+// TLSConfig holds transport security configuration.
+// TLS is enabled when a certificate file is specified.
+type TLSConfig struct {
+	// PEM-encoded certificate chain presented by the server.
+	CertFile string `yaml:"cert_file"`
+	// PEM-encoded private key of the server certificate.
+	KeyFile string `yaml:"key_file"`
+	// PEM-encoded certificates of the authorities used to verify client
+	// certificates. If set, clients may authenticate with certificates.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// Require clients to present a certificate signed by a client CA (mutual TLS).
+	// Values: [ true, false ]
+	RequireClientCert bool `yaml:"require_client_cert"`
+}
+
+// RateLimitsConfig holds rate limiting configuration. Limits apply separately
+// to each combination of caller, project, and class of method. Callers are
+// identified by client certificate or IP address.
+type RateLimitsConfig struct {
+	// Limits for methods that get and list resources.
+	Read RateLimitConfig `yaml:"read"`
+	// Limits for methods that create, update, and delete resources.
+	Write RateLimitConfig `yaml:"write"`
+	// Limits for methods that get spec and artifact contents.
+	Contents RateLimitConfig `yaml:"contents"`
+}
+
+// RateLimitConfig configures a token bucket.
+type RateLimitConfig struct {
+	// Number of calls allowed per second. If unset or zero, calls are not limited.
+	Rate float64 `yaml:"rate"`
+	// Number of calls that can be made at once. If unset or zero, the rate is used.
+	Burst int `yaml:"burst"`
+}
+
+// RetentionConfig configures the pruning of spec and deployment revisions.
+type RetentionConfig struct {
+	// Seconds between background runs that prune revisions according to
+	// the retention policies of each project. If zero, revisions are only
+	// pruned by calls to PruneRevisions.
+	PruneInterval int `yaml:"prune_interval"`
+}
+
+// ReferencesConfig configures the checking of references from APIs and
+// deployments to other resources.
+type ReferencesConfig struct {
+	// Require references to name existing resources in the same project.
+	// Values: [ true, false ]
+	Validate bool `yaml:"validate"`
+	// Action taken on references to a resource when it is deleted.
+	// If unset, references are left unchanged.
+	// Values: [ block, clear, cascade ]
+	OnDelete string `yaml:"on_delete"`
+}
+
+// SpecsConfig configures the checking of specs.
+type SpecsConfig struct {
+	// Reject specs with MIME types that can't be parsed. Otherwise they are
+	// stored as they are.
+	// Values: [ true, false ]
+	StrictMimeTypes bool `yaml:"strict_mime_types"`
+}
+
+// QuotasConfig holds limits on the storage used by each project.
+// Unset or zero values allow unlimited usage.
+type QuotasConfig struct {
+	// Maximum number of APIs.
+	MaxApis int64 `yaml:"max_apis"`
+	// Maximum number of specs.
+	MaxSpecs int64 `yaml:"max_specs"`
+	// Maximum number of spec and deployment revisions.
+	MaxRevisions int64 `yaml:"max_revisions"`
+	// Maximum total size in bytes of spec and artifact contents.
+	MaxBlobBytes int64 `yaml:"max_blob_bytes"`
+}
+
+// Enabled returns true if the server should use TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
 // default configuration
-var config = ServerConfig{
-	Port: 8080,
+var defaultConfig = ServerConfig{
+	Port:            8080,
+	ShutdownTimeout: 30,
 	Database: DatabaseConfig{
 		Driver: "sqlite3",
 		Config: "file:/tmp/registry.db",
@@ -87,8 +223,15 @@ var config = ServerConfig{
 		Enable:  false,
 		Project: "",
 	},
+	HTTP: HTTPConfig{
+		Enable: false,
+		Port:   0,
+	},
 }
 
+// current configuration
+var config = defaultConfig
+
 func main() {
 	var configPath string
 	pflag.StringVarP(&configPath, "configuration", "c", "", "The server configuration file to load.")
@@ -98,27 +241,21 @@ func main() {
 	bootLogger := log.NewLogger()
 	if configPath != "" {
 		bootLogger.Infof("Loading configuration from %s", configPath)
-		raw, err := ioutil.ReadFile(configPath)
-		if err != nil {
-			bootLogger.WithError(err).Fatal("Failed to open config file")
-		}
-		// Expand environment variables before unmarshaling.
-		expanded := []byte(os.ExpandEnv(string(raw)))
-		err = yaml.Unmarshal(expanded, &config)
-		if err != nil {
-			bootLogger.WithError(err).Fatalf("Failed to read config file")
+		if err := readConfig(configPath, &config); err != nil {
+			bootLogger.WithError(err).Fatal("Failed to read config file")
 		}
 	}
 
-	if err := validateConfig(); err != nil {
+	if err := validateConfig(config); err != nil {
 		bootLogger.WithError(err).Fatalf("Invalid configuration")
 	}
 
 	// Use logging options from the server config.
+	// They are shared by all loggers so that they can be reloaded.
 	var (
-		logOpts        = loggerOptions(config.Logging)
-		logger         = log.NewLogger(logOpts...)
-		logInterceptor = interceptor.CallLogger(logOpts...)
+		logConfig      = log.NewConfig(loggerOptions(config.Logging)...)
+		logger         = log.NewLogger(logConfig.Option())
+		logInterceptor = interceptor.CallLogger(logConfig.Option())
 	)
 
 	logger.Infof("Configured port %d", config.Port)
@@ -131,34 +268,307 @@ func main() {
 	defer listener.Close()
 
 	registryServer, err := registry.New(registry.Config{
-		Database:  config.Database.Driver,
-		DBConfig:  config.Database.Config,
-		LogLevel:  config.Logging.Level,
-		LogFormat: config.Logging.Format,
-		Notify:    config.Pubsub.Enable,
-		ProjectID: config.Pubsub.Project,
+		Database:        config.Database.Driver,
+		DBConfig:        config.Database.Config,
+		LogLevel:        config.Logging.Level,
+		LogFormat:       config.Logging.Format,
+		Notify:          config.Pubsub.Enable,
+		ProjectID:       config.Pubsub.Project,
+		Quotas:          quotas(config.Quotas),
+		Locations:       config.Locations,
+		PruneInterval:   time.Duration(config.Retention.PruneInterval) * time.Second,
+		References:      references(config.References),
+		StrictMimeTypes: config.Specs.StrictMimeTypes,
 	})
 	if err != nil {
 		logger.WithError(err).Fatalf("Failed to create registry server")
 	}
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(logInterceptor))
+	// The overall server status reports liveness and is always SERVING.
+	// Individual services report readiness, which depends on storage.
+	healthServer := health.NewServer()
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	healthDone := make(chan struct{})
+	go func() {
+		defer close(healthDone)
+		watchHealth(healthCtx, logger, registryServer, healthServer)
+	}()
+
+	var tlsConfig *tls.Config
+	if config.TLS.Enabled() {
+		reloader, err := newTLSReloader(config.TLS, logger)
+		if err != nil {
+			logger.WithError(err).Fatalf("Failed to load TLS certificates")
+		}
+		tlsConfig = reloader.Config()
+	}
+
+	// Calls are logged before rate limits are applied so that rejected calls are logged.
+	limiter := ratelimit.New(rateLimits(config.RateLimits))
+	interceptors := grpc.ChainUnaryInterceptor(logInterceptor, limiter.UnaryInterceptor())
+
+	serverOpts := []grpc.ServerOption{interceptors}
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := newGRPCServer(registryServer, healthServer, serverOpts...)
+	grpcServers := []*grpc.Server{grpcServer}
+	var httpServers []*http.Server
+
+	if !config.HTTP.Enable {
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		logger.Infof("Listening on %s", listener.Addr())
+	} else {
+		// Transcoded calls are made to a second gRPC server over an in-memory
+		// connection so that they pass through the same interceptors as other
+		// gRPC calls. Transport security is provided by the HTTP listener.
+		local := newGRPCServer(registryServer, healthServer, interceptors)
+		grpcServers = append(grpcServers, local)
+		httpServers = serveHTTP(logger, grpcServer, local, listener, tlsConfig)
+	}
+
+	// Reload the configuration on hangup signals until interrupted.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range signals {
+		if sig != syscall.SIGHUP {
+			logger.Infof("Received %s, shutting down", sig)
+			break
+		}
+		logger.Info("Reloading configuration")
+		err := reloadConfig(configPath, logger, logConfig, limiter, registryServer)
+		if err != nil {
+			logger.WithError(err).Error("Failed to reload configuration")
+		} else {
+			logger.Info("Reloaded configuration")
+		}
+		registryServer.RecordReload(err)
+	}
+
+	// Report that the services are unavailable while requests are drained.
+	healthServer.Shutdown()
+	stop(logger, time.Duration(config.ShutdownTimeout)*time.Second, grpcServers, httpServers)
+	// Stop checking storage before it is closed.
+	stopHealth()
+	<-healthDone
+	registryServer.Close()
+	logger.Info("Shutdown complete")
+}
+
+// readConfig reads a configuration file into conf.
+func readConfig(path string, conf *ServerConfig) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// Expand environment variables before unmarshaling.
+	expanded := []byte(os.ExpandEnv(string(raw)))
+	return yaml.Unmarshal(expanded, conf)
+}
+
+// reloadConfig reads the configuration file again and applies the sections
+// that can be changed while the server is running: logging, pubsub,
+// rate_limits, quotas, locations, retention, references, and shutdown_timeout.
+// Changes to other sections are logged and ignored.
+func reloadConfig(path string, logger log.Logger, logConfig *log.Config, limiter *ratelimit.Limiter, s *registry.RegistryServer) error {
+	if path == "" {
+		return errors.New("server was started without a configuration file")
+	}
+
+	next := defaultConfig
+	if err := readConfig(path, &next); err != nil {
+		return err
+	}
+	if err := validateConfig(next); err != nil {
+		return err
+	}
+
+	if sections := restartSections(config, next); len(sections) > 0 {
+		logger.Warnf("Changes to %s require a restart and were not applied", strings.Join(sections, ", "))
+	}
+
+	logConfig.Set(loggerOptions(next.Logging)...)
+	if next.RateLimits != config.RateLimits {
+		limiter.SetConfig(rateLimits(next.RateLimits))
+	}
+	s.Reload(registry.Config{
+		Notify:          next.Pubsub.Enable,
+		ProjectID:       next.Pubsub.Project,
+		Quotas:          quotas(next.Quotas),
+		Locations:       next.Locations,
+		PruneInterval:   time.Duration(next.Retention.PruneInterval) * time.Second,
+		References:      references(next.References),
+		StrictMimeTypes: next.Specs.StrictMimeTypes,
+	})
+	config.Logging = next.Logging
+	config.Pubsub = next.Pubsub
+	config.RateLimits = next.RateLimits
+	config.Quotas = next.Quotas
+	config.Locations = next.Locations
+	config.Retention = next.Retention
+	config.References = next.References
+	config.Specs = next.Specs
+	config.ShutdownTimeout = next.ShutdownTimeout
+	return nil
+}
+
+// restartSections returns the names of changed sections that can't be reloaded.
+func restartSections(current, next ServerConfig) []string {
+	var sections []string
+	if current.Port != next.Port {
+		sections = append(sections, "port")
+	}
+	if !reflect.DeepEqual(current.Database, next.Database) {
+		sections = append(sections, "database")
+	}
+	if !reflect.DeepEqual(current.HTTP, next.HTTP) {
+		sections = append(sections, "http")
+	}
+	if !reflect.DeepEqual(current.TLS, next.TLS) {
+		sections = append(sections, "tls")
+	}
+	return sections
+}
+
+// stop stops all servers, waiting up to timeout for in-flight requests to
+// complete before closing the remaining connections.
+func stop(logger log.Logger, timeout time.Duration, grpcServers []*grpc.Server, httpServers []*http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		// HTTP servers are stopped first because they make calls to gRPC servers.
+		for _, s := range httpServers {
+			_ = s.Shutdown(ctx)
+		}
+		for _, s := range grpcServers {
+			s.GracefulStop()
+		}
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Info("Completed in-flight requests")
+	case <-ctx.Done():
+		logger.Warnf("In-flight requests did not complete within %s, closing connections", timeout)
+		for _, s := range httpServers {
+			_ = s.Close()
+		}
+		for _, s := range grpcServers {
+			s.Stop()
+		}
+	}
+}
+
+// newGRPCServer returns a gRPC server for the registry, admin, health, and
+// reflection services.
+func newGRPCServer(s *registry.RegistryServer, h *health.Server, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
 	reflection.Register(grpcServer)
-	rpc.RegisterRegistryServer(grpcServer, registryServer)
-	rpc.RegisterAdminServer(grpcServer, registryServer)
+	rpc.RegisterRegistryServer(grpcServer, s)
+	rpc.RegisterAdminServer(grpcServer, s)
+	healthpb.RegisterHealthServer(grpcServer, h)
+	return grpcServer
+}
+
+// localBufferSize is the size of the in-memory buffer used for transcoded calls.
+const localBufferSize = 1024 * 1024
+
+// serveHTTP serves gRPC on listener and also serves HTTP/JSON and gRPC-Web,
+// either on the same listener or on a separate port. HTTP/JSON requests are
+// transcoded into calls to local. If tlsConfig is non-nil, HTTP requests are
+// served over TLS. It returns the HTTP servers that were started.
+func serveHTTP(logger log.Logger, grpcServer, local *grpc.Server, listener *net.TCPListener, tlsConfig *tls.Config) []*http.Server {
+	ctx := context.Background()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	localListener := bufconn.Listen(localBufferSize)
+	go func() {
+		_ = local.Serve(localListener)
+	}()
+	conn, err := grpc.DialContext(ctx, "local",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return localListener.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		logger.WithError(err).Fatalf("Failed to create local gRPC connection")
+	}
+
+	handler, err := newHTTPHandler(ctx, grpcServer, conn, config.HTTP.CORS)
+	if err != nil {
+		logger.WithError(err).Fatalf("Failed to create HTTP handler")
+	}
+
+	if config.HTTP.Port == 0 || config.HTTP.Port == port {
+		mixed := &http.Server{Handler: newMixedHandler(grpcServer, handler)}
+		go func() {
+			_ = mixed.Serve(httpListener(listener, tlsConfig))
+		}()
+		logger.Infof("Listening on %s (gRPC, gRPC-Web, and HTTP/JSON)", listener.Addr())
+		return []*http.Server{mixed}
+	}
+
+	tcpListener, err := net.ListenTCP("tcp", &net.TCPAddr{
+		Port: config.HTTP.Port,
+	})
+	if err != nil {
+		logger.WithError(err).Fatalf("Failed to create HTTP listener")
+	}
 
 	go func() {
 		_ = grpcServer.Serve(listener)
 	}()
-	logger.Infof("Listening on %s", listener.Addr())
+	server := &http.Server{Handler: handler}
+	go func() {
+		_ = server.Serve(httpListener(tcpListener, tlsConfig))
+	}()
+	logger.Infof("Listening on %s (gRPC)", listener.Addr())
+	logger.Infof("Listening on %s (gRPC-Web and HTTP/JSON)", tcpListener.Addr())
+	return []*http.Server{server}
+}
+
+// httpListener returns a listener that accepts TLS connections if tlsConfig
+// is non-nil and unencrypted connections otherwise.
+func httpListener(l net.Listener, tlsConfig *tls.Config) net.Listener {
+	if tlsConfig == nil {
+		return l
+	}
+	return tls.NewListener(l, tlsConfig)
+}
 
-	// Wait for an interruption signal.
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
-	<-done
+// watchHealth periodically updates the health server with the readiness of
+// the registry server, logging whenever readiness changes, until ctx is done.
+func watchHealth(ctx context.Context, logger log.Logger, s *registry.RegistryServer, h *health.Server) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	first, ready := true, false
+	for {
+		err := s.UpdateHealth(ctx, h)
+		if ctx.Err() != nil {
+			return
+		}
+		if first || ready != (err == nil) {
+			if err != nil {
+				logger.WithError(err).Warn("Registry server is not ready")
+			} else {
+				logger.Info("Registry server is ready")
+			}
+		}
+		first, ready = false, err == nil
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-func validateConfig() error {
+func validateConfig(config ServerConfig) error {
 	if config.Port < 0 {
 		return fmt.Errorf("invalid port %q: must be non-negative", config.Port)
 	}
@@ -181,6 +591,76 @@ func validateConfig() error {
 		return fmt.Errorf("invalid logging format %q: must be one of [json, text]", format)
 	}
 
+	if config.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid shutdown_timeout %d: must be non-negative", config.ShutdownTimeout)
+	}
+
+	if config.HTTP.Port < 0 {
+		return fmt.Errorf("invalid http.port %d: must be non-negative", config.HTTP.Port)
+	}
+
+	if config.HTTP.CORS.MaxAge < 0 {
+		return fmt.Errorf("invalid http.cors.max_age %d: must be non-negative", config.HTTP.CORS.MaxAge)
+	}
+
+	for _, l := range []struct {
+		class string
+		limit RateLimitConfig
+	}{
+		{"read", config.RateLimits.Read},
+		{"write", config.RateLimits.Write},
+		{"contents", config.RateLimits.Contents},
+	} {
+		if l.limit.Rate < 0 {
+			return fmt.Errorf("invalid rate_limits.%s.rate %g: must be non-negative", l.class, l.limit.Rate)
+		}
+		if l.limit.Burst < 0 {
+			return fmt.Errorf("invalid rate_limits.%s.burst %d: must be non-negative", l.class, l.limit.Burst)
+		}
+	}
+
+	for _, q := range []struct {
+		name  string
+		limit int64
+	}{
+		{"max_apis", config.Quotas.MaxApis},
+		{"max_specs", config.Quotas.MaxSpecs},
+		{"max_revisions", config.Quotas.MaxRevisions},
+		{"max_blob_bytes", config.Quotas.MaxBlobBytes},
+	} {
+		if q.limit < 0 {
+			return fmt.Errorf("invalid quotas.%s %d: must be non-negative", q.name, q.limit)
+		}
+	}
+
+	if config.Retention.PruneInterval < 0 {
+		return fmt.Errorf("invalid retention.prune_interval %d: must be non-negative", config.Retention.PruneInterval)
+	}
+
+	switch action := config.References.OnDelete; action {
+	case "", "block", "clear", "cascade":
+	default:
+		return fmt.Errorf("invalid references.on_delete %q: must be one of [block, clear, cascade]", action)
+	}
+
+	for _, id := range config.Locations {
+		if err := (names.Location{ProjectID: "-", LocationID: id}).Validate(); err != nil {
+			return fmt.Errorf("invalid locations entry %q: %s", id, err)
+		}
+	}
+
+	if conf := config.TLS; conf.Enabled() != (conf.KeyFile != "") {
+		return fmt.Errorf("invalid tls: cert_file and key_file must be set together")
+	}
+
+	if conf := config.TLS; !conf.Enabled() && conf.ClientCAFile != "" {
+		return fmt.Errorf("invalid tls.client_ca_file %q: requires tls.cert_file", conf.ClientCAFile)
+	}
+
+	if conf := config.TLS; conf.RequireClientCert && conf.ClientCAFile == "" {
+		return fmt.Errorf("invalid tls.require_client_cert: requires tls.client_ca_file")
+	}
+
 	if project := config.Pubsub.Project; config.Pubsub.Enable && project == "" {
 		return fmt.Errorf("invalid pubsub.project %q: pubsub cannot be enabled without GCP project ID", project)
 	}
@@ -188,6 +668,30 @@ func validateConfig() error {
 	return nil
 }
 
+func rateLimits(conf RateLimitsConfig) ratelimit.Config {
+	return ratelimit.Config{
+		Read:     ratelimit.Limit{Rate: conf.Read.Rate, Burst: conf.Read.Burst},
+		Write:    ratelimit.Limit{Rate: conf.Write.Rate, Burst: conf.Write.Burst},
+		Contents: ratelimit.Limit{Rate: conf.Contents.Rate, Burst: conf.Contents.Burst},
+	}
+}
+
+func quotas(conf QuotasConfig) registry.Quotas {
+	return registry.Quotas{
+		MaxApis:      conf.MaxApis,
+		MaxSpecs:     conf.MaxSpecs,
+		MaxRevisions: conf.MaxRevisions,
+		MaxBlobBytes: conf.MaxBlobBytes,
+	}
+}
+
+func references(conf ReferencesConfig) registry.ReferencePolicy {
+	return registry.ReferencePolicy{
+		Validate: conf.Validate,
+		OnDelete: registry.OnDelete(conf.OnDelete),
+	}
+}
+
 func loggerOptions(conf LoggingConfig) []log.Option {
 	opts := make([]log.Option, 0, 2)
 	switch conf.Level {
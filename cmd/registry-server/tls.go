@@ -0,0 +1,144 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/apigee/registry/log"
+)
+
+// tlsReloader provides TLS configurations for incoming connections.
+// Certificate files are checked on each handshake and reloaded when they
+// change, so certificates can be rotated without restarting the server.
+// If a reload fails, the previously loaded configuration continues to be used.
+type tlsReloader struct {
+	conf   TLSConfig
+	logger log.Logger
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+	current  *tls.Config
+}
+
+func newTLSReloader(conf TLSConfig, logger log.Logger) (*tlsReloader, error) {
+	r := &tlsReloader{
+		conf:   conf,
+		logger: logger,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Config returns a TLS configuration for a server that uses the reloader.
+func (r *tlsReloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}
+}
+
+func (r *tlsReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changed() {
+		if err := r.load(); err != nil {
+			r.logger.WithError(err).Error("Failed to reload TLS certificates")
+		} else {
+			r.logger.Info("Reloaded TLS certificates")
+		}
+	}
+	return r.current, nil
+}
+
+// files returns the names of all files used by the configuration.
+func (r *tlsReloader) files() []string {
+	files := []string{r.conf.CertFile, r.conf.KeyFile}
+	if r.conf.ClientCAFile != "" {
+		files = append(files, r.conf.ClientCAFile)
+	}
+	return files
+}
+
+// changed returns true if any file has been modified since the last attempt
+// to load it.
+func (r *tlsReloader) changed() bool {
+	for _, name := range r.files() {
+		if !modTime(name).Equal(r.modTimes[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+// modTime returns the modification time of a file, or the zero time if the
+// file can't be read.
+func modTime(name string) time.Time {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (r *tlsReloader) load() error {
+	// Modification times are recorded before reading so that changes made
+	// while loading are picked up on a later handshake. They are recorded
+	// even if loading fails, so that a failed load is only retried after
+	// the files change again.
+	r.modTimes = make(map[string]time.Time)
+	for _, name := range r.files() {
+		r.modTimes[name] = modTime(name)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.conf.CertFile, r.conf.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	c := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// Both are needed when HTTP and gRPC requests share a port.
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	if r.conf.ClientCAFile != "" {
+		pem, err := ioutil.ReadFile(r.conf.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		c.ClientCAs = x509.NewCertPool()
+		if !c.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.conf.ClientCAFile)
+		}
+		if r.conf.RequireClientCert {
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			c.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	r.current = c
+	return nil
+}
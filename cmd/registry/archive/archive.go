@@ -0,0 +1,227 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive reads and writes project archives, which are gzipped tar
+// files that hold the resources of a project. The layout is described in
+// README.md.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Format identifies project archives.
+	Format = "registry-archive"
+	// Version is the version of the layout that is written. Readers accept
+	// archives with this version or an earlier one.
+	Version = 2
+
+	manifestFile = "archive.yaml"
+	metadataFile = "metadata.json"
+	contentsFile = "contents"
+)
+
+// Manifest describes an archive. It is the first file of the archive.
+type Manifest struct {
+	Format     string    `yaml:"format"`
+	Version    int       `yaml:"version"`
+	Project    string    `yaml:"project"` // name of the exported project
+	CreateTime time.Time `yaml:"create_time"`
+}
+
+// Checksum returns the hash of contents, in the form of the hash fields of
+// specs and artifacts, which are empty for empty contents.
+func Checksum(contents []byte) string {
+	if len(contents) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
+// RevisionPath returns the path of a revision of a spec, deployment, or
+// artifact. The index orders revisions from the oldest, starting with 1.
+func RevisionPath(resourcePath string, index int, revisionID string) string {
+	return fmt.Sprintf("%s/revisions/%04d-%s", resourcePath, index, revisionID)
+}
+
+// Writer writes an archive.
+type Writer struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+	mt time.Time
+}
+
+// NewWriter writes the manifest of an archive and returns a writer for its
+// resources.
+func NewWriter(w io.Writer, project string) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	aw := &Writer{gz: gz, tw: tar.NewWriter(gz), mt: time.Now().UTC().Round(time.Second)}
+	b, err := yaml.Marshal(&Manifest{
+		Format:     Format,
+		Version:    Version,
+		Project:    project,
+		CreateTime: aw.mt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := aw.writeFile(manifestFile, b); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write adds a resource at a path relative to the project. The root path ""
+// is the project itself. Contents are written before the metadata, so that
+// readers have both when they reach the metadata.
+func (w *Writer) Write(resourcePath string, metadata proto.Message, contents []byte) error {
+	if contents != nil {
+		if err := w.writeFile(path.Join(resourcePath, contentsFile), contents); err != nil {
+			return err
+		}
+	}
+	b, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return w.writeFile(path.Join(resourcePath, metadataFile), b)
+}
+
+// Close finishes the archive. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+func (w *Writer) writeFile(name string, b []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(b)),
+		ModTime:  w.mt,
+	}); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(b)
+	return err
+}
+
+// Entry is a resource read from an archive.
+type Entry struct {
+	Path     string // relative to the project, "" for the project
+	Metadata []byte // JSON encoding of the resource message
+	Contents []byte // nil if the resource has no contents
+}
+
+// Unmarshal decodes the metadata of an entry.
+func (e *Entry) Unmarshal(m proto.Message) error {
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(e.Metadata, m); err != nil {
+		return fmt.Errorf("invalid metadata of %q: %s", e.Path, err)
+	}
+	return nil
+}
+
+// Reader reads the resources of an archive in the order that they were
+// written.
+type Reader struct {
+	Manifest Manifest
+
+	gz *gzip.Reader
+	tr *tar.Reader
+}
+
+// NewReader reads and checks the manifest of an archive.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a project archive: %s", err)
+	}
+	ar := &Reader{gz: gz, tr: tar.NewReader(gz)}
+	h, err := ar.tr.Next()
+	if err != nil || h.Name != manifestFile {
+		return nil, fmt.Errorf("not a project archive: %s must be its first file", manifestFile)
+	}
+	b, err := ioutil.ReadAll(ar.tr)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &ar.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", manifestFile, err)
+	}
+	if ar.Manifest.Format != Format {
+		return nil, fmt.Errorf("not a project archive: format is %q", ar.Manifest.Format)
+	}
+	if ar.Manifest.Version < 1 || ar.Manifest.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d, this version of registry reads versions up to %d", ar.Manifest.Version, Version)
+	}
+	return ar, nil
+}
+
+// Next returns the next resource of the archive, or io.EOF at its end.
+func (r *Reader) Next() (*Entry, error) {
+	var contents []byte
+	var contentsPath string
+	for {
+		h, err := r.tr.Next()
+		if err == io.EOF {
+			if contents != nil {
+				return nil, fmt.Errorf("%s has contents but no metadata", contentsPath)
+			}
+			return nil, io.EOF
+		} else if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := ioutil.ReadAll(r.tr)
+		if err != nil {
+			return nil, err
+		}
+
+		dir, file := path.Split(h.Name)
+		dir = strings.TrimSuffix(dir, "/")
+		switch file {
+		case contentsFile:
+			if contents != nil {
+				return nil, fmt.Errorf("%s has contents but no metadata", contentsPath)
+			}
+			contents, contentsPath = b, dir
+		case metadataFile:
+			if contents != nil && contentsPath != dir {
+				return nil, fmt.Errorf("%s has contents but no metadata", contentsPath)
+			}
+			return &Entry{Path: dir, Metadata: b, Contents: contents}, nil
+		default:
+			return nil, errors.New("unexpected file " + h.Name)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestRoundTrip(t *testing.T) {
+	type resource struct {
+		path     string
+		message  proto.Message
+		contents []byte
+	}
+	resources := []resource{
+		{"", &rpc.Project{Name: "projects/p", DisplayName: "P"}, nil},
+		{"locations/global/apis/a", &rpc.Api{Name: "projects/p/locations/global/apis/a"}, nil},
+		{
+			RevisionPath("locations/global/apis/a/versions/v/specs/s", 1, "1111aaaa"),
+			&rpc.ApiSpec{Name: "projects/p/locations/global/apis/a/versions/v/specs/s@1111aaaa", RevisionTags: []string{"prod"}},
+			[]byte("openapi: 3.0.0"),
+		},
+		{"locations/global/artifacts/empty", &rpc.Artifact{Name: "projects/p/locations/global/artifacts/empty"}, []byte{}},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "projects/p")
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %s", err)
+	}
+	for _, r := range resources {
+		if err := w.Write(r.path, r.message, r.contents); err != nil {
+			t.Fatalf("Write(%q) returned error: %s", r.path, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() returned error: %s", err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader() returned error: %s", err)
+	}
+	if r.Manifest.Project != "projects/p" || r.Manifest.Version != Version {
+		t.Errorf("NewReader() read unexpected manifest %+v", r.Manifest)
+	}
+	for _, want := range resources {
+		e, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next() returned error: %s", err)
+		}
+		if e.Path != want.path {
+			t.Errorf("Next() returned path %q, want %q", e.Path, want.path)
+		}
+		if diff := cmp.Diff(want.contents, e.Contents); diff != "" {
+			t.Errorf("Next() returned unexpected contents of %q (-want +got):\n%s", want.path, diff)
+		}
+		got := want.message.ProtoReflect().New().Interface()
+		if err := e.Unmarshal(got); err != nil {
+			t.Fatalf("Unmarshal() returned error: %s", err)
+		}
+		if diff := cmp.Diff(want.message, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Unmarshal() returned unexpected message (-want +got):\n%s", diff)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() at the end returned %v, want io.EOF", err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	archive := func(files ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for i := 0; i < len(files); i += 2 {
+			_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: files[i], Mode: 0644, Size: int64(len(files[i+1]))})
+			_, _ = tw.Write([]byte(files[i+1]))
+		}
+		tw.Close()
+		gz.Close()
+		return &buf
+	}
+	manifest := "format: registry-archive\nversion: 1\nproject: projects/p\n"
+
+	tests := []struct {
+		desc    string
+		archive io.Reader
+	}{
+		{"not gzipped", bytes.NewBufferString("hello")},
+		{"no manifest", archive("metadata.json", "{}")},
+		{"other format", archive("archive.yaml", "format: other\nversion: 1\n")},
+		{"newer version", archive("archive.yaml", "format: registry-archive\nversion: 99\n")},
+		{"contents without metadata", archive("archive.yaml", manifest, "a/contents", "x", "b/metadata.json", "{}")},
+		{"unexpected file", archive("archive.yaml", manifest, "a/other", "x")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			r, err := NewReader(test.archive)
+			if err != nil {
+				return
+			}
+			for {
+				if _, err = r.Next(); err != nil {
+					break
+				}
+			}
+			if err == io.EOF {
+				t.Errorf("reading succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	// The hash of "hello" as computed by the registry.
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got := Checksum([]byte("hello")); got != want {
+		t.Errorf("Checksum() returned %q, want %q", got, want)
+	}
+	if got := Checksum(nil); got != "" {
+		t.Errorf("Checksum(nil) returned %q, want \"\"", got)
+	}
+}
@@ -93,6 +93,8 @@ func matchAndHandleAnnotateCmd(
 		return annotateVersions(ctx, client, version, filter, labeling, taskQueue)
 	} else if spec, err := names.ParseSpecCollection(name); err == nil {
 		return annotateSpecs(ctx, client, spec, filter, labeling, taskQueue)
+	} else if artifact, err := names.ParseArtifactCollection(name); err == nil {
+		return annotateArtifacts(ctx, client, artifact, filter, labeling, taskQueue)
 	}
 
 	// Then try to match resource names.
@@ -102,6 +104,8 @@ func matchAndHandleAnnotateCmd(
 		return annotateVersions(ctx, client, version, filter, labeling, taskQueue)
 	} else if spec, err := names.ParseSpec(name); err == nil {
 		return annotateSpecs(ctx, client, spec, filter, labeling, taskQueue)
+	} else if artifact, err := names.ParseArtifact(name); err == nil {
+		return annotateArtifacts(ctx, client, artifact, filter, labeling, taskQueue)
 	} else {
 		return fmt.Errorf("unsupported resource name %s", name)
 	}
@@ -154,6 +158,23 @@ func annotateSpecs(
 	})
 }
 
+func annotateArtifacts(
+	ctx context.Context,
+	client *gapic.RegistryClient,
+	artifact names.Artifact,
+	filterFlag string,
+	labeling *core.Labeling,
+	taskQueue chan<- core.Task) error {
+	// Artifacts are updated by replacement, so their contents are needed.
+	return core.ListArtifacts(ctx, client, artifact, filterFlag, true, func(artifact *rpc.Artifact) {
+		taskQueue <- &annotateArtifactTask{
+			client:   client,
+			artifact: artifact,
+			labeling: labeling,
+		}
+	})
+}
+
 type annotateApiTask struct {
 	client   connection.Client
 	api      *rpc.Api
@@ -234,3 +255,27 @@ func (task *annotateSpecTask) Run(ctx context.Context) error {
 		})
 	return err
 }
+
+type annotateArtifactTask struct {
+	client   connection.Client
+	artifact *rpc.Artifact
+	labeling *core.Labeling
+}
+
+func (task *annotateArtifactTask) String() string {
+	return "annotate " + task.artifact.Name
+}
+
+func (task *annotateArtifactTask) Run(ctx context.Context) error {
+	var err error
+	task.artifact.Annotations, err = task.labeling.Apply(task.artifact.Annotations)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Errorf("Invalid annotation")
+		return nil
+	}
+	_, err = task.client.ReplaceArtifact(ctx,
+		&rpc.ReplaceArtifactRequest{
+			Artifact: task.artifact,
+		})
+	return err
+}
@@ -0,0 +1,376 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	var (
+		file   string
+		prune  bool
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply -f FILE|DIR [NAME]",
+		Short: "Apply YAML descriptions of resources to the API Registry",
+		Long: `Apply YAML descriptions of resources to the API Registry.
+
+Files have the shape written by "registry export yaml". Each document
+describes the project, API, version, or spec named by its "name" field or by
+the NAME argument. Spec contents are read from the path in a spec's "file"
+field, relative to the YAML file. When a directory is given, every .yaml and
+.yml file in it is applied.
+
+Resources that don't exist are created and resources that differ from the
+files are updated. With --prune, resources in the collections listed in the
+files (e.g. the versions of an API) that aren't in the files are deleted.
+With --dry-run, the changes are printed but not made.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			want := newDesired()
+			if err := readPath(want, file, name); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read files")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			live := make(map[string]*resource)
+			for name := range want.resources {
+				r, err := getResource(ctx, client, adminClient, name)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatalf("Failed to get %s", name)
+				}
+				if r != nil {
+					live[name] = r
+				}
+			}
+
+			var extra []string
+			if prune {
+				for _, c := range want.collections {
+					children, err := listChildren(ctx, client, c)
+					if err != nil {
+						log.FromContext(ctx).WithError(err).Fatalf("Failed to list %s of %s", c.kind, c.parent)
+					}
+					extra = append(extra, children...)
+				}
+			}
+
+			actions := plan(want.resources, live, extra)
+			for _, a := range actions {
+				fmt.Fprintln(cmd.OutOrStdout(), a)
+				if dryRun {
+					continue
+				}
+				if err := perform(ctx, client, adminClient, a, want.resources[a.name], live[a.name]); err != nil {
+					log.FromContext(ctx).WithError(err).Fatalf("Failed to %s", a)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "A YAML file or a directory of YAML files to apply")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete resources that aren't in the files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print changes without making them")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// readPath reads a YAML file or all YAML files in a directory.
+func readPath(want *desired, path, name string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return want.readFile(path, name)
+	}
+
+	return filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml":
+			if err := want.readFile(p, name); err != nil {
+				return fmt.Errorf("%s: %s", p, err)
+			}
+		}
+		return nil
+	})
+}
+
+// getResource returns the live state of a resource, or nil if it doesn't exist.
+func getResource(ctx context.Context, client connection.Client, adminClient connection.AdminClient, name string) (*resource, error) {
+	r, err := get(ctx, client, adminClient, name)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	return r, err
+}
+
+func get(ctx context.Context, client connection.Client, adminClient connection.AdminClient, name string) (*resource, error) {
+	if _, err := names.ParseProject(name); err == nil {
+		p, err := adminClient.GetProject(ctx, &rpc.GetProjectRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		return &resource{name: name, fields: map[string]string{
+			"display_name": p.GetDisplayName(),
+			"description":  p.GetDescription(),
+		}}, nil
+	} else if _, err := names.ParseApi(name); err == nil {
+		a, err := client.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		return &resource{name: name, fields: map[string]string{
+			"display_name":        a.GetDisplayName(),
+			"description":         a.GetDescription(),
+			"availability":        a.GetAvailability(),
+			"recommended_version": a.GetRecommendedVersion(),
+		}, labels: a.GetLabels(), annotations: a.GetAnnotations()}, nil
+	} else if _, err := names.ParseVersion(name); err == nil {
+		v, err := client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		return &resource{name: name, fields: map[string]string{
+			"display_name": v.GetDisplayName(),
+			"description":  v.GetDescription(),
+			"state":        v.GetState(),
+		}, labels: v.GetLabels(), annotations: v.GetAnnotations()}, nil
+	} else if _, err := names.ParseSpec(name); err == nil {
+		s, err := client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		return &resource{name: name, fields: map[string]string{
+			"filename":    s.GetFilename(),
+			"description": s.GetDescription(),
+			"mime_type":   s.GetMimeType(),
+			"source_uri":  s.GetSourceUri(),
+		}, labels: s.GetLabels(), annotations: s.GetAnnotations(), hash: s.GetHash()}, nil
+	} else if _, err := names.ParseArtifact(name); err == nil {
+		a, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		contents, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		return &resource{name: name, fields: map[string]string{
+			"mime_type": a.GetMimeType(),
+		}, labels: a.GetLabels(), annotations: a.GetAnnotations(), contents: contents.GetData()}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported resource name %q", name)
+}
+
+// listChildren returns the names of the live resources in a collection.
+func listChildren(ctx context.Context, client connection.Client, c collection) ([]string, error) {
+	var children []string
+	var next func() (string, error)
+	switch c.kind {
+	case "apis":
+		project, err := names.ParseProject(c.parent)
+		if err != nil {
+			return nil, err
+		}
+		it := client.ListApis(ctx, &rpc.ListApisRequest{Parent: project.Location("-").String()})
+		next = func() (string, error) { m, err := it.Next(); return m.GetName(), err }
+	case "versions":
+		it := client.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{Parent: c.parent})
+		next = func() (string, error) { m, err := it.Next(); return m.GetName(), err }
+	case "specs":
+		it := client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: c.parent})
+		next = func() (string, error) { m, err := it.Next(); return m.GetName(), err }
+	case "artifacts":
+		it := client.ListArtifacts(ctx, &rpc.ListArtifactsRequest{Parent: c.parent})
+		next = func() (string, error) { m, err := it.Next(); return m.GetName(), err }
+	default:
+		return nil, fmt.Errorf("unsupported collection %q", c.kind)
+	}
+
+	for {
+		name, err := next()
+		if err == iterator.Done {
+			return children, nil
+		} else if status.Code(err) == codes.NotFound {
+			// The parent will be created, so it has no children yet.
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		children = append(children, name)
+	}
+}
+
+// perform makes the change described by an action. Creates and updates use
+// allow_missing so that both are handled as updates.
+func perform(ctx context.Context, client connection.Client, adminClient connection.AdminClient, a action, want, live *resource) error {
+	if a.verb == "delete" {
+		return remove(ctx, client, a.name)
+	}
+
+	mask := &fieldmaskpb.FieldMask{Paths: a.paths}
+	if _, err := names.ParseProject(a.name); err == nil {
+		_, err := adminClient.UpdateProject(ctx, &rpc.UpdateProjectRequest{
+			Project: &rpc.Project{
+				Name:        a.name,
+				DisplayName: want.fields["display_name"],
+				Description: want.fields["description"],
+			},
+			UpdateMask:   mask,
+			AllowMissing: true,
+		})
+		return err
+	} else if _, err := names.ParseApi(a.name); err == nil {
+		_, err := client.UpdateApi(ctx, &rpc.UpdateApiRequest{
+			Api: &rpc.Api{
+				Name:               a.name,
+				DisplayName:        want.fields["display_name"],
+				Description:        want.fields["description"],
+				Availability:       want.fields["availability"],
+				RecommendedVersion: want.fields["recommended_version"],
+				Labels:             want.labels,
+				Annotations:        want.annotations,
+			},
+			UpdateMask:   mask,
+			AllowMissing: true,
+		})
+		return err
+	} else if _, err := names.ParseVersion(a.name); err == nil {
+		_, err := client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+			ApiVersion: &rpc.ApiVersion{
+				Name:        a.name,
+				DisplayName: want.fields["display_name"],
+				Description: want.fields["description"],
+				State:       want.fields["state"],
+				Labels:      want.labels,
+				Annotations: want.annotations,
+			},
+			UpdateMask:   mask,
+			AllowMissing: true,
+		})
+		return err
+	} else if _, err := names.ParseSpec(a.name); err == nil {
+		_, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+			ApiSpec: &rpc.ApiSpec{
+				Name:        a.name,
+				Filename:    want.fields["filename"],
+				Description: want.fields["description"],
+				MimeType:    want.fields["mime_type"],
+				SourceUri:   want.fields["source_uri"],
+				Contents:    want.contents,
+				Labels:      want.labels,
+				Annotations: want.annotations,
+			},
+			UpdateMask:   mask,
+			AllowMissing: true,
+		})
+		return err
+	} else if _, err := names.ParseArtifact(a.name); err == nil {
+		return replaceArtifact(ctx, client, want, live)
+	}
+
+	return fmt.Errorf("unsupported resource name %q", a.name)
+}
+
+// replaceArtifact creates or replaces an artifact. Artifacts can't be
+// partially updated, so fields that aren't in the files keep their live values.
+func replaceArtifact(ctx context.Context, client connection.Client, want, live *resource) error {
+	if live == nil {
+		name, err := names.ParseArtifact(want.name)
+		if err != nil {
+			return err
+		}
+		_, err = client.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+			Parent:     name.Parent(),
+			ArtifactId: name.ArtifactID(),
+			Artifact: &rpc.Artifact{
+				MimeType:    want.fields["mime_type"],
+				Contents:    want.contents,
+				Labels:      want.labels,
+				Annotations: want.annotations,
+			},
+		})
+		return err
+	}
+
+	artifact := &rpc.Artifact{
+		Name:        want.name,
+		MimeType:    live.fields["mime_type"],
+		Contents:    live.contents,
+		Labels:      live.labels,
+		Annotations: live.annotations,
+	}
+	if v, ok := want.fields["mime_type"]; ok {
+		artifact.MimeType = v
+	}
+	if want.contents != nil {
+		artifact.Contents = want.contents
+	}
+	if want.labels != nil {
+		artifact.Labels = want.labels
+	}
+	if want.annotations != nil {
+		artifact.Annotations = want.annotations
+	}
+	_, err := client.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{Artifact: artifact})
+	return err
+}
+
+func remove(ctx context.Context, client connection.Client, name string) error {
+	if _, err := names.ParseApi(name); err == nil {
+		return client.DeleteApi(ctx, &rpc.DeleteApiRequest{Name: name})
+	} else if _, err := names.ParseVersion(name); err == nil {
+		return client.DeleteApiVersion(ctx, &rpc.DeleteApiVersionRequest{Name: name})
+	} else if _, err := names.ParseSpec(name); err == nil {
+		return client.DeleteApiSpec(ctx, &rpc.DeleteApiSpecRequest{Name: name})
+	} else if _, err := names.ParseArtifact(name); err == nil {
+		return client.DeleteArtifact(ctx, &rpc.DeleteArtifactRequest{Name: name})
+	}
+	return fmt.Errorf("unsupported resource name %q", name)
+}
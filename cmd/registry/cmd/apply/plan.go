@@ -0,0 +1,531 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/server/registry/mimetypes"
+	"github.com/apigee/registry/server/registry/names"
+	"gopkg.in/yaml.v3"
+)
+
+// The documents read by apply have the shape written by "registry export yaml".
+// Fields that are set by the registry, like createTime and hash, are accepted
+// and ignored so that exported files can be applied without changes.
+
+type projectDoc struct {
+	Name        string                  `yaml:"name"`
+	DisplayName *string                 `yaml:"display_name"`
+	Description *string                 `yaml:"description"`
+	Apis        map[string]*apiDoc      `yaml:"apis"`
+	Artifacts   map[string]*artifactDoc `yaml:"artifacts"`
+}
+
+type apiDoc struct {
+	Name               string                  `yaml:"name"`
+	DisplayName        *string                 `yaml:"display_name"`
+	Description        *string                 `yaml:"description"`
+	Availability       *string                 `yaml:"availability"`
+	RecommendedVersion *string                 `yaml:"recommended_version"`
+	Labels             map[string]string       `yaml:"labels"`
+	Annotations        map[string]string       `yaml:"annotations"`
+	Versions           map[string]*versionDoc  `yaml:"versions"`
+	Artifacts          map[string]*artifactDoc `yaml:"artifacts"`
+	CreateTime         string                  `yaml:"createTime"`
+}
+
+type versionDoc struct {
+	Name        string                  `yaml:"name"`
+	DisplayName *string                 `yaml:"display_name"`
+	Description *string                 `yaml:"description"`
+	State       *string                 `yaml:"state"`
+	Labels      map[string]string       `yaml:"labels"`
+	Annotations map[string]string       `yaml:"annotations"`
+	Specs       map[string]*specDoc     `yaml:"specs"`
+	Artifacts   map[string]*artifactDoc `yaml:"artifacts"`
+	CreateTime  string                  `yaml:"createTime"`
+}
+
+type specDoc struct {
+	Name        string                  `yaml:"name"`
+	File        string                  `yaml:"file"`
+	Filename    *string                 `yaml:"filename"`
+	Description *string                 `yaml:"description"`
+	MimeType    *string                 `yaml:"mime_type"`
+	SourceURI   *string                 `yaml:"source_uri"`
+	Labels      map[string]string       `yaml:"labels"`
+	Annotations map[string]string       `yaml:"annotations"`
+	Artifacts   map[string]*artifactDoc `yaml:"artifacts"`
+	CreateTime  string                  `yaml:"createTime"`
+	Hash        string                  `yaml:"hash"`
+	Size        int64                   `yaml:"size"`
+	RevisionID  string                  `yaml:"revisionId"`
+}
+
+type artifactDoc struct {
+	MimeType    *string           `yaml:"mime_type"`
+	Contents    *string           `yaml:"contents"`
+	File        string            `yaml:"file"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+	CreateTime  string            `yaml:"createTime"`
+}
+
+// resource is the state of a registry resource, either as described by a
+// file or as read from the registry. Only the fields that are set in a file
+// are compared with the registry.
+type resource struct {
+	name        string
+	fields      map[string]string // String fields by their update mask path.
+	labels      map[string]string // Nil if not set.
+	annotations map[string]string // Nil if not set.
+	contents    []byte            // Nil if not set. Stored as given, e.g. gzipped.
+	hash        string            // For specs, the hash of uncompressed contents.
+}
+
+// desired is the state of the registry described by a set of files.
+type desired struct {
+	resources map[string]*resource
+	// collections are the parents and kinds of children that are listed in
+	// the files, e.g. "projects/p/locations/global/apis/a" and "versions".
+	// Only these collections are pruned.
+	collections []collection
+}
+
+type collection struct {
+	parent string
+	kind   string
+}
+
+func newDesired() *desired {
+	return &desired{resources: make(map[string]*resource)}
+}
+
+// readFile adds the resources described by the YAML documents in a file. Each
+// document describes the resource named by its "name" field or, if that is
+// empty, by name.
+func (d *desired) readFile(filename, name string) error {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return d.read(b, filepath.Dir(filename), name)
+}
+
+// read adds the resources described by YAML documents. Files referenced by the
+// documents are read relative to dir.
+func (d *desired) read(b []byte, dir, name string) error {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := d.readDoc(&node, dir, name); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *desired) readDoc(node *yaml.Node, dir, name string) error {
+	var doc struct {
+		Name string `yaml:"name"`
+	}
+	if err := node.Decode(&doc); err != nil {
+		return err
+	}
+	if doc.Name != "" {
+		name = doc.Name
+	}
+
+	if project, err := names.ParseProject(name); err == nil {
+		var doc projectDoc
+		if err := decodeStrict(node, &doc); err != nil {
+			return err
+		}
+		return d.addProject(project, &doc, dir)
+	} else if api, err := names.ParseApi(name); err == nil {
+		var doc apiDoc
+		if err := decodeStrict(node, &doc); err != nil {
+			return err
+		}
+		return d.addApi(api, &doc, dir)
+	} else if version, err := names.ParseVersion(name); err == nil {
+		var doc versionDoc
+		if err := decodeStrict(node, &doc); err != nil {
+			return err
+		}
+		return d.addVersion(version, &doc, dir)
+	} else if spec, err := names.ParseSpec(name); err == nil {
+		var doc specDoc
+		if err := decodeStrict(node, &doc); err != nil {
+			return err
+		}
+		return d.addSpec(spec, &doc, dir)
+	} else if name == "" {
+		return errors.New("documents must have a name or a name must be given as an argument")
+	}
+
+	return fmt.Errorf("%q is not a project, API, version, or spec name", name)
+}
+
+// decodeStrict decodes a node and fails on unknown fields, so that typos in
+// field names aren't silently ignored.
+func decodeStrict(node *yaml.Node, v interface{}) error {
+	b, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+func (d *desired) add(r *resource) error {
+	if _, ok := d.resources[r.name]; ok {
+		return fmt.Errorf("%s is described more than once", r.name)
+	}
+	d.resources[r.name] = r
+	return nil
+}
+
+func (d *desired) addProject(project names.Project, doc *projectDoc, dir string) error {
+	r := &resource{name: project.String(), fields: map[string]string{}}
+	setField(r, "display_name", doc.DisplayName)
+	setField(r, "description", doc.Description)
+	if err := d.add(r); err != nil {
+		return err
+	}
+
+	if doc.Apis != nil {
+		d.collections = append(d.collections, collection{parent: project.String(), kind: "apis"})
+	}
+	for _, key := range sortedKeys(doc.Apis) {
+		// APIs outside the default location are qualified by their location.
+		location, id := names.DefaultLocation, key
+		if parts := strings.SplitN(key, "/", 2); len(parts) == 2 {
+			location, id = parts[0], parts[1]
+		}
+		if err := d.addApi(project.Location(location).Api(id), doc.Apis[key], dir); err != nil {
+			return err
+		}
+	}
+
+	return d.addArtifacts(project.Location(names.DefaultLocation), doc.Artifacts, dir)
+}
+
+func (d *desired) addApi(api names.Api, doc *apiDoc, dir string) error {
+	if doc == nil {
+		doc = &apiDoc{}
+	}
+	if err := api.Validate(); err != nil {
+		return err
+	}
+	r := &resource{name: api.String(), fields: map[string]string{}, labels: doc.Labels, annotations: doc.Annotations}
+	setField(r, "display_name", doc.DisplayName)
+	setField(r, "description", doc.Description)
+	setField(r, "availability", doc.Availability)
+	setField(r, "recommended_version", doc.RecommendedVersion)
+	if err := d.add(r); err != nil {
+		return err
+	}
+
+	if doc.Versions != nil {
+		d.collections = append(d.collections, collection{parent: api.String(), kind: "versions"})
+	}
+	for _, id := range sortedKeys(doc.Versions) {
+		if err := d.addVersion(api.Version(id), doc.Versions[id], dir); err != nil {
+			return err
+		}
+	}
+
+	return d.addArtifacts(api, doc.Artifacts, dir)
+}
+
+func (d *desired) addVersion(version names.Version, doc *versionDoc, dir string) error {
+	if doc == nil {
+		doc = &versionDoc{}
+	}
+	if err := version.Validate(); err != nil {
+		return err
+	}
+	r := &resource{name: version.String(), fields: map[string]string{}, labels: doc.Labels, annotations: doc.Annotations}
+	setField(r, "display_name", doc.DisplayName)
+	setField(r, "description", doc.Description)
+	setField(r, "state", doc.State)
+	if err := d.add(r); err != nil {
+		return err
+	}
+
+	if doc.Specs != nil {
+		d.collections = append(d.collections, collection{parent: version.String(), kind: "specs"})
+	}
+	for _, id := range sortedKeys(doc.Specs) {
+		// Exports include spec revisions, which can't be applied.
+		if strings.Contains(id, "@") {
+			continue
+		}
+		if err := d.addSpec(version.Spec(id), doc.Specs[id], dir); err != nil {
+			return err
+		}
+	}
+
+	return d.addArtifacts(version, doc.Artifacts, dir)
+}
+
+func (d *desired) addSpec(spec names.Spec, doc *specDoc, dir string) error {
+	if doc == nil {
+		doc = &specDoc{}
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	r := &resource{name: spec.String(), fields: map[string]string{}, labels: doc.Labels, annotations: doc.Annotations}
+	setField(r, "description", doc.Description)
+	setField(r, "source_uri", doc.SourceURI)
+	if doc.MimeType != nil {
+		// The registry stores MIME types in their normal form.
+		mimeType, err := mimetypes.Normalize(*doc.MimeType)
+		if err != nil {
+			return fmt.Errorf("%s: invalid mime_type %q: %s", spec, *doc.MimeType, err)
+		}
+		r.fields["mime_type"] = mimeType
+	}
+
+	if doc.File != "" {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, doc.File))
+		if err != nil {
+			return fmt.Errorf("%s: %s", spec, err)
+		}
+		r.hash = hashForBytes(contents)
+		if mimetypes.IsCompressed(r.fields["mime_type"], mimetypes.GZip) {
+			if contents, err = core.GZippedBytes(contents); err != nil {
+				return fmt.Errorf("%s: %s", spec, err)
+			}
+		}
+		r.contents = contents
+		if doc.Filename == nil {
+			r.fields["filename"] = filepath.Base(doc.File)
+		}
+	}
+	setField(r, "filename", doc.Filename)
+
+	if err := d.add(r); err != nil {
+		return err
+	}
+
+	return d.addArtifacts(spec, doc.Artifacts, dir)
+}
+
+type artifactParent interface {
+	Artifact(id string) names.Artifact
+	String() string
+}
+
+func (d *desired) addArtifacts(parent artifactParent, docs map[string]*artifactDoc, dir string) error {
+	if docs == nil {
+		return nil
+	}
+	d.collections = append(d.collections, collection{parent: parent.String(), kind: "artifacts"})
+
+	for _, id := range sortedKeys(docs) {
+		artifact := parent.Artifact(id)
+		if err := artifact.Validate(); err != nil {
+			return err
+		}
+		doc := docs[id]
+		if doc == nil {
+			doc = &artifactDoc{}
+		}
+		r := &resource{name: artifact.String(), fields: map[string]string{}, labels: doc.Labels, annotations: doc.Annotations}
+		setField(r, "mime_type", doc.MimeType)
+		switch {
+		case doc.Contents != nil && doc.File != "":
+			return fmt.Errorf("%s: only one of contents and file may be set", artifact)
+		case doc.Contents != nil:
+			r.contents = []byte(*doc.Contents)
+		case doc.File != "":
+			contents, err := ioutil.ReadFile(filepath.Join(dir, doc.File))
+			if err != nil {
+				return fmt.Errorf("%s: %s", artifact, err)
+			}
+			r.contents = contents
+		}
+		if err := d.add(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setField(r *resource, path string, value *string) {
+	if value != nil {
+		r.fields[path] = *value
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*apiDoc:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*versionDoc:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*specDoc:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*artifactDoc:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hashForBytes(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// action is a change to a single resource.
+type action struct {
+	verb  string   // "create", "update", or "delete".
+	name  string   // The name of the changed resource.
+	paths []string // For creates and updates, the fields that are set.
+}
+
+func (a action) String() string {
+	if len(a.paths) == 0 || a.verb == "delete" {
+		return fmt.Sprintf("%s %s", a.verb, a.name)
+	}
+	return fmt.Sprintf("%s %s (%s)", a.verb, a.name, strings.Join(a.paths, ", "))
+}
+
+// plan returns the actions that change the live resources to the desired
+// ones. Resources named in prune are deleted. Creates and updates are ordered
+// so that parents come before their children, and deletes come last with
+// children before their parents.
+func plan(want map[string]*resource, live map[string]*resource, prune []string) []action {
+	var actions []action
+	for _, name := range sortedNames(want) {
+		w := want[name]
+		l, ok := live[name]
+		if !ok {
+			actions = append(actions, action{verb: "create", name: name, paths: w.paths()})
+		} else if paths := changes(w, l); len(paths) > 0 {
+			actions = append(actions, action{verb: "update", name: name, paths: paths})
+		}
+	}
+
+	prune = append([]string(nil), prune...)
+	sort.Sort(sort.Reverse(sort.StringSlice(prune)))
+	for _, name := range prune {
+		if _, ok := want[name]; !ok {
+			actions = append(actions, action{verb: "delete", name: name})
+		}
+	}
+	return actions
+}
+
+// paths returns the update mask paths of the fields that are set.
+func (r *resource) paths() []string {
+	var paths []string
+	for path := range r.fields {
+		paths = append(paths, path)
+	}
+	if r.labels != nil {
+		paths = append(paths, "labels")
+	}
+	if r.annotations != nil {
+		paths = append(paths, "annotations")
+	}
+	if r.contents != nil {
+		paths = append(paths, "contents")
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// changes returns the update mask paths of the fields of want that differ
+// from live.
+func changes(want, live *resource) []string {
+	var paths []string
+	for path, value := range want.fields {
+		if live.fields[path] != value {
+			paths = append(paths, path)
+		}
+	}
+	if want.labels != nil && !equalMaps(want.labels, live.labels) {
+		paths = append(paths, "labels")
+	}
+	if want.annotations != nil && !equalMaps(want.annotations, live.annotations) {
+		paths = append(paths, "annotations")
+	}
+	if want.contents != nil {
+		// Spec contents are compared by hash because the registry may store
+		// them compressed.
+		if want.hash != "" || live.hash != "" {
+			if want.hash != live.hash {
+				paths = append(paths, "contents")
+			}
+		} else if !bytes.Equal(want.contents, live.contents) {
+			paths = append(paths, "contents")
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func equalMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedNames(m map[string]*resource) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
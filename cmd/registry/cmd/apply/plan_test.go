@@ -0,0 +1,220 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testDoc has the shape written by "registry export yaml".
+const testDoc = `name: projects/p
+apis:
+  a:
+    createTime: "2021-10-01T00:00:00Z"
+    availability: GENERAL
+    recommended_version: v1
+    labels:
+      team: core
+    versions:
+      v1:
+        createTime: "2021-10-01T00:00:00Z"
+        state: PRODUCTION
+        specs:
+          openapi.yaml:
+            mime_type: Application/X.OpenAPI+GZip; Version=3
+            file: openapi.yaml
+            hash: ignored
+            size: 10
+            createTime: "2021-10-01T00:00:00Z"
+            revisionId: ignored
+          openapi.yaml@abc:
+            mime_type: application/x.openapi;version=3
+    artifacts:
+      notes:
+        mime_type: text/plain
+        contents: hello
+        createTime: "2021-10-01T00:00:00Z"
+      logo:
+        contents: !!binary AAEC
+  europe-west1/b: {}
+`
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte("openapi: 3.0.0\n"), 0644); err != nil {
+		t.Fatalf("Setup: failed to write spec: %s", err)
+	}
+
+	want := newDesired()
+	if err := want.read([]byte(testDoc), dir, ""); err != nil {
+		t.Fatalf("read() returned error: %s", err)
+	}
+
+	var got []string
+	for _, name := range sortedNames(want.resources) {
+		got = append(got, action{verb: "create", name: name, paths: want.resources[name].paths()}.String())
+	}
+	expected := []string{
+		"create projects/p",
+		"create projects/p/locations/europe-west1/apis/b",
+		"create projects/p/locations/global/apis/a (availability, labels, recommended_version)",
+		"create projects/p/locations/global/apis/a/artifacts/logo (contents)",
+		"create projects/p/locations/global/apis/a/artifacts/notes (contents, mime_type)",
+		"create projects/p/locations/global/apis/a/versions/v1 (state)",
+		"create projects/p/locations/global/apis/a/versions/v1/specs/openapi.yaml (contents, filename, mime_type)",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("read() returned unexpected resources (-want +got):\n%s", diff)
+	}
+
+	spec := want.resources["projects/p/locations/global/apis/a/versions/v1/specs/openapi.yaml"]
+	if got, want := spec.fields["mime_type"], "application/x.openapi+gzip;version=3"; got != want {
+		t.Errorf("read() returned spec mime_type %q, want %q", got, want)
+	}
+	if got, want := spec.hash, hashForBytes([]byte("openapi: 3.0.0\n")); got != want {
+		t.Errorf("read() returned spec hash %q, want the hash of the uncompressed file %q", got, want)
+	}
+	if logo := want.resources["projects/p/locations/global/apis/a/artifacts/logo"]; !cmp.Equal(logo.contents, []byte{0, 1, 2}) {
+		t.Errorf("read() returned binary artifact contents %v, want %v", logo.contents, []byte{0, 1, 2})
+	}
+
+	collections := []collection{
+		{parent: "projects/p", kind: "apis"},
+		{parent: "projects/p/locations/global/apis/a", kind: "versions"},
+		{parent: "projects/p/locations/global/apis/a/versions/v1", kind: "specs"},
+		{parent: "projects/p/locations/global/apis/a", kind: "artifacts"},
+	}
+	if diff := cmp.Diff(collections, want.collections, cmp.AllowUnexported(collection{})); diff != "" {
+		t.Errorf("read() returned unexpected collections (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		doc  string
+		name string
+	}{
+		{
+			desc: "missing name",
+			doc:  "apis: {}",
+		},
+		{
+			desc: "unsupported name",
+			doc:  "name: projects/p/locations/global/apis/a/deployments/d",
+		},
+		{
+			desc: "unknown field",
+			doc:  "availability: GENERAL\nversoins: {}",
+			name: "projects/p/locations/global/apis/a",
+		},
+		{
+			desc: "invalid mime type",
+			doc:  "mime_type: openapi",
+			name: "projects/p/locations/global/apis/a/versions/v1/specs/s",
+		},
+		{
+			desc: "contents and file",
+			doc:  "artifacts:\n  x:\n    contents: hello\n    file: x.txt",
+			name: "projects/p/locations/global/apis/a",
+		},
+		{
+			desc: "duplicate resource",
+			doc:  "name: projects/p/locations/global/apis/a\n---\nname: projects/p/locations/global/apis/a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := newDesired().read([]byte(test.doc), t.TempDir(), test.name); err == nil {
+				t.Errorf("read(%q) succeeded, want error", test.doc)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	want := map[string]*resource{
+		"projects/p/locations/global/apis/new": {
+			name:   "projects/p/locations/global/apis/new",
+			fields: map[string]string{"availability": "GENERAL"},
+		},
+		"projects/p/locations/global/apis/same": {
+			name:   "projects/p/locations/global/apis/same",
+			fields: map[string]string{"availability": "GENERAL"},
+			labels: map[string]string{},
+		},
+		"projects/p/locations/global/apis/changed": {
+			name:   "projects/p/locations/global/apis/changed",
+			fields: map[string]string{"availability": "GENERAL", "description": "d"},
+			labels: map[string]string{"a": "b"},
+		},
+		"projects/p/locations/global/apis/changed/versions/v1/specs/s": {
+			name:     "projects/p/locations/global/apis/changed/versions/v1/specs/s",
+			fields:   map[string]string{},
+			contents: []byte("zipped"),
+			hash:     "new",
+		},
+		"projects/p/locations/global/apis/changed/artifacts/x": {
+			name:     "projects/p/locations/global/apis/changed/artifacts/x",
+			fields:   map[string]string{},
+			contents: []byte("hello"),
+		},
+	}
+	live := map[string]*resource{
+		"projects/p/locations/global/apis/same": {
+			name:   "projects/p/locations/global/apis/same",
+			fields: map[string]string{"availability": "GENERAL", "description": "unmanaged"},
+		},
+		"projects/p/locations/global/apis/changed": {
+			name:   "projects/p/locations/global/apis/changed",
+			fields: map[string]string{"availability": "GENERAL", "description": ""},
+		},
+		"projects/p/locations/global/apis/changed/versions/v1/specs/s": {
+			name:   "projects/p/locations/global/apis/changed/versions/v1/specs/s",
+			fields: map[string]string{},
+			hash:   "old",
+		},
+		"projects/p/locations/global/apis/changed/artifacts/x": {
+			name:     "projects/p/locations/global/apis/changed/artifacts/x",
+			fields:   map[string]string{},
+			contents: []byte("hello"),
+		},
+	}
+	prune := []string{
+		"projects/p/locations/global/apis/same",
+		"projects/p/locations/global/apis/old",
+		"projects/p/locations/global/apis/old/versions/v1",
+	}
+
+	var got []string
+	for _, a := range plan(want, live, prune) {
+		got = append(got, a.String())
+	}
+	expected := []string{
+		"update projects/p/locations/global/apis/changed (description, labels)",
+		"update projects/p/locations/global/apis/changed/versions/v1/specs/s (contents)",
+		"create projects/p/locations/global/apis/new (availability)",
+		"delete projects/p/locations/global/apis/old/versions/v1",
+		"delete projects/p/locations/global/apis/old",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("plan() returned unexpected actions (-want +got):\n%s", diff)
+	}
+}
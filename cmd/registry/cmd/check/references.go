@@ -0,0 +1,156 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func referencesCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "references PROJECT",
+		Short: "Report references from APIs and deployments that do not name existing resources",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			project, err := names.ParseProject(args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse project name")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			dangling, err := danglingReferences(ctx, client, project)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to check references")
+			}
+
+			for _, r := range dangling {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\t%s\n", r.source, r.field, r.value, r.problem)
+			}
+			if len(dangling) > 0 {
+				log.Fatalf(ctx, "Found %d dangling references in %s", len(dangling), project)
+			}
+		},
+	}
+}
+
+// danglingReference is a reference that does not name an existing resource.
+type danglingReference struct {
+	source  string // name of the API or deployment containing the reference
+	field   string
+	value   string
+	problem string
+}
+
+// danglingReferences returns the references from the APIs and deployments of
+// a project that do not name existing resources in the project.
+func danglingReferences(ctx context.Context, client connection.Client, project names.Project) ([]danglingReference, error) {
+	var dangling []danglingReference
+	check := func(source, field, value string, get func(string) error) error {
+		if value == "" {
+			return nil
+		}
+		problem, err := checkReference(project, value, get)
+		if err != nil {
+			return err
+		} else if problem != "" {
+			dangling = append(dangling, danglingReference{source: source, field: field, value: value, problem: problem})
+		}
+		return nil
+	}
+
+	getVersion := func(name string) error {
+		_, err := client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+		return err
+	}
+	getDeployment := func(name string) error {
+		_, err := client.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name})
+		return err
+	}
+	getSpec := func(name string) error {
+		_, err := client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+		return err
+	}
+
+	var apis []*rpc.Api
+	err := core.ListAPIs(ctx, client, project.Location("-").Api("-"), "", func(api *rpc.Api) {
+		apis = append(apis, api)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, api := range apis {
+		if err := check(api.GetName(), "recommended_version", api.GetRecommendedVersion(), getVersion); err != nil {
+			return nil, err
+		}
+		if err := check(api.GetName(), "recommended_deployment", api.GetRecommendedDeployment(), getDeployment); err != nil {
+			return nil, err
+		}
+	}
+
+	it := client.ListApiDeployments(ctx, &rpc.ListApiDeploymentsRequest{
+		Parent: project.Location("-").Api("-").String(),
+	})
+	for {
+		deployment, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if err := check(deployment.GetName(), "api_spec_revision", deployment.GetApiSpecRevision(), getSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	return dangling, nil
+}
+
+// checkReference describes the problem with a reference from a resource in
+// project, or returns an empty string if the reference names an existing
+// resource. References to the "latest" revision name the current revision.
+func checkReference(project names.Project, value string, get func(string) error) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(value), strings.ToLower(project.String())+"/") {
+		return fmt.Sprintf("not a resource in %s", project), nil
+	}
+
+	err := get(strings.TrimSuffix(value, "@latest"))
+	switch status.Code(err) {
+	case codes.OK:
+		return "", nil
+	case codes.NotFound:
+		return "not found", nil
+	case codes.InvalidArgument:
+		return "invalid resource name", nil
+	default:
+		return "", err
+	}
+}
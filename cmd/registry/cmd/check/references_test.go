@@ -0,0 +1,66 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckReference(t *testing.T) {
+	const spec = "projects/my-project/locations/global/apis/a/versions/v/specs/s"
+	project := names.Project{ProjectID: "my-project"}
+
+	// The fake registry contains only the spec.
+	get := func(name string) error {
+		if name == spec {
+			return nil
+		}
+		if _, err := names.ParseSpec(name); err != nil {
+			if _, err := names.ParseSpecRevision(name); err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		return status.Error(codes.NotFound, name)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: spec, want: ""},
+		{value: spec + "@latest", want: ""},
+		{value: spec + "@12345678", want: "not found"},
+		{value: "projects/my-project/locations/global/apis/a/versions/v/specs/other", want: "not found"},
+		{value: "projects/my-project/bad", want: "invalid resource name"},
+		{value: "projects/other-project/locations/global/apis/a/versions/v/specs/s", want: "not a resource in projects/my-project"},
+		{value: "some-revision", want: "not a resource in projects/my-project"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := checkReference(project, test.value, get)
+			if err != nil {
+				t.Fatalf("checkReference(%q) returned error: %s", test.value, err)
+			}
+			if got != test.want {
+				t.Errorf("checkReference(%q) returned %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/cmd/registry/metrics"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	changeStatsRelation   = "change-stats"
+	changeMetricsRelation = "change-metrics"
+
+	changeStatsMessageType   = "google.cloud.apigeeregistry.applications.v1alpha1.ChangeStats"
+	changeMetricsMessageType = "google.cloud.apigeeregistry.applications.v1alpha1.ChangeMetrics"
+)
+
+func changeMetricsCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "change-metrics",
+		Short: "Compute metrics of the changes between revisions of specs",
+		Long: `Compute metrics of the changes between revisions of specs, using the
+ChangeDetails artifacts stored by "registry compute diffs". The changes of
+each spec, version, or API are summarized in a change-stats artifact and a
+change-metrics artifact. Specs, versions, and APIs whose change-metrics
+artifacts were updated after all of their ChangeDetails artifacts are
+skipped.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			filter, err := cmd.Flags().GetString("filter")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			// Initialize task queue.
+			taskQueue, wait := core.WorkerPool(ctx, 64)
+			defer wait()
+			// Generate tasks.
+			err = matchAndHandleChangeMetricsCmd(ctx, client, args[0], filter, func(parent string, details names.Artifact) {
+				taskQueue <- &computeChangeMetricsTask{
+					client:  client,
+					parent:  parent,
+					details: details,
+				}
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to match or handle command")
+			}
+		},
+	}
+}
+
+// matchAndHandleChangeMetricsCmd calls handler with each matching resource and
+// the pattern of the artifacts with the details of its changes.
+func matchAndHandleChangeMetricsCmd(
+	ctx context.Context,
+	client connection.Client,
+	name string,
+	filter string,
+	handler func(parent string, details names.Artifact),
+) error {
+	if api, err := names.ParseApi(name); err == nil {
+		return core.ListAPIs(ctx, client, api, filter, func(api *rpc.Api) {
+			if name, err := names.ParseApi(api.GetName()); err == nil {
+				handler(api.GetName(), name.Version("-").Spec("-").Artifact("-"))
+			}
+		})
+	} else if version, err := names.ParseVersion(name); err == nil {
+		return core.ListVersions(ctx, client, version, filter, func(version *rpc.ApiVersion) {
+			if name, err := names.ParseVersion(version.GetName()); err == nil {
+				handler(version.GetName(), name.Spec("-").Artifact("-"))
+			}
+		})
+	} else if spec, err := names.ParseSpec(name); err == nil {
+		return core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+			if name, err := names.ParseSpec(spec.GetName()); err == nil {
+				handler(spec.GetName(), name.Artifact("-"))
+			}
+		})
+	}
+	return fmt.Errorf("unsupported argument: %s", name)
+}
+
+type computeChangeMetricsTask struct {
+	client  connection.Client
+	parent  string
+	details names.Artifact
+}
+
+func (task *computeChangeMetricsTask) String() string {
+	return "compute change-metrics " + task.parent
+}
+
+func (task *computeChangeMetricsTask) Run(ctx context.Context) error {
+	statsName, err := names.ParseArtifact(task.parent + "/artifacts/" + changeStatsRelation)
+	if err != nil {
+		return err
+	}
+	metricsName, err := names.ParseArtifact(task.parent + "/artifacts/" + changeMetricsRelation)
+	if err != nil {
+		return err
+	}
+
+	// Details are listed without their contents to check if they changed.
+	var updated []time.Time
+	filter := fmt.Sprintf("mime_type == '%s'", core.MimeTypeForMessageType(changeDetailsMessageType))
+	if err := core.ListArtifacts(ctx, task.client, task.details, filter, false, func(a *rpc.Artifact) {
+		updated = append(updated, a.GetUpdateTime().AsTime())
+	}); err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		log.Debugf(ctx, "Skipping %s, it has no computed diffs", task.parent)
+		return nil
+	}
+	existing, err := task.client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: metricsName.String()})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+	if metricsUpToDate(existing, updated) {
+		log.Debugf(ctx, "Skipping %s, its change metrics are up to date", task.parent)
+		return nil
+	}
+
+	var details []*rpc.ChangeDetails
+	if err := core.ListArtifacts(ctx, task.client, task.details, filter, true, func(a *rpc.Artifact) {
+		d := &rpc.ChangeDetails{}
+		if err := proto.Unmarshal(a.GetContents(), d); err != nil {
+			log.FromContext(ctx).WithError(err).Errorf("Invalid ChangeDetails: %s", a.GetName())
+			return
+		}
+		details = append(details, d)
+	}); err != nil {
+		return err
+	}
+	if len(details) == 0 {
+		log.Debugf(ctx, "Skipping %s, it has no computed diffs", task.parent)
+		return nil
+	}
+
+	// The metrics are stored last, so that their update time covers the stats.
+	stats := metrics.ComputeStats(details...)
+	if err := setMessageArtifact(ctx, task.client, statsName, changeStatsMessageType, stats); err != nil {
+		return err
+	}
+	return setMessageArtifact(ctx, task.client, metricsName, changeMetricsMessageType, metrics.ComputeMetrics(stats))
+}
+
+// metricsUpToDate returns true if a change-metrics artifact exists and was
+// updated after each of the ChangeDetails artifacts that it summarizes.
+func metricsUpToDate(metrics *rpc.Artifact, detailsUpdated []time.Time) bool {
+	if metrics == nil {
+		return false
+	}
+	t := metrics.GetUpdateTime().AsTime()
+	for _, u := range detailsUpdated {
+		if u.After(t) {
+			return false
+		}
+	}
+	return true
+}
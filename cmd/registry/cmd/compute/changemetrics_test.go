@@ -0,0 +1,59 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMetricsUpToDate(t *testing.T) {
+	now := time.Now()
+	metrics := &rpc.Artifact{UpdateTime: timestamppb.New(now)}
+
+	tests := []struct {
+		desc    string
+		metrics *rpc.Artifact
+		details []time.Time
+		want    bool
+	}{
+		{
+			desc:    "missing metrics",
+			details: []time.Time{now.Add(-time.Hour)},
+		},
+		{
+			desc:    "older details",
+			metrics: metrics,
+			details: []time.Time{now.Add(-time.Hour), now},
+			want:    true,
+		},
+		{
+			desc:    "newer details",
+			metrics: metrics,
+			details: []time.Time{now.Add(-time.Hour), now.Add(time.Second)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := metricsUpToDate(test.metrics, test.details); got != test.want {
+				t.Errorf("metricsUpToDate() returned %t, want %t", got, test.want)
+			}
+		})
+	}
+}
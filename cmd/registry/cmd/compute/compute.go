@@ -27,9 +27,11 @@ func Command(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.AddCommand(conformanceCommand(ctx))
+	cmd.AddCommand(changeMetricsCommand(ctx))
 	cmd.AddCommand(complexityCommand(ctx))
 	cmd.AddCommand(descriptorCommand(ctx))
 	cmd.AddCommand(detailsCommand(ctx))
+	cmd.AddCommand(diffsCommand(ctx))
 	cmd.AddCommand(indexCommand(ctx))
 	cmd.AddCommand(lintCommand(ctx))
 	cmd.AddCommand(lintStatsCommand(ctx))
@@ -59,11 +59,8 @@ func conformanceCommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatalf("The provided argument %s does not match the regex of a spec", name)
 			}
 
-			artifact, err := names.ParseArtifact(fmt.Sprintf("projects/%s/locations/%s/artifacts/-", spec.ProjectID, names.Location))
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Invalid project")
-			}
-
+			// Style guides are stored in the location of the spec.
+			artifact := spec.Api().Location().Artifact("-")
 			err = core.ListArtifacts(ctx, client, artifact, filter, true, func(artifact *rpc.Artifact) {
 				// Only consider artifacts which have the styleguide mimetype.
 				messageType, err := core.MessageTypeForMimeType(artifact.GetMimeType())
@@ -0,0 +1,189 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	diffMessageType          = "google.cloud.apigeeregistry.applications.v1alpha1.Diff"
+	changeDetailsMessageType = "google.cloud.apigeeregistry.applications.v1alpha1.ChangeDetails"
+)
+
+// Artifacts of a spec are shared by its revisions, so the artifacts of a pair
+// of revisions are named with both revision IDs.
+func diffRelation(base, revision string) string {
+	return "diff-" + base + "-" + revision
+}
+
+func changeDetailsRelation(base, revision string) string {
+	return "change-details-" + base + "-" + revision
+}
+
+func diffsCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diffs",
+		Short: "Compute the changes between consecutive revisions of OpenAPI 3 specs",
+		Long: `Compute the changes between consecutive revisions of OpenAPI 3 specs.
+For each pair of revisions, a Diff artifact and a ChangeDetails artifact that
+classifies its changes are stored on the spec. Pairs that already have a
+ChangeDetails artifact are skipped.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			filter, err := cmd.Flags().GetString("filter")
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			spec, err := names.ParseSpec(args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse spec name")
+			}
+
+			// Initialize task queue.
+			taskQueue, wait := core.WorkerPool(ctx, 64)
+			defer wait()
+			// Generate tasks.
+			err = core.ListSpecs(ctx, client, spec, filter, func(spec *rpc.ApiSpec) {
+				if !mimetypes.Is(spec.GetMimeType(), mimetypes.OpenAPI, "3") {
+					log.Debugf(ctx, "Skipping %s, only OpenAPI 3 specs can be compared", spec.GetName())
+					return
+				}
+				taskQueue <- &computeDiffsTask{
+					client:   client,
+					specName: spec.GetName(),
+				}
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to list specs")
+			}
+		},
+	}
+}
+
+type computeDiffsTask struct {
+	client   connection.Client
+	specName string
+}
+
+func (task *computeDiffsTask) String() string {
+	return "compute diffs " + task.specName
+}
+
+func (task *computeDiffsTask) Run(ctx context.Context) error {
+	spec, err := names.ParseSpec(task.specName)
+	if err != nil {
+		return err
+	}
+
+	// Revisions are listed from the newest to the oldest.
+	var revisions []string
+	if err := core.ListSpecRevisions(ctx, task.client, spec, "", func(s *rpc.ApiSpec) {
+		revisions = append([]string{s.GetName()}, revisions...)
+	}); err != nil {
+		return err
+	}
+
+	computed := make(map[string]bool)
+	filter := fmt.Sprintf("mime_type == '%s'", core.MimeTypeForMessageType(changeDetailsMessageType))
+	if err := core.ListArtifacts(ctx, task.client, spec.Artifact("-"), filter, false, func(a *rpc.Artifact) {
+		if name, err := names.ParseArtifact(a.GetName()); err == nil {
+			computed[name.ArtifactID()] = true
+		}
+	}); err != nil {
+		return err
+	}
+
+	for _, pair := range uncomputedPairs(revisions, computed) {
+		if err := task.computeDiff(ctx, spec, pair[0], pair[1]); err != nil {
+			log.FromContext(ctx).WithError(err).Errorf("Failed to compare %s and %s", pair[0], pair[1])
+		}
+	}
+	return nil
+}
+
+func (task *computeDiffsTask) computeDiff(ctx context.Context, spec names.Spec, base, revision string) error {
+	baseName, err := names.ParseSpecRevision(base)
+	if err != nil {
+		return err
+	}
+	revisionName, err := names.ParseSpecRevision(revision)
+	if err != nil {
+		return err
+	}
+	log.Debugf(ctx, "Computing changes from %s to %s", base, revision)
+
+	diff, details, err := core.DiffSpecRevisions(ctx, task.client, base, revision)
+	if err != nil {
+		return err
+	}
+
+	// The diff is stored first, so that the details mark the pair as computed.
+	if err := setMessageArtifact(ctx, task.client,
+		spec.Artifact(diffRelation(baseName.RevisionID, revisionName.RevisionID)), diffMessageType, diff); err != nil {
+		return err
+	}
+	return setMessageArtifact(ctx, task.client,
+		spec.Artifact(changeDetailsRelation(baseName.RevisionID, revisionName.RevisionID)), changeDetailsMessageType, details)
+}
+
+// uncomputedPairs returns the pairs of consecutive revisions, listed from the
+// oldest to the newest, that don't have a ChangeDetails artifact.
+func uncomputedPairs(revisions []string, computed map[string]bool) [][2]string {
+	var pairs [][2]string
+	for i := 1; i < len(revisions); i++ {
+		base, err := names.ParseSpecRevision(revisions[i-1])
+		if err != nil {
+			continue
+		}
+		revision, err := names.ParseSpecRevision(revisions[i])
+		if err != nil {
+			continue
+		}
+		if computed[changeDetailsRelation(base.RevisionID, revision.RevisionID)] {
+			continue
+		}
+		pairs = append(pairs, [2]string{revisions[i-1], revisions[i]})
+	}
+	return pairs
+}
+
+func setMessageArtifact(ctx context.Context, client connection.Client, name names.Artifact, messageType string, message proto.Message) error {
+	contents, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return core.SetArtifact(ctx, client, &rpc.Artifact{
+		Name:     name.String(),
+		MimeType: core.MimeTypeForMessageType(messageType),
+		Contents: contents,
+	})
+}
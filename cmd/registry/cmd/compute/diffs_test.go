@@ -0,0 +1,65 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUncomputedPairs(t *testing.T) {
+	const spec = "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml"
+	revisions := []string{spec + "@aaaa1111", spec + "@bbbb2222", spec + "@cccc3333"}
+
+	tests := []struct {
+		desc      string
+		revisions []string
+		computed  map[string]bool
+		want      [][2]string
+	}{
+		{
+			desc:      "single revision",
+			revisions: revisions[:1],
+		},
+		{
+			desc:      "nothing computed",
+			revisions: revisions,
+			want: [][2]string{
+				{revisions[0], revisions[1]},
+				{revisions[1], revisions[2]},
+			},
+		},
+		{
+			desc:      "skips computed pairs",
+			revisions: revisions,
+			computed: map[string]bool{
+				changeDetailsRelation("aaaa1111", "bbbb2222"): true,
+			},
+			want: [][2]string{
+				{revisions[1], revisions[2]},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := uncomputedPairs(test.revisions, test.computed)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("uncomputedPairs() returned unexpected pairs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
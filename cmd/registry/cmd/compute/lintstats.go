@@ -170,13 +170,13 @@ func computeLintStatsProjects(ctx context.Context,
 	return core.ListProjects(ctx, adminClient, projectName, filter, func(project *rpc.Project) {
 		project_stats := &rpc.LintStats{}
 
-		if err := core.ListAPIs(ctx, client, projectName.Api(""), filter, func(api *rpc.Api) {
+		if err := core.ListAPIs(ctx, client, projectName.Location("-").Api(""), filter, func(api *rpc.Api) {
 			aggregateLintStats(ctx, client, api.GetName(), linter, project_stats)
 		}); err != nil {
 			return
 		}
-		// Store the aggregate stats on this project
-		_ = storeLintStatsArtifact(ctx, client, project.GetName()+"/locations/global", linter, project_stats)
+		// Store the aggregate stats of all locations on this project
+		_ = storeLintStatsArtifact(ctx, client, project.GetName()+"/locations/"+names.DefaultLocation, linter, project_stats)
 		log.Debug(ctx, project.GetName())
 	})
 }
@@ -0,0 +1,180 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the contexts used to connect to registries",
+		Long: `Manage the contexts used to connect to registries.
+
+Contexts are stored in a configuration file, by default
+~/.config/registry/config.yaml, or the file named by APG_REGISTRY_CONFIG.
+Clients use the current context, or the context named by the --context flag.
+The APG_REGISTRY_* environment variables override the settings of contexts.`,
+	}
+
+	cmd.AddCommand(setContextCommand(ctx))
+	cmd.AddCommand(useContextCommand(ctx))
+	cmd.AddCommand(viewCommand(ctx))
+	return cmd
+}
+
+func setContextCommand(ctx context.Context) *cobra.Command {
+	var c connection.Context
+	cmd := &cobra.Command{
+		Use:   "set-context NAME",
+		Short: "Create or update a context",
+		Long: `Create or update a context. Only the settings given as flags are changed.
+The first context that is created becomes the current context.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, config := readConfig(ctx)
+			name := args[0]
+
+			existing, ok := config.Contexts[name]
+			if !ok {
+				existing = &connection.Context{}
+			}
+			flags := cmd.Flags()
+			set := func(flag string, dst *string, src string) {
+				if flags.Changed(flag) {
+					*dst = src
+				}
+			}
+			set("address", &existing.Address, c.Address)
+			set("token", &existing.Token, c.Token)
+			set("token-command", &existing.TokenCommand, c.TokenCommand)
+			set("ca-file", &existing.CAFile, c.CAFile)
+			set("client-cert-file", &existing.ClientCertFile, c.ClientCertFile)
+			set("client-key-file", &existing.ClientKeyFile, c.ClientKeyFile)
+			set("server-name", &existing.ServerName, c.ServerName)
+			set("project", &existing.Project, c.Project)
+			set("location", &existing.Location, c.Location)
+			if flags.Changed("insecure") {
+				existing.Insecure = c.Insecure
+			}
+
+			if config.Contexts == nil {
+				config.Contexts = make(map[string]*connection.Context)
+			}
+			config.Contexts[name] = existing
+			if config.CurrentContext == "" {
+				config.CurrentContext = name
+			}
+			if err := config.Write(path); err != nil {
+				log.FromContext(ctx).WithError(err).Fatalf("Failed to write %s", path)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&c.Address, "address", "", "Address of the registry, e.g. localhost:8080")
+	cmd.Flags().BoolVar(&c.Insecure, "insecure", false, "Connect without TLS")
+	cmd.Flags().StringVar(&c.Token, "token", "", "Bearer token sent with each call")
+	cmd.Flags().StringVar(&c.TokenCommand, "token-command", "", "Shell command that prints a bearer token, e.g. \"gcloud auth print-access-token\"")
+	cmd.Flags().StringVar(&c.CAFile, "ca-file", "", "PEM-encoded certificates used to verify the server")
+	cmd.Flags().StringVar(&c.ClientCertFile, "client-cert-file", "", "PEM-encoded client certificate for mutual TLS")
+	cmd.Flags().StringVar(&c.ClientKeyFile, "client-key-file", "", "PEM-encoded private key of the client certificate")
+	cmd.Flags().StringVar(&c.ServerName, "server-name", "", "Server name used to verify the server certificate")
+	cmd.Flags().StringVar(&c.Project, "project", "", "Default project")
+	cmd.Flags().StringVar(&c.Location, "location", "", "Default location")
+	return cmd
+}
+
+func useContextCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use-context NAME",
+		Short: "Set the current context",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, config := readConfig(ctx)
+			name := args[0]
+			if _, ok := config.Contexts[name]; !ok {
+				log.Fatalf(ctx, "Context %q is not configured, create it with \"registry config set-context\"", name)
+			}
+			config.CurrentContext = name
+			if err := config.Write(path); err != nil {
+				log.FromContext(ctx).WithError(err).Fatalf("Failed to write %s", path)
+			}
+		},
+	}
+}
+
+func viewCommand(ctx context.Context) *cobra.Command {
+	var raw bool
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Print the configuration file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_, config := readConfig(ctx)
+			if err := writeConfig(cmd.OutOrStdout(), config, raw); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print configuration")
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print tokens instead of redacting them")
+	return cmd
+}
+
+func readConfig(ctx context.Context) (string, *connection.Config) {
+	path, err := connection.ConfigPath()
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to find configuration file")
+	}
+	config, err := connection.ReadConfig(path)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to read configuration file")
+	}
+	return path, config
+}
+
+// writeConfig writes a configuration as YAML. Unless raw is set, tokens are
+// redacted so that the output can be shared.
+func writeConfig(w io.Writer, config *connection.Config, raw bool) error {
+	if !raw {
+		redacted := &connection.Config{
+			CurrentContext: config.CurrentContext,
+			Contexts:       make(map[string]*connection.Context, len(config.Contexts)),
+		}
+		for name, c := range config.Contexts {
+			copy := *c
+			if copy.Token != "" {
+				copy.Token = "REDACTED"
+			}
+			redacted.Contexts[name] = &copy
+		}
+		config = redacted
+	}
+
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, string(b))
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfig(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	old, ok := os.LookupEnv("APG_REGISTRY_CONFIG")
+	os.Setenv("APG_REGISTRY_CONFIG", path)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv("APG_REGISTRY_CONFIG", old)
+		} else {
+			os.Unsetenv("APG_REGISTRY_CONFIG")
+		}
+	})
+
+	for _, args := range [][]string{
+		{"set-context", "local", "--address", "localhost:8080", "--insecure", "--project", "sandbox"},
+		{"set-context", "prod", "--address", "registry.example.com:443", "--token", "secret"},
+		{"set-context", "local", "--location", "us-central1"},
+		{"use-context", "prod"},
+	} {
+		cmd := Command(ctx)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute(%v) returned error: %s", args, err)
+		}
+	}
+
+	cmd := Command(ctx)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"view"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute(view) returned error: %s", err)
+	}
+
+	want := `current-context: prod
+contexts:
+    local:
+        address: localhost:8080
+        insecure: true
+        project: sandbox
+        location: us-central1
+    prod:
+        address: registry.example.com:443
+        token: REDACTED
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("view printed unexpected configuration (-want +got):\n%s", diff)
+	}
+}
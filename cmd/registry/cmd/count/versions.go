@@ -17,6 +17,8 @@ package count
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
@@ -29,42 +31,100 @@ import (
 
 func versionsCommand(ctx context.Context) *cobra.Command {
 	var filter string
+	var output string
+	var columns []string
 	cmd := &cobra.Command{
 		Use:   "versions",
 		Short: "Count the number of versions of specified APIs",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			var printer *core.Printer
+			if output != "" {
+				var err error
+				printer, err = core.NewPrinter(cmd.OutOrStdout(), output, columns)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+				}
+				printer.List = true
+			}
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 			// Initialize task queue.
 			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
 			// Generate tasks.
+			counts := &versionCounts{counts: make(map[string]int)}
 			name := args[0]
-			if api, err := names.ParseApi(name); err == nil {
-				// Iterate through a collection of APIs and count the number of versions of each.
-				err = core.ListAPIs(ctx, client, api, filter, func(api *rpc.Api) {
-					taskQueue <- &countVersionsTask{
-						client:  client,
-						apiName: api.Name,
-					}
-				})
-				if err != nil {
-					log.FromContext(ctx).WithError(err).Fatal("Failed to list APIs")
+			api, err := names.ParseApi(name)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatalf("Unsupported argument %q, must be an API name", name)
+			}
+			// Iterate through a collection of APIs and count the number of versions of each.
+			err = core.ListAPIs(ctx, client, api, filter, func(api *rpc.Api) {
+				taskQueue <- &countVersionsTask{
+					client:  client,
+					apiName: api.Name,
+					counts:  counts,
+				}
+			})
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to list APIs")
+			}
+			wait()
+
+			if printer == nil {
+				return
+			}
+			for _, row := range counts.rows() {
+				if err := printer.Print(row); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print counts")
 				}
 			}
+			if err := printer.Flush(); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print counts")
+			}
 		},
 	}
 
 	cmd.Flags().StringVar(&filter, "filter", "", "Filter selected resources")
+	cmd.Flags().StringVarP(&output, "output", "o", "", core.OutputUsage)
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, core.ColumnsUsage)
 	return cmd
 }
 
+// versionCounts collects the counts computed by concurrent tasks.
+type versionCounts struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func (c *versionCounts) set(api string, count int) {
+	c.Lock()
+	defer c.Unlock()
+	c.counts[api] = count
+}
+
+// rows returns the counts ordered by API name.
+func (c *versionCounts) rows() []map[string]interface{} {
+	c.Lock()
+	defer c.Unlock()
+	apis := make([]string, 0, len(c.counts))
+	for api := range c.counts {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	rows := make([]map[string]interface{}, len(apis))
+	for i, api := range apis {
+		rows[i] = map[string]interface{}{"name": api, "versions": c.counts[api]}
+	}
+	return rows
+}
+
 type countVersionsTask struct {
 	client  connection.Client
 	apiName string
+	counts  *versionCounts
 }
 
 func (task *countVersionsTask) String() string {
@@ -88,6 +148,7 @@ func (task *countVersionsTask) Run(ctx context.Context) error {
 		}
 	}
 	log.Debugf(ctx, "%d\t%s", count, task.apiName)
+	task.counts.set(task.apiName, count)
 	subject := task.apiName
 	relation := "versionCount"
 	artifact := &rpc.Artifact{
@@ -0,0 +1,204 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	var output string
+	var artifactID string
+	cmd := &cobra.Command{
+		Use:   "diff SPEC[@REVISION] [SPEC@REVISION]",
+		Short: "Compare two revisions of an OpenAPI 3 spec",
+		Long: `Compare two revisions of an OpenAPI 3 spec and print the changes, grouped
+as breaking, non-breaking, and unknown changes. Given only a spec, its
+latest revision is compared with the previous one.
+
+The command exits with status 1 if there are breaking changes.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var printer *core.Printer
+			if output != "" {
+				if output != "json" && output != "yaml" {
+					log.Fatalf(ctx, "Unsupported output format %q, must be json or yaml", output)
+				}
+				var err error
+				if printer, err = core.NewPrinter(cmd.OutOrStdout(), output, nil); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid output format")
+				}
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			base, revision := "", ""
+			if len(args) == 2 {
+				base, revision = args[0], args[1]
+			} else if base, revision, err = latestRevisions(ctx, client, args[0]); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to find revisions to compare")
+			}
+
+			_, details, err := core.DiffSpecRevisions(ctx, client, base, revision)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatalf("Failed to compare %s and %s", base, revision)
+			}
+
+			if artifactID != "" {
+				if err := saveChanges(ctx, client, revision, artifactID, details); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to save changes")
+				}
+			}
+
+			if printer == nil {
+				if err := writeChanges(cmd.OutOrStdout(), details); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print changes")
+				}
+			} else {
+				if err := printer.Print(details); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print changes")
+				}
+				if err := printer.Flush(); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print changes")
+				}
+			}
+
+			// Breaking changes fail the command so that it can gate CI pipelines.
+			if !isEmpty(details.GetBreakingChanges()) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: json or yaml, prints the ChangeDetails message")
+	cmd.Flags().StringVar(&artifactID, "output-artifact", "", "ID of an artifact of the later revision to save the changes in")
+	return cmd
+}
+
+// latestRevisions returns the names of the previous and latest revisions of a spec.
+func latestRevisions(ctx context.Context, client connection.Client, name string) (string, string, error) {
+	spec, err := names.ParseSpec(name)
+	if err != nil {
+		return "", "", fmt.Errorf("%q is not a spec, give a spec or two spec revisions", name)
+	}
+
+	// Revisions are listed from the newest to the oldest.
+	var revisions []string
+	if err := core.ListSpecRevisions(ctx, client, spec, "", func(s *rpc.ApiSpec) {
+		if len(revisions) < 2 {
+			revisions = append(revisions, s.GetName())
+		}
+	}); err != nil {
+		return "", "", err
+	}
+	if len(revisions) < 2 {
+		return "", "", fmt.Errorf("%s has only one revision", name)
+	}
+	return revisions[1], revisions[0], nil
+}
+
+func saveChanges(ctx context.Context, client connection.Client, revision, artifactID string, details *rpc.ChangeDetails) error {
+	name, err := names.ParseSpecRevision(revision)
+	if err != nil {
+		return err
+	}
+	contents, err := proto.Marshal(details)
+	if err != nil {
+		return err
+	}
+	// Artifacts of a spec are shared by its revisions.
+	return core.SetArtifact(ctx, client, &rpc.Artifact{
+		Name:     name.Spec().Artifact(artifactID).String(),
+		MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.applications.v1alpha1.ChangeDetails"),
+		Contents: contents,
+	})
+}
+
+// writeChanges writes a readable summary of changes.
+func writeChanges(w io.Writer, details *rpc.ChangeDetails) error {
+	sections := []struct {
+		title string
+		diff  *rpc.Diff
+	}{
+		{"Breaking changes", details.GetBreakingChanges()},
+		{"Non-breaking changes", details.GetNonBreakingChanges()},
+		{"Unknown changes", details.GetUnknownChanges()},
+	}
+
+	for _, s := range sections {
+		if isEmpty(s.diff) {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s:\n", s.title); err != nil {
+			return err
+		}
+		for _, line := range changeLines(s.diff) {
+			if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	if isEmpty(details.GetBreakingChanges()) && isEmpty(details.GetNonBreakingChanges()) && isEmpty(details.GetUnknownChanges()) {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+	return nil
+}
+
+func changeLines(diff *rpc.Diff) []string {
+	var lines []string
+	for _, a := range sorted(diff.GetAdditions()) {
+		lines = append(lines, "added: "+a)
+	}
+	for _, d := range sorted(diff.GetDeletions()) {
+		lines = append(lines, "deleted: "+d)
+	}
+	modified := make([]string, 0, len(diff.GetModifications()))
+	for path := range diff.GetModifications() {
+		modified = append(modified, path)
+	}
+	sort.Strings(modified)
+	for _, path := range modified {
+		change := diff.GetModifications()[path]
+		lines = append(lines, fmt.Sprintf("modified: %s: %s -> %s", path, change.GetFrom(), change.GetTo()))
+	}
+	return lines
+}
+
+func sorted(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
+
+func isEmpty(diff *rpc.Diff) bool {
+	return len(diff.GetAdditions()) == 0 && len(diff.GetDeletions()) == 0 && len(diff.GetModifications()) == 0
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteChanges(t *testing.T) {
+	tests := []struct {
+		desc    string
+		details *rpc.ChangeDetails
+		want    string
+	}{
+		{
+			desc:    "no changes",
+			details: &rpc.ChangeDetails{},
+			want:    "No changes.\n",
+		},
+		{
+			desc: "grouped changes",
+			details: &rpc.ChangeDetails{
+				BreakingChanges: &rpc.Diff{
+					Deletions: []string{"paths./pets.post", "paths./pets.get"},
+					Modifications: map[string]*rpc.Diff_ValueChange{
+						"paths./pets.get.parameters.limit.required": {From: "false", To: "true"},
+					},
+				},
+				NonBreakingChanges: &rpc.Diff{
+					Additions: []string{"info.description"},
+				},
+				UnknownChanges: &rpc.Diff{},
+			},
+			want: `Breaking changes:
+  deleted: paths./pets.get
+  deleted: paths./pets.post
+  modified: paths./pets.get.parameters.limit.required: false -> true
+Non-breaking changes:
+  added: info.description
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeChanges(&out, test.details); err != nil {
+				t.Fatalf("writeChanges() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, out.String()); diff != "" {
+				t.Errorf("writeChanges() printed unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/archive"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+)
+
+func archiveCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive PROJECT",
+		Short: "Export a project to an archive",
+		Long: `Export a project to a gzipped tar file that is written to standard output.
+The archive holds the project, its APIs, versions, specs with all of their
+revisions and tags, deployments with their revisions, artifacts with their
+revisions, artifact types, and relationships. Its layout is described in cmd/registry/archive/README.md. Use "registry import
+archive" to recreate the project.`,
+		Example: "registry export archive my-project > my-project.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if !strings.HasPrefix(name, "projects/") {
+				name = "projects/" + name
+			}
+			project, err := names.ParseProject(name)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse project name")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			w, err := archive.NewWriter(cmd.OutOrStdout(), project.String())
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to write archive")
+			}
+			e := &exporter{client: client, adminClient: adminClient, project: project, w: w}
+			if err := e.exportProject(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to export project")
+			}
+			if err := w.Close(); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to write archive")
+			}
+		},
+	}
+}
+
+// exporter writes the resources of a project to an archive in the order in
+// which they can be recreated.
+type exporter struct {
+	client      connection.Client
+	adminClient connection.AdminClient
+	project     names.Project
+	w           *archive.Writer
+}
+
+// path returns the path of a resource in the archive.
+func (e *exporter) path(name string) string {
+	return strings.TrimPrefix(name, e.project.String()+"/")
+}
+
+func (e *exporter) exportProject(ctx context.Context) error {
+	project, err := e.adminClient.GetProject(ctx, &rpc.GetProjectRequest{Name: e.project.String()})
+	if err != nil {
+		return err
+	}
+	if err := e.w.Write("", project, nil); err != nil {
+		return err
+	}
+
+	// Artifact types are written first, because they validate artifacts.
+	locations := e.project.Location("-")
+	if err := e.exportArtifactTypes(ctx, locations.String()); err != nil {
+		return err
+	}
+	if err := e.exportArtifacts(ctx, locations.String()); err != nil {
+		return err
+	}
+	it := e.client.ListApis(ctx, &rpc.ListApisRequest{Parent: locations.String()})
+	for {
+		api, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := e.exportApi(ctx, api); err != nil {
+			return err
+		}
+	}
+
+	// Relationships are written last, because they can refer to any API.
+	return e.exportRelationships(ctx, locations.Api("-").String())
+}
+
+func (e *exporter) exportArtifactTypes(ctx context.Context, parent string) error {
+	it := e.client.ListArtifactTypes(ctx, &rpc.ListArtifactTypesRequest{Parent: parent})
+	for {
+		artifactType, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := e.w.Write(e.path(artifactType.GetName()), artifactType, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *exporter) exportRelationships(ctx context.Context, parent string) error {
+	it := e.client.ListRelationships(ctx, &rpc.ListRelationshipsRequest{Parent: parent})
+	for {
+		relationship, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := e.w.Write(e.path(relationship.GetName()), relationship, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *exporter) exportApi(ctx context.Context, api *rpc.Api) error {
+	if err := e.w.Write(e.path(api.GetName()), api, nil); err != nil {
+		return err
+	}
+
+	versions := e.client.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{Parent: api.GetName()})
+	for {
+		version, err := versions.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := e.exportVersion(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	deployments := e.client.ListApiDeployments(ctx, &rpc.ListApiDeploymentsRequest{Parent: api.GetName()})
+	for {
+		deployment, err := deployments.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := e.exportDeployment(ctx, deployment); err != nil {
+			return err
+		}
+	}
+
+	return e.exportArtifacts(ctx, api.GetName())
+}
+
+func (e *exporter) exportVersion(ctx context.Context, version *rpc.ApiVersion) error {
+	if err := e.w.Write(e.path(version.GetName()), version, nil); err != nil {
+		return err
+	}
+
+	specs := e.client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: version.GetName()})
+	for {
+		spec, err := specs.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := e.exportSpec(ctx, spec); err != nil {
+			return err
+		}
+	}
+
+	return e.exportArtifacts(ctx, version.GetName())
+}
+
+func (e *exporter) exportSpec(ctx context.Context, spec *rpc.ApiSpec) error {
+	// Revisions are listed from the newest.
+	var revisions []*rpc.ApiSpec
+	it := e.client.ListApiSpecRevisions(ctx, &rpc.ListApiSpecRevisionsRequest{Name: spec.GetName()})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		revisions = append([]*rpc.ApiSpec{r}, revisions...)
+	}
+
+	for i, r := range revisions {
+		// Contents are returned uncompressed.
+		contents, err := e.client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: r.GetName()})
+		if err != nil {
+			return err
+		}
+		data := contents.GetData()
+		if data == nil {
+			data = []byte{}
+		}
+		path := archive.RevisionPath(e.path(spec.GetName()), i+1, r.GetRevisionId())
+		if err := e.w.Write(path, r, data); err != nil {
+			return err
+		}
+	}
+
+	return e.exportArtifacts(ctx, spec.GetName())
+}
+
+func (e *exporter) exportDeployment(ctx context.Context, deployment *rpc.ApiDeployment) error {
+	// Revisions are listed from the newest.
+	var revisions []*rpc.ApiDeployment
+	it := e.client.ListApiDeploymentRevisions(ctx, &rpc.ListApiDeploymentRevisionsRequest{Name: deployment.GetName()})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		revisions = append([]*rpc.ApiDeployment{r}, revisions...)
+	}
+
+	for i, r := range revisions {
+		path := archive.RevisionPath(e.path(deployment.GetName()), i+1, r.GetRevisionId())
+		if err := e.w.Write(path, r, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportArtifacts(ctx context.Context, parent string) error {
+	it := e.client.ListArtifacts(ctx, &rpc.ListArtifactsRequest{Parent: parent})
+	for {
+		artifact, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := e.exportArtifact(ctx, artifact); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *exporter) exportArtifact(ctx context.Context, artifact *rpc.Artifact) error {
+	// Revisions are listed from the newest.
+	var revisions []*rpc.Artifact
+	it := e.client.ListArtifactRevisions(ctx, &rpc.ListArtifactRevisionsRequest{Name: artifact.GetName()})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		revisions = append([]*rpc.Artifact{r}, revisions...)
+	}
+
+	for i, r := range revisions {
+		contents, err := e.client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: r.GetName()})
+		if err != nil {
+			return err
+		}
+		// Contents are archived uncompressed, like the contents that are hashed.
+		data := contents.GetData()
+		if mimetypes.IsCompressed(r.GetMimeType(), mimetypes.GZip) && len(data) > 0 {
+			if data, err = core.GUnzippedBytes(data); err != nil {
+				return err
+			}
+		}
+		if data == nil {
+			data = []byte{}
+		}
+		path := archive.RevisionPath(e.path(artifact.GetName()), i+1, r.GetRevisionId())
+		if err := e.w.Write(path, r, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
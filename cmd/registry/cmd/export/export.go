@@ -26,7 +26,9 @@ func Command(ctx context.Context) *cobra.Command {
 		Short: "Export resources from the API Registry",
 	}
 
+	cmd.AddCommand(archiveCommand(ctx))
 	cmd.AddCommand(csvCommand(ctx))
+	cmd.AddCommand(filesCommand(ctx))
 	cmd.AddCommand(sheetCommand(ctx))
 	cmd.AddCommand(yamlCommand(ctx))
 
@@ -0,0 +1,284 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/cmd/upload/bulk"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// filesIndexFile is the name of the index that is written at the top of the
+// output directory.
+const filesIndexFile = "index.yaml"
+
+// filesIndex lists the spec revisions that were written and their files.
+type filesIndex struct {
+	Specs []filesIndexEntry `yaml:"specs"`
+}
+
+type filesIndexEntry struct {
+	Name         string   `yaml:"name"` // name of the spec revision
+	MimeType     string   `yaml:"mime_type"`
+	Hash         string   `yaml:"hash,omitempty"`
+	RevisionTags []string `yaml:"revision_tags,omitempty"`
+	Files        []string `yaml:"files"` // relative to the output directory
+}
+
+func filesCommand(ctx context.Context) *cobra.Command {
+	var (
+		dir       string
+		filter    string
+		revisions bool
+	)
+	cmd := &cobra.Command{
+		Use:   "files PARENT --dir DIRECTORY",
+		Short: "Export spec contents to a directory tree",
+		Long: `Export the contents of specs to a directory tree that "registry upload bulk"
+can read back. The latest revision of each spec under PARENT, which can be
+a project, location, API, version, or spec, is written to
+DIRECTORY/API/VERSION/SPEC, so specs named like openapi.yaml are read back
+by "upload bulk openapi". Contents are uncompressed. Specs that are zip
+archives, like Protocol Buffer specs, are unzipped at the top of DIRECTORY
+if "upload bulk protos" would read them back with the same name, and into
+DIRECTORY/API/VERSION/SPEC/ otherwise.
+
+A project is exported from its global location; other locations are
+exported by naming them. With --revisions, every earlier revision is also
+written to DIRECTORY/.revisions/API/VERSION/SPEC/REVISION/, in the same
+layout, which bulk uploads skip. The files that are written for each
+revision are listed in DIRECTORY/index.yaml.`,
+		Example: "registry export files projects/my-project/locations/global/apis/petstore --dir out",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			pattern, err := specPattern(args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse parent")
+			}
+			if dir == "" {
+				log.Fatal(ctx, "An output directory is required, set --dir")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			index := &filesIndex{Specs: make([]filesIndexEntry, 0)}
+			var exportErr error
+			err = core.ListSpecs(ctx, client, pattern, filter, func(spec *rpc.ApiSpec) {
+				if exportErr != nil {
+					return
+				}
+				entries, err := exportSpecFiles(ctx, client, spec, dir, revisions)
+				if err != nil {
+					exportErr = fmt.Errorf("failed to export %s: %s", spec.GetName(), err)
+					return
+				}
+				index.Specs = append(index.Specs, entries...)
+			})
+			if err == nil {
+				err = exportErr
+			}
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to export specs")
+			}
+
+			b, err := yaml.Marshal(index)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to marshal index")
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, filesIndexFile), b, 0644); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to write index")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to write files to")
+	cmd.Flags().StringVar(&filter, "filter", "", "Filter selected specs")
+	cmd.Flags().BoolVar(&revisions, "revisions", false, "Also write every earlier revision to a hidden .revisions directory")
+	return cmd
+}
+
+// specPattern returns the pattern of the specs under a parent. Patterns are
+// limited to one location, because its specs are written to the same tree.
+func specPattern(parent string) (names.Spec, error) {
+	var pattern names.Spec
+	if project, err := names.ParseProject(parent); err == nil {
+		pattern = project.Location(names.DefaultLocation).Api("-").Version("-").Spec("-")
+	} else if location, err := names.ParseLocation(parent); err == nil {
+		pattern = location.Api("-").Version("-").Spec("-")
+	} else if api, err := names.ParseApi(parent); err == nil {
+		pattern = api.Version("-").Spec("-")
+	} else if version, err := names.ParseVersion(parent); err == nil {
+		pattern = version.Spec("-")
+	} else if spec, err := names.ParseSpec(parent); err == nil {
+		pattern = spec
+	} else {
+		return names.Spec{}, fmt.Errorf("unsupported parent %q, must be a project, location, API, version, or spec", parent)
+	}
+
+	if pattern.LocationID == "-" {
+		return names.Spec{}, fmt.Errorf("unsupported parent %q, must be in a single location", parent)
+	}
+	return pattern, nil
+}
+
+// revisionsDir is the directory of earlier spec revisions. Bulk uploads skip
+// it because it is hidden.
+const revisionsDir = ".revisions"
+
+// exportSpecFiles writes the latest revision of a spec and, if revisions is
+// set, its earlier revisions. It returns the index entries of the revisions.
+func exportSpecFiles(ctx context.Context, client connection.Client, spec *rpc.ApiSpec, dir string, revisions bool) ([]filesIndexEntry, error) {
+	name, err := names.ParseSpec(spec.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := core.GetBytesForSpec(ctx, client, spec)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := writeSpecFiles(dir, "", spec, contents)
+	if err != nil {
+		return nil, err
+	}
+	entries := []filesIndexEntry{entry}
+	if !revisions {
+		return entries, nil
+	}
+
+	var writeErr error
+	err = core.ListSpecRevisions(ctx, client, name, "", func(revision *rpc.ApiSpec) {
+		if writeErr != nil || revision.GetRevisionId() == spec.GetRevisionId() {
+			return
+		}
+		contents, err := core.GetBytesForSpec(ctx, client, revision)
+		if err != nil {
+			writeErr = err
+			return
+		}
+		root := path.Join(revisionsDir, name.ApiID, name.VersionID, name.SpecID, revision.GetRevisionId())
+		entry, err := writeSpecFiles(dir, root, revision, contents)
+		if err != nil {
+			writeErr = err
+			return
+		}
+		entries = append(entries, entry)
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// writeSpecFiles writes the uncompressed contents of a spec revision to the
+// tree at root, a subdirectory of dir, and returns its index entry.
+func writeSpecFiles(dir, root string, spec *rpc.ApiSpec, contents []byte) (filesIndexEntry, error) {
+	// Specs are listed without revision IDs and revisions with them.
+	name, err := names.ParseSpec(strings.SplitN(spec.GetName(), "@", 2)[0])
+	if err != nil {
+		return filesIndexEntry{}, err
+	}
+	entry := filesIndexEntry{
+		Name:         name.String() + "@" + spec.GetRevisionId(),
+		MimeType:     spec.GetMimeType(),
+		Hash:         spec.GetHash(),
+		RevisionTags: spec.GetRevisionTags(),
+		Files:        make([]string, 0),
+	}
+	specDir := path.Join(root, name.ApiID, name.VersionID)
+
+	if core.IsZipArchive(spec.GetMimeType()) {
+		subdir := path.Join(specDir, name.SpecID)
+		if protoArchive(name, contents) {
+			subdir = root
+		}
+		out := filepath.Join(dir, filepath.FromSlash(subdir))
+		paths, err := core.UnzipArchiveToPath(contents, out)
+		if err != nil {
+			return entry, err
+		}
+		for _, p := range paths {
+			if info, err := os.Stat(p); err != nil || info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return entry, err
+			}
+			entry.Files = append(entry.Files, filepath.ToSlash(rel))
+		}
+		sort.Strings(entry.Files)
+		return entry, nil
+	}
+
+	// Files are named by their spec IDs, which bulk uploads read back.
+	out := filepath.Join(dir, filepath.FromSlash(specDir))
+	if err := os.MkdirAll(out, os.ModePerm); err != nil {
+		return entry, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(out, name.SpecID), contents, 0644); err != nil {
+		return entry, err
+	}
+	entry.Files = append(entry.Files, path.Join(specDir, name.SpecID))
+	return entry, nil
+}
+
+// protoArchive returns true if the files of a zip archive are in a directory
+// that "upload bulk protos" reads as the API, version, and spec of name.
+func protoArchive(name names.Spec, contents []byte) bool {
+	r, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil || len(r.File) == 0 {
+		return false
+	}
+
+	var common []string
+	for i, f := range r.File {
+		parts := strings.Split(path.Dir(f.Name), "/")
+		if i == 0 {
+			common = parts
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+
+	apiID, versionID, specID, err := bulk.ProtoIDs(strings.Join(common, "/"))
+	return err == nil && apiID == name.ApiID && versionID == name.VersionID && specID == name.SpecID
+}
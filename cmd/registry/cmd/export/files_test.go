@@ -0,0 +1,352 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/cmd/upload/bulk"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSpecPattern(t *testing.T) {
+	tests := []struct {
+		parent string
+		want   string
+	}{
+		{"projects/p", "projects/p/locations/global/apis/-/versions/-/specs/-"},
+		{"projects/p/locations/l", "projects/p/locations/l/apis/-/versions/-/specs/-"},
+		{"projects/p/locations/global/apis/a", "projects/p/locations/global/apis/a/versions/-/specs/-"},
+		{"projects/p/locations/global/apis/a/versions/v", "projects/p/locations/global/apis/a/versions/v/specs/-"},
+		{"projects/p/locations/global/apis/a/versions/v/specs/s", "projects/p/locations/global/apis/a/versions/v/specs/s"},
+	}
+	for _, test := range tests {
+		got, err := specPattern(test.parent)
+		if err != nil {
+			t.Errorf("specPattern(%q) returned error: %s", test.parent, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("specPattern(%q) returned %q, want %q", test.parent, got, test.want)
+		}
+	}
+
+	for _, parent := range []string{
+		"projects/p/locations/global/apis/a/artifacts/x",
+		"projects/p/locations/-",
+		"projects/p/locations/-/apis/a",
+	} {
+		if _, err := specPattern(parent); err == nil {
+			t.Errorf("specPattern(%q) succeeded, want error", parent)
+		}
+	}
+}
+
+func TestWriteSpecFiles(t *testing.T) {
+	archive := func(names ...string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, name := range names {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatalf("Create(%q) returned error: %s", name, err)
+			}
+			if _, err := w.Write([]byte("syntax = \"proto3\";")); err != nil {
+				t.Fatalf("Write(%q) returned error: %s", name, err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("Close() returned error: %s", err)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		desc     string
+		root     string
+		spec     *rpc.ApiSpec
+		contents []byte
+		want     filesIndexEntry
+	}{
+		{
+			desc: "file",
+			spec: &rpc.ApiSpec{
+				Name:       "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml",
+				Filename:   "petstore.yaml",
+				MimeType:   "application/x.openapi+gzip;version=3",
+				RevisionId: "1111aaaa",
+				Hash:       "abc",
+			},
+			contents: []byte("openapi: 3.0.0"),
+			want: filesIndexEntry{
+				Name:     "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml@1111aaaa",
+				MimeType: "application/x.openapi+gzip;version=3",
+				Hash:     "abc",
+				Files:    []string{"a/v/openapi.yaml"},
+			},
+		},
+		{
+			desc: "revision",
+			root: ".revisions/a/v/openapi.yaml/2222bbbb",
+			spec: &rpc.ApiSpec{
+				Name:         "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml@2222bbbb",
+				Filename:     "../../escape.yaml",
+				MimeType:     "application/x.openapi;version=3",
+				RevisionId:   "2222bbbb",
+				RevisionTags: []string{"prod"},
+			},
+			contents: []byte("openapi: 3.0.1"),
+			want: filesIndexEntry{
+				Name:         "projects/p/locations/global/apis/a/versions/v/specs/openapi.yaml@2222bbbb",
+				MimeType:     "application/x.openapi;version=3",
+				RevisionTags: []string{"prod"},
+				Files:        []string{".revisions/a/v/openapi.yaml/2222bbbb/a/v/openapi.yaml"},
+			},
+		},
+		{
+			desc: "archive of a bulk upload",
+			spec: &rpc.ApiSpec{
+				Name:       "projects/p/locations/global/apis/google-example/versions/v1/specs/protos.zip",
+				Filename:   "protos.zip",
+				MimeType:   "application/x.protobuf+zip",
+				RevisionId: "3333cccc",
+			},
+			contents: archive("google/example/v1/a.proto", "google/example/v1/b.proto"),
+			want: filesIndexEntry{
+				Name:     "projects/p/locations/global/apis/google-example/versions/v1/specs/protos.zip@3333cccc",
+				MimeType: "application/x.protobuf+zip",
+				Files: []string{
+					"google/example/v1/a.proto",
+					"google/example/v1/b.proto",
+				},
+			},
+		},
+		{
+			desc: "other archive",
+			spec: &rpc.ApiSpec{
+				Name:       "projects/p/locations/global/apis/example/versions/v1/specs/protos",
+				Filename:   "protos.zip",
+				MimeType:   "application/x.protobuf+zip",
+				RevisionId: "4444dddd",
+			},
+			contents: archive("google/example/v1/a.proto", "google/example/v1/b.proto"),
+			want: filesIndexEntry{
+				Name:     "projects/p/locations/global/apis/example/versions/v1/specs/protos@4444dddd",
+				MimeType: "application/x.protobuf+zip",
+				Files: []string{
+					"example/v1/protos/google/example/v1/a.proto",
+					"example/v1/protos/google/example/v1/b.proto",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			got, err := writeSpecFiles(dir, test.root, test.spec, test.contents)
+			if err != nil {
+				t.Fatalf("writeSpecFiles() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("writeSpecFiles() returned unexpected entry (-want +got):\n%s", diff)
+			}
+			for _, f := range got.Files {
+				if _, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+					t.Errorf("writeSpecFiles() didn't write %s: %s", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExportFilesRoundTrip(t *testing.T) {
+	const (
+		source = "export-files-source"
+		target = "export-files-target"
+	)
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	for _, project := range []string{source, target} {
+		err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+		if err != nil && status.Code(err) != codes.NotFound {
+			t.Fatalf("Setup: Failed to delete test project: %s", err)
+		}
+		if _, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+			ProjectId: project,
+			Project:   &rpc.Project{},
+		}); err != nil {
+			t.Fatalf("Setup: Failed to create test project: %s", err)
+		}
+		defer func(project string) {
+			_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+		}(project)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"google/example/v1/a.proto", "google/example/v1/b.proto"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Setup: Failed to create %s: %s", name, err)
+		}
+		if _, err := w.Write([]byte("syntax = \"proto3\"; // " + name)); err != nil {
+			t.Fatalf("Setup: Failed to write %s: %s", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Setup: Failed to close archive: %s", err)
+	}
+
+	// The OpenAPI spec has an earlier revision, which isn't read back.
+	seed := []struct {
+		parent   string
+		specID   string
+		mimeType string
+		contents [][]byte
+	}{
+		{
+			parent:   "projects/" + source + "/locations/global/apis/petstore/versions/v1",
+			specID:   "openapi.yaml",
+			mimeType: "application/x.openapi;version=3",
+			contents: [][]byte{[]byte("openapi: 3.0.0\n"), []byte("openapi: 3.0.1\n")},
+		},
+		{
+			parent:   "projects/" + source + "/locations/global/apis/google-example/versions/v1",
+			specID:   "protos.zip",
+			mimeType: "application/x.protobuf+zip",
+			contents: [][]byte{buf.Bytes()},
+		},
+	}
+	for _, s := range seed {
+		api := strings.SplitN(s.parent, "/versions/", 2)[0]
+		if _, err := client.UpdateApi(ctx, &rpc.UpdateApiRequest{
+			Api:          &rpc.Api{Name: api},
+			AllowMissing: true,
+		}); err != nil {
+			t.Fatalf("Setup: Failed to update %s: %s", api, err)
+		}
+		if _, err := client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+			ApiVersion:   &rpc.ApiVersion{Name: s.parent},
+			AllowMissing: true,
+		}); err != nil {
+			t.Fatalf("Setup: Failed to update %s: %s", s.parent, err)
+		}
+		name := s.parent + "/specs/" + s.specID
+		for _, contents := range s.contents {
+			if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+				ApiSpec: &rpc.ApiSpec{
+					Name:     name,
+					MimeType: s.mimeType,
+					Contents: contents,
+				},
+				AllowMissing: true,
+			}); err != nil {
+				t.Fatalf("Setup: Failed to update %s: %s", name, err)
+			}
+		}
+	}
+
+	roundTrip := func(api, style string) {
+		dir := t.TempDir()
+		export := Command(ctx)
+		export.SetArgs([]string{"files", "projects/" + source + "/locations/global/apis/" + api, "--dir", dir, "--revisions"})
+		if err := export.Execute(); err != nil {
+			t.Fatalf("Export of %s returned error: %s", api, err)
+		}
+		upload := bulk.Command(ctx)
+		upload.SetOut(ioutil.Discard)
+		upload.SetArgs([]string{style, dir, "--project-id", target})
+		if err := upload.Execute(); err != nil {
+			t.Fatalf("Upload of %s returned error: %s", api, err)
+		}
+	}
+	roundTrip("petstore", "openapi")
+	roundTrip("google-example", "protos")
+
+	files := func(spec *rpc.ApiSpec) map[string]string {
+		t.Helper()
+		contents, err := client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: spec.GetName()})
+		if err != nil {
+			t.Fatalf("Failed to get contents of %s: %s", spec.GetName(), err)
+		}
+		data := contents.GetData()
+		if spec.GetMimeType() != "application/x.protobuf+zip" {
+			return map[string]string{"": string(data)}
+		}
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("Failed to read archive of %s: %s", spec.GetName(), err)
+		}
+		m := make(map[string]string)
+		for _, f := range r.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("Failed to open %s: %s", f.Name, err)
+			}
+			b, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatalf("Failed to read %s: %s", f.Name, err)
+			}
+			m[f.Name] = string(b)
+		}
+		return m
+	}
+	specs := func(project string) map[string]map[string]string {
+		t.Helper()
+		m := make(map[string]map[string]string)
+		it := client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: "projects/" + project + "/locations/global/apis/-/versions/-"})
+		for {
+			spec, err := it.Next()
+			if err == iterator.Done {
+				break
+			} else if err != nil {
+				t.Fatalf("Failed to list specs: %s", err)
+			}
+			m[spec.GetName()[len("projects/"+project):]] = files(spec)
+		}
+		return m
+	}
+
+	want, got := specs(source), specs(target)
+	if diff := cmp.Diff(want, got); diff != "" {
+		keys := make([]string, 0, len(got))
+		for k := range got {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		t.Errorf("Round trip returned unexpected specs %v (-want +got):\n%s", keys, diff)
+	}
+}
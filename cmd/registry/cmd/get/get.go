@@ -16,21 +16,48 @@ package get
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func Command(ctx context.Context) *cobra.Command {
 	var getContents bool
+	var at string
+	var output string
+	var columns []string
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get resources from the API Registry",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			var printer *core.Printer
+			if output != "" {
+				var err error
+				printer, err = core.NewPrinter(cmd.OutOrStdout(), output, columns)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+				}
+			}
+
+			var readTime *timestamppb.Timestamp
+			if at != "" {
+				t, err := time.Parse(time.RFC3339Nano, at)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid --at time, must be in RFC 3339 format")
+				}
+				readTime = timestamppb.New(t)
+			}
+
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
@@ -40,39 +67,107 @@ func Command(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
-			var name string
-			if len(args) > 0 {
-				name = args[0]
+			name := args[0]
+			var message proto.Message
+			if readTime != nil {
+				message, err = getAt(ctx, client, adminClient, name, readTime, getContents)
+			} else {
+				message, err = get(ctx, client, adminClient, name, getContents)
+			}
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatalf("Failed to get %s", name)
 			}
 
-			var err2 error
-			if project, err := names.ParseProject(name); err == nil {
-				_, err2 = core.GetProject(ctx, adminClient, project, core.PrintProjectDetail)
-			} else if api, err := names.ParseApi(name); err == nil {
-				_, err2 = core.GetAPI(ctx, client, api, core.PrintAPIDetail)
-			} else if version, err := names.ParseVersion(name); err == nil {
-				_, err2 = core.GetVersion(ctx, client, version, core.PrintVersionDetail)
-			} else if spec, err := names.ParseSpec(name); err == nil {
-				if getContents {
-					_, err2 = core.GetSpec(ctx, client, spec, getContents, core.PrintSpecContents)
-				} else {
-					_, err2 = core.GetSpec(ctx, client, spec, getContents, core.PrintSpecDetail)
-				}
-			} else if artifact, err := names.ParseArtifact(name); err == nil {
-				if getContents {
-					_, err2 = core.GetArtifact(ctx, client, artifact, getContents, core.PrintArtifactContents)
-				} else {
-					_, err2 = core.GetArtifact(ctx, client, artifact, getContents, core.PrintArtifactDetail)
-				}
-			} else {
-				log.Debugf(ctx, "Unsupported entity %+v", args)
+			if printer == nil {
+				printResource(message, getContents)
+				return
+			}
+			if err := printer.Print(message); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print resource")
 			}
-			if err2 != nil {
-				log.FromContext(ctx).WithError(err2).Debugf("Failed to get resource")
+			if err := printer.Flush(); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to print resource")
 			}
 		},
 	}
 
 	cmd.Flags().BoolVar(&getContents, "contents", false, "Include resource contents if available")
+	cmd.Flags().StringVar(&at, "at", "", "Get the resource as it was at a time (RFC 3339)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", core.OutputUsage)
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, core.ColumnsUsage)
 	return cmd
 }
+
+// printResource prints a resource in the default format. Spec and artifact
+// contents are printed instead of the resource when they were requested.
+func printResource(message proto.Message, getContents bool) {
+	switch message := message.(type) {
+	case *rpc.ApiSpec:
+		if getContents {
+			core.PrintSpecContents(message)
+			return
+		}
+	case *rpc.Artifact:
+		if getContents {
+			core.PrintArtifactContents(message)
+			return
+		}
+	}
+	core.PrintMessage(message)
+}
+
+func get(ctx context.Context, client *gapic.RegistryClient, adminClient *gapic.AdminClient, name string, getContents bool) (proto.Message, error) {
+	if project, err := names.ParseProject(name); err == nil {
+		return core.GetProject(ctx, adminClient, project, nil)
+	} else if api, err := names.ParseApi(name); err == nil {
+		return core.GetAPI(ctx, client, api, nil)
+	} else if version, err := names.ParseVersion(name); err == nil {
+		return core.GetVersion(ctx, client, version, nil)
+	} else if spec, err := names.ParseSpec(name); err == nil {
+		return core.GetSpec(ctx, client, spec, getContents, nil)
+	} else if artifact, err := names.ParseArtifact(name); err == nil {
+		return core.GetArtifact(ctx, client, artifact, getContents, nil)
+	}
+	return nil, fmt.Errorf("unsupported entity %q", name)
+}
+
+// getAt gets a resource as it was at a time. Contents are read from the
+// revision that was current at that time.
+func getAt(ctx context.Context, client *gapic.RegistryClient, adminClient *gapic.AdminClient, name string, readTime *timestamppb.Timestamp, getContents bool) (proto.Message, error) {
+	if project, err := names.ParseProject(name); err == nil {
+		return adminClient.GetProject(ctx, &rpc.GetProjectRequest{Name: project.String(), ReadTime: readTime})
+	} else if api, err := names.ParseApi(name); err == nil {
+		return client.GetApi(ctx, &rpc.GetApiRequest{Name: api.String(), ReadTime: readTime})
+	} else if version, err := names.ParseVersion(name); err == nil {
+		return client.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: version.String(), ReadTime: readTime})
+	} else if spec, err := names.ParseSpec(name); err == nil {
+		message, err := client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: spec.String(), ReadTime: readTime})
+		if err != nil || !getContents {
+			return message, err
+		}
+		contents, err := client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{
+			Name: spec.Revision(message.GetRevisionId()).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		message.Contents = contents.GetData()
+		message.MimeType = contents.GetContentType()
+		return message, nil
+	} else if artifact, err := names.ParseArtifact(name); err == nil {
+		message, err := client.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: artifact.String(), ReadTime: readTime})
+		if err != nil || !getContents {
+			return message, err
+		}
+		contents, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{
+			Name: artifact.Revision(message.GetRevisionId()).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		message.Contents = contents.GetData()
+		message.MimeType = contents.GetContentType()
+		return message, nil
+	}
+	return nil, fmt.Errorf("unsupported entity %q", name)
+}
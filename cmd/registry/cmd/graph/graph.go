@@ -0,0 +1,168 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	var (
+		format string
+		kind   string
+		target string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "graph PROJECT_OR_API",
+		Short: "Print the relationships between resources in the API Registry",
+		Long: `Print the relationships between resources in the API Registry.
+
+Relationships are listed from a project or from a single API. Use --target to
+list only the relationships to a resource and its children, e.g. to find
+everything that depends on a deployment.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parent, err := parentApis(args[0])
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse name")
+			}
+
+			if format != "dot" && format != "json" {
+				log.Fatalf(ctx, "Unsupported format %q: must be dot or json", format)
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			var filters []string
+			if kind != "" {
+				filters = append(filters, fmt.Sprintf("kind == %q", kind))
+			}
+			if target != "" {
+				filters = append(filters, fmt.Sprintf("(target == %q || target.startsWith(%q) || target.startsWith(%q))", target, target+"/", target+"@"))
+			}
+
+			var relationships []*rpc.Relationship
+			it := client.ListRelationships(ctx, &rpc.ListRelationshipsRequest{
+				Parent: parent.String(),
+				Filter: strings.Join(filters, " && "),
+			})
+			for {
+				relationship, err := it.Next()
+				if err == iterator.Done {
+					break
+				} else if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to list relationships")
+				}
+				relationships = append(relationships, relationship)
+			}
+
+			if format == "json" {
+				err = writeJSON(cmd.OutOrStdout(), relationships)
+			} else {
+				err = writeDOT(cmd.OutOrStdout(), relationships)
+			}
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to write graph")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "dot", "Output format (dot or json)")
+	cmd.Flags().StringVar(&kind, "kind", "", "Only print relationships of this kind, e.g. depends-on")
+	cmd.Flags().StringVar(&target, "target", "", "Only print relationships to this resource or its children")
+	return cmd
+}
+
+// parentApis returns the APIs whose relationships are printed for a project
+// or API name.
+func parentApis(name string) (names.Api, error) {
+	if api, err := names.ParseApi(name); err == nil {
+		return api, nil
+	}
+
+	project, err := names.ParseProject(name)
+	if err != nil {
+		return names.Api{}, fmt.Errorf("%q is not a project or API name", name)
+	}
+
+	return project.Location("-").Api("-"), nil
+}
+
+// Edge is a relationship in the JSON representation of a graph.
+type Edge struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+// Graph is the JSON representation of a graph.
+type Graph struct {
+	Nodes []string `json:"nodes"`
+	Edges []Edge   `json:"edges"`
+}
+
+// newGraph returns the graph of a list of relationships with sorted nodes.
+func newGraph(relationships []*rpc.Relationship) Graph {
+	g := Graph{Nodes: []string{}, Edges: make([]Edge, len(relationships))}
+	seen := make(map[string]bool)
+	for i, r := range relationships {
+		g.Edges[i] = Edge{Name: r.GetName(), Source: r.GetSource(), Target: r.GetTarget(), Kind: r.GetKind()}
+		for _, node := range []string{r.GetSource(), r.GetTarget()} {
+			if !seen[node] {
+				seen[node] = true
+				g.Nodes = append(g.Nodes, node)
+			}
+		}
+	}
+	sort.Strings(g.Nodes)
+	return g
+}
+
+func writeJSON(w io.Writer, relationships []*rpc.Relationship) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newGraph(relationships))
+}
+
+func writeDOT(w io.Writer, relationships []*rpc.Relationship) error {
+	g := newGraph(relationships)
+	lines := []string{"digraph registry {"}
+	for _, node := range g.Nodes {
+		lines = append(lines, fmt.Sprintf("  %q;", node))
+	}
+	for _, e := range g.Edges {
+		lines = append(lines, fmt.Sprintf("  %q -> %q [label=%q];", e.Source, e.Target, e.Kind))
+	}
+	lines = append(lines, "}")
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
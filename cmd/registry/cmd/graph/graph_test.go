@@ -0,0 +1,101 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testRelationships = []*rpc.Relationship{
+	{
+		Name:   "projects/p/locations/global/apis/a/relationships/uses-b",
+		Source: "projects/p/locations/global/apis/a",
+		Target: "projects/p/locations/global/apis/b/deployments/prod",
+		Kind:   "depends-on",
+	},
+	{
+		Name:   "projects/p/locations/global/apis/c/relationships/wraps-b",
+		Source: "projects/p/locations/global/apis/c/versions/v1",
+		Target: "projects/p/locations/global/apis/b",
+		Kind:   "wraps",
+	},
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeDOT(&buf, testRelationships); err != nil {
+		t.Fatalf("writeDOT() returned error: %s", err)
+	}
+
+	want := `digraph registry {
+  "projects/p/locations/global/apis/a";
+  "projects/p/locations/global/apis/b";
+  "projects/p/locations/global/apis/b/deployments/prod";
+  "projects/p/locations/global/apis/c/versions/v1";
+  "projects/p/locations/global/apis/a" -> "projects/p/locations/global/apis/b/deployments/prod" [label="depends-on"];
+  "projects/p/locations/global/apis/c/versions/v1" -> "projects/p/locations/global/apis/b" [label="wraps"];
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("writeDOT() returned unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, testRelationships); err != nil {
+		t.Fatalf("writeJSON() returned error: %s", err)
+	}
+
+	var got Graph
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("writeJSON() returned invalid JSON: %s", err)
+	}
+	if diff := cmp.Diff(newGraph(testRelationships), got); diff != "" {
+		t.Errorf("writeJSON() returned unexpected graph (-want +got):\n%s", diff)
+	}
+	if len(got.Nodes) != 4 || len(got.Edges) != 2 {
+		t.Errorf("writeJSON() returned %d nodes and %d edges, want 4 and 2", len(got.Nodes), len(got.Edges))
+	}
+}
+
+func TestParentApis(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "projects/p", want: "projects/p/locations/-/apis/-"},
+		{name: "projects/p/locations/global/apis/a", want: "projects/p/locations/global/apis/a"},
+	}
+
+	for _, test := range tests {
+		got, err := parentApis(test.name)
+		if err != nil {
+			t.Fatalf("parentApis(%q) returned error: %s", test.name, err)
+		}
+		if got.String() != test.want {
+			t.Errorf("parentApis(%q) returned %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if _, err := parentApis("projects/p/locations/global/apis/a/versions/v"); err == nil {
+		t.Errorf("parentApis() of a version succeeded, want error")
+	}
+}
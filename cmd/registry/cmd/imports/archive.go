@@ -0,0 +1,493 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/archive"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func archiveCommand(ctx context.Context) *cobra.Command {
+	var projectID string
+	cmd := &cobra.Command{
+		Use:   "archive FILE",
+		Short: "Import a project from an archive",
+		Long: `Import a project from an archive written by "registry export archive".
+Use "-" to read the archive from standard input. The project is created, so
+it must not exist. Its ID is the ID of the exported project unless --project
+is set.
+
+The contents of spec and artifact revisions are checked against the hashes
+that are stored in the archive, and the import fails if they don't match.
+Archives written by earlier versions of registry, which hold only the latest
+revision of each artifact and no artifact types or relationships, can also be
+imported.`,
+		Example: "registry import archive my-project.tar.gz --project my-copy",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var r io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to open archive")
+				}
+				defer f.Close()
+				r = f
+			}
+			ar, err := archive.NewReader(r)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to read archive")
+			}
+			source, err := names.ParseProject(ar.Manifest.Project)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid archive")
+			}
+			if projectID == "" {
+				projectID = source.ProjectID
+			}
+			target, err := names.ParseProject("projects/" + projectID)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse project name")
+			}
+
+			i := &importer{
+				source:    source,
+				target:    target,
+				out:       cmd.OutOrStdout(),
+				revisions: make(map[string]string),
+				artifacts: make(map[string]bool),
+			}
+			if i.client, err = connection.NewClient(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			if i.adminClient, err = connection.NewAdminClient(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			for {
+				e, err := ar.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to read archive")
+				}
+				if err := i.importEntry(ctx, e); err != nil {
+					log.FromContext(ctx).WithError(err).Fatalf("Failed to import %q", e.Path)
+				}
+			}
+			if err := i.finish(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to import project")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project", "", "ID of the project to create (default is the ID of the exported project)")
+	return cmd
+}
+
+// revisionPathRegexp matches the paths of spec, deployment, and artifact
+// revisions and captures the paths of the specs, deployments, and artifacts.
+var revisionPathRegexp = regexp.MustCompile(`^(.+)/revisions/[0-9]+-[^/]+$`)
+
+type importer struct {
+	client         connection.Client
+	adminClient    connection.AdminClient
+	source, target names.Project
+	out            io.Writer
+
+	// revisions maps the names of archived spec and deployment revisions to
+	// the names of the created revisions.
+	revisions map[string]string
+	// artifacts holds the names of the artifacts that have been created, so
+	// that their later revisions replace them.
+	artifacts map[string]bool
+	// updates are applied after all resources are created, because they can
+	// refer to later resources or be restricted by lifecycles.
+	updates []func(context.Context) error
+}
+
+// name returns the name of the resource at a path of the archive.
+func (i *importer) name(path string) string {
+	if path == "" {
+		return i.target.String()
+	}
+	return i.target.String() + "/" + path
+}
+
+// renameReference returns the name of the imported copy of a resource named
+// in the archive.
+func (i *importer) renameReference(name string) string {
+	if name == "" {
+		return ""
+	}
+	if copy, ok := i.revisions[name]; ok {
+		return copy
+	}
+	prefix := i.source.String() + "/"
+	if !strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return i.target.String() + "/" + strings.TrimPrefix(name, prefix)
+}
+
+func (i *importer) report(verb, name string) {
+	fmt.Fprintf(i.out, "%s %s\n", verb, name)
+}
+
+func (i *importer) importEntry(ctx context.Context, e *archive.Entry) error {
+	if e.Path == "" {
+		return i.importProject(ctx, e)
+	}
+	if m := revisionPathRegexp.FindStringSubmatch(e.Path); m != nil {
+		if spec, err := names.ParseSpec(i.name(m[1])); err == nil {
+			return i.importSpecRevision(ctx, spec, e)
+		}
+		if deployment, err := names.ParseDeployment(i.name(m[1])); err == nil {
+			return i.importDeploymentRevision(ctx, deployment, e)
+		}
+		if artifact, err := names.ParseArtifact(i.name(m[1])); err == nil {
+			return i.importArtifactRevision(ctx, artifact, e)
+		}
+	}
+	name := i.name(e.Path)
+	// Archives of version 1 hold artifacts without their revisions.
+	if artifact, err := names.ParseArtifact(name); err == nil {
+		return i.importArtifactRevision(ctx, artifact, e)
+	}
+	if artifactType, err := names.ParseArtifactType(name); err == nil {
+		return i.importArtifactType(ctx, artifactType, e)
+	}
+	if relationship, err := names.ParseRelationship(name); err == nil {
+		return i.importRelationship(ctx, relationship, e)
+	}
+	if api, err := names.ParseApi(name); err == nil {
+		return i.importApi(ctx, api, e)
+	}
+	if version, err := names.ParseVersion(name); err == nil {
+		return i.importVersion(ctx, version, e)
+	}
+	return fmt.Errorf("unexpected resource %q", e.Path)
+}
+
+func (i *importer) importProject(ctx context.Context, e *archive.Entry) error {
+	project := &rpc.Project{}
+	if err := e.Unmarshal(project); err != nil {
+		return err
+	}
+	created, err := i.adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: i.target.ProjectID,
+		Project: &rpc.Project{
+			DisplayName: project.GetDisplayName(),
+			Description: project.GetDescription(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	i.report("created", created.GetName())
+
+	if len(project.GetRetentionPolicies()) == 0 && project.GetApiLifecycle() == nil && project.GetVersionLifecycle() == nil {
+		return nil
+	}
+	i.updates = append(i.updates, func(ctx context.Context) error {
+		_, err := i.adminClient.UpdateProject(ctx, &rpc.UpdateProjectRequest{
+			Project: &rpc.Project{
+				Name:              created.GetName(),
+				RetentionPolicies: project.GetRetentionPolicies(),
+				ApiLifecycle:      project.GetApiLifecycle(),
+				VersionLifecycle:  project.GetVersionLifecycle(),
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies", "api_lifecycle", "version_lifecycle"}},
+		})
+		return err
+	})
+	return nil
+}
+
+func (i *importer) importApi(ctx context.Context, name names.Api, e *archive.Entry) error {
+	api := &rpc.Api{}
+	if err := e.Unmarshal(api); err != nil {
+		return err
+	}
+	created, err := i.client.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: name.Parent(),
+		ApiId:  name.ApiID,
+		Api: &rpc.Api{
+			DisplayName: api.GetDisplayName(),
+			Description: api.GetDescription(),
+			Labels:      api.GetLabels(),
+			Annotations: api.GetAnnotations(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	i.report("created", created.GetName())
+
+	if api.GetAvailability() == "" && api.GetRecommendedVersion() == "" && api.GetRecommendedDeployment() == "" {
+		return nil
+	}
+	i.updates = append(i.updates, func(ctx context.Context) error {
+		_, err := i.client.UpdateApi(ctx, &rpc.UpdateApiRequest{
+			Api: &rpc.Api{
+				Name:                  created.GetName(),
+				Availability:          api.GetAvailability(),
+				RecommendedVersion:    i.renameReference(api.GetRecommendedVersion()),
+				RecommendedDeployment: i.renameReference(api.GetRecommendedDeployment()),
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"availability", "recommended_version", "recommended_deployment"}},
+		})
+		return err
+	})
+	return nil
+}
+
+func (i *importer) importVersion(ctx context.Context, name names.Version, e *archive.Entry) error {
+	version := &rpc.ApiVersion{}
+	if err := e.Unmarshal(version); err != nil {
+		return err
+	}
+	created, err := i.client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       name.Parent(),
+		ApiVersionId: name.VersionID,
+		ApiVersion: &rpc.ApiVersion{
+			DisplayName: version.GetDisplayName(),
+			Description: version.GetDescription(),
+			Labels:      version.GetLabels(),
+			Annotations: version.GetAnnotations(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	i.report("created", created.GetName())
+
+	if version.GetState() == "" {
+		return nil
+	}
+	i.updates = append(i.updates, func(ctx context.Context) error {
+		_, err := i.client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+			ApiVersion: &rpc.ApiVersion{Name: created.GetName(), State: version.GetState()},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"state"}},
+		})
+		return err
+	})
+	return nil
+}
+
+func (i *importer) importSpecRevision(ctx context.Context, name names.Spec, e *archive.Entry) error {
+	spec := &rpc.ApiSpec{}
+	if err := e.Unmarshal(spec); err != nil {
+		return err
+	}
+	if got := archive.Checksum(e.Contents); got != spec.GetHash() {
+		return fmt.Errorf("checksum of contents is %s, want %s", got, spec.GetHash())
+	}
+	contents, err := compressed(spec.GetMimeType(), e.Contents)
+	if err != nil {
+		return err
+	}
+
+	created, err := i.client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{
+			Name:        name.String(),
+			Filename:    spec.GetFilename(),
+			Description: spec.GetDescription(),
+			MimeType:    spec.GetMimeType(),
+			SourceUri:   spec.GetSourceUri(),
+			Labels:      spec.GetLabels(),
+			Annotations: spec.GetAnnotations(),
+			Contents:    contents,
+		},
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"filename", "description", "mime_type", "source_uri", "labels", "annotations", "contents"}},
+		AllowMissing: true,
+	})
+	if err != nil {
+		return err
+	}
+	revision := name.String() + "@" + created.GetRevisionId()
+	i.revisions[spec.GetName()] = revision
+	i.report("created", revision)
+
+	for _, tag := range spec.GetRevisionTags() {
+		if _, err := i.client.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{Name: revision, Tag: tag}); err != nil {
+			return err
+		}
+		i.report("tagged", name.String()+"@"+tag)
+	}
+	return nil
+}
+
+func (i *importer) importDeploymentRevision(ctx context.Context, name names.Deployment, e *archive.Entry) error {
+	deployment := &rpc.ApiDeployment{}
+	if err := e.Unmarshal(deployment); err != nil {
+		return err
+	}
+	created, err := i.client.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+		ApiDeployment: &rpc.ApiDeployment{
+			Name:               name.String(),
+			DisplayName:        deployment.GetDisplayName(),
+			Description:        deployment.GetDescription(),
+			ApiSpecRevision:    i.renameReference(deployment.GetApiSpecRevision()),
+			EndpointUri:        deployment.GetEndpointUri(),
+			ExternalChannelUri: deployment.GetExternalChannelUri(),
+			IntendedAudience:   deployment.GetIntendedAudience(),
+			AccessGuidance:     deployment.GetAccessGuidance(),
+			Labels:             deployment.GetLabels(),
+			Annotations:        deployment.GetAnnotations(),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name", "description", "api_spec_revision", "endpoint_uri",
+			"external_channel_uri", "intended_audience", "access_guidance", "labels", "annotations"}},
+		AllowMissing: true,
+	})
+	if err != nil {
+		return err
+	}
+	revision := name.String() + "@" + created.GetRevisionId()
+	i.revisions[deployment.GetName()] = revision
+	i.report("created", revision)
+
+	for _, tag := range deployment.GetRevisionTags() {
+		if _, err := i.client.TagApiDeploymentRevision(ctx, &rpc.TagApiDeploymentRevisionRequest{Name: revision, Tag: tag}); err != nil {
+			return err
+		}
+		i.report("tagged", name.String()+"@"+tag)
+	}
+	return nil
+}
+
+func (i *importer) importArtifactRevision(ctx context.Context, name names.Artifact, e *archive.Entry) error {
+	artifact := &rpc.Artifact{}
+	if err := e.Unmarshal(artifact); err != nil {
+		return err
+	}
+	if got := archive.Checksum(e.Contents); got != artifact.GetHash() {
+		return fmt.Errorf("checksum of contents is %s, want %s", got, artifact.GetHash())
+	}
+	contents, err := compressed(artifact.GetMimeType(), e.Contents)
+	if err != nil {
+		return err
+	}
+
+	body := &rpc.Artifact{
+		MimeType:    artifact.GetMimeType(),
+		Labels:      artifact.GetLabels(),
+		Annotations: artifact.GetAnnotations(),
+		Contents:    contents,
+	}
+	var created *rpc.Artifact
+	if i.artifacts[name.String()] {
+		body.Name = name.String()
+		created, err = i.client.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{Artifact: body})
+	} else {
+		created, err = i.client.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+			Parent:     name.Parent(),
+			ArtifactId: name.ArtifactID(),
+			Artifact:   body,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	i.artifacts[name.String()] = true
+	revision := name.String() + "@" + created.GetRevisionId()
+	i.report("created", revision)
+
+	for _, tag := range artifact.GetRevisionTags() {
+		if _, err := i.client.TagArtifactRevision(ctx, &rpc.TagArtifactRevisionRequest{Name: revision, Tag: tag}); err != nil {
+			return err
+		}
+		i.report("tagged", name.String()+"@"+tag)
+	}
+	return nil
+}
+
+func (i *importer) importArtifactType(ctx context.Context, name names.ArtifactType, e *archive.Entry) error {
+	artifactType := &rpc.ArtifactType{}
+	if err := e.Unmarshal(artifactType); err != nil {
+		return err
+	}
+	created, err := i.client.CreateArtifactType(ctx, &rpc.CreateArtifactTypeRequest{
+		Parent:         name.Parent(),
+		ArtifactTypeId: name.ArtifactTypeID,
+		ArtifactType: &rpc.ArtifactType{
+			Description: artifactType.GetDescription(),
+			MimeType:    artifactType.GetMimeType(),
+			Schema:      artifactType.GetSchema(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	i.report("created", created.GetName())
+	return nil
+}
+
+func (i *importer) importRelationship(ctx context.Context, name names.Relationship, e *archive.Entry) error {
+	relationship := &rpc.Relationship{}
+	if err := e.Unmarshal(relationship); err != nil {
+		return err
+	}
+	created, err := i.client.CreateRelationship(ctx, &rpc.CreateRelationshipRequest{
+		Parent:         name.Parent(),
+		RelationshipId: name.RelationshipID,
+		Relationship: &rpc.Relationship{
+			Source:      i.renameReference(relationship.GetSource()),
+			Target:      i.renameReference(relationship.GetTarget()),
+			Kind:        relationship.GetKind(),
+			Description: relationship.GetDescription(),
+			Labels:      relationship.GetLabels(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	i.report("created", created.GetName())
+	return nil
+}
+
+// compressed returns archived contents, which are uncompressed, in the
+// compression of their MIME type.
+func compressed(mimeType string, contents []byte) ([]byte, error) {
+	if mimetypes.IsCompressed(mimeType, mimetypes.GZip) && len(contents) > 0 {
+		return core.GZippedBytes(contents)
+	}
+	return contents, nil
+}
+
+// finish applies the updates that were deferred until all resources were
+// created.
+func (i *importer) finish(ctx context.Context) error {
+	for _, update := range i.updates {
+		if err := update(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imports
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/cmd/registry/cmd/export"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevisionPathRegexp(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"locations/global/apis/a/versions/v/specs/s/revisions/0001-1111aaaa", "locations/global/apis/a/versions/v/specs/s"},
+		{"locations/global/apis/a/deployments/d/revisions/0012-2222bbbb", "locations/global/apis/a/deployments/d"},
+		{"locations/global/apis/a/versions/v/specs/s/artifacts/revisions", ""},
+		{"locations/global/apis/revisions", ""},
+	}
+	for _, test := range tests {
+		got := ""
+		if m := revisionPathRegexp.FindStringSubmatch(test.path); m != nil {
+			got = m[1]
+		}
+		if got != test.want {
+			t.Errorf("revisionPathRegexp matched %q in %q, want %q", got, test.path, test.want)
+		}
+	}
+}
+
+func TestRenameReference(t *testing.T) {
+	i := &importer{
+		source:    names.Project{ProjectID: "staging"},
+		target:    names.Project{ProjectID: "restored"},
+		revisions: map[string]string{"projects/staging/locations/global/apis/a/versions/v/specs/s@1111aaaa": "projects/restored/locations/global/apis/a/versions/v/specs/s@2222bbbb"},
+	}
+	tests := []struct {
+		name, want string
+	}{
+		{"projects/staging/locations/global/apis/a/versions/v", "projects/restored/locations/global/apis/a/versions/v"},
+		{"projects/staging/locations/global/apis/a/versions/v/specs/s@1111aaaa", "projects/restored/locations/global/apis/a/versions/v/specs/s@2222bbbb"},
+		{"projects/other/locations/global/apis/a", "projects/other/locations/global/apis/a"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := i.renameReference(test.name); got != test.want {
+			t.Errorf("renameReference(%q) returned %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	const (
+		source = "archive-source"
+		target = "archive-target"
+	)
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	for _, project := range []string{source, target} {
+		err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+		if err != nil && status.Code(err) != codes.NotFound {
+			t.Fatalf("Setup: Failed to delete test project: %s", err)
+		}
+		defer func(project string) {
+			_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+		}(project)
+	}
+	if _, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: source,
+		Project:   &rpc.Project{},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create test project: %s", err)
+	}
+
+	location := "projects/" + source + "/locations/global"
+	if _, err := client.CreateArtifactType(ctx, &rpc.CreateArtifactTypeRequest{
+		Parent:         location,
+		ArtifactTypeId: "notes",
+		ArtifactType: &rpc.ArtifactType{
+			MimeType: "application/json;type=notes",
+			Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: `{"type": "object"}`},
+		},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create artifact type: %s", err)
+	}
+	artifact, err := client.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+		Parent:     location,
+		ArtifactId: "notes",
+		Artifact:   &rpc.Artifact{MimeType: "application/json;type=notes", Contents: []byte(`{"n": 1}`)},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create artifact: %s", err)
+	}
+	if _, err := client.TagArtifactRevision(ctx, &rpc.TagArtifactRevisionRequest{
+		Name: artifact.GetName() + "@" + artifact.GetRevisionId(),
+		Tag:  "first",
+	}); err != nil {
+		t.Fatalf("Setup: Failed to tag artifact: %s", err)
+	}
+	if _, err := client.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{
+		Artifact: &rpc.Artifact{Name: artifact.GetName(), MimeType: "application/json;type=notes", Contents: []byte(`{"n": 2}`)},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to replace artifact: %s", err)
+	}
+
+	for _, api := range []string{"a", "b"} {
+		if _, err := client.CreateApi(ctx, &rpc.CreateApiRequest{Parent: location, ApiId: api, Api: &rpc.Api{}}); err != nil {
+			t.Fatalf("Setup: Failed to create API: %s", err)
+		}
+	}
+	if _, err := client.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       location + "/apis/b",
+		ApiVersionId: "v1",
+		ApiVersion:   &rpc.ApiVersion{},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create version: %s", err)
+	}
+	spec, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    location + "/apis/b/versions/v1",
+		ApiSpecId: "s",
+		ApiSpec:   &rpc.ApiSpec{MimeType: "application/x.openapi;version=3", Contents: []byte("openapi: 3.0.0")},
+	})
+	if err != nil {
+		t.Fatalf("Setup: Failed to create spec: %s", err)
+	}
+	if _, err := client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{Name: spec.GetName(), Contents: []byte("openapi: 3.0.1")},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to update spec: %s", err)
+	}
+	// The relationship refers to the first revision of a spec in another API.
+	if _, err := client.CreateRelationship(ctx, &rpc.CreateRelationshipRequest{
+		Parent:         location + "/apis/a",
+		RelationshipId: "uses-b",
+		Relationship: &rpc.Relationship{
+			Target: spec.GetName() + "@" + spec.GetRevisionId(),
+			Kind:   "depends-on",
+		},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create relationship: %s", err)
+	}
+
+	var buf bytes.Buffer
+	exportCmd := export.Command(ctx)
+	exportCmd.SetOut(&buf)
+	exportCmd.SetArgs([]string{"archive", source})
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatalf("Export returned error: %s", err)
+	}
+	importCmd := Command(ctx)
+	importCmd.SetIn(&buf)
+	importCmd.SetOut(ioutil.Discard)
+	importCmd.SetArgs([]string{"archive", "-", "--project", target})
+	if err := importCmd.Execute(); err != nil {
+		t.Fatalf("Import returned error: %s", err)
+	}
+
+	copy := "projects/" + target + "/locations/global"
+	artifactType, err := client.GetArtifactType(ctx, &rpc.GetArtifactTypeRequest{Name: copy + "/artifactTypes/notes"})
+	if err != nil {
+		t.Fatalf("GetArtifactType() returned error: %s", err)
+	}
+	if got, want := artifactType.GetJsonSchema(), `{"type": "object"}`; got != want {
+		t.Errorf("GetArtifactType() returned schema %q, want %q", got, want)
+	}
+
+	// Revisions are listed from the newest.
+	type revision struct {
+		Contents string
+		Tags     []string
+	}
+	var revisions []revision
+	it := client.ListArtifactRevisions(ctx, &rpc.ListArtifactRevisionsRequest{Name: copy + "/artifacts/notes"})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			t.Fatalf("ListArtifactRevisions() returned error: %s", err)
+		}
+		contents, err := client.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: r.GetName()})
+		if err != nil {
+			t.Fatalf("GetArtifactContents() returned error: %s", err)
+		}
+		revisions = append(revisions, revision{Contents: string(contents.GetData()), Tags: r.GetRevisionTags()})
+	}
+	want := []revision{{Contents: `{"n": 2}`}, {Contents: `{"n": 1}`, Tags: []string{"first"}}}
+	if diff := cmp.Diff(want, revisions); diff != "" {
+		t.Errorf("Imported artifact has unexpected revisions (-want +got):\n%s", diff)
+	}
+
+	relationship, err := client.GetRelationship(ctx, &rpc.GetRelationshipRequest{Name: copy + "/apis/a/relationships/uses-b"})
+	if err != nil {
+		t.Fatalf("GetRelationship() returned error: %s", err)
+	}
+	if !strings.HasPrefix(relationship.GetTarget(), copy+"/") {
+		t.Errorf("Imported relationship targets %q, want a resource of %s", relationship.GetTarget(), copy)
+	}
+	first, err := client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: relationship.GetTarget()})
+	if err != nil {
+		t.Fatalf("GetApiSpecContents(%q) returned error: %s", relationship.GetTarget(), err)
+	}
+	if got, want := string(first.GetData()), "openapi: 3.0.0"; got != want {
+		t.Errorf("Imported relationship targets contents %q, want %q", got, want)
+	}
+	if got, want := relationship.GetKind(), "depends-on"; got != want {
+		t.Errorf("Imported relationship has kind %q, want %q", got, want)
+	}
+}
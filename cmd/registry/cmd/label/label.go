@@ -93,6 +93,8 @@ func matchAndHandleLabelCmd(
 		return labelVersions(ctx, client, version, filter, labeling, taskQueue)
 	} else if spec, err := names.ParseSpecCollection(name); err == nil {
 		return labelSpecs(ctx, client, spec, filter, labeling, taskQueue)
+	} else if artifact, err := names.ParseArtifactCollection(name); err == nil {
+		return labelArtifacts(ctx, client, artifact, filter, labeling, taskQueue)
 	}
 
 	// Then try to match resource names.
@@ -102,6 +104,8 @@ func matchAndHandleLabelCmd(
 		return labelVersions(ctx, client, version, filter, labeling, taskQueue)
 	} else if spec, err := names.ParseSpec(name); err == nil {
 		return labelSpecs(ctx, client, spec, filter, labeling, taskQueue)
+	} else if artifact, err := names.ParseArtifact(name); err == nil {
+		return labelArtifacts(ctx, client, artifact, filter, labeling, taskQueue)
 	} else {
 		return fmt.Errorf("unsupported resource name %s", name)
 	}
@@ -154,6 +158,23 @@ func labelSpecs(
 	})
 }
 
+func labelArtifacts(
+	ctx context.Context,
+	client *gapic.RegistryClient,
+	artifact names.Artifact,
+	filterFlag string,
+	labeling *core.Labeling,
+	taskQueue chan<- core.Task) error {
+	// Artifacts are updated by replacement, so their contents are needed.
+	return core.ListArtifacts(ctx, client, artifact, filterFlag, true, func(artifact *rpc.Artifact) {
+		taskQueue <- &labelArtifactTask{
+			client:   client,
+			artifact: artifact,
+			labeling: labeling,
+		}
+	})
+}
+
 type labelApiTask struct {
 	client   connection.Client
 	api      *rpc.Api
@@ -234,3 +255,27 @@ func (task *labelSpecTask) Run(ctx context.Context) error {
 		})
 	return err
 }
+
+type labelArtifactTask struct {
+	client   connection.Client
+	artifact *rpc.Artifact
+	labeling *core.Labeling
+}
+
+func (task *labelArtifactTask) String() string {
+	return "label " + task.artifact.Name
+}
+
+func (task *labelArtifactTask) Run(ctx context.Context) error {
+	var err error
+	task.artifact.Labels, err = task.labeling.Apply(task.artifact.Labels)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Errorf("Invalid labelling")
+		return nil
+	}
+	_, err = task.client.ReplaceArtifact(ctx,
+		&rpc.ReplaceArtifactRequest{
+			Artifact: task.artifact,
+		})
+	return err
+}
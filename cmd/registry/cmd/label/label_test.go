@@ -27,14 +27,16 @@ import (
 
 func TestLabel(t *testing.T) {
 	const (
-		projectID   = "label-test"
-		projectName = "projects/" + projectID
-		apiID       = "sample"
-		apiName     = projectName + "/locations/global/apis/" + apiID
-		versionID   = "1.0.0"
-		versionName = apiName + "/versions/" + versionID
-		specID      = "openapi.json"
-		specName    = versionName + "/specs/" + specID
+		projectID    = "label-test"
+		projectName  = "projects/" + projectID
+		apiID        = "sample"
+		apiName      = projectName + "/locations/global/apis/" + apiID
+		versionID    = "1.0.0"
+		versionName  = apiName + "/versions/" + versionID
+		specID       = "openapi.json"
+		specName     = versionName + "/specs/" + specID
+		artifactID   = "complexity"
+		artifactName = apiName + "/artifacts/" + artifactID
 	)
 
 	// Create a registry client.
@@ -97,6 +99,18 @@ func TestLabel(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating spec %s", err)
 	}
+	// Create a sample artifact.
+	_, err = registryClient.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+		Parent:     apiName,
+		ArtifactId: artifactID,
+		Artifact: &rpc.Artifact{
+			MimeType: "text/plain",
+			Contents: []byte("sample"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error creating artifact %s", err)
+	}
 
 	testCases := []struct {
 		comment  string
@@ -168,6 +182,33 @@ func TestLabel(t *testing.T) {
 		}
 	}
 
+	// test labels for artifacts.
+	for _, tc := range testCases {
+		cmd := Command(ctx)
+		cmd.SetArgs(append([]string{artifactName}, tc.args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() with args %+v returned error: %s", tc.args, err)
+		}
+		artifact, err := registryClient.GetArtifact(ctx, &rpc.GetArtifactRequest{
+			Name: artifactName,
+		})
+		if err != nil {
+			t.Errorf("Error getting artifact %s", err)
+		} else {
+			if diff := cmp.Diff(artifact.Labels, tc.expected); diff != "" {
+				t.Errorf("labels were incorrectly set %+v", artifact.Labels)
+			}
+		}
+		contents, err := registryClient.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{
+			Name: artifactName,
+		})
+		if err != nil {
+			t.Errorf("Error getting artifact contents %s", err)
+		} else if string(contents.GetData()) != "sample" {
+			t.Errorf("artifact contents were changed to %q", contents.GetData())
+		}
+	}
+
 	// Delete the test project.
 	if false {
 		req := &rpc.DeleteProjectRequest{
@@ -21,17 +21,40 @@ import (
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 )
 
 func Command(ctx context.Context) *cobra.Command {
 	var filter string
+	var output string
+	var columns []string
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List resources in the API Registry",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			// By default, only the names of resources are printed.
+			print := func(m proto.Message) {
+				fmt.Fprintln(cmd.OutOrStdout(), m.(interface{ GetName() string }).GetName())
+			}
+			var printer *core.Printer
+			if output != "" {
+				var err error
+				printer, err = core.NewPrinter(cmd.OutOrStdout(), output, columns)
+				if err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+				}
+				printer.List = true
+				print = func(m proto.Message) {
+					if err := printer.Print(m); err != nil {
+						log.FromContext(ctx).WithError(err).Fatal("Failed to print resource")
+					}
+				}
+			}
+
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
@@ -40,14 +63,21 @@ func Command(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
-			err = matchAndHandleListCmd(ctx, client, adminClient, args[0], filter)
+			err = matchAndHandleListCmd(ctx, client, adminClient, args[0], filter, print)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to match or handle command")
 			}
+			if printer != nil {
+				if err := printer.Flush(); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to print resources")
+				}
+			}
 		},
 	}
 
 	cmd.Flags().StringVar(&filter, "filter", "", "Filter selected resources")
+	cmd.Flags().StringVarP(&output, "output", "o", "", core.OutputUsage)
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, core.ColumnsUsage)
 	return cmd
 }
 
@@ -57,32 +87,38 @@ func matchAndHandleListCmd(
 	adminClient connection.AdminClient,
 	name string,
 	filter string,
+	print func(proto.Message),
 ) error {
+	printProject := func(m *rpc.Project) { print(m) }
+	printAPI := func(m *rpc.Api) { print(m) }
+	printVersion := func(m *rpc.ApiVersion) { print(m) }
+	printSpec := func(m *rpc.ApiSpec) { print(m) }
+	printArtifact := func(m *rpc.Artifact) { print(m) }
 
 	// First try to match collection names.
 	if project, err := names.ParseProjectCollection(name); err == nil {
-		return core.ListProjects(ctx, adminClient, project, filter, core.PrintProject)
+		return core.ListProjects(ctx, adminClient, project, filter, printProject)
 	} else if api, err := names.ParseApiCollection(name); err == nil {
-		return core.ListAPIs(ctx, client, api, filter, core.PrintAPI)
+		return core.ListAPIs(ctx, client, api, filter, printAPI)
 	} else if version, err := names.ParseVersionCollection(name); err == nil {
-		return core.ListVersions(ctx, client, version, filter, core.PrintVersion)
+		return core.ListVersions(ctx, client, version, filter, printVersion)
 	} else if spec, err := names.ParseSpecCollection(name); err == nil {
-		return core.ListSpecs(ctx, client, spec, filter, core.PrintSpec)
+		return core.ListSpecs(ctx, client, spec, filter, printSpec)
 	} else if artifact, err := names.ParseArtifactCollection(name); err == nil {
-		return core.ListArtifacts(ctx, client, artifact, filter, false, core.PrintArtifact)
+		return core.ListArtifacts(ctx, client, artifact, filter, false, printArtifact)
 	}
 
 	// Then try to match resource names.
 	if project, err := names.ParseProjectCollection(name); err == nil {
-		return core.ListProjects(ctx, adminClient, project, filter, core.PrintProject)
+		return core.ListProjects(ctx, adminClient, project, filter, printProject)
 	} else if api, err := names.ParseApi(name); err == nil {
-		return core.ListAPIs(ctx, client, api, filter, core.PrintAPI)
+		return core.ListAPIs(ctx, client, api, filter, printAPI)
 	} else if version, err := names.ParseVersion(name); err == nil {
-		return core.ListVersions(ctx, client, version, filter, core.PrintVersion)
+		return core.ListVersions(ctx, client, version, filter, printVersion)
 	} else if spec, err := names.ParseSpec(name); err == nil {
-		return core.ListSpecs(ctx, client, spec, filter, core.PrintSpec)
+		return core.ListSpecs(ctx, client, spec, filter, printSpec)
 	} else if artifact, err := names.ParseArtifact(name); err == nil {
-		return core.ListArtifacts(ctx, client, artifact, filter, false, core.PrintArtifact)
+		return core.ListArtifacts(ctx, client, artifact, filter, false, printArtifact)
 	}
 
 	// If nothing matched, return an error.
@@ -0,0 +1,770 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func Command(ctx context.Context) *cobra.Command {
+	var (
+		toContext string
+		to        connection.Settings
+		toProject string
+		include   []string
+		exclude   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mirror SOURCE_PROJECT",
+		Short: "Copy a project to another registry",
+		Long: `Copy a project to another registry, or to another project of the same
+registry. APIs, versions, specs with all of their revisions and tags,
+deployments with their revisions, and artifacts are copied. Revisions are
+created in their original order, and references to revisions are rewritten
+to name the copies.
+
+Mirroring is incremental: resources that already match are skipped, spec
+revisions are matched by the hashes of their contents, and artifacts by
+their hashes. An interrupted mirror resumes when it is run again.
+
+The destination is described by --to-context, a context of the configuration
+file, and by the --to-address, --to-insecure, and --to-token flags, which
+override the settings of the context.
+
+--include and --exclude select resources with patterns of their names
+relative to their location, like "apis/petstore" or
+"apis/*/versions/*/specs/*/artifacts/lint-*", where "*" matches any ID.
+Resources are copied if they, their parents, or their children match an
+include pattern, and are skipped if they or their parents match an exclude
+pattern. Artifact revisions, artifact types, and relationships are not copied.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if !strings.HasPrefix(name, "projects/") {
+				name = "projects/" + name
+			}
+			source, err := names.ParseProject(name)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse project name")
+			}
+			if toProject == "" {
+				toProject = source.ProjectID
+			}
+			target, err := names.ParseProject("projects/" + toProject)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse destination project name")
+			}
+
+			selector, err := newSelector(include, exclude)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Invalid pattern")
+			}
+
+			settings := &connection.Settings{}
+			if toContext != "" {
+				if settings, err = connection.ContextSettings(toContext); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to read destination context")
+				}
+			}
+			flags := cmd.Flags()
+			if flags.Changed("to-address") {
+				settings.Address = to.Address
+			}
+			if flags.Changed("to-insecure") {
+				settings.Insecure = to.Insecure
+			}
+			if flags.Changed("to-token") {
+				settings.Token = to.Token
+			}
+			if settings.Address == "" {
+				log.Fatal(ctx, "A destination is required, set --to-address or --to-context")
+			}
+			if current, err := connection.ActiveSettings(); err == nil &&
+				current.Address == settings.Address && source == target {
+				log.Fatal(ctx, "The source and destination are the same project")
+			}
+
+			m := &mirror{
+				source:    source,
+				target:    target,
+				selector:  selector,
+				out:       cmd.OutOrStdout(),
+				revisions: make(map[string]string),
+			}
+			if m.from, err = connection.NewClient(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			if m.fromAdmin, err = connection.NewAdminClient(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+			if m.to, err = connection.NewClientWithSettings(ctx, settings); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get destination client")
+			}
+			if m.toAdmin, err = connection.NewAdminClientWithSettings(ctx, settings); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get destination client")
+			}
+
+			if err := m.mirrorProject(ctx); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to mirror project")
+			}
+			if m.failures > 0 {
+				log.Fatalf(ctx, "Failed to mirror %d resources, run the command again to retry", m.failures)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&toContext, "to-context", "", "Context of the configuration file that describes the destination registry")
+	cmd.Flags().StringVar(&to.Address, "to-address", "", "Address of the destination registry, e.g. localhost:8080")
+	cmd.Flags().BoolVar(&to.Insecure, "to-insecure", false, "Connect to the destination registry without TLS")
+	cmd.Flags().StringVar(&to.Token, "to-token", "", "Bearer token sent to the destination registry")
+	cmd.Flags().StringVar(&toProject, "to-project", "", "ID of the destination project (default is the ID of the source project)")
+	cmd.Flags().StringSliceVar(&include, "include", nil, "Copy only resources that match these patterns")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "Skip resources that match these patterns")
+	return cmd
+}
+
+type mirror struct {
+	from, to           connection.Client
+	fromAdmin, toAdmin connection.AdminClient
+	source, target     names.Project
+	selector           *selector
+	out                io.Writer
+	failures           int
+
+	// revisions maps the names of source spec and deployment revisions to
+	// the names of their copies.
+	revisions map[string]string
+}
+
+// rename returns the name of the copy of a source resource.
+func (m *mirror) rename(name string) string {
+	prefix := m.source.String() + "/"
+	if !strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return m.target.String() + "/" + strings.TrimPrefix(name, prefix)
+}
+
+// renameReference returns a reference to the copy of a source resource. A
+// revision ID is replaced by the ID of the copied revision, if it is known.
+func (m *mirror) renameReference(name string) string {
+	if name == "" {
+		return ""
+	}
+	if copy, ok := m.revisions[name]; ok {
+		return copy
+	}
+	return m.rename(name)
+}
+
+// relative returns the name of a resource relative to its location.
+func relative(name string) string {
+	parts := strings.SplitN(name, "/", 5)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+func (m *mirror) selected(name string) bool {
+	return m.selector.selected(relative(name))
+}
+
+func (m *mirror) fail(ctx context.Context, name string, err error) {
+	m.failures++
+	log.FromContext(ctx).WithError(err).Errorf("Failed to mirror %s", name)
+}
+
+func (m *mirror) report(verb, name string) {
+	fmt.Fprintf(m.out, "%s %s\n", verb, name)
+}
+
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+func (m *mirror) mirrorProject(ctx context.Context) error {
+	source, err := m.fromAdmin.GetProject(ctx, &rpc.GetProjectRequest{Name: m.source.String()})
+	if err != nil {
+		return err
+	}
+	existing, err := m.toAdmin.GetProject(ctx, &rpc.GetProjectRequest{Name: m.target.String()})
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	want := &rpc.Project{
+		Name:        m.target.String(),
+		DisplayName: source.GetDisplayName(),
+		Description: source.GetDescription(),
+	}
+	if existing == nil || existing.GetDisplayName() != want.GetDisplayName() || existing.GetDescription() != want.GetDescription() {
+		if _, err := m.toAdmin.UpdateProject(ctx, &rpc.UpdateProjectRequest{
+			Project:      want,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"display_name", "description"}},
+			AllowMissing: true,
+		}); err != nil {
+			return err
+		}
+		m.report(verb(existing == nil), want.GetName())
+	}
+
+	locations := m.source.Location("-")
+	m.mirrorArtifacts(ctx, locations.String())
+	it := m.from.ListApis(ctx, &rpc.ListApisRequest{Parent: locations.String()})
+	for {
+		api, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return err
+		}
+		if m.selected(api.GetName()) {
+			m.mirrorApi(ctx, api)
+		}
+	}
+
+	// Lifecycles can restrict the states of APIs and versions, so they are
+	// copied after the APIs and versions.
+	if !equalPolicies(existing.GetRetentionPolicies(), source.GetRetentionPolicies()) ||
+		!proto.Equal(existing.GetApiLifecycle(), source.GetApiLifecycle()) ||
+		!proto.Equal(existing.GetVersionLifecycle(), source.GetVersionLifecycle()) {
+		if _, err := m.toAdmin.UpdateProject(ctx, &rpc.UpdateProjectRequest{
+			Project: &rpc.Project{
+				Name:              m.target.String(),
+				RetentionPolicies: source.GetRetentionPolicies(),
+				ApiLifecycle:      source.GetApiLifecycle(),
+				VersionLifecycle:  source.GetVersionLifecycle(),
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies", "api_lifecycle", "version_lifecycle"}},
+		}); err != nil {
+			m.fail(ctx, m.target.String(), err)
+		}
+	}
+	return nil
+}
+
+func (m *mirror) mirrorApi(ctx context.Context, source *rpc.Api) {
+	name := m.rename(source.GetName())
+	existing, err := m.to.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+	if err != nil && !isNotFound(err) {
+		m.fail(ctx, name, err)
+		return
+	}
+
+	// The availability and recommendations are set after the children, which
+	// they can depend on.
+	want := &rpc.Api{
+		Name:        name,
+		DisplayName: source.GetDisplayName(),
+		Description: source.GetDescription(),
+		Labels:      source.GetLabels(),
+		Annotations: source.GetAnnotations(),
+	}
+	if existing == nil || !proto.Equal(want, &rpc.Api{
+		Name:        name,
+		DisplayName: existing.GetDisplayName(),
+		Description: existing.GetDescription(),
+		Labels:      existing.GetLabels(),
+		Annotations: existing.GetAnnotations(),
+	}) {
+		if _, err := m.to.UpdateApi(ctx, &rpc.UpdateApiRequest{
+			Api:          want,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"display_name", "description", "labels", "annotations"}},
+			AllowMissing: true,
+		}); err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		m.report(verb(existing == nil), name)
+	}
+
+	versions := m.from.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{Parent: source.GetName()})
+	for {
+		version, err := versions.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		if m.selected(version.GetName()) {
+			m.mirrorVersion(ctx, version)
+		}
+	}
+
+	deployments := m.from.ListApiDeployments(ctx, &rpc.ListApiDeploymentsRequest{Parent: source.GetName()})
+	for {
+		deployment, err := deployments.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		if m.selected(deployment.GetName()) {
+			m.mirrorDeployment(ctx, deployment)
+		}
+	}
+
+	m.mirrorArtifacts(ctx, source.GetName())
+
+	availability := source.GetAvailability()
+	recommendedVersion := m.renameReference(source.GetRecommendedVersion())
+	recommendedDeployment := m.renameReference(source.GetRecommendedDeployment())
+	if existing == nil && availability == "" && recommendedVersion == "" && recommendedDeployment == "" {
+		return
+	}
+	if existing != nil && existing.GetAvailability() == availability &&
+		existing.GetRecommendedVersion() == recommendedVersion &&
+		existing.GetRecommendedDeployment() == recommendedDeployment {
+		return
+	}
+	if _, err := m.to.UpdateApi(ctx, &rpc.UpdateApiRequest{
+		Api: &rpc.Api{
+			Name:                  name,
+			Availability:          availability,
+			RecommendedVersion:    recommendedVersion,
+			RecommendedDeployment: recommendedDeployment,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"availability", "recommended_version", "recommended_deployment"}},
+	}); err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+	if existing != nil {
+		m.report("updated", name)
+	}
+}
+
+func (m *mirror) mirrorVersion(ctx context.Context, source *rpc.ApiVersion) {
+	name := m.rename(source.GetName())
+	existing, err := m.to.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+	if err != nil && !isNotFound(err) {
+		m.fail(ctx, name, err)
+		return
+	}
+
+	// The state is set after the children, because a lifecycle can require
+	// artifacts to enter a state.
+	want := &rpc.ApiVersion{
+		Name:        name,
+		DisplayName: source.GetDisplayName(),
+		Description: source.GetDescription(),
+		Labels:      source.GetLabels(),
+		Annotations: source.GetAnnotations(),
+	}
+	if existing == nil || !proto.Equal(want, &rpc.ApiVersion{
+		Name:        name,
+		DisplayName: existing.GetDisplayName(),
+		Description: existing.GetDescription(),
+		Labels:      existing.GetLabels(),
+		Annotations: existing.GetAnnotations(),
+	}) {
+		if _, err := m.to.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+			ApiVersion:   want,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"display_name", "description", "labels", "annotations"}},
+			AllowMissing: true,
+		}); err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		m.report(verb(existing == nil), name)
+	}
+
+	specs := m.from.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: source.GetName()})
+	for {
+		spec, err := specs.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		if m.selected(spec.GetName()) {
+			m.mirrorSpec(ctx, spec)
+		}
+	}
+
+	m.mirrorArtifacts(ctx, source.GetName())
+
+	if existing.GetState() == source.GetState() {
+		return
+	}
+	if _, err := m.to.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+		ApiVersion: &rpc.ApiVersion{Name: name, State: source.GetState()},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"state"}},
+	}); err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+	if existing != nil {
+		m.report("updated", name)
+	}
+}
+
+func (m *mirror) mirrorSpec(ctx context.Context, spec *rpc.ApiSpec) {
+	name := m.rename(spec.GetName())
+	source, err := m.specRevisions(ctx, m.from, spec.GetName())
+	if err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+	existing, err := m.specRevisions(ctx, m.to, name)
+	if err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+
+	// Revisions are matched by the hashes of their contents.
+	start := revisionsToCreate(specHashes(source), specHashes(existing))
+	copies := make(map[string]*rpc.ApiSpec, len(existing))
+	for _, r := range existing {
+		copies[r.GetHash()] = r
+	}
+	for _, r := range source[:start] {
+		if c, ok := copies[r.GetHash()]; ok {
+			m.revisions[r.GetName()] = c.GetName()
+		}
+	}
+
+	latest := (*rpc.ApiSpec)(nil)
+	if len(existing) > 0 {
+		latest = existing[len(existing)-1]
+	}
+	fields := []string{"filename", "description", "mime_type", "source_uri", "labels", "annotations"}
+	for _, r := range source[start:] {
+		contents, err := m.from.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: r.GetName()})
+		if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		// Contents are returned uncompressed.
+		data := contents.GetData()
+		if mimetypes.IsCompressed(r.GetMimeType(), mimetypes.GZip) {
+			if data, err = core.GZippedBytes(data); err != nil {
+				m.fail(ctx, name, err)
+				return
+			}
+		}
+		want := specFields(name, r)
+		want.Contents = data
+		created, err := m.to.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+			ApiSpec:      want,
+			UpdateMask:   &fieldmaskpb.FieldMask{Paths: append(fields, "contents")},
+			AllowMissing: true,
+		})
+		if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		latest = created
+		m.revisions[r.GetName()] = name + "@" + created.GetRevisionId()
+		m.report("created", m.revisions[r.GetName()])
+	}
+
+	// The latest revision can have changed without new contents.
+	if len(source) > 0 && latest != nil {
+		want := specFields(name, source[len(source)-1])
+		if !proto.Equal(want, specFields(name, latest)) {
+			if _, err := m.to.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+				ApiSpec:    want,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: fields},
+			}); err != nil {
+				m.fail(ctx, name, err)
+				return
+			}
+			m.report("updated", name)
+		}
+	}
+
+	tags := make(map[string]bool)
+	for _, r := range existing {
+		for _, tag := range r.GetRevisionTags() {
+			tags[r.GetName()+"@"+tag] = true
+		}
+	}
+	for _, r := range source {
+		copy, ok := m.revisions[r.GetName()]
+		if !ok {
+			continue
+		}
+		for _, tag := range r.GetRevisionTags() {
+			if tags[copy+"@"+tag] {
+				continue
+			}
+			if _, err := m.to.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{Name: copy, Tag: tag}); err != nil {
+				m.fail(ctx, copy, err)
+				continue
+			}
+			m.report("tagged", name+"@"+tag)
+		}
+	}
+
+	m.mirrorArtifacts(ctx, spec.GetName())
+}
+
+// specRevisions returns the revisions of a spec from the oldest to the newest.
+func (m *mirror) specRevisions(ctx context.Context, client connection.Client, name string) ([]*rpc.ApiSpec, error) {
+	var revisions []*rpc.ApiSpec
+	it := client.ListApiSpecRevisions(ctx, &rpc.ListApiSpecRevisionsRequest{Name: name})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if isNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		revisions = append([]*rpc.ApiSpec{r}, revisions...)
+	}
+	return revisions, nil
+}
+
+func specHashes(revisions []*rpc.ApiSpec) []string {
+	hashes := make([]string, len(revisions))
+	for i, r := range revisions {
+		hashes[i] = r.GetHash()
+	}
+	return hashes
+}
+
+func specFields(name string, s *rpc.ApiSpec) *rpc.ApiSpec {
+	return &rpc.ApiSpec{
+		Name:        name,
+		Filename:    s.GetFilename(),
+		Description: s.GetDescription(),
+		MimeType:    s.GetMimeType(),
+		SourceUri:   s.GetSourceUri(),
+		Labels:      s.GetLabels(),
+		Annotations: s.GetAnnotations(),
+	}
+}
+
+func (m *mirror) mirrorDeployment(ctx context.Context, deployment *rpc.ApiDeployment) {
+	name := m.rename(deployment.GetName())
+	source, err := m.deploymentRevisions(ctx, m.from, deployment.GetName())
+	if err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+	existing, err := m.deploymentRevisions(ctx, m.to, name)
+	if err != nil {
+		m.fail(ctx, name, err)
+		return
+	}
+
+	// Revisions are created by changes to the spec revision and endpoint, so
+	// they are matched by those fields, with spec revisions of the copy.
+	sourceKeys := make([]string, len(source))
+	for i, r := range source {
+		sourceKeys[i] = m.renameReference(r.GetApiSpecRevision()) + " " + r.GetEndpointUri()
+	}
+	existingKeys := make([]string, len(existing))
+	copies := make(map[string]*rpc.ApiDeployment, len(existing))
+	for i, r := range existing {
+		existingKeys[i] = r.GetApiSpecRevision() + " " + r.GetEndpointUri()
+		copies[existingKeys[i]] = r
+	}
+	start := revisionsToCreate(sourceKeys, existingKeys)
+	for i, r := range source[:start] {
+		if c, ok := copies[sourceKeys[i]]; ok {
+			m.revisions[r.GetName()] = c.GetName()
+		}
+	}
+
+	latest := (*rpc.ApiDeployment)(nil)
+	if len(existing) > 0 {
+		latest = existing[len(existing)-1]
+	}
+	fields := []string{"display_name", "description", "api_spec_revision", "endpoint_uri",
+		"external_channel_uri", "intended_audience", "access_guidance", "labels", "annotations"}
+	for _, r := range source[start:] {
+		created, err := m.to.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+			ApiDeployment: m.deploymentFields(name, r),
+			UpdateMask:    &fieldmaskpb.FieldMask{Paths: fields},
+			AllowMissing:  true,
+		})
+		if err != nil {
+			m.fail(ctx, name, err)
+			return
+		}
+		latest = created
+		m.revisions[r.GetName()] = name + "@" + created.GetRevisionId()
+		m.report("created", m.revisions[r.GetName()])
+	}
+
+	// The latest revision can have changed without a new revision.
+	if len(source) > 0 && latest != nil {
+		want := m.deploymentFields(name, source[len(source)-1])
+		have := m.deploymentFields(name, latest)
+		have.ApiSpecRevision = latest.GetApiSpecRevision()
+		if !proto.Equal(want, have) {
+			if _, err := m.to.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+				ApiDeployment: want,
+				UpdateMask:    &fieldmaskpb.FieldMask{Paths: fields},
+			}); err != nil {
+				m.fail(ctx, name, err)
+				return
+			}
+			m.report("updated", name)
+		}
+	}
+
+	tags := make(map[string]bool)
+	for _, r := range existing {
+		for _, tag := range r.GetRevisionTags() {
+			tags[r.GetName()+"@"+tag] = true
+		}
+	}
+	for _, r := range source {
+		copy, ok := m.revisions[r.GetName()]
+		if !ok {
+			continue
+		}
+		for _, tag := range r.GetRevisionTags() {
+			if tags[copy+"@"+tag] {
+				continue
+			}
+			if _, err := m.to.TagApiDeploymentRevision(ctx, &rpc.TagApiDeploymentRevisionRequest{Name: copy, Tag: tag}); err != nil {
+				m.fail(ctx, copy, err)
+				continue
+			}
+			m.report("tagged", name+"@"+tag)
+		}
+	}
+}
+
+// deploymentRevisions returns the revisions of a deployment from the oldest
+// to the newest.
+func (m *mirror) deploymentRevisions(ctx context.Context, client connection.Client, name string) ([]*rpc.ApiDeployment, error) {
+	var revisions []*rpc.ApiDeployment
+	it := client.ListApiDeploymentRevisions(ctx, &rpc.ListApiDeploymentRevisionsRequest{Name: name})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if isNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		revisions = append([]*rpc.ApiDeployment{r}, revisions...)
+	}
+	return revisions, nil
+}
+
+func (m *mirror) deploymentFields(name string, d *rpc.ApiDeployment) *rpc.ApiDeployment {
+	return &rpc.ApiDeployment{
+		Name:               name,
+		DisplayName:        d.GetDisplayName(),
+		Description:        d.GetDescription(),
+		ApiSpecRevision:    m.renameReference(d.GetApiSpecRevision()),
+		EndpointUri:        d.GetEndpointUri(),
+		ExternalChannelUri: d.GetExternalChannelUri(),
+		IntendedAudience:   d.GetIntendedAudience(),
+		AccessGuidance:     d.GetAccessGuidance(),
+		Labels:             d.GetLabels(),
+		Annotations:        d.GetAnnotations(),
+	}
+}
+
+// mirrorArtifacts copies the artifacts of a resource. Artifacts are matched
+// by their hashes.
+func (m *mirror) mirrorArtifacts(ctx context.Context, parent string) {
+	it := m.from.ListArtifacts(ctx, &rpc.ListArtifactsRequest{Parent: parent})
+	for {
+		artifact, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			m.fail(ctx, m.rename(parent), err)
+			return
+		}
+		if !m.selected(artifact.GetName()) {
+			continue
+		}
+
+		name := m.rename(artifact.GetName())
+		existing, err := m.to.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+		if err != nil && !isNotFound(err) {
+			m.fail(ctx, name, err)
+			continue
+		}
+		if existing != nil && existing.GetHash() == artifact.GetHash() &&
+			proto.Equal(artifactFields(existing), artifactFields(artifact)) {
+			continue
+		}
+
+		contents, err := m.from.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: artifact.GetName()})
+		if err != nil {
+			m.fail(ctx, name, err)
+			continue
+		}
+		want := artifactFields(artifact)
+		want.Name = name
+		want.Contents = contents.GetData()
+		if err := core.SetArtifact(ctx, m.to, want); err != nil {
+			m.fail(ctx, name, err)
+			continue
+		}
+		m.report(verb(existing == nil), name)
+	}
+}
+
+func artifactFields(a *rpc.Artifact) *rpc.Artifact {
+	return &rpc.Artifact{
+		MimeType:    a.GetMimeType(),
+		Labels:      a.GetLabels(),
+		Annotations: a.GetAnnotations(),
+	}
+}
+
+func equalPolicies(a, b []*rpc.RetentionPolicy) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func verb(created bool) string {
+	if created {
+		return "created"
+	}
+	return "updated"
+}
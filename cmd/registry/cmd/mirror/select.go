@@ -0,0 +1,125 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// selector selects resources by their names relative to their location,
+// like "apis/petstore/versions/v1" or "artifacts/summary".
+type selector struct {
+	include [][]string
+	exclude [][]string
+}
+
+func newSelector(include, exclude []string) (*selector, error) {
+	s := &selector{}
+	for _, p := range include {
+		segments, err := patternSegments(p)
+		if err != nil {
+			return nil, err
+		}
+		s.include = append(s.include, segments)
+	}
+	for _, p := range exclude {
+		segments, err := patternSegments(p)
+		if err != nil {
+			return nil, err
+		}
+		s.exclude = append(s.exclude, segments)
+	}
+	return s, nil
+}
+
+func patternSegments(pattern string) ([]string, error) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
+	}
+	return segments, nil
+}
+
+// selected returns true if a resource should be mirrored. A resource is
+// included if it, one of its parents, or one of its children matches an
+// include pattern, so that parents of included resources are mirrored too.
+// It is excluded if it or one of its parents matches an exclude pattern.
+func (s *selector) selected(name string) bool {
+	segments := strings.Split(name, "/")
+	for _, p := range s.exclude {
+		if len(p) <= len(segments) && matchPrefix(p, segments) {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, p := range s.include {
+		if matchPrefix(p, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix returns true if the shorter of a pattern and a name matches the
+// start of the other.
+func matchPrefix(pattern, segments []string) bool {
+	n := len(pattern)
+	if len(segments) < n {
+		n = len(segments)
+	}
+	for i := 0; i < n; i++ {
+		if ok, _ := path.Match(pattern[i], segments[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// revisionsToCreate returns the index of the first source revision that must
+// be created in the destination. Revisions are identified by keys, such as
+// content hashes, and listed from the oldest to the newest. If the destination
+// revisions start the source history, the rest are created. Otherwise the
+// revisions after the last one that matches the newest destination revision
+// are created; if none matches, all of them are.
+func revisionsToCreate(source, destination []string) int {
+	if len(destination) == 0 {
+		return 0
+	}
+	if len(destination) <= len(source) {
+		prefix := true
+		for i := range destination {
+			if destination[i] != source[i] {
+				prefix = false
+				break
+			}
+		}
+		if prefix {
+			return len(destination)
+		}
+	}
+	latest := destination[len(destination)-1]
+	for i := len(source) - 1; i >= 0; i-- {
+		if source[i] == latest {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"testing"
+
+	"github.com/apigee/registry/server/registry/names"
+)
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		desc    string
+		include []string
+		exclude []string
+		want    map[string]bool
+	}{
+		{
+			desc: "everything",
+			want: map[string]bool{
+				"apis/petstore":          true,
+				"artifacts/summary":      true,
+				"apis/petstore/versions": true,
+			},
+		},
+		{
+			desc:    "included API",
+			include: []string{"apis/petstore"},
+			want: map[string]bool{
+				"apis/petstore":                        true,
+				"apis/petstore/versions/v1/specs/spec": true,
+				"apis/bookstore":                       false,
+				"artifacts/summary":                    false,
+			},
+		},
+		{
+			desc:    "included artifacts with parents",
+			include: []string{"apis/*/versions/*/specs/*/artifacts/lint-*"},
+			want: map[string]bool{
+				"apis/petstore":                                         true,
+				"apis/petstore/versions/v1/specs/spec":                  true,
+				"apis/petstore/versions/v1/specs/spec/artifacts/lint-x": true,
+				"apis/petstore/versions/v1/specs/spec/artifacts/score":  false,
+				"apis/petstore/versions/v1/artifacts/lint-x":            false,
+			},
+		},
+		{
+			desc:    "excluded resources and their children",
+			exclude: []string{"apis/test-*", "apis/*/deployments/*"},
+			want: map[string]bool{
+				"apis/petstore":                              true,
+				"apis/test-api":                              false,
+				"apis/test-api/versions/v1":                  false,
+				"apis/petstore/deployments/prod":             false,
+				"apis/petstore/deployments/prod/artifacts/x": false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			s, err := newSelector(test.include, test.exclude)
+			if err != nil {
+				t.Fatalf("newSelector() returned error: %s", err)
+			}
+			for name, want := range test.want {
+				if got := s.selected(name); got != want {
+					t.Errorf("selected(%q) returned %t, want %t", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorErrors(t *testing.T) {
+	if _, err := newSelector([]string{"apis/[petstore"}, nil); err == nil {
+		t.Errorf("newSelector() with an invalid pattern succeeded, want error")
+	}
+}
+
+func TestRevisionsToCreate(t *testing.T) {
+	tests := []struct {
+		desc        string
+		source      []string
+		destination []string
+		want        int
+	}{
+		{"new resource", []string{"a", "b"}, nil, 0},
+		{"up to date", []string{"a", "b"}, []string{"a", "b"}, 2},
+		{"partial copy", []string{"a", "b", "c"}, []string{"a"}, 1},
+		{"partial copy of a rollback", []string{"a", "b", "a"}, []string{"a"}, 1},
+		{"pruned destination", []string{"a", "b", "c"}, []string{"b"}, 2},
+		{"diverged destination", []string{"a", "b"}, []string{"x"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := revisionsToCreate(test.source, test.destination); got != test.want {
+				t.Errorf("revisionsToCreate(%v, %v) returned %d, want %d", test.source, test.destination, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	m := &mirror{
+		source:    names.Project{ProjectID: "staging"},
+		target:    names.Project{ProjectID: "prod"},
+		revisions: map[string]string{"projects/staging/locations/global/apis/a/versions/v/specs/s@1111aaaa": "projects/prod/locations/global/apis/a/versions/v/specs/s@2222bbbb"},
+	}
+	tests := []struct {
+		name, want string
+	}{
+		{"projects/staging/locations/global/apis/a", "projects/prod/locations/global/apis/a"},
+		{"projects/staging/locations/global/apis/a/versions/v/specs/s@1111aaaa", "projects/prod/locations/global/apis/a/versions/v/specs/s@2222bbbb"},
+		{"projects/staging/locations/global/apis/a/versions/v/specs/s@latest", "projects/prod/locations/global/apis/a/versions/v/specs/s@latest"},
+		{"projects/other/locations/global/apis/a", "projects/other/locations/global/apis/a"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := m.renameReference(test.name); got != test.want {
+			t.Errorf("renameReference(%q) returned %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if got, want := relative("projects/staging/locations/global/apis/a/artifacts/x"), "apis/a/artifacts/x"; got != want {
+		t.Errorf("relative() returned %q, want %q", got, want)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
@@ -74,13 +75,15 @@ func Command(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to fetch manifest")
 			}
 
-			projectID, err := core.ProjectID(manifestName)
+			// Manifests apply to resources in the location that contains them.
+			name, err := names.ParseArtifact(manifestName)
 			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to extract project ID")
+				log.FromContext(ctx).WithError(err).Fatal("Failed to parse manifest name")
 			}
+			location := names.Project{ProjectID: name.ProjectID()}.Location(name.LocationID())
 
 			log.Debug(ctx, "Generating the list of actions...")
-			actions := controller.ProcessManifest(ctx, client, projectID, manifest)
+			actions := controller.ProcessManifest(ctx, client, location, manifest)
 
 			// The monitoring metrics/dashboards are built on top of the format of the log messages here.
 			// Check the metric filters before making any changes to the format.
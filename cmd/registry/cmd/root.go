@@ -19,26 +19,39 @@ import (
 	"fmt"
 
 	"github.com/apigee/registry/cmd/registry/cmd/annotate"
+	"github.com/apigee/registry/cmd/registry/cmd/apply"
+	"github.com/apigee/registry/cmd/registry/cmd/check"
 	"github.com/apigee/registry/cmd/registry/cmd/compute"
+	"github.com/apigee/registry/cmd/registry/cmd/config"
 	"github.com/apigee/registry/cmd/registry/cmd/delete"
+	"github.com/apigee/registry/cmd/registry/cmd/diff"
 	"github.com/apigee/registry/cmd/registry/cmd/export"
 	"github.com/apigee/registry/cmd/registry/cmd/get"
+	"github.com/apigee/registry/cmd/registry/cmd/graph"
+	"github.com/apigee/registry/cmd/registry/cmd/imports"
 	"github.com/apigee/registry/cmd/registry/cmd/index"
 	"github.com/apigee/registry/cmd/registry/cmd/label"
 	"github.com/apigee/registry/cmd/registry/cmd/list"
+	"github.com/apigee/registry/cmd/registry/cmd/mirror"
 	"github.com/apigee/registry/cmd/registry/cmd/resolve"
 	"github.com/apigee/registry/cmd/registry/cmd/upload"
 	"github.com/apigee/registry/cmd/registry/cmd/vocabulary"
+	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
 func Command(ctx context.Context) *cobra.Command {
+	var contextName string
 	var cmd = &cobra.Command{
 		Use:   "registry",
 		Short: "A simple and eclectic utility for working with the API Registry",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			connection.SelectContext(contextName)
+		},
 	}
+	cmd.PersistentFlags().StringVar(&contextName, "context", "", "Name of the configured context to connect with (see \"registry config\")")
 
 	// Bind a logger instance to the local context with metadata for outbound requests.
 	logger := log.NewLogger(log.DebugLevel)
@@ -47,14 +60,21 @@ func Command(ctx context.Context) *cobra.Command {
 	})
 
 	cmd.AddCommand(annotate.Command(ctx))
+	cmd.AddCommand(apply.Command(ctx))
+	cmd.AddCommand(check.Command(ctx))
 	cmd.AddCommand(compute.Command(ctx))
+	cmd.AddCommand(config.Command(ctx))
 	cmd.AddCommand(resolve.Command(ctx))
 	cmd.AddCommand(delete.Command(ctx))
+	cmd.AddCommand(diff.Command(ctx))
 	cmd.AddCommand(export.Command(ctx))
 	cmd.AddCommand(get.Command(ctx))
+	cmd.AddCommand(graph.Command(ctx))
+	cmd.AddCommand(imports.Command(ctx))
 	cmd.AddCommand(index.Command(ctx))
 	cmd.AddCommand(label.Command(ctx))
 	cmd.AddCommand(list.Command(ctx))
+	cmd.AddCommand(mirror.Command(ctx))
 	cmd.AddCommand(upload.Command(ctx))
 	cmd.AddCommand(vocabulary.Command(ctx))
 
@@ -16,8 +16,23 @@ package bulk
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func Command(ctx context.Context) *cobra.Command {
@@ -31,6 +46,203 @@ func Command(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(protosCommand(ctx))
 
 	cmd.PersistentFlags().String("project-id", "", "Project ID to use for each upload")
-	_ = cmd.MarkFlagRequired("project-id")
+	cmd.PersistentFlags().String("location", names.DefaultLocation, "Location to use for each upload")
+	cmd.PersistentFlags().Bool("dry-run", false, "Print the specs that would be created, updated, or deleted without changing them")
+	cmd.PersistentFlags().Bool("delete", false, "Delete specs that were uploaded from the same sources and whose source files are gone")
 	return cmd
 }
+
+// uploader uploads the specs of a bulk upload and counts the results. It is
+// shared by concurrent upload tasks.
+type uploader struct {
+	client connection.Client
+	dryRun bool
+	out    io.Writer
+
+	mu      sync.Mutex
+	counts  map[string]int
+	sources map[string]bool // names of specs that have source files
+}
+
+func newUploader(cmd *cobra.Command, client connection.Client) (*uploader, error) {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return nil, err
+	}
+	return &uploader{
+		client:  client,
+		dryRun:  dryRun,
+		out:     cmd.OutOrStdout(),
+		counts:  make(map[string]int),
+		sources: make(map[string]bool),
+	}, nil
+}
+
+// pastTense maps the actions of an uploader to the words that report them.
+var pastTense = map[string]string{
+	"create": "created",
+	"update": "updated",
+	"skip":   "skipped",
+	"fail":   "failed",
+	"delete": "deleted",
+}
+
+func (u *uploader) report(action, name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.counts[action]++
+	switch {
+	case action == "skip" || action == "fail":
+	case u.dryRun:
+		fmt.Fprintf(u.out, "would %s %s\n", action, name)
+	default:
+		fmt.Fprintf(u.out, "%s %s\n", pastTense[action], name)
+	}
+}
+
+// addSource records that a spec has a source file, so that it isn't deleted.
+func (u *uploader) addSource(name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.sources[name] = true
+}
+
+func (u *uploader) fail(ctx context.Context, name string, err error) {
+	log.FromContext(ctx).WithError(err).Errorf("Failed to upload %s", name)
+	u.report("fail", name)
+}
+
+// upload creates or updates a spec unless the registry already has its
+// contents, which are compared by hash. The hash of a spec is the SHA-256
+// checksum of its contents, after they are uncompressed if they are gzipped.
+// Specs with the same contents but another source URI, like those uploaded
+// before source URIs were set, only have their source URIs updated.
+// The API and version of a new spec are created with createParents, and
+// only the errors that it returns are returned. Other failures are counted.
+func (u *uploader) upload(ctx context.Context, spec *rpc.ApiSpec, hash string, createParents func(context.Context) error) error {
+	name := spec.GetName()
+	u.addSource(name)
+
+	existing, err := u.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+	if err != nil && status.Code(err) != codes.NotFound {
+		u.fail(ctx, name, err)
+		return nil
+	}
+	action := "create"
+	var mask *fieldmaskpb.FieldMask
+	if existing != nil {
+		action = "update"
+		if existing.GetHash() == hash {
+			if existing.GetSourceUri() == spec.GetSourceUri() {
+				log.Debugf(ctx, "Matched already uploaded spec %s", name)
+				u.report("skip", name)
+				return nil
+			}
+			mask = &fieldmaskpb.FieldMask{Paths: []string{"source_uri"}}
+		}
+	}
+	if u.dryRun {
+		u.report(action, name)
+		return nil
+	}
+
+	if existing == nil {
+		if err := createParents(ctx); err != nil {
+			return err
+		}
+	}
+	if _, err := u.client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec:      spec,
+		UpdateMask:   mask,
+		AllowMissing: true,
+	}); err != nil {
+		u.fail(ctx, name, err)
+		return nil
+	}
+	u.report(action, name)
+	return nil
+}
+
+// deleteMissing deletes the specs of a location that have no source files
+// and that could have been produced by the upload, which is checked by
+// produced.
+func (u *uploader) deleteMissing(ctx context.Context, location names.Location, produced func(spec *rpc.ApiSpec) bool) error {
+	var missing []string
+	err := core.ListSpecs(ctx, u.client, location.Api("-").Version("-").Spec("-"), "", func(spec *rpc.ApiSpec) {
+		if produced(spec) && !u.sources[spec.GetName()] {
+			missing = append(missing, spec.GetName())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range missing {
+		if !u.dryRun {
+			if err := u.client.DeleteApiSpec(ctx, &rpc.DeleteApiSpecRequest{Name: name}); err != nil {
+				log.FromContext(ctx).WithError(err).Errorf("Failed to delete %s", name)
+				u.report("fail", name)
+				continue
+			}
+		}
+		u.report("delete", name)
+	}
+	return nil
+}
+
+// skipHidden returns filepath.SkipDir for hidden directories below an
+// uploaded directory, like .git or the revisions written by export files.
+func skipHidden(directory, path string, info os.FileInfo) error {
+	if info.IsDir() && path != directory && strings.HasPrefix(info.Name(), ".") {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// sourceRoot returns the prefix of the source URIs of specs uploaded from a
+// directory. It is the base URI if one is set, or else the file URL of the
+// directory as it was named, so that specs uploaded from a relative path
+// keep their source URIs wherever the directory is checked out.
+func sourceRoot(baseURI, directory string) string {
+	if baseURI != "" {
+		return strings.TrimSuffix(baseURI, "/")
+	}
+	path := filepath.ToSlash(filepath.Clean(directory))
+	if filepath.IsAbs(directory) {
+		return (&url.URL{Scheme: "file", Path: path}).String()
+	}
+	return (&url.URL{Scheme: "file", Opaque: path}).String()
+}
+
+// underRoot returns true if a source URI is below one of a list of roots.
+func underRoot(sourceURI string, roots []string) bool {
+	for _, root := range roots {
+		if strings.HasPrefix(sourceURI, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// finish deletes specs without source files if the delete flag is set,
+// prints a summary, and fails if any spec failed.
+func (u *uploader) finish(ctx context.Context, cmd *cobra.Command, location names.Location, produced func(spec *rpc.ApiSpec) bool) {
+	del, err := cmd.Flags().GetBool("delete")
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get delete from flags")
+	}
+	if del {
+		if err := u.deleteMissing(ctx, location, produced); err != nil {
+			log.FromContext(ctx).WithError(err).Fatal("Failed to delete specs")
+		}
+	}
+
+	prefix := ""
+	if u.dryRun {
+		prefix = "Dry run: "
+	}
+	fmt.Fprintf(u.out, "%s%d created, %d updated, %d skipped, %d failed, %d deleted\n", prefix,
+		u.counts["create"], u.counts["update"], u.counts["skip"], u.counts["fail"], u.counts["delete"])
+	if u.counts["fail"] > 0 {
+		log.Fatalf(ctx, "Failed to upload %d specs", u.counts["fail"])
+	}
+}
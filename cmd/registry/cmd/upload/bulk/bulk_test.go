@@ -0,0 +1,220 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOpenAPIIncrementalUpload(t *testing.T) {
+	const project = "upload-bulk-demo"
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	if _, err := adminClient.CreateProject(ctx, &rpc.CreateProjectRequest{
+		ProjectId: project,
+		Project:   &rpc.Project{},
+	}); err != nil {
+		t.Fatalf("Setup: Failed to create test project: %s", err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+	}()
+
+	dir := t.TempDir()
+	write := func(path, contents string) {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Setup: Failed to create directory: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("Setup: Failed to write spec: %s", err)
+		}
+	}
+	uploadDir := func(dir string, flags ...string) []string {
+		cmd := Command(ctx)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(append([]string{"openapi", dir, "--project-id", project}, flags...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() with args %v returned error: %s", flags, err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		sort.Strings(lines)
+		return lines
+	}
+	upload := func(flags ...string) []string {
+		return uploadDir(dir, flags...)
+	}
+	specs := func() []string {
+		var names []string
+		it := client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: "projects/" + project + "/locations/global/apis/-/versions/-"})
+		for {
+			spec, err := it.Next()
+			if err == iterator.Done {
+				break
+			} else if err != nil {
+				t.Fatalf("Failed to list specs: %s", err)
+			}
+			names = append(names, spec.GetName())
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	const (
+		a     = "projects/upload-bulk-demo/locations/global/apis/a/versions/v1/specs/openapi.yaml"
+		b     = "projects/upload-bulk-demo/locations/global/apis/b/versions/v1/specs/openapi.yaml"
+		c     = "projects/upload-bulk-demo/locations/global/apis/c/versions/v1/specs/openapi.yaml"
+		other = "projects/upload-bulk-demo/locations/global/apis/a/versions/v1/specs/other.yaml"
+	)
+	write("a/v1/openapi.yaml", "openapi: 3.0.0\n")
+	write("b/v1/openapi.yaml", "openapi: 3.0.1\n")
+
+	steps := []struct {
+		desc   string
+		before func()
+		flags  []string
+		want   []string
+		specs  []string
+	}{
+		{
+			desc:  "dry run of new specs",
+			flags: []string{"--dry-run"},
+			want:  []string{"Dry run: 2 created, 0 updated, 0 skipped, 0 failed, 0 deleted", "would create " + a, "would create " + b},
+		},
+		{
+			desc:  "new specs",
+			want:  []string{"2 created, 0 updated, 0 skipped, 0 failed, 0 deleted", "created " + a, "created " + b},
+			specs: []string{a, b},
+		},
+		{
+			desc:  "unchanged specs",
+			want:  []string{"0 created, 0 updated, 2 skipped, 0 failed, 0 deleted"},
+			specs: []string{a, b},
+		},
+		{
+			desc:  "unchanged specs from another source",
+			flags: []string{"--base-uri", "https://example.com/specs/"},
+			want:  []string{"0 created, 2 updated, 0 skipped, 0 failed, 0 deleted", "updated " + a, "updated " + b},
+			specs: []string{a, b},
+		},
+		{
+			desc:  "unchanged specs from the original source",
+			want:  []string{"0 created, 2 updated, 0 skipped, 0 failed, 0 deleted", "updated " + a, "updated " + b},
+			specs: []string{a, b},
+		},
+		{
+			desc:   "changed spec",
+			before: func() { write("a/v1/openapi.yaml", "openapi: 3.0.2\n") },
+			want:   []string{"0 created, 1 updated, 1 skipped, 0 failed, 0 deleted", "updated " + a},
+			specs:  []string{a, b},
+		},
+		{
+			desc: "missing spec without deletion",
+			before: func() {
+				if err := os.RemoveAll(filepath.Join(dir, "b")); err != nil {
+					t.Fatalf("Setup: Failed to remove spec: %s", err)
+				}
+			},
+			want:  []string{"0 created, 0 updated, 1 skipped, 0 failed, 0 deleted"},
+			specs: []string{a, b},
+		},
+		{
+			desc: "dry run of deletion",
+			before: func() {
+				// Specs from other directories and other tools are not deleted.
+				otherDir := t.TempDir()
+				if err := os.MkdirAll(filepath.Join(otherDir, "c/v1"), 0755); err != nil {
+					t.Fatalf("Setup: Failed to create directory: %s", err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(otherDir, "c/v1/openapi.yaml"), []byte("openapi: 3.0.3\n"), 0644); err != nil {
+					t.Fatalf("Setup: Failed to write spec: %s", err)
+				}
+				uploadDir(otherDir)
+				if _, err := client.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+					Parent:    "projects/" + project + "/locations/global/apis/a/versions/v1",
+					ApiSpecId: "other.yaml",
+					ApiSpec:   &rpc.ApiSpec{MimeType: "application/x.openapi;version=3", Contents: []byte("openapi: 3.0.0\n")},
+				}); err != nil {
+					t.Fatalf("Setup: Failed to create spec: %s", err)
+				}
+			},
+			flags: []string{"--delete", "--dry-run"},
+			want:  []string{"Dry run: 0 created, 0 updated, 1 skipped, 0 failed, 1 deleted", "would delete " + b},
+			specs: []string{a, other, b, c},
+		},
+		{
+			desc:  "deletion",
+			flags: []string{"--delete"},
+			want:  []string{"0 created, 0 updated, 1 skipped, 0 failed, 1 deleted", "deleted " + b},
+			specs: []string{a, other, c},
+		},
+	}
+
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		if diff := cmp.Diff(step.want, upload(step.flags...)); diff != "" {
+			t.Errorf("%s: unexpected output (-want +got):\n%s", step.desc, diff)
+		}
+		if diff := cmp.Diff(step.specs, specs()); diff != "" {
+			t.Errorf("%s: unexpected specs (-want +got):\n%s", step.desc, diff)
+		}
+	}
+}
+
+func TestSourceRoot(t *testing.T) {
+	tests := []struct {
+		baseURI   string
+		directory string
+		want      string
+	}{
+		{"https://example.com/specs/", "specs", "https://example.com/specs"},
+		{"", "specs", "file:specs"},
+		{"", "./specs/", "file:specs"},
+		{"", "/tmp/specs", "file:///tmp/specs"},
+	}
+	for _, test := range tests {
+		if got := sourceRoot(test.baseURI, test.directory); got != test.want {
+			t.Errorf("sourceRoot(%q, %q) returned %q, want %q", test.baseURI, test.directory, got, test.want)
+		}
+	}
+}
@@ -18,11 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 
 	discovery "github.com/google/gnostic/discovery"
@@ -33,9 +36,9 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 		Use:   "discovery",
 		Short: "Bulk-upload API Discovery documents from the Google API Discovery service",
 		Run: func(cmd *cobra.Command, args []string) {
-			projectID, err := cmd.Flags().GetString("project-id")
+			projectID, locationID, err := core.ProjectAndLocation(cmd)
 			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
 			}
 
 			client, err := connection.NewClient(ctx)
@@ -43,26 +46,37 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
-			// create a queue for upload tasks and wait for the workers to finish after filling it.
-			taskQueue, wait := core.WorkerPool(ctx, 64)
-			defer wait()
+			u, err := newUploader(cmd, client)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get flags")
+			}
 
 			discoveryResponse, err := discovery.FetchList()
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to fetch discovery list")
 			}
 
+			// create a queue for upload tasks and wait for the workers to finish after filling it.
+			taskQueue, wait := core.WorkerPool(ctx, 64)
 			// Create an upload job for each API.
 			for _, api := range discoveryResponse.APIs {
 				taskQueue <- &uploadDiscoveryTask{
-					client:    client,
-					path:      api.DiscoveryRestURL,
-					projectID: projectID,
-					apiID:     sanitize(api.Name),
-					versionID: sanitize(api.Version),
-					specID:    "discovery.json",
+					client:     client,
+					uploader:   u,
+					path:       api.DiscoveryRestURL,
+					projectID:  projectID,
+					locationID: locationID,
+					apiID:      sanitize(api.Name),
+					versionID:  sanitize(api.Version),
+					specID:     "discovery.json",
 				}
 			}
+			wait()
+
+			location := names.Project{ProjectID: projectID}.Location(locationID)
+			u.finish(ctx, cmd, location, func(spec *rpc.ApiSpec) bool {
+				return core.IsDiscovery(spec.GetMimeType()) && fromDiscoveryService(spec.GetSourceUri())
+			})
 		},
 	}
 
@@ -70,13 +84,15 @@ func discoveryCommand(ctx context.Context) *cobra.Command {
 }
 
 type uploadDiscoveryTask struct {
-	client    connection.Client
-	path      string
-	projectID string
-	apiID     string
-	versionID string
-	specID    string
-	contents  []byte
+	client     connection.Client
+	uploader   *uploader
+	path       string
+	projectID  string
+	locationID string
+	apiID      string
+	versionID  string
+	specID     string
+	contents   []byte
 }
 
 func (task *uploadDiscoveryTask) String() string {
@@ -88,22 +104,21 @@ func (task *uploadDiscoveryTask) Run(ctx context.Context) error {
 	// Fetch the contents of the discovery doc.
 	// Do this first in case the doc URL is invalid; we skip APIs with these errors.
 	if err := task.fetchDiscoveryDoc(); err != nil {
-		log.FromContext(ctx).WithError(err).Error("Failed to download discovery doc")
+		task.uploader.addSource(task.specName())
+		task.uploader.fail(ctx, task.specName(), fmt.Errorf("failed to download discovery doc: %s", err))
 		return nil
 	}
+	// Create or update the spec as needed.
+	return task.createOrUpdateSpec(ctx)
+}
+
+func (task *uploadDiscoveryTask) createParents(ctx context.Context) error {
 	// If the API does not exist, create it.
 	if err := task.createAPI(ctx); err != nil {
 		return err
 	}
 	// If the API version does not exist, create it.
-	if err := task.createVersion(ctx); err != nil {
-		return err
-	}
-	// Create or update the spec as needed.
-	if err := task.createOrUpdateSpec(ctx); err != nil {
-		return err
-	}
-	return nil
+	return task.createVersion(ctx)
 }
 
 func (task *uploadDiscoveryTask) createAPI(ctx context.Context) error {
@@ -145,41 +160,33 @@ func (task *uploadDiscoveryTask) createVersion(ctx context.Context) error {
 	return nil
 }
 
-func (task *uploadDiscoveryTask) createOrUpdateSpec(ctx context.Context) error {
-	// Use the spec size and hash to avoid unnecessary uploads.
-	spec, err := task.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{
-		Name: task.specName(),
-	})
-
-	if err == nil && int(spec.GetSizeBytes()) == len(task.contents) && spec.GetHash() == hashForBytes(task.contents) {
-		log.Debugf(ctx, "Matched already uploaded spec %s", task.specName())
-		return nil
+// fromDiscoveryService returns true if a source URI is the URL of a document
+// served by the Discovery service, which serves documents from googleapis.com.
+func fromDiscoveryService(sourceURI string) bool {
+	u, err := url.Parse(sourceURI)
+	if err != nil {
+		return false
 	}
+	host := u.Hostname()
+	return host == "googleapis.com" || strings.HasSuffix(host, ".googleapis.com")
+}
 
+func (task *uploadDiscoveryTask) createOrUpdateSpec(ctx context.Context) error {
 	gzippedContents, err := core.GZippedBytes(task.contents)
 	if err != nil {
 		return err
 	}
 
-	request := &rpc.UpdateApiSpecRequest{
-		ApiSpec: &rpc.ApiSpec{
-			Name:      task.specName(),
-			MimeType:  core.DiscoveryMimeType("+gzip"),
-			Filename:  "discovery.json",
-			Contents:  gzippedContents,
-			SourceUri: task.path,
-		},
-		AllowMissing: true,
+	spec := &rpc.ApiSpec{
+		Name:      task.specName(),
+		MimeType:  core.DiscoveryMimeType("+gzip"),
+		Filename:  "discovery.json",
+		Contents:  gzippedContents,
+		SourceUri: task.path,
 	}
 
-	response, err := task.client.UpdateApiSpec(ctx, request)
-	if err != nil {
-		log.FromContext(ctx).WithError(err).Debugf("Error %s [contents-length: %d]", task.specName(), len(task.contents))
-	} else {
-		log.Debugf(ctx, "Updated %s", response.Name)
-	}
-
-	return nil
+	// Use the spec hash to avoid unnecessary uploads.
+	return task.uploader.upload(ctx, spec, hashForBytes(task.contents), task.createParents)
 }
 
 func (task *uploadDiscoveryTask) projectName() string {
@@ -187,7 +194,7 @@ func (task *uploadDiscoveryTask) projectName() string {
 }
 
 func (task *uploadDiscoveryTask) apiName() string {
-	return fmt.Sprintf("%s/locations/global/apis/%s", task.projectName(), task.apiID)
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
 }
 
 func (task *uploadDiscoveryTask) versionName() string {
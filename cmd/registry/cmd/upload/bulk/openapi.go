@@ -28,6 +28,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 )
 
@@ -36,11 +37,14 @@ func openAPICommand(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "openapi",
 		Short: "Bulk-upload OpenAPI descriptions from a directory of specs",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Bulk-upload OpenAPI descriptions from a directory of specs. Specs are
+read from files named openapi.yaml, openapi.json, swagger.yaml, or
+swagger.json, and specs whose contents are unchanged are skipped.`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			projectID, err := cmd.Flags().GetString("project-id")
+			projectID, locationID, err := core.ProjectAndLocation(cmd)
 			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
 			}
 
 			client, err := connection.NewClient(ctx)
@@ -48,50 +52,88 @@ func openAPICommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
+			u, err := newUploader(cmd, client)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get flags")
+			}
+			roots := make([]string, 0, len(args))
 			for _, arg := range args {
-				scanDirectoryForOpenAPI(ctx, client, projectID, baseURI, arg)
+				root := sourceRoot(baseURI, arg)
+				if err := scanDirectoryForOpenAPI(ctx, client, u, projectID, locationID, root, arg); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to walk directory")
+				}
+				roots = append(roots, root)
 			}
+			location := names.Project{ProjectID: projectID}.Location(locationID)
+			u.finish(ctx, cmd, location, func(spec *rpc.ApiSpec) bool {
+				return (core.IsOpenAPIv2(spec.GetMimeType()) || core.IsOpenAPIv3(spec.GetMimeType())) && underRoot(spec.GetSourceUri(), roots)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&baseURI, "base-uri", "", "Prefix to use for the source_uri field of each spec upload")
+	cmd.Flags().StringVar(&baseURI, "base-uri", "", "Prefix to use for the source_uri field of each spec upload (default is the file URL of the uploaded directory, relative if the directory is)")
 	return cmd
 }
 
-func scanDirectoryForOpenAPI(ctx context.Context, client connection.Client, projectID, baseURI, directory string) {
+func scanDirectoryForOpenAPI(ctx context.Context, client connection.Client, u *uploader, projectID, locationID, baseURI, directory string) error {
 	// create a queue for upload tasks and wait for the workers to finish after filling it.
 	taskQueue, wait := core.WorkerPool(ctx, 64)
 	defer wait()
 
 	// walk a directory hierarchy, uploading every API spec that matches a set of expected file names.
-	if err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
+		} else if err := skipHidden(directory, path, info); err != nil {
+			return err
 		}
 
 		task := &uploadOpenAPITask{
-			client:    client,
-			projectID: projectID,
-			baseURI:   baseURI,
-			path:      path,
-			directory: directory,
+			client:     client,
+			uploader:   u,
+			projectID:  projectID,
+			locationID: locationID,
+			baseURI:    baseURI,
+			path:       path,
+			directory:  directory,
 		}
 
-		switch {
-		case strings.HasSuffix(path, "swagger.yaml"), strings.HasSuffix(path, "swagger.json"):
-			task.version = "2"
-			taskQueue <- task
-		case strings.HasSuffix(path, "openapi.yaml"), strings.HasSuffix(path, "openapi.json"):
-			task.version = "3"
+		if task.version = OpenAPIVersion(path); task.version != "" {
 			taskQueue <- task
 		}
 
 		return nil
-	}); err != nil {
-		log.FromContext(ctx).WithError(err).Debug("Failed to walk directory")
+	})
+}
+
+// OpenAPIVersion returns the OpenAPI version of a spec file, which is
+// recognized by its name, or "" if the file isn't an OpenAPI spec.
+func OpenAPIVersion(path string) string {
+	switch {
+	case strings.HasSuffix(path, "swagger.yaml"), strings.HasSuffix(path, "swagger.json"):
+		return "2"
+	case strings.HasSuffix(path, "openapi.yaml"), strings.HasSuffix(path, "openapi.json"):
+		return "3"
+	default:
+		return ""
 	}
 }
 
+// OpenAPIIDs returns the IDs of the API, version, and spec that are uploaded
+// from a file, given the path of the file relative to the uploaded directory.
+func OpenAPIIDs(path string) (apiID, versionID, specID string, err error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return "", "", "", fmt.Errorf("invalid API path: %s", path)
+	}
+
+	apiParts := parts[0 : len(parts)-2]
+	apiPart := strings.ReplaceAll(strings.Join(apiParts, "-"), "/", "-")
+	versionPart := parts[len(parts)-2]
+	specPart := parts[len(parts)-1]
+	return sanitize(apiPart), sanitize(versionPart), sanitize(specPart), nil
+}
+
 // sanitize converts a name into a "safe" form for use as an identifier
 func sanitize(name string) string {
 	// identifiers are lower-case
@@ -108,15 +150,17 @@ func sanitize(name string) string {
 }
 
 type uploadOpenAPITask struct {
-	client    connection.Client
-	baseURI   string
-	path      string
-	directory string
-	version   string
-	projectID string
-	apiID     string // computed at runtime
-	versionID string // computed at runtime
-	specID    string // computed at runtime
+	client     connection.Client
+	uploader   *uploader
+	baseURI    string
+	path       string
+	directory  string
+	version    string
+	projectID  string
+	locationID string
+	apiID      string // computed at runtime
+	versionID  string // computed at runtime
+	specID     string // computed at runtime
 }
 
 func (task *uploadOpenAPITask) String() string {
@@ -130,38 +174,23 @@ func (task *uploadOpenAPITask) Run(ctx context.Context) error {
 	}
 	log.Infof(ctx, "Uploading apis/%s/versions/%s/specs/%s", task.apiID, task.versionID, task.specID)
 
+	// Create or update the spec as needed.
+	return task.createOrUpdateSpec(ctx)
+}
+
+func (task *uploadOpenAPITask) createParents(ctx context.Context) error {
 	// If the API does not exist, create it.
 	if err := task.createAPI(ctx); err != nil {
 		return err
 	}
 	// If the API version does not exist, create it.
-	if err := task.createVersion(ctx); err != nil {
-		return err
-	}
-	// Create or update the spec as needed.
-	if err := task.createOrUpdateSpec(ctx); err != nil {
-		return err
-	}
-	return nil
+	return task.createVersion(ctx)
 }
 
 func (task *uploadOpenAPITask) populateFields() error {
-	parts := strings.Split(task.apiPath(), "/")
-	if len(parts) < 3 {
-		return fmt.Errorf("invalid API path: %s", task.apiPath())
-	}
-
-	apiParts := parts[0 : len(parts)-2]
-	apiPart := strings.ReplaceAll(strings.Join(apiParts, "-"), "/", "-")
-	task.apiID = sanitize(apiPart)
-
-	versionPart := parts[len(parts)-2]
-	task.versionID = sanitize(versionPart)
-
-	specPart := parts[len(parts)-1]
-	task.specID = sanitize(specPart)
-
-	return nil
+	var err error
+	task.apiID, task.versionID, task.specID, err = OpenAPIIDs(task.apiPath())
+	return err
 }
 
 func (task *uploadOpenAPITask) createAPI(ctx context.Context) error {
@@ -209,42 +238,21 @@ func (task *uploadOpenAPITask) createOrUpdateSpec(ctx context.Context) error {
 		return err
 	}
 
-	// Use the spec size and hash to avoid unnecessary uploads.
-	spec, err := task.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{
-		Name: task.specName(),
-	})
-
-	if err == nil && int(spec.GetSizeBytes()) == len(contents) && spec.GetHash() == hashForBytes(contents) {
-		log.Debugf(ctx, "Matched already uploaded spec %s", task.specName())
-		return nil
-	}
-
 	gzippedContents, err := core.GZippedBytes(contents)
 	if err != nil {
 		return err
 	}
 
-	request := &rpc.UpdateApiSpecRequest{
-		ApiSpec: &rpc.ApiSpec{
-			Name:     task.specName(),
-			MimeType: core.OpenAPIMimeType("+gzip", task.version),
-			Filename: task.fileName(),
-			Contents: gzippedContents,
-		},
-		AllowMissing: true,
-	}
-	if task.baseURI != "" {
-		request.ApiSpec.SourceUri = fmt.Sprintf("%s/%s", task.baseURI, task.apiPath())
-	}
-
-	response, err := task.client.UpdateApiSpec(ctx, request)
-	if err != nil {
-		log.FromContext(ctx).WithError(err).Debugf("Error %s [contents-length: %d]", task.specName(), len(contents))
-	} else {
-		log.Debugf(ctx, "Updated %s", response.Name)
+	spec := &rpc.ApiSpec{
+		Name:      task.specName(),
+		MimeType:  core.OpenAPIMimeType("+gzip", task.version),
+		Filename:  task.fileName(),
+		Contents:  gzippedContents,
+		SourceUri: fmt.Sprintf("%s/%s", task.baseURI, task.apiPath()),
 	}
 
-	return nil
+	// Use the spec hash to avoid unnecessary uploads.
+	return task.uploader.upload(ctx, spec, hashForBytes(contents), task.createParents)
 }
 
 func (task *uploadOpenAPITask) projectName() string {
@@ -252,7 +260,7 @@ func (task *uploadOpenAPITask) projectName() string {
 }
 
 func (task *uploadOpenAPITask) apiName() string {
-	return fmt.Sprintf("%s/locations/global/apis/%s", task.projectName(), task.apiID)
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
 }
 
 func (task *uploadOpenAPITask) versionName() string {
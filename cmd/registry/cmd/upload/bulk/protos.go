@@ -27,6 +27,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 )
 
@@ -35,11 +36,14 @@ func protosCommand(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "protos",
 		Short: "Bulk-upload Protocol Buffer descriptions from a directory of specs",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Bulk-upload Protocol Buffer descriptions from a directory of specs. Each
+directory with a version-like name, like v1 or v2beta, is uploaded as a zip
+archive, and specs whose contents are unchanged are skipped.`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			projectID, err := cmd.Flags().GetString("project-id")
+			projectID, locationID, err := core.ProjectAndLocation(cmd)
 			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get project-id from flags")
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
 			}
 
 			client, err := connection.NewClient(ctx)
@@ -47,56 +51,91 @@ func protosCommand(ctx context.Context) *cobra.Command {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
 			}
 
+			u, err := newUploader(cmd, client)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get flags")
+			}
+			roots := make([]string, 0, len(args))
 			for _, arg := range args {
-				scanDirectoryForProtos(ctx, client, projectID, baseURI, arg)
+				root := sourceRoot(baseURI, arg)
+				if err := scanDirectoryForProtos(ctx, client, u, projectID, locationID, root, arg); err != nil {
+					log.FromContext(ctx).WithError(err).Fatal("Failed to walk directory")
+				}
+				roots = append(roots, root)
 			}
+			location := names.Project{ProjectID: projectID}.Location(locationID)
+			u.finish(ctx, cmd, location, func(spec *rpc.ApiSpec) bool {
+				return core.IsProto(spec.GetMimeType()) && underRoot(spec.GetSourceUri(), roots)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&baseURI, "base-uri", "", "Prefix to use for the source_uri field of each proto upload")
+	cmd.Flags().StringVar(&baseURI, "base-uri", "", "Prefix to use for the source_uri field of each proto upload (default is the file URL of the uploaded directory, relative if the directory is)")
 	return cmd
 }
 
-func scanDirectoryForProtos(ctx context.Context, client connection.Client, projectID, baseURI, directory string) {
+func scanDirectoryForProtos(ctx context.Context, client connection.Client, u *uploader, projectID, locationID, baseURI, directory string) error {
 	// create a queue for upload tasks and wait for the workers to finish after filling it.
 	taskQueue, wait := core.WorkerPool(ctx, 64)
 	defer wait()
 
-	dirPattern := regexp.MustCompile("v.*[1-9]+.*")
-	if err := filepath.Walk(directory, func(filepath string, info os.FileInfo, err error) error {
+	return filepath.Walk(directory, func(filepath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
+		} else if err := skipHidden(directory, filepath, info); err != nil {
+			return err
 		}
 
 		// Skip non-matching directories.
 		filename := path.Base(filepath)
-		if !info.IsDir() || !dirPattern.MatchString(filename) {
+		if !info.IsDir() || !protoVersionPattern.MatchString(filename) {
 			return nil
 		}
 
 		taskQueue <- &uploadProtoTask{
-			client:    client,
-			baseURI:   baseURI,
-			projectID: projectID,
-			path:      filepath,
-			directory: directory,
+			client:     client,
+			uploader:   u,
+			baseURI:    baseURI,
+			projectID:  projectID,
+			locationID: locationID,
+			path:       filepath,
+			directory:  directory,
 		}
 
 		return nil
-	}); err != nil {
-		log.FromContext(ctx).WithError(err).Debug("Failed to walk directory")
+	})
+}
+
+// protoVersionPattern matches the names of directories of Protocol Buffer
+// specs, which are versions like v1 or v2beta.
+var protoVersionPattern = regexp.MustCompile("v.*[1-9]+.*")
+
+// ProtoIDs returns the IDs of the API, version, and spec that are uploaded
+// from a directory of Protocol Buffer files, given the path of the directory
+// relative to the uploaded directory.
+func ProtoIDs(path string) (apiID, versionID, specID string, err error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || !protoVersionPattern.MatchString(parts[len(parts)-1]) {
+		return "", "", "", fmt.Errorf("invalid API path: %s", path)
 	}
+
+	apiParts := parts[0 : len(parts)-1]
+	apiPart := strings.ReplaceAll(strings.Join(apiParts, "-"), "/", "-")
+	versionPart := parts[len(parts)-1]
+	return sanitize(apiPart), sanitize(versionPart), sanitize(protosFileName), nil
 }
 
 type uploadProtoTask struct {
-	client    connection.Client
-	baseURI   string
-	projectID string
-	path      string
-	directory string
-	apiID     string // computed at runtime
-	versionID string // computed at runtime
-	specID    string // computed at runtime
+	client     connection.Client
+	uploader   *uploader
+	baseURI    string
+	projectID  string
+	locationID string
+	path       string
+	directory  string
+	apiID      string // computed at runtime
+	versionID  string // computed at runtime
+	specID     string // computed at runtime
 }
 
 func (task *uploadProtoTask) String() string {
@@ -105,35 +144,28 @@ func (task *uploadProtoTask) String() string {
 
 func (task *uploadProtoTask) Run(ctx context.Context) error {
 	// Populate API path fields using the file's path.
-	task.populateFields()
+	if err := task.populateFields(); err != nil {
+		return err
+	}
 	log.Infof(ctx, "Uploading apis/%s/versions/%s/specs/%s", task.apiID, task.versionID, task.specID)
 
+	// Create or update the spec as needed.
+	return task.createOrUpdateSpec(ctx)
+}
+
+func (task *uploadProtoTask) createParents(ctx context.Context) error {
 	// If the API does not exist, create it.
 	if err := task.createAPI(ctx); err != nil {
 		return err
 	}
 	// If the API version does not exist, create it.
-	if err := task.createVersion(ctx); err != nil {
-		return err
-	}
-	// Create or update the spec as needed.
-	if err := task.createOrUpdateSpec(ctx); err != nil {
-		return err
-	}
-	return nil
+	return task.createVersion(ctx)
 }
 
-func (task *uploadProtoTask) populateFields() {
-	parts := strings.Split(task.apiPath(), "/")
-	apiParts := parts[0 : len(parts)-1]
-	apiPart := strings.ReplaceAll(strings.Join(apiParts, "-"), "/", "-")
-	task.apiID = sanitize(apiPart)
-
-	versionPart := parts[len(parts)-1]
-	task.versionID = sanitize(versionPart)
-
-	specPart := task.fileName()
-	task.specID = sanitize(specPart)
+func (task *uploadProtoTask) populateFields() error {
+	var err error
+	task.apiID, task.versionID, task.specID, err = ProtoIDs(task.apiPath())
+	return err
 }
 
 func (task *uploadProtoTask) createAPI(ctx context.Context) error {
@@ -181,37 +213,17 @@ func (task *uploadProtoTask) createOrUpdateSpec(ctx context.Context) error {
 		return err
 	}
 
-	// Use the spec size and hash to avoid unnecessary uploads.
-	spec, err := task.client.GetApiSpec(ctx, &rpc.GetApiSpecRequest{
-		Name: task.specName(),
-	})
-
-	if err == nil && int(spec.GetSizeBytes()) == len(contents) && spec.GetHash() == hashForBytes(contents) {
-		log.Debugf(ctx, "Matched already uploaded spec %s", task.specName())
-		return nil
-	}
-
-	request := &rpc.UpdateApiSpecRequest{
-		ApiSpec: &rpc.ApiSpec{
-			Name:     task.specName(),
-			MimeType: core.ProtobufMimeType("+zip"),
-			Filename: task.fileName(),
-			Contents: contents,
-		},
-		AllowMissing: true,
-	}
-	if task.baseURI != "" {
-		request.ApiSpec.SourceUri = fmt.Sprintf("%s/%s", task.baseURI, task.apiPath())
-	}
-
-	response, err := task.client.UpdateApiSpec(ctx, request)
-	if err != nil {
-		log.FromContext(ctx).WithError(err).Errorf("Error %s [contents-length: %d]", task.specName(), len(contents))
-	} else {
-		log.Debugf(ctx, "Updated %s", response.Name)
+	spec := &rpc.ApiSpec{
+		Name:      task.specName(),
+		MimeType:  core.ProtobufMimeType("+zip"),
+		Filename:  task.fileName(),
+		Contents:  contents,
+		SourceUri: fmt.Sprintf("%s/%s", task.baseURI, task.apiPath()),
 	}
 
-	return nil
+	// Use the spec hash to avoid unnecessary uploads. Zip archives aren't
+	// uncompressed before they are hashed.
+	return task.uploader.upload(ctx, spec, hashForBytes(contents), task.createParents)
 }
 
 func (task *uploadProtoTask) projectName() string {
@@ -219,7 +231,7 @@ func (task *uploadProtoTask) projectName() string {
 }
 
 func (task *uploadProtoTask) apiName() string {
-	return fmt.Sprintf("%s/locations/global/apis/%s", task.projectName(), task.apiID)
+	return fmt.Sprintf("%s/locations/%s/apis/%s", task.projectName(), task.locationID, task.apiID)
 }
 
 func (task *uploadProtoTask) versionName() string {
@@ -235,8 +247,11 @@ func (task *uploadProtoTask) apiPath() string {
 	return strings.TrimPrefix(task.path, prefix)
 }
 
+// protosFileName is the file name of each uploaded archive of Protocol Buffer files.
+const protosFileName = "protos.zip"
+
 func (task *uploadProtoTask) fileName() string {
-	return "protos.zip"
+	return protosFileName
 }
 
 func (task *uploadProtoTask) zipContents() ([]byte, error) {
@@ -27,6 +27,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -34,8 +35,9 @@ import (
 
 func csvCommand(ctx context.Context) *cobra.Command {
 	var (
-		projectID string
-		delimiter string
+		projectID  string
+		locationID string
+		delimiter  string
 	)
 
 	cmd := &cobra.Command{
@@ -43,6 +45,11 @@ func csvCommand(ctx context.Context) *cobra.Command {
 		Short: "Upload API specs from a CSV file",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			projectID, locationID, err = core.ProjectAndLocation(cmd)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
+			}
 			if len(delimiter) != 1 {
 				log.Fatalf(ctx, "Invalid delimiter %q: must be exactly one character", delimiter)
 			}
@@ -79,19 +86,20 @@ func csvCommand(ctx context.Context) *cobra.Command {
 				}
 
 				taskQueue <- &uploadSpecTask{
-					client:    client,
-					projectID: projectID,
-					apiID:     row.ApiID,
-					versionID: row.VersionID,
-					specID:    row.SpecID,
-					filepath:  row.Filepath,
+					client:     client,
+					projectID:  projectID,
+					locationID: locationID,
+					apiID:      row.ApiID,
+					versionID:  row.VersionID,
+					specID:     row.SpecID,
+					filepath:   row.Filepath,
 				}
 			}
 		},
 	}
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use for each upload")
-	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use for each upload")
 	cmd.Flags().StringVar(&delimiter, "delimiter", ",", "Field delimiter for the CSV file")
 	return cmd
 }
@@ -169,17 +177,19 @@ func (r *uploadCSVReader) buildColumnIndex(header []string) error {
 }
 
 type uploadSpecTask struct {
-	client    connection.Client
-	projectID string
-	apiID     string
-	versionID string
-	specID    string
-	filepath  string
+	client     connection.Client
+	projectID  string
+	locationID string
+	apiID      string
+	versionID  string
+	specID     string
+	filepath   string
 }
 
 func (t uploadSpecTask) Run(ctx context.Context) error {
+	location := names.Project{ProjectID: t.projectID}.Location(t.locationID)
 	api, err := t.client.CreateApi(ctx, &rpc.CreateApiRequest{
-		Parent: fmt.Sprintf("projects/%s/locations/global", t.projectID),
+		Parent: location.String(),
 		ApiId:  t.apiID,
 		Api:    &rpc.Api{},
 	})
@@ -189,7 +199,7 @@ func (t uploadSpecTask) Run(ctx context.Context) error {
 		log.Debugf(ctx, "Created API: %s", api.GetName())
 	case codes.AlreadyExists:
 		api = &rpc.Api{
-			Name: fmt.Sprintf("projects/%s/locations/global/apis/%s", t.projectID, t.apiID),
+			Name: location.Api(t.apiID).String(),
 		}
 	default:
 		return fmt.Errorf("failed to ensure API exists: %s", err)
@@ -206,7 +216,7 @@ func (t uploadSpecTask) Run(ctx context.Context) error {
 		log.Debugf(ctx, "Created API version: %s", version.GetName())
 	case codes.AlreadyExists:
 		version = &rpc.ApiVersion{
-			Name: fmt.Sprintf("projects/%s/apis/%s/versions/%s", t.projectID, t.apiID, t.versionID),
+			Name: location.Api(t.apiID).Version(t.versionID).String(),
 		}
 	default:
 		return fmt.Errorf("failed to ensure API version exists: %s", err)
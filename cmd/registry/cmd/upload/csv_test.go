@@ -105,6 +105,21 @@ func TestUploadCSV(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "custom location",
+			args: []string{
+				filepath.Join("testdata", "out-of-order-columns.csv"),
+				"--project-id", testProject,
+				"--location", "us-central1",
+			},
+			want: []*rpc.ApiSpec{
+				{
+					Name:     fmt.Sprintf("projects/%s/locations/us-central1/apis/cloudtasks/versions/v2/specs/openapi.yaml", testProject),
+					MimeType: gzipOpenAPIv3,
+					Contents: cloudtasksGA,
+				},
+			},
+		},
 		{
 			desc: "empty sheet",
 			args: []string{
@@ -143,7 +158,7 @@ func TestUploadCSV(t *testing.T) {
 			}
 
 			it := client.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{
-				Parent:   fmt.Sprintf("projects/%s/locations/global/apis/-/versions/-", testProject),
+				Parent:   fmt.Sprintf("projects/%s/locations/-/apis/-/versions/-", testProject),
 				PageSize: int32(len(test.want)),
 			})
 
@@ -0,0 +1,466 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apigee/registry/cmd/registry/cmd/upload/bulk"
+	"github.com/apigee/registry/cmd/registry/core"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Annotations that record the commit that a spec revision was imported from.
+const (
+	gitCommitAnnotation  = "git-commit"
+	gitAuthorAnnotation  = "git-author"
+	gitMessageAnnotation = "git-message"
+)
+
+func gitCommand(ctx context.Context) *cobra.Command {
+	var (
+		projectID  string
+		locationID string
+		ref        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "git REPO_PATH --project-id=value [--ref=value]",
+		Short: "Upload the history of API specs from a local git repository",
+		Long: `Upload the history of API specs from a local git repository. The first-parent
+history of --ref is walked from the oldest commit, and every commit that
+changes an OpenAPI spec creates a revision of its spec. Specs are found and
+named like they are by "registry upload bulk openapi", with REPO_PATH as the
+uploaded directory.
+
+The SHA, author, and message of each commit are recorded in the git-commit,
+git-author, and git-message annotations of its revisions, and commits that
+were already uploaded are skipped. Git tags become revision tags of the
+revisions that are current at the tagged commits. Tag names are lowercased
+and characters other than letters, digits, and hyphens are replaced by
+hyphens, so "v1.0.0" becomes "v1-0-0".`,
+		Example: "registry upload git ./petstore --project-id my-project",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			projectID, locationID, err = core.ProjectAndLocation(cmd)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
+			}
+
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			adminClient, err := connection.NewAdminClient(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
+			}
+
+			core.EnsureProjectExists(ctx, adminClient, projectID)
+
+			u := &gitUploader{
+				client:    client,
+				repo:      gitRepo(args[0]),
+				location:  names.Location{ProjectID: projectID, LocationID: locationID},
+				out:       cmd.OutOrStdout(),
+				revisions: make(map[string]*gitSpecRevisions),
+				current:   make(map[string]string),
+			}
+			if err := u.upload(ctx, ref); err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to upload git history")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use for each upload")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use for each upload")
+	cmd.Flags().StringVar(&ref, "ref", "HEAD", "Commit whose history is uploaded")
+	return cmd
+}
+
+// gitRepo is the path of a directory in a local git repository.
+type gitRepo string
+
+// run runs a git command in the repository and returns its output.
+func (r gitRepo) run(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", string(r), "-c", "core.quotePath=false"}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s failed: %s: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// gitCommit is a commit and the files it changed, which are relative to the
+// top of the repository.
+type gitCommit struct {
+	sha     string
+	changed []string
+	deleted []string
+}
+
+// commits returns the first-parent history of ref from the oldest commit.
+// Commits that don't change files are included, because they can be tagged.
+func (r gitRepo) commits(ref string) ([]gitCommit, error) {
+	sha, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.run("log", "--reverse", "--first-parent", "-m", "--no-renames", "--name-status", "--format=%x00%H", sha, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseGitLog(string(b)), nil
+}
+
+// resolve returns the SHA of the commit named by ref. Refs that git would
+// read as options are rejected.
+func (r gitRepo) resolve(ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+	b, err := r.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("invalid ref %q: not a commit", ref)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// parseGitLog parses the output of git log with --name-status and a format
+// that starts each commit with a NUL and its SHA.
+func parseGitLog(out string) []gitCommit {
+	var commits []gitCommit
+	for _, entry := range strings.Split(out, "\x00") {
+		lines := strings.Split(strings.TrimSpace(entry), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		commit := gitCommit{sha: lines[0]}
+		for _, line := range lines[1:] {
+			fields := strings.SplitN(line, "\t", 2)
+			if len(fields) != 2 {
+				continue
+			}
+			if fields[0] == "D" {
+				commit.deleted = append(commit.deleted, fields[1])
+			} else {
+				commit.changed = append(commit.changed, fields[1])
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// tags returns the names of the tags of each tagged commit.
+func (r gitRepo) tags() (map[string][]string, error) {
+	// Annotated tags are peeled to the commits they point to.
+	b, err := r.run("for-each-ref", "--format=%(objectname) %(*objectname) %(refname:strip=2)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 2:
+			tags[fields[0]] = append(tags[fields[0]], fields[1])
+		case 3:
+			tags[fields[1]] = append(tags[fields[1]], fields[2])
+		}
+	}
+	return tags, nil
+}
+
+var invalidTagChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// revisionTag converts a git tag into a revision tag, or returns "" if the
+// tag has no usable characters.
+func revisionTag(tag string) string {
+	tag = invalidTagChars.ReplaceAllString(strings.ToLower(tag), "-")
+	if len(tag) > 40 {
+		tag = tag[:40]
+	}
+	return strings.Trim(tag, "-")
+}
+
+// gitSpecRevisions describes the revisions of a spec that were uploaded.
+type gitSpecRevisions struct {
+	latest  string              // ID of the latest revision
+	hash    string              // hash of the latest revision
+	commits map[string]string   // revision IDs by commit SHA
+	tags    map[string][]string // revision tags by revision ID
+}
+
+type gitUploader struct {
+	client    connection.Client
+	repo      gitRepo
+	location  names.Location
+	out       io.Writer
+	revisions map[string]*gitSpecRevisions // by spec name
+	current   map[string]string            // revision IDs of the specs in the checked out tree
+}
+
+func (u *gitUploader) upload(ctx context.Context, ref string) error {
+	// Paths in git output are relative to the top of the repository.
+	b, err := u.repo.run("rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	prefix := strings.TrimSpace(string(b))
+
+	commits, err := u.repo.commits(ref)
+	if err != nil {
+		return err
+	}
+	tags, err := u.repo.tags()
+	if err != nil {
+		return err
+	}
+
+	for _, commit := range commits {
+		for _, file := range commit.deleted {
+			if !strings.HasPrefix(file, prefix) {
+				continue
+			}
+			if spec, _, err := u.spec(strings.TrimPrefix(file, prefix)); err == nil {
+				delete(u.current, spec.String())
+			}
+		}
+		for _, file := range commit.changed {
+			rel := strings.TrimPrefix(file, prefix)
+			if !strings.HasPrefix(file, prefix) || bulk.OpenAPIVersion(rel) == "" {
+				continue
+			}
+			if err := u.uploadFile(ctx, commit.sha, file, rel); err != nil {
+				return err
+			}
+		}
+		for _, tag := range tags[commit.sha] {
+			if err := u.tag(ctx, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// spec returns the name and OpenAPI version of the spec that is uploaded from
+// a path relative to the repository directory.
+func (u *gitUploader) spec(rel string) (names.Spec, string, error) {
+	apiID, versionID, specID, err := bulk.OpenAPIIDs(rel)
+	if err != nil {
+		return names.Spec{}, "", err
+	}
+	return u.location.Api(apiID).Version(versionID).Spec(specID), bulk.OpenAPIVersion(rel), nil
+}
+
+// uploadFile creates a revision of the spec of a file that was changed by a
+// commit, unless the commit was already uploaded or didn't change the file's
+// contents.
+func (u *gitUploader) uploadFile(ctx context.Context, sha, file, rel string) error {
+	spec, version, err := u.spec(rel)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Warnf("Skipping %s", file)
+		return nil
+	}
+	revisions, err := u.specRevisions(ctx, spec)
+	if err != nil {
+		return err
+	}
+	if id, ok := revisions.commits[sha]; ok {
+		u.current[spec.String()] = id
+		return nil
+	}
+
+	contents, err := u.repo.run("show", sha+":"+file)
+	if err != nil {
+		return err
+	}
+	hash := ""
+	if len(contents) > 0 {
+		hash = fmt.Sprintf("%x", sha256.Sum256(contents))
+	}
+	if revisions.latest != "" && hash == revisions.hash {
+		u.current[spec.String()] = revisions.latest
+		return nil
+	}
+
+	metadata, err := u.repo.run("show", "-s", "--format=%an <%ae>%x00%B", sha)
+	if err != nil {
+		return err
+	}
+	fields := strings.SplitN(string(metadata), "\x00", 2)
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected metadata of commit %s", sha)
+	}
+
+	gzippedContents, err := core.GZippedBytes(contents)
+	if err != nil {
+		return err
+	}
+
+	// Parents are created as needed, like they are by bulk uploads.
+	if _, err := u.client.UpdateApi(ctx, &rpc.UpdateApiRequest{
+		Api:          &rpc.Api{Name: spec.Api().String(), DisplayName: spec.ApiID},
+		AllowMissing: true,
+	}); err != nil {
+		return err
+	}
+	if _, err := u.client.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+		ApiVersion:   &rpc.ApiVersion{Name: spec.Version().String()},
+		AllowMissing: true,
+	}); err != nil {
+		return err
+	}
+
+	response, err := u.client.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{
+			Name:     spec.String(),
+			Filename: path.Base(file),
+			MimeType: core.OpenAPIMimeType("+gzip", version),
+			Contents: gzippedContents,
+			Annotations: map[string]string{
+				gitCommitAnnotation:  sha,
+				gitAuthorAnnotation:  fields[0],
+				gitMessageAnnotation: strings.TrimSpace(fields[1]),
+			},
+		},
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"filename", "mime_type", "contents", "annotations"}},
+		AllowMissing: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	revisions.latest = response.GetRevisionId()
+	revisions.hash = hash
+	revisions.commits[sha] = response.GetRevisionId()
+	u.current[spec.String()] = response.GetRevisionId()
+	fmt.Fprintf(u.out, "created %s@%s\n", spec, response.GetRevisionId())
+	return nil
+}
+
+// specRevisions returns the revisions of a spec, which are listed once and
+// then kept up to date as revisions are created and tagged.
+func (u *gitUploader) specRevisions(ctx context.Context, spec names.Spec) (*gitSpecRevisions, error) {
+	if revisions, ok := u.revisions[spec.String()]; ok {
+		return revisions, nil
+	}
+
+	revisions := &gitSpecRevisions{
+		commits: make(map[string]string),
+		tags:    make(map[string][]string),
+	}
+	// Revisions are listed from the newest.
+	it := u.client.ListApiSpecRevisions(ctx, &rpc.ListApiSpecRevisionsRequest{Name: spec.String()})
+	for first := true; ; first = false {
+		revision, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if status.Code(err) == codes.NotFound {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if first {
+			revisions.latest = revision.GetRevisionId()
+			revisions.hash = revision.GetHash()
+		}
+		if sha, ok := revision.GetAnnotations()[gitCommitAnnotation]; ok {
+			revisions.commits[sha] = revision.GetRevisionId()
+		}
+		revisions.tags[revision.GetRevisionId()] = revision.GetRevisionTags()
+	}
+
+	u.revisions[spec.String()] = revisions
+	return revisions, nil
+}
+
+// tag tags the current revisions of all specs with a git tag.
+func (u *gitUploader) tag(ctx context.Context, gitTag string) error {
+	tag := revisionTag(gitTag)
+	if tag == "" {
+		log.Warnf(ctx, "Skipping tag %q, which has no characters that can be used in revision tags", gitTag)
+		return nil
+	}
+
+	specs := make([]string, 0, len(u.current))
+	for spec := range u.current {
+		specs = append(specs, spec)
+	}
+	sort.Strings(specs)
+
+	for _, spec := range specs {
+		id := u.current[spec]
+		revisions := u.revisions[spec]
+		if containsString(revisions.tags[id], tag) {
+			continue
+		}
+		if _, err := u.client.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{
+			Name: spec + "@" + id,
+			Tag:  tag,
+		}); err != nil {
+			return err
+		}
+		// A tag names a single revision, so it is moved from any other.
+		for other, tags := range revisions.tags {
+			revisions.tags[other] = removeString(tags, tag)
+		}
+		revisions.tags[id] = append(revisions.tags[id], tag)
+		fmt.Fprintf(u.out, "tagged %s@%s\n", spec, tag)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	var result []string
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
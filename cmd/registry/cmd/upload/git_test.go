@@ -0,0 +1,207 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseGitLog(t *testing.T) {
+	out := "\x00aaaa\n\nA\tapis/a/v1/openapi.yaml\nM\tREADME.md\n\x00bbbb\n\x00cccc\n\nD\tapis/a/v1/openapi.yaml\n"
+	want := []gitCommit{
+		{sha: "aaaa", changed: []string{"apis/a/v1/openapi.yaml", "README.md"}},
+		{sha: "bbbb"},
+		{sha: "cccc", deleted: []string{"apis/a/v1/openapi.yaml"}},
+	}
+	if diff := cmp.Diff(want, parseGitLog(out), cmp.AllowUnexported(gitCommit{})); diff != "" {
+		t.Errorf("parseGitLog() returned unexpected commits (-want +got):\n%s", diff)
+	}
+}
+
+func TestRevisionTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"v1", "v1"},
+		{"v1.0.0", "v1-0-0"},
+		{"Release/2021_10", "release-2021-10"},
+		{"...", ""},
+		{strings.Repeat("a", 50), strings.Repeat("a", 40)},
+	}
+	for _, test := range tests {
+		if got := revisionTag(test.tag); got != test.want {
+			t.Errorf("revisionTag(%q) returned %q, want %q", test.tag, got, test.want)
+		}
+	}
+}
+
+func TestGitCommitsOfInvalidRefs(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test Author", "GIT_AUTHOR_EMAIL=author@example.com",
+			"GIT_COMMITTER_NAME=Test Author", "GIT_COMMITTER_EMAIL=author@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Setup: git %v failed: %s: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "Start")
+
+	output := filepath.Join(t.TempDir(), "log")
+	for _, ref := range []string{"--output=" + output, "missing", "HEAD:README.md"} {
+		if _, err := gitRepo(repo).commits(ref); err == nil {
+			t.Errorf("commits(%q) returned no error", ref)
+		}
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("commits() of an option wrote %s", output)
+	}
+
+	if commits, err := gitRepo(repo).commits("HEAD"); err != nil {
+		t.Errorf("commits(%q) returned error: %s", "HEAD", err)
+	} else if len(commits) != 1 {
+		t.Errorf("commits(%q) returned %d commits, want 1", "HEAD", len(commits))
+	}
+}
+
+func TestUploadGit(t *testing.T) {
+	const project = "upload-git-demo"
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	adminClient, err := connection.NewAdminClient(ctx)
+	if err != nil {
+		t.Fatalf("Setup: Failed to create client: %s", err)
+	}
+	err = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Fatalf("Setup: Failed to delete test project: %s", err)
+	}
+	defer func() {
+		_ = adminClient.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: "projects/" + project})
+	}()
+
+	// Specs are uploaded from a subdirectory of the repository.
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "apis")
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test Author", "GIT_AUTHOR_EMAIL=author@example.com",
+			"GIT_COMMITTER_NAME=Test Author", "GIT_COMMITTER_EMAIL=author@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Setup: git %v failed: %s: %s", args, err, out)
+		}
+	}
+	write := func(path, contents string) {
+		path = filepath.Join(repo, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Setup: Failed to create directory: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("Setup: Failed to write file: %s", err)
+		}
+	}
+	upload := func() []string {
+		cmd := Command(ctx)
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"git", dir, "--project-id", project})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() returned error: %s", err)
+		}
+		return strings.Fields(out.String())
+	}
+
+	git("init", "-q")
+	write("apis/petstore/v1/openapi.yaml", "openapi: 3.0.0\n")
+	write("README.md", "Petstore\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add the petstore API")
+	write("apis/petstore/v1/openapi.yaml", "openapi: 3.0.1\n")
+	git("commit", "-q", "-a", "-m", "Update the petstore API\n\nWith more details.")
+	write("README.md", "Petstore API\n")
+	git("commit", "-q", "-a", "-m", "Update the README")
+	git("tag", "-a", "v1.0.0", "-m", "First release")
+
+	got := upload()
+
+	const spec = "projects/upload-git-demo/locations/global/apis/petstore/versions/v1/specs/openapi.yaml"
+	var revisions []*rpc.ApiSpec
+	it := client.ListApiSpecRevisions(ctx, &rpc.ListApiSpecRevisionsRequest{Name: spec})
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			t.Fatalf("Failed to list revisions: %s", err)
+		}
+		// Revisions are listed from the newest.
+		revisions = append([]*rpc.ApiSpec{r}, revisions...)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Upload created %d revisions, want 2", len(revisions))
+	}
+
+	want := []string{
+		"created", spec + "@" + revisions[0].GetRevisionId(),
+		"created", spec + "@" + revisions[1].GetRevisionId(),
+		"tagged", spec + "@v1-0-0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected output (-want +got):\n%s", diff)
+	}
+
+	messages := []string{"Add the petstore API", "Update the petstore API\n\nWith more details."}
+	for i, r := range revisions {
+		annotations := r.GetAnnotations()
+		if annotations[gitCommitAnnotation] == "" {
+			t.Errorf("Revision %d has no %s annotation", i+1, gitCommitAnnotation)
+		}
+		if got, want := annotations[gitAuthorAnnotation], "Test Author <author@example.com>"; got != want {
+			t.Errorf("Revision %d has %s %q, want %q", i+1, gitAuthorAnnotation, got, want)
+		}
+		if got, want := annotations[gitMessageAnnotation], messages[i]; got != want {
+			t.Errorf("Revision %d has %s %q, want %q", i+1, gitMessageAnnotation, got, want)
+		}
+	}
+	if diff := cmp.Diff([]string{"v1-0-0"}, revisions[1].GetRevisionTags()); diff != "" {
+		t.Errorf("Unexpected tags of the latest revision (-want +got):\n%s", diff)
+	}
+
+	// Uploading again finds nothing new.
+	if got := upload(); len(got) != 0 {
+		t.Errorf("Second upload returned %v, want no output", got)
+	}
+}
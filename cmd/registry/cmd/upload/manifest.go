@@ -24,6 +24,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -49,12 +50,17 @@ func readManifestProto(filename string) (*rpc.Manifest, error) {
 }
 
 func manifestCommand(ctx context.Context) *cobra.Command {
-	var projectID string
+	var projectID, locationID string
 	cmd := &cobra.Command{
-		Use:   "manifest FILE_PATH --project-id=value",
+		Use:   "manifest FILE_PATH --project-id=value [--location=value]",
 		Short: "Upload a dependency manifest",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			projectID, locationID, err = core.ProjectAndLocation(cmd)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
+			}
 			manifestPath := args[0]
 			if manifestPath == "" {
 				log.Fatal(ctx, "Please provide manifest-path")
@@ -84,7 +90,7 @@ func manifestCommand(ctx context.Context) *cobra.Command {
 			}
 
 			artifact := &rpc.Artifact{
-				Name:     "projects/" + projectID + "/locations/global/artifacts/" + manifest.GetId(),
+				Name:     names.Project{ProjectID: projectID}.Location(locationID).Artifact(manifest.GetId()).String(),
 				MimeType: core.MimeTypeForMessageType("google.cloud.apigeeregistry.v1.controller.Manifest"),
 				Contents: manifestData,
 			}
@@ -97,6 +103,6 @@ func manifestCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use when saving the result manifest artifact")
-	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use when saving the result manifest artifact")
 	return cmd
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
 	"github.com/spf13/cobra"
 )
 
@@ -113,17 +114,7 @@ func uploadSpecDirectory(ctx context.Context, dirname string, client *gapic.Regi
 }
 
 func uploadSpecFile(ctx context.Context, filename string, client *gapic.RegistryClient, version string, style string) error {
-	var mimeType string
-	switch style {
-	case "openapi":
-		if strings.Contains(filename, "swagger") { // TODO: switch on actual spec contents
-			mimeType = core.OpenAPIMimeType("+gzip", "2")
-		} else {
-			mimeType = core.OpenAPIMimeType("+gzip", "3")
-		}
-	case "discovery":
-		mimeType = core.DiscoveryMimeType("+gzip")
-	default:
+	if style != "openapi" && style != "discovery" {
 		return fmt.Errorf("unsupported file style %s", style)
 	}
 	specID := filepath.Base(filename)
@@ -136,6 +127,10 @@ func uploadSpecFile(ctx context.Context, filename string, client *gapic.Registry
 		if err != nil {
 			log.FromContext(ctx).WithError(err).Debug("Failed to read file")
 		} else {
+			mimeType := core.DiscoveryMimeType("+gzip")
+			if style == "openapi" {
+				mimeType = openAPIMimeType(filename, bytes)
+			}
 			request := &rpc.CreateApiSpecRequest{
 				Parent:    version,
 				ApiSpecId: specID,
@@ -159,3 +154,15 @@ func uploadSpecFile(ctx context.Context, filename string, client *gapic.Registry
 	}
 	return nil
 }
+
+// openAPIMimeType returns the MIME type of an OpenAPI spec, with the version
+// detected from its contents or, failing that, its filename.
+func openAPIMimeType(filename string, contents []byte) string {
+	if m, err := mimetypes.Parse(mimetypes.Detect(filename, contents)); err == nil && m.Format() == mimetypes.OpenAPI {
+		return core.OpenAPIMimeType("+gzip", m.Version())
+	}
+	if strings.Contains(filename, "swagger") {
+		return core.OpenAPIMimeType("+gzip", "2")
+	}
+	return core.OpenAPIMimeType("+gzip", "3")
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -52,12 +53,17 @@ func readStyleGuideProto(filename string) (*rpc.StyleGuide, error) {
 }
 
 func styleGuideCommand(ctx context.Context) *cobra.Command {
-	var projectID string
+	var projectID, locationID string
 	cmd := &cobra.Command{
-		Use:   "styleguide FILE_PATH --project-id=value",
+		Use:   "styleguide FILE_PATH --project-id=value [--location=value]",
 		Short: "Upload an API style guide",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			projectID, locationID, err = core.ProjectAndLocation(cmd)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Fatal("Failed to get project and location")
+			}
 			styleGuidePath := args[0]
 			if styleGuidePath == "" {
 				log.Fatal(ctx, "Please provide style guide path")
@@ -78,10 +84,7 @@ func styleGuideCommand(ctx context.Context) *cobra.Command {
 			}
 
 			artifact := &rpc.Artifact{
-				Name: "projects/" +
-					projectID +
-					"/locations/global/artifacts/" +
-					styleGuide.GetId(),
+				Name: names.Project{ProjectID: projectID}.Location(locationID).Artifact(styleGuide.GetId()).String(),
 				MimeType: core.MimeTypeForMessageType(
 					"google.cloud.apigeeregistry.applications.v1alpha1.StyleGuide",
 				),
@@ -96,6 +99,6 @@ func styleGuideCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID to use when storing the styleguide artifact")
-	_ = cmd.MarkFlagRequired("project-id")
+	cmd.Flags().StringVar(&locationID, "location", names.DefaultLocation, "Location to use when storing the styleguide artifact")
 	return cmd
 }
@@ -29,6 +29,7 @@ func Command(ctx context.Context) *cobra.Command {
 
 	cmd.AddCommand(bulk.Command(ctx))
 	cmd.AddCommand(csvCommand(ctx))
+	cmd.AddCommand(gitCommand(ctx))
 	cmd.AddCommand(manifestCommand(ctx))
 	cmd.AddCommand(specCommand(ctx))
 	cmd.AddCommand(styleGuideCommand(ctx))
@@ -17,7 +17,6 @@ package vocabulary
 import (
 	"context"
 
-	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/google/gnostic/metrics/vocabulary"
@@ -34,10 +33,7 @@ func intersectionCommand(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
 			}
-			output, err := cmd.Flags().GetString("output")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get output from flags")
-			}
+			output, printer := resultOutput(ctx, cmd)
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
@@ -47,11 +43,11 @@ func intersectionCommand(ctx context.Context) *cobra.Command {
 			if output != "" {
 				setVocabularyToArtifact(ctx, client, vocab, output)
 			} else {
-				core.PrintMessage(vocab)
+				printResult(ctx, printer, vocab)
 			}
 		},
 	}
 
-	cmd.Flags().String("output", "", "Artifact name to use when saving the vocabulary artifact")
+	cmd.Flags().String("output-artifact", "", "Artifact name to use when saving the vocabulary artifact")
 	return cmd
 }
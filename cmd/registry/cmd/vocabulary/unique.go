@@ -19,7 +19,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/google/gnostic/metrics/vocabulary"
@@ -48,13 +47,14 @@ func uniqueCommand(ctx context.Context) *cobra.Command {
 			}
 			names, inputs := collectInputVocabularies(ctx, client, args, filter)
 			list := vocabulary.FilterCommon(inputs)
-			if outputID != "" {
+			_, printer := resultOutput(ctx, cmd)
+			if outputID != "" && printer == nil {
 				for i, unique := range list.Vocabularies {
 					outputArtifactName := filepath.Dir(names[i]) + "/" + outputID
 					setVocabularyToArtifact(ctx, client, unique, outputArtifactName)
 				}
 			} else {
-				core.PrintMessage(list)
+				printResult(ctx, printer, list)
 			}
 		},
 	}
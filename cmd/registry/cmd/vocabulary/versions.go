@@ -18,7 +18,6 @@ import (
 	"context"
 	"strings"
 
-	"github.com/apigee/registry/cmd/registry/core"
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/google/gnostic/metrics/vocabulary"
@@ -35,10 +34,7 @@ func versionsCommand(ctx context.Context) *cobra.Command {
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get filter from flags")
 			}
-			output, err := cmd.Flags().GetString("output")
-			if err != nil {
-				log.FromContext(ctx).WithError(err).Fatal("Failed to get output from flags")
-			}
+			output, printer := resultOutput(ctx, cmd)
 			client, err := connection.NewClient(ctx)
 			if err != nil {
 				log.FromContext(ctx).WithError(err).Fatal("Failed to get client")
@@ -53,11 +49,11 @@ func versionsCommand(ctx context.Context) *cobra.Command {
 			if output != "" {
 				setVersionHistoryToArtifact(ctx, client, history, output)
 			} else {
-				core.PrintMessage(history)
+				printResult(ctx, printer, history)
 			}
 		},
 	}
 
-	cmd.Flags().String("output", "", "Artifact name to use when saving the vocabulary artifact")
+	cmd.Flags().String("output-artifact", "", "Artifact name to use when saving the vocabulary artifact")
 	return cmd
 }
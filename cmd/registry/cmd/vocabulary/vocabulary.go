@@ -42,9 +42,54 @@ func Command(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(versionsCommand(ctx))
 
 	cmd.PersistentFlags().String("filter", "", "Filter selected resources")
+	cmd.PersistentFlags().StringP("output", "o", "", core.OutputUsage)
+	cmd.PersistentFlags().StringSlice("columns", nil, core.ColumnsUsage)
 	return cmd
 }
 
+// resultOutput returns the name of the artifact that a result is saved to, or
+// the printer that prints it in an output format. For compatibility, --output
+// also accepts an artifact name.
+func resultOutput(ctx context.Context, cmd *cobra.Command) (string, *core.Printer) {
+	// Not every command saves results to a named artifact.
+	artifact, _ := cmd.Flags().GetString("output-artifact")
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get output from flags")
+	}
+	columns, err := cmd.Flags().GetStringSlice("columns")
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to get columns from flags")
+	}
+
+	if _, err := names.ParseArtifact(output); err == nil {
+		log.Warnf(ctx, "Using --output for artifact names is deprecated, use --output-artifact %s", output)
+		return output, nil
+	}
+	if output == "" {
+		return artifact, nil
+	}
+	printer, err := core.NewPrinter(cmd.OutOrStdout(), output, columns)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Invalid output flags")
+	}
+	return "", printer
+}
+
+// printResult prints a result with a printer or, if there is none, as JSON.
+func printResult(ctx context.Context, printer *core.Printer, message proto.Message) {
+	if printer == nil {
+		core.PrintMessage(message)
+		return
+	}
+	if err := printer.Print(message); err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to print result")
+	}
+	if err := printer.Flush(); err != nil {
+		log.FromContext(ctx).WithError(err).Fatal("Failed to print result")
+	}
+}
+
 func collectInputVocabularies(ctx context.Context, client connection.Client, args []string, filter string) ([]string, []*metrics.Vocabulary) {
 	inputNames := make([]string, 0)
 	inputs := make([]*metrics.Vocabulary, 0)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 )
 
 // Tests for error paths in the controller
@@ -167,7 +168,7 @@ func TestControllerErrors(t *testing.T) {
 			createSpec(ctx, registryClient, t, "projects/controller-test/locations/global/apis/petstore/versions/1.1.0", "openapi.yaml", gzipOpenAPIv3)
 
 			// Test GeneratedResource pattern
-			actions, err := processManifestResource(ctx, registryClient, projectID, test.generatedResource)
+			actions, err := processManifestResource(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), test.generatedResource)
 			if err == nil {
 				t.Errorf("Expected processManifestResource() to return an error, got: %v", actions)
 			}
@@ -22,6 +22,7 @@ import (
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 )
 
 type Action struct {
@@ -33,7 +34,7 @@ type Action struct {
 func ProcessManifest(
 	ctx context.Context,
 	client connection.Client,
-	projectID string,
+	location names.Location,
 	manifest *rpc.Manifest) []*Action {
 
 	var actions []*Action
@@ -46,7 +47,7 @@ func ProcessManifest(
 			continue
 		}
 
-		newActions, err := processManifestResource(ctx, client, projectID, resource)
+		newActions, err := processManifestResource(ctx, client, location, resource)
 		if err != nil {
 			log.FromContext(ctx).WithError(err).Debugf("Skipping resource: %q", resource)
 			continue
@@ -60,13 +61,13 @@ func ProcessManifest(
 func processManifestResource(
 	ctx context.Context,
 	client connection.Client,
-	projectID string,
+	location names.Location,
 	resource *rpc.GeneratedResource) ([]*Action, error) {
 	// Generate dependency map
-	resourcePattern := fmt.Sprintf("projects/%s/locations/global/%s", projectID, resource.Pattern)
+	resourcePattern := fmt.Sprintf("%s/%s", location, resource.Pattern)
 	dependencyMaps := make([]map[string]time.Time, 0, len(resource.Dependencies))
 	for _, dependency := range resource.Dependencies {
-		dMap, err := generateDependencyMap(ctx, client, resourcePattern, dependency, projectID)
+		dMap, err := generateDependencyMap(ctx, client, resourcePattern, dependency, location)
 		if err != nil {
 			return nil, fmt.Errorf("error while generating dependency map for %v: %s", dependency, err)
 		}
@@ -94,7 +95,7 @@ func generateDependencyMap(
 	client connection.Client,
 	resourcePattern string,
 	dependency *rpc.Dependency,
-	projectID string) (map[string]time.Time, error) {
+	location names.Location) (map[string]time.Time, error) {
 	// Creates a map of the resources to group them into corresponding buckets
 	// of match pattern which store the maxTimestamp
 	// An example entry will look like this:
@@ -108,7 +109,7 @@ func generateDependencyMap(
 	sourceMap := make(map[string]time.Time)
 
 	// Extend the dependency pattern if it contains $resource.api like pattern
-	extDependencyQuery, err := extendDependencyPattern(resourcePattern, dependency.Pattern, projectID)
+	extDependencyQuery, err := extendDependencyPattern(resourcePattern, dependency.Pattern, location)
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -176,7 +177,7 @@ func TestArtifacts(t *testing.T) {
 					},
 				},
 			}
-			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+			actions := ProcessManifest(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), manifest)
 
 			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
@@ -308,7 +309,7 @@ func TestAggregateArtifacts(t *testing.T) {
 					},
 				},
 			}
-			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+			actions := ProcessManifest(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), manifest)
 
 			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
@@ -488,7 +489,7 @@ func TestDerivedArtifacts(t *testing.T) {
 					},
 				},
 			}
-			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+			actions := ProcessManifest(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), manifest)
 
 			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
@@ -578,7 +579,7 @@ func TestReceiptArtifacts(t *testing.T) {
 					},
 				},
 			}
-			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+			actions := ProcessManifest(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), manifest)
 
 			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
@@ -686,7 +687,7 @@ func TestReceiptAggArtifacts(t *testing.T) {
 					},
 				},
 			}
-			actions := ProcessManifest(ctx, registryClient, projectID, manifest)
+			actions := ProcessManifest(ctx, registryClient, names.Project{ProjectID: projectID}.Location(names.DefaultLocation), manifest)
 
 			if diff := cmp.Diff(test.want, actions, sortActions); diff != "" {
 				t.Errorf("ProcessManifest(%+v) returned unexpected diff (-want +got):\n%s", manifest, diff)
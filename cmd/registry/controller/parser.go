@@ -28,7 +28,7 @@ const resourceKW = "$resource"
 func extendDependencyPattern(
 	resourcePattern string,
 	dependencyPattern string,
-	projectID string) (string, error) {
+	location names.Location) (string, error) {
 	// Extends the source pattern by replacing references to $resource
 	// Example:
 	// resourcePattern: "projects/demo/locations/global/apis/-/versions/-/specs/-/artifacts/-"
@@ -40,7 +40,7 @@ func extendDependencyPattern(
 	// Returns "projects/demo/locations/global/apis/-/versions/-"
 
 	if !strings.HasPrefix(dependencyPattern, resourceKW) {
-		return fmt.Sprintf("projects/%s/locations/global/%s", projectID, dependencyPattern), nil
+		return fmt.Sprintf("%s/%s", location, dependencyPattern), nil
 	}
 
 	entityRegex := regexp.MustCompile(fmt.Sprintf(`(\%s\.(api|version|spec|artifact))(/|$)`, resourceKW))
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
 )
 
 func TestExtendDependencyPattern(t *testing.T) {
@@ -63,7 +64,7 @@ func TestExtendDependencyPattern(t *testing.T) {
 	const projectID = "demo"
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			got, err := extendDependencyPattern(test.resourcePattern, test.dependencyPattern, projectID)
+			got, err := extendDependencyPattern(test.resourcePattern, test.dependencyPattern, names.Project{ProjectID: projectID}.Location(names.DefaultLocation))
 			if err != nil {
 				t.Errorf("extendDependencyPattern returned unexpected error: %s", err)
 			}
@@ -100,7 +101,7 @@ func TestExtendDependencyPatternError(t *testing.T) {
 	const projectID = "demo"
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			got, err := extendDependencyPattern(test.resourcePattern, test.dependencyPattern, projectID)
+			got, err := extendDependencyPattern(test.resourcePattern, test.dependencyPattern, names.Project{ProjectID: projectID}.Location(names.DefaultLocation))
 			if err == nil {
 				t.Errorf("expected extendDependencyPattern to return error, got: %q", got)
 			}
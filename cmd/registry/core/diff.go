@@ -0,0 +1,65 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	breakingchangedetector "github.com/apigee/registry/cmd/registry/breaking-change-detector"
+	"github.com/apigee/registry/cmd/registry/diff"
+	"github.com/apigee/registry/connection"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
+)
+
+// DiffSpecRevisions returns the changes between two revisions of an OpenAPI 3
+// spec, along with their classification as breaking, non-breaking, or unknown.
+// Revisions are named by spec revision names like "SPEC@REVISION".
+func DiffSpecRevisions(ctx context.Context, client connection.Client, base, revision string) (*rpc.Diff, *rpc.ChangeDetails, error) {
+	baseContents, err := getOpenAPI3Contents(ctx, client, base)
+	if err != nil {
+		return nil, nil, err
+	}
+	revisionContents, err := getOpenAPI3Contents(ctx, client, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d, err := diff.GetDiff(baseContents, revisionContents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, breakingchangedetector.GetChangeDetails(d), nil
+}
+
+// getOpenAPI3Contents returns the uncompressed contents of a spec revision,
+// which must be an OpenAPI 3 spec.
+func getOpenAPI3Contents(ctx context.Context, client connection.Client, name string) ([]byte, error) {
+	body, err := client.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	if !mimetypes.Is(body.GetContentType(), mimetypes.OpenAPI, "3") {
+		return nil, fmt.Errorf("%s has MIME type %q, only OpenAPI 3 specs can be compared", name, body.GetContentType())
+	}
+	contents := body.GetData()
+	if mimetypes.IsCompressed(body.GetContentType(), mimetypes.GZip) {
+		if contents, err = GUnzippedBytes(contents); err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
@@ -0,0 +1,284 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputUsage describes the values of the --output flag.
+const OutputUsage = "Output format: json, yaml, table, name, or template=<go-template>"
+
+// ColumnsUsage describes the values of the --columns flag.
+const ColumnsUsage = "Columns of table output, e.g. name,labels.team"
+
+// Printer writes resources in an output format. Resources are written when
+// the printer is flushed, so that tables can be aligned and lists can be
+// written as single JSON or YAML documents.
+//
+// All formats render resources the same way: fields are named as in the
+// proto definitions, timestamps are in RFC 3339 format, and labels and
+// annotations are maps. Tables render maps as comma-separated key=value pairs.
+type Printer struct {
+	// List writes JSON and YAML as a list of resources, even if there is
+	// only one. Set it for commands that list resources.
+	List bool
+
+	w        io.Writer
+	format   string
+	columns  []string
+	template *template.Template
+	items    []interface{}
+}
+
+// NewPrinter returns a printer for an output format. Tables have the given
+// columns or, if there are none, the default columns of their resources.
+func NewPrinter(w io.Writer, output string, columns []string) (*Printer, error) {
+	p := &Printer{w: w, format: output, columns: columns}
+	switch {
+	case output == "json", output == "yaml", output == "table", output == "name":
+	case strings.HasPrefix(output, "template="):
+		t, err := template.New("output").Parse(strings.TrimPrefix(output, "template="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %s", err)
+		}
+		p.format, p.template = "template", t
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, must be json, yaml, table, name, or template=<go-template>", output)
+	}
+	if len(columns) > 0 && p.format != "table" {
+		return nil, fmt.Errorf("columns can only be selected for table output")
+	}
+	return p, nil
+}
+
+// Print adds a resource to the output. Resources are either messages or maps
+// of field names to values.
+func (p *Printer) Print(v interface{}) error {
+	item, err := outputValue(v)
+	if err != nil {
+		return err
+	}
+	if p.columns == nil && p.format == "table" {
+		p.columns = defaultColumns(v)
+	}
+	p.items = append(p.items, item)
+	return nil
+}
+
+// Flush writes the resources that have been printed.
+func (p *Printer) Flush() error {
+	items := p.items
+	p.items = nil
+
+	switch p.format {
+	case "json":
+		var v interface{} = items
+		if !p.List && len(items) == 1 {
+			v = items[0]
+		} else if items == nil {
+			v = []interface{}{}
+		}
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	case "yaml":
+		var v interface{} = items
+		if !p.List && len(items) == 1 {
+			v = items[0]
+		} else if items == nil {
+			v = []interface{}{}
+		}
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(b)
+		return err
+	case "name":
+		for _, item := range items {
+			if _, err := fmt.Fprintln(p.w, cellValue(lookup(item, "name"))); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "template":
+		for _, item := range items {
+			if err := p.template.Execute(p.w, item); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(p.w); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "table":
+		var buf bytes.Buffer
+		tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		header := make([]string, len(p.columns))
+		for i, c := range p.columns {
+			header[i] = strings.ToUpper(c)
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, item := range items {
+			row := make([]string, len(p.columns))
+			for i, c := range p.columns {
+				row[i] = cellValue(lookup(item, c))
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		// Empty trailing cells leave spaces at the ends of lines.
+		for _, line := range strings.SplitAfter(buf.String(), "\n") {
+			if line == "" {
+				continue
+			}
+			if _, err := fmt.Fprintln(p.w, strings.TrimRight(line, " \n")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// outputValue converts a resource to generic maps and values, the form used
+// by every output format.
+func outputValue(v interface{}) (interface{}, error) {
+	var b []byte
+	var err error
+	if m, ok := v.(proto.Message); ok {
+		b, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return numbers(value), nil
+}
+
+// numbers replaces JSON numbers with integers or floats, so that YAML output
+// doesn't quote them or write integers in exponent notation.
+func numbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = numbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = numbers(e)
+		}
+	}
+	return v
+}
+
+// lookup returns the value of a field. Fields of nested maps, like a label,
+// are named by paths such as "labels.team".
+func lookup(item interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		item = m[key]
+	}
+	return item
+}
+
+// cellValue renders a value as a single line of text.
+func cellValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + "=" + cellValue(v[k])
+		}
+		return strings.Join(pairs, ",")
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = cellValue(e)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// defaultColumns returns the table columns of a resource.
+func defaultColumns(v interface{}) []string {
+	switch v := v.(type) {
+	case *rpc.Project:
+		return []string{"name", "display_name", "create_time"}
+	case *rpc.Api:
+		return []string{"name", "display_name", "availability", "recommended_version", "create_time"}
+	case *rpc.ApiVersion:
+		return []string{"name", "state", "create_time"}
+	case *rpc.ApiSpec:
+		return []string{"name", "mime_type", "revision_id", "create_time"}
+	case *rpc.Artifact:
+		return []string{"name", "mime_type", "create_time"}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	default:
+		return []string{"name"}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPrinter(t *testing.T) {
+	created := timestamppb.New(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	apis := []*rpc.Api{
+		{
+			Name:         "projects/p/locations/global/apis/a",
+			DisplayName:  "A",
+			Availability: "GENERAL",
+			CreateTime:   created,
+			Labels:       map[string]string{"team": "core", "tier": "1"},
+		},
+		{
+			Name:       "projects/p/locations/global/apis/bee",
+			CreateTime: created,
+		},
+	}
+
+	tests := []struct {
+		output  string
+		columns []string
+		list    bool
+		want    string
+	}{
+		{
+			output: "name",
+			want:   "projects/p/locations/global/apis/a\nprojects/p/locations/global/apis/bee\n",
+		},
+		{
+			output:  "table",
+			columns: []string{"name", "labels", "labels.team"},
+			want: `NAME                                  LABELS            LABELS.TEAM
+projects/p/locations/global/apis/a    team=core,tier=1  core
+projects/p/locations/global/apis/bee
+`,
+		},
+		{
+			output: "template={{.name}} {{.create_time}}",
+			want:   "projects/p/locations/global/apis/a 2021-10-01T12:00:00Z\nprojects/p/locations/global/apis/bee 2021-10-01T12:00:00Z\n",
+		},
+		{
+			output: "yaml",
+			list:   true,
+			want: `- availability: GENERAL
+  create_time: "2021-10-01T12:00:00Z"
+  display_name: A
+  labels:
+    team: core
+    tier: "1"
+  name: projects/p/locations/global/apis/a
+- create_time: "2021-10-01T12:00:00Z"
+  name: projects/p/locations/global/apis/bee
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			var buf bytes.Buffer
+			p, err := NewPrinter(&buf, test.output, test.columns)
+			if err != nil {
+				t.Fatalf("NewPrinter(%q) returned error: %s", test.output, err)
+			}
+			p.List = test.list
+			for _, api := range apis {
+				if err := p.Print(api); err != nil {
+					t.Fatalf("Print(%v) returned error: %s", api, err)
+				}
+			}
+			if err := p.Flush(); err != nil {
+				t.Fatalf("Flush() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, buf.String()); diff != "" {
+				t.Errorf("Printer wrote unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrinterJSON(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := NewPrinter(&buf, "json", nil)
+	if err != nil {
+		t.Fatalf("NewPrinter() returned error: %s", err)
+	}
+	if err := p.Print(map[string]interface{}{"name": "projects/p/locations/global/apis/a", "versions": 12}); err != nil {
+		t.Fatalf("Print() returned error: %s", err)
+	}
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush() returned error: %s", err)
+	}
+
+	want := `{
+  "name": "projects/p/locations/global/apis/a",
+  "versions": 12
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Printer wrote unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	tests := []struct {
+		output  string
+		columns []string
+	}{
+		{output: "xml"},
+		{output: "template={{.name"},
+		{output: "json", columns: []string{"name"}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewPrinter(&bytes.Buffer{}, test.output, test.columns); err == nil {
+			t.Errorf("NewPrinter(%q, %v) succeeded, want error", test.output, test.columns)
+		}
+	}
+}
@@ -92,8 +92,16 @@ func PrintArtifactContents(artifact *rpc.Artifact) {
 		unmarshalAndPrint(artifact.GetContents(), &metrics.Vocabulary{})
 	case "gnostic.metrics.VersionHistory":
 		unmarshalAndPrint(artifact.GetContents(), &metrics.VersionHistory{})
+	case "google.cloud.apigeeregistry.applications.v1alpha1.ChangeDetails":
+		unmarshalAndPrint(artifact.GetContents(), &rpc.ChangeDetails{})
+	case "google.cloud.apigeeregistry.applications.v1alpha1.ChangeMetrics":
+		unmarshalAndPrint(artifact.GetContents(), &rpc.ChangeMetrics{})
+	case "google.cloud.apigeeregistry.applications.v1alpha1.ChangeStats":
+		unmarshalAndPrint(artifact.GetContents(), &rpc.ChangeStats{})
 	case "google.cloud.apigeeregistry.applications.v1alpha1.ConformanceReport":
 		unmarshalAndPrint(artifact.GetContents(), &rpc.ConformanceReport{})
+	case "google.cloud.apigeeregistry.applications.v1alpha1.Diff":
+		unmarshalAndPrint(artifact.GetContents(), &rpc.Diff{})
 	case "google.cloud.apigeeregistry.applications.v1alpha1.Index":
 		unmarshalAndPrint(artifact.GetContents(), &rpc.Index{})
 	case "google.cloud.apigeeregistry.applications.v1alpha1.Lint":
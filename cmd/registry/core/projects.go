@@ -16,10 +16,13 @@ package core
 
 import (
 	"context"
+	"errors"
 
+	"github.com/apigee/registry/connection"
 	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/log"
 	"github.com/apigee/registry/rpc"
+	"github.com/spf13/cobra"
 )
 
 func EnsureProjectExists(ctx context.Context, client *gapic.AdminClient, projectID string) {
@@ -37,3 +40,33 @@ func EnsureProjectExists(ctx context.Context, client *gapic.AdminClient, project
 		log.FromContext(ctx).WithError(err).Fatal("GetProject returned error during project existence check")
 	}
 }
+
+// ProjectAndLocation returns the project and location set by the
+// --project-id and --location flags of a command. Flags that aren't set
+// default to the project and location of the active connection context.
+func ProjectAndLocation(cmd *cobra.Command) (string, string, error) {
+	projectID, err := cmd.Flags().GetString("project-id")
+	if err != nil {
+		return "", "", err
+	}
+	locationID, err := cmd.Flags().GetString("location")
+	if err != nil {
+		return "", "", err
+	}
+
+	settings, err := connection.ActiveSettings()
+	if err != nil {
+		return "", "", err
+	}
+	if projectID == "" {
+		projectID = settings.Project
+	}
+	if !cmd.Flags().Changed("location") && settings.Location != "" {
+		locationID = settings.Location
+	}
+
+	if projectID == "" {
+		return "", "", errors.New("--project-id must be set when the context has no default project")
+	}
+	return projectID, locationID, nil
+}
@@ -18,61 +18,58 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/apigee/registry/server/registry/mimetypes"
 )
 
 // OpenAPIMimeType returns a MIME type for an OpenAPI description of an API.
 func OpenAPIMimeType(compression, version string) string {
-	return fmt.Sprintf("application/x.openapi%s;version=%s", compression, version)
+	return mimetypes.ForFormat(mimetypes.OpenAPI, strings.TrimPrefix(compression, "+"), version)
 }
 
 // DiscoveryMimeType returns a MIME type for a Discovery description of an API.
 func DiscoveryMimeType(compression string) string {
-	return fmt.Sprintf("application/x.discovery%s", compression)
+	return mimetypes.ForFormat(mimetypes.Discovery, strings.TrimPrefix(compression, "+"), "")
 }
 
 // ProtobufMimeType returns a MIME type for a Protocol Buffers description of an API.
 func ProtobufMimeType(compression string) string {
-	return fmt.Sprintf("application/x.protobuf%s", compression)
+	return mimetypes.ForFormat(mimetypes.Protobuf, strings.TrimPrefix(compression, "+"), "")
 }
 
-// TODO: tighten these up, possibly using regular expressions.
-
 // IsAsyncAPIv2 returns true if a MIME type represents an AsyncAPI v2 spec.
 func IsAsyncAPIv2(mimeType string) bool {
-	return strings.Contains(mimeType, "asyncapi") &&
-		strings.Contains(mimeType, "version=2")
+	return mimetypes.Is(mimeType, mimetypes.AsyncAPI, "2")
 }
 
 // IsOpenAPIv2 returns true if a MIME type represents an OpenAPI v2 spec.
 func IsOpenAPIv2(mimeType string) bool {
-	return strings.Contains(mimeType, "openapi") &&
-		strings.Contains(mimeType, "version=2")
+	return mimetypes.Is(mimeType, mimetypes.OpenAPI, "2")
 }
 
 // IsOpenAPIv3 returns true if a MIME type represents an OpenAPI v3 spec.
 func IsOpenAPIv3(mimeType string) bool {
-	return strings.Contains(mimeType, "openapi") &&
-		strings.Contains(mimeType, "version=3")
+	return mimetypes.Is(mimeType, mimetypes.OpenAPI, "3")
 }
 
 // IsDiscovery returns true if a MIME type represents a Google API Discovery document.
 func IsDiscovery(mimeType string) bool {
-	return strings.Contains(mimeType, "discovery")
+	return mimetypes.Is(mimeType, mimetypes.Discovery, "")
 }
 
 // IsProto returns true if a MIME type represents a Protocol Buffers Language API description.
 func IsProto(mimeType string) bool {
-	return strings.Contains(mimeType, "proto")
+	return mimetypes.Is(mimeType, mimetypes.Protobuf, "")
 }
 
 // IsGZipCompressed returns true if a MIME type represents a type compressed with GZip encoding.
 func IsGZipCompressed(mimeType string) bool {
-	return strings.Contains(mimeType, "+gzip")
+	return mimetypes.IsCompressed(mimeType, mimetypes.GZip)
 }
 
 // IsZipArchive returns true if a MIME type represents a type stored as a multifile Zip archive.
 func IsZipArchive(mimeType string) bool {
-	return strings.Contains(mimeType, "+zip")
+	return mimetypes.IsCompressed(mimeType, mimetypes.Zip)
 }
 
 // MimeTypeForMessageType returns a MIME type that represents a Protocol Buffer message type.
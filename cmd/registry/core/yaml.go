@@ -16,9 +16,12 @@ package core
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"path"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/apigee/registry/gapic"
 	"github.com/apigee/registry/log"
@@ -54,9 +57,14 @@ func exportProject(ctx context.Context, client *gapic.RegistryClient, adminClien
 	}
 	projectMapContent := nodeSlice()
 	apisMapContent := nodeSlice()
-	err = ListAPIs(ctx, client, project.Api(""), "", func(message *rpc.Api) {
+	err = ListAPIs(ctx, client, project.Location("-").Api(""), "", func(message *rpc.Api) {
 		apiMapContent := exportAPI(ctx, client, message)
-		apisMapContent = appendPair(apisMapContent, path.Base(message.Name), nodeForMapping(apiMapContent))
+		// APIs outside the default location are qualified by their location.
+		key := path.Base(message.Name)
+		if api, err := names.ParseApi(message.Name); err == nil && api.LocationID != names.DefaultLocation {
+			key = api.LocationID + "/" + key
+		}
+		apisMapContent = appendPair(apisMapContent, key, nodeForMapping(apiMapContent))
 	})
 	if err != nil {
 		log.FromContext(ctx).WithError(err).Fatal("Failed to list APIs")
@@ -149,8 +157,14 @@ func exportSpec(ctx context.Context, client *gapic.RegistryClient, message *rpc.
 func exportArtifact(ctx context.Context, client *gapic.RegistryClient, message *rpc.Artifact) []*yaml.Node {
 	artifactMapContent := nodeSlice()
 	artifactMapContent = appendPair(artifactMapContent, "mime_type", nodeForString(message.GetMimeType()))
-	artifactMapContent = appendPair(artifactMapContent, "contents", nodeForString(fmt.Sprintf("%+v", message.GetContents())))
+	artifactMapContent = appendPair(artifactMapContent, "contents", nodeForBytes(message.GetContents()))
 	artifactMapContent = appendPair(artifactMapContent, "createTime", nodeForTime(message.CreateTime.AsTime()))
+	if len(message.GetLabels()) > 0 {
+		artifactMapContent = appendPair(artifactMapContent, "labels", nodeForStringMap(message.GetLabels()))
+	}
+	if len(message.GetAnnotations()) > 0 {
+		artifactMapContent = appendPair(artifactMapContent, "annotations", nodeForStringMap(message.GetAnnotations()))
+	}
 	return artifactMapContent
 }
 
@@ -172,6 +186,19 @@ func nodeForString(value string) *yaml.Node {
 	}
 }
 
+// nodeForBytes returns a string node for UTF-8 text and a base64-encoded
+// binary node for other contents, so that both can be read back.
+func nodeForBytes(value []byte) *yaml.Node {
+	if utf8.Valid(value) {
+		return nodeForString(string(value))
+	}
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!binary",
+		Value: base64.StdEncoding.EncodeToString(value),
+	}
+}
+
 func nodeForInt64(value int64) *yaml.Node {
 	return &yaml.Node{
 		Kind:  yaml.ScalarNode,
@@ -180,6 +207,20 @@ func nodeForInt64(value int64) *yaml.Node {
 	}
 }
 
+// nodeForStringMap returns a mapping node with the entries of a map in key order.
+func nodeForStringMap(m map[string]string) *yaml.Node {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	content := nodeSlice()
+	for _, k := range keys {
+		content = appendPair(content, k, nodeForString(m[k]))
+	}
+	return nodeForMapping(content)
+}
+
 func nodeForTime(t time.Time) *yaml.Node {
 	s, _ := t.MarshalText()
 	return nodeForString(string(s))
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // UnzipArchiveToPath will decompress a zip archive, writing all files and folders
@@ -69,6 +70,10 @@ func UnzipArchiveToPath(b []byte, dest string) ([]string, error) {
 	return filenames, nil
 }
 
+// zipModTime is the modification time of the files in archives created by
+// ZipArchiveOfPath, which is the earliest time of the zip format.
+var zipModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
 // ZipArchiveOfPath reads the contents of a path into a zip archive.
 // The specified prefix is stripped from file names in the archive.
 // Based on an example published at https://golangcode.com/create-zip-files-in-go/
@@ -113,6 +118,9 @@ func addFileToZip(zipWriter *zip.Writer, filename, prefix string) error {
 	// Set to Deflate to gain better compression
 	// see http://golang.org/pkg/archive/zip/#pkg-constants
 	header.Method = zip.Deflate
+	// Modification times are fixed so that archives of the same files are
+	// identical and have the same hash.
+	header.Modified = zipModTime
 	writer, err := zipWriter.CreateHeader(header)
 	if err != nil {
 		return err
@@ -24,18 +24,9 @@ func ComputeStats(diffs ...*rpc.ChangeDetails) *rpc.ChangeStats {
 	var nonbreaking int64 = 0
 	var unknown int64 = 0
 	for _, diff := range diffs {
-
-		breaking += int64(len(diff.BreakingChanges.Additions))
-		breaking += int64(len(diff.BreakingChanges.Deletions))
-		breaking += int64(len(diff.BreakingChanges.Modifications))
-
-		nonbreaking += int64(len(diff.NonBreakingChanges.Additions))
-		nonbreaking += int64(len(diff.NonBreakingChanges.Deletions))
-		nonbreaking += int64(len(diff.NonBreakingChanges.Modifications))
-
-		unknown += int64(len(diff.UnknownChanges.Additions))
-		unknown += int64(len(diff.UnknownChanges.Deletions))
-		unknown += int64(len(diff.UnknownChanges.Modifications))
+		breaking += changeCount(diff.GetBreakingChanges())
+		nonbreaking += changeCount(diff.GetNonBreakingChanges())
+		unknown += changeCount(diff.GetUnknownChanges())
 	}
 
 	return &rpc.ChangeStats{
@@ -48,11 +39,17 @@ func ComputeStats(diffs ...*rpc.ChangeDetails) *rpc.ChangeStats {
 
 // ComputeMetrics will compute the metrics proto for a list of Classified Diffs.
 func ComputeMetrics(stats *rpc.ChangeStats) *rpc.ChangeMetrics {
-	breakingChangePercentage := (float64(stats.BreakingChangeCount) /
-		float64(stats.BreakingChangeCount+stats.NonbreakingChangeCount))
-	breakingChangeRate := float64(stats.BreakingChangeCount) / float64(stats.DiffCount)
-	return &rpc.ChangeMetrics{
-		BreakingChangePercentage: breakingChangePercentage,
-		BreakingChangeRate:       breakingChangeRate,
+	metrics := &rpc.ChangeMetrics{}
+	// Metrics of stats without changes or diffs are zero instead of NaN.
+	if changes := stats.BreakingChangeCount + stats.NonbreakingChangeCount; changes > 0 {
+		metrics.BreakingChangePercentage = float64(stats.BreakingChangeCount) / float64(changes)
 	}
+	if stats.DiffCount > 0 {
+		metrics.BreakingChangeRate = float64(stats.BreakingChangeCount) / float64(stats.DiffCount)
+	}
+	return metrics
+}
+
+func changeCount(diff *rpc.Diff) int64 {
+	return int64(len(diff.GetAdditions()) + len(diff.GetDeletions()) + len(diff.GetModifications()))
 }
@@ -186,6 +186,21 @@ func TestMetrics(t *testing.T) {
 				DiffCount:              2,
 			},
 		},
+		{
+			desc: "No Changes Test",
+			diffProtos: []*rpc.ChangeDetails{
+				{},
+			},
+			wantMetrics: &rpc.ChangeMetrics{
+				BreakingChangePercentage: 0,
+				BreakingChangeRate:       0,
+			},
+			wantStats: &rpc.ChangeStats{
+				BreakingChangeCount:    0,
+				NonbreakingChangeCount: 0,
+				DiffCount:              1,
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
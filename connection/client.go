@@ -17,8 +17,6 @@ package connection
 import (
 	"context"
 	"fmt"
-	"os"
-	"strconv"
 
 	"github.com/apigee/registry/gapic"
 	"golang.org/x/oauth2"
@@ -28,19 +26,25 @@ import (
 
 // Settings configure the client.
 type Settings struct {
-	Address  string // service address
-	Insecure bool   // if true, connect over HTTP
-	Token    string // bearer token
+	Address        string // service address
+	Insecure       bool   // if true, connect over HTTP
+	Token          string // bearer token
+	CAFile         string // PEM-encoded certificates used to verify the server
+	ClientCertFile string // PEM-encoded client certificate for mutual TLS
+	ClientKeyFile  string // PEM-encoded private key of the client certificate
+	ServerName     string // overrides the server name used to verify the server
+	Project        string // default project
+	Location       string // default location
 }
 
 func newSettings() (*Settings, error) {
-	settings := &Settings{}
-	settings.Address = os.Getenv("APG_REGISTRY_ADDRESS")
+	settings, err := ActiveSettings()
+	if err != nil {
+		return nil, err
+	}
 	if settings.Address == "" {
-		return nil, fmt.Errorf("rpc error: APG_REGISTRY_ADDRESS must be set")
+		return nil, fmt.Errorf("rpc error: APG_REGISTRY_ADDRESS must be set or a context must be configured with \"registry config set-context\"")
 	}
-	settings.Insecure, _ = strconv.ParseBool(os.Getenv("APG_REGISTRY_INSECURE"))
-	settings.Token = os.Getenv("APG_REGISTRY_TOKEN")
 	return settings, nil
 }
 
@@ -56,6 +60,12 @@ func clientOptions(settings *Settings) ([]option.ClientOption, error) {
 			return nil, err
 		}
 		opts = append(opts, option.WithGRPCConn(conn))
+	} else if settings.customTLS() {
+		conn, err := dialTLS(settings)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, option.WithGRPCConn(conn))
 	}
 	if settings.Token != "" {
 		opts = append(opts, option.WithTokenSource(oauth2.StaticTokenSource(
@@ -0,0 +1,244 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apigee/registry/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the contents of a configuration file, which holds named
+// contexts with the settings of different registries.
+type Config struct {
+	CurrentContext string              `yaml:"current-context,omitempty"`
+	Contexts       map[string]*Context `yaml:"contexts,omitempty"`
+}
+
+// Context holds the settings of a connection to a registry.
+type Context struct {
+	Address        string `yaml:"address,omitempty"`
+	Insecure       bool   `yaml:"insecure,omitempty"`
+	Token          string `yaml:"token,omitempty"`
+	TokenCommand   string `yaml:"token-command,omitempty"` // a shell command that prints a token
+	CAFile         string `yaml:"ca-file,omitempty"`
+	ClientCertFile string `yaml:"client-cert-file,omitempty"`
+	ClientKeyFile  string `yaml:"client-key-file,omitempty"`
+	ServerName     string `yaml:"server-name,omitempty"`
+	Project        string `yaml:"project,omitempty"`  // default project
+	Location       string `yaml:"location,omitempty"` // default location
+}
+
+// selectedContext overrides the current context of the configuration file.
+var selectedContext string
+
+// SelectContext selects a context of the configuration file to use instead
+// of its current context.
+func SelectContext(name string) {
+	selectedContext = name
+}
+
+// ConfigPath returns the path of the configuration file, which is set by
+// APG_REGISTRY_CONFIG or is config.yaml in the user's registry config directory.
+func ConfigPath() (string, error) {
+	if path := os.Getenv("APG_REGISTRY_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "registry", "config.yaml"), nil
+}
+
+// ReadConfig reads a configuration file. A missing file is an empty
+// configuration.
+func ReadConfig(path string) (*Config, error) {
+	config := &Config{}
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %s", path, err)
+	}
+	return config, nil
+}
+
+// Write writes a configuration file. It is only readable by its owner,
+// because contexts can hold tokens.
+func (c *Config) Write(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+// ContextNames returns the names of the contexts in sorted order.
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeContextName returns the name of the selected context or, if none is
+// selected, of the current context.
+func (c *Config) activeContextName() string {
+	if selectedContext != "" {
+		return selectedContext
+	}
+	return c.CurrentContext
+}
+
+// ActiveContext returns the selected context or, if none is selected, the
+// current context. It returns nil if neither is set.
+func (c *Config) ActiveContext() (*Context, error) {
+	name := c.activeContextName()
+	if name == "" {
+		return nil, nil
+	}
+	context, ok := c.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q is not configured", name)
+	}
+	return context, nil
+}
+
+// ActiveSettings returns the settings of the active context of the
+// configuration file, overridden by any settings in environment variables.
+// If APG_REGISTRY_ADDRESS names another registry than the context, the
+// context's token, token command, and TLS settings are ignored, so that its
+// credentials aren't sent to that registry. Unlike the clients, it doesn't
+// require an address.
+func ActiveSettings() (*Settings, error) {
+	settings := &Settings{}
+
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	active, err := config.ActiveContext()
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		settings = active.settings()
+	}
+
+	if address := os.Getenv("APG_REGISTRY_ADDRESS"); address != "" {
+		if active != nil && address != active.Address {
+			// The context's credentials are for another registry.
+			settings = &Settings{Insecure: settings.Insecure, Project: settings.Project, Location: settings.Location}
+			active = nil
+		}
+		settings.Address = address
+		log.Debugf(context.Background(), "Using address %s from APG_REGISTRY_ADDRESS", address)
+	} else if settings.Address != "" {
+		log.Debugf(context.Background(), "Using address %s from context %q", settings.Address, config.activeContextName())
+	}
+	if v, ok := os.LookupEnv("APG_REGISTRY_INSECURE"); ok {
+		settings.Insecure, _ = strconv.ParseBool(v)
+	}
+	setFromEnv(&settings.Token, "APG_REGISTRY_TOKEN")
+	setFromEnv(&settings.CAFile, "APG_REGISTRY_CA_FILE")
+	setFromEnv(&settings.ClientCertFile, "APG_REGISTRY_CLIENT_CERT_FILE")
+	setFromEnv(&settings.ClientKeyFile, "APG_REGISTRY_CLIENT_KEY_FILE")
+	setFromEnv(&settings.ServerName, "APG_REGISTRY_SERVER_NAME")
+
+	// A token command is only run if no token is set.
+	if settings.Token == "" && active != nil && active.TokenCommand != "" {
+		if settings.Token, err = runTokenCommand(active.TokenCommand); err != nil {
+			return nil, err
+		}
+	}
+
+	return settings, nil
+}
+
+// ContextSettings returns the settings of a named context of the
+// configuration file. Unlike ActiveSettings, it ignores environment variables,
+// so it can describe a registry other than the one that clients use.
+func ContextSettings(name string) (*Settings, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	context, ok := config.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q is not configured", name)
+	}
+	settings := context.settings()
+	if settings.Token == "" && context.TokenCommand != "" {
+		if settings.Token, err = runTokenCommand(context.TokenCommand); err != nil {
+			return nil, err
+		}
+	}
+	return settings, nil
+}
+
+func (c *Context) settings() *Settings {
+	return &Settings{
+		Address:        c.Address,
+		Insecure:       c.Insecure,
+		Token:          c.Token,
+		CAFile:         c.CAFile,
+		ClientCertFile: c.ClientCertFile,
+		ClientKeyFile:  c.ClientKeyFile,
+		ServerName:     c.ServerName,
+		Project:        c.Project,
+		Location:       c.Location,
+	}
+}
+
+func runTokenCommand(command string) (string, error) {
+	out, err := exec.Command("sh", "-c", command).Output()
+	if err != nil {
+		return "", fmt.Errorf("token command %q failed: %s", command, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func setFromEnv(v *string, name string) {
+	if s := os.Getenv(name); s != "" {
+		*v = s
+	}
+}
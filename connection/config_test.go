@@ -0,0 +1,210 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// setenv sets an environment variable for the duration of a test.
+func setenv(t *testing.T, name, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(name)
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatalf("Setenv(%q) returned error: %s", name, err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, old)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+// unsetenv unsets an environment variable for the duration of a test.
+func unsetenv(t *testing.T, name string) {
+	t.Helper()
+	old, ok := os.LookupEnv(name)
+	os.Unsetenv(name)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, old)
+		}
+	})
+}
+
+func writeTestConfig(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "registry", "config.yaml")
+	setenv(t, "APG_REGISTRY_CONFIG", path)
+	for _, name := range []string{
+		"APG_REGISTRY_ADDRESS",
+		"APG_REGISTRY_INSECURE",
+		"APG_REGISTRY_TOKEN",
+		"APG_REGISTRY_CA_FILE",
+		"APG_REGISTRY_CLIENT_CERT_FILE",
+		"APG_REGISTRY_CLIENT_KEY_FILE",
+		"APG_REGISTRY_SERVER_NAME",
+	} {
+		unsetenv(t, name)
+	}
+
+	config := &Config{
+		CurrentContext: "local",
+		Contexts: map[string]*Context{
+			"local": {
+				Address:  "localhost:8080",
+				Insecure: true,
+				Project:  "sandbox",
+			},
+			"prod": {
+				Address:      "registry.example.com:443",
+				TokenCommand: "echo secret",
+				CAFile:       "/etc/registry/ca.pem",
+				Location:     "us-central1",
+			},
+		},
+	}
+	if err := config.Write(path); err != nil {
+		t.Fatalf("Setup: Write() returned error: %s", err)
+	}
+	t.Cleanup(func() { SelectContext("") })
+}
+
+func TestActiveSettings(t *testing.T) {
+	tests := []struct {
+		desc    string
+		context string
+		env     map[string]string
+		want    *Settings
+	}{
+		{
+			desc: "current context",
+			want: &Settings{Address: "localhost:8080", Insecure: true, Project: "sandbox"},
+		},
+		{
+			desc:    "selected context with token command",
+			context: "prod",
+			want:    &Settings{Address: "registry.example.com:443", Token: "secret", CAFile: "/etc/registry/ca.pem", Location: "us-central1"},
+		},
+		{
+			desc:    "address from environment drops context credentials",
+			context: "prod",
+			env:     map[string]string{"APG_REGISTRY_ADDRESS": "localhost:9999"},
+			want:    &Settings{Address: "localhost:9999", Location: "us-central1"},
+		},
+		{
+			desc:    "same address from environment keeps context credentials",
+			context: "prod",
+			env:     map[string]string{"APG_REGISTRY_ADDRESS": "registry.example.com:443"},
+			want:    &Settings{Address: "registry.example.com:443", Token: "secret", CAFile: "/etc/registry/ca.pem", Location: "us-central1"},
+		},
+		{
+			desc:    "environment variables take precedence",
+			context: "prod",
+			env: map[string]string{
+				"APG_REGISTRY_ADDRESS":  "localhost:9999",
+				"APG_REGISTRY_INSECURE": "true",
+				"APG_REGISTRY_TOKEN":    "from-env",
+			},
+			want: &Settings{Address: "localhost:9999", Insecure: true, Token: "from-env", Location: "us-central1"},
+		},
+		{
+			desc: "insecure disabled by environment",
+			env:  map[string]string{"APG_REGISTRY_INSECURE": "false"},
+			want: &Settings{Address: "localhost:8080", Project: "sandbox"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			writeTestConfig(t)
+			for k, v := range test.env {
+				setenv(t, k, v)
+			}
+			SelectContext(test.context)
+
+			got, err := ActiveSettings()
+			if err != nil {
+				t.Fatalf("ActiveSettings() returned error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ActiveSettings() returned unexpected settings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestActiveSettingsErrors(t *testing.T) {
+	writeTestConfig(t)
+	SelectContext("missing")
+	if _, err := ActiveSettings(); err == nil {
+		t.Errorf("ActiveSettings() with an unconfigured context succeeded, want error")
+	}
+}
+
+func TestContextSettings(t *testing.T) {
+	writeTestConfig(t)
+	setenv(t, "APG_REGISTRY_ADDRESS", "localhost:9999")
+
+	got, err := ContextSettings("prod")
+	if err != nil {
+		t.Fatalf("ContextSettings() returned error: %s", err)
+	}
+	want := &Settings{Address: "registry.example.com:443", Token: "secret", CAFile: "/etc/registry/ca.pem", Location: "us-central1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ContextSettings() returned unexpected settings (-want +got):\n%s", diff)
+	}
+
+	if _, err := ContextSettings("missing"); err == nil {
+		t.Errorf("ContextSettings() with an unconfigured context succeeded, want error")
+	}
+}
+
+func TestNewSettingsRequiresAddress(t *testing.T) {
+	setenv(t, "APG_REGISTRY_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	unsetenv(t, "APG_REGISTRY_ADDRESS")
+	if _, err := newSettings(); err == nil {
+		t.Errorf("newSettings() without an address succeeded, want error")
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	writeTestConfig(t)
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("ConfigPath() returned error: %s", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat(%q) returned error: %s", path, err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("config file has permissions %o, want 600", perm)
+	}
+
+	config, err := ReadConfig(path)
+	if err != nil {
+		t.Fatalf("ReadConfig() returned error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"local", "prod"}, config.ContextNames()); diff != "" {
+		t.Errorf("ContextNames() returned unexpected names (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+)
+
+// customTLS returns true if the settings require a TLS configuration
+// other than the default one.
+func (s *Settings) customTLS() bool {
+	return s.CAFile != "" || s.ClientCertFile != "" || s.ClientKeyFile != "" || s.ServerName != ""
+}
+
+// tlsConfig returns the TLS configuration described by the settings.
+func (s *Settings) tlsConfig() (*tls.Config, error) {
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: s.ServerName,
+	}
+	if s.CAFile != "" {
+		pem, err := ioutil.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("rpc error: no certificates found in %s", s.CAFile)
+		}
+	}
+	if (s.ClientCertFile == "") != (s.ClientKeyFile == "") {
+		return nil, fmt.Errorf("rpc error: client certificate and key must be set together")
+	}
+	if s.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.ClientCertFile, s.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
+
+// dialTLS returns a connection that uses the TLS configuration described by
+// the settings. Connections made this way don't use default credentials, so
+// the bearer token, if any, is attached to each call.
+func dialTLS(s *Settings) (*grpc.ClientConn, error) {
+	c, err := s.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(c))}
+	if s.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(oauth.NewOauthAccess(&oauth2.Token{
+			AccessToken: s.Token,
+			TokenType:   "Bearer",
+		})))
+	}
+	return grpc.Dial(s.Address, opts...)
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connection
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// issuer is a certificate and key that can sign other certificates.
+type issuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCertificate(t *testing.T, parent *issuer, template *x509.Certificate) (*issuer, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() returned error: %s", err)
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signer := &issuer{cert: template, key: key}
+	if parent != nil {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() returned error: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() returned error: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() returned error: %s", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return &issuer{cert: cert, key: key}, certPEM, keyPEM
+}
+
+func writeFile(t *testing.T, dir, name string, contents []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		t.Fatalf("WriteFile(%s) returned error: %s", path, err)
+	}
+	return path
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caPEM, _ := newCertificate(t, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	_, serverPEM, serverKeyPEM := newCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "registry.internal"},
+		DNSNames:    []string{"registry.internal"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	_, clientPEM, clientKeyPEM := newCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	serverCert, err := tls.X509KeyPair(serverPEM, serverKeyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair() returned error: %s", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() returned error: %s", err)
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	base := Settings{
+		Address:    listener.Addr().String(),
+		CAFile:     writeFile(t, dir, "ca.pem", caPEM),
+		ServerName: "registry.internal",
+	}
+	withClientCert := base
+	withClientCert.ClientCertFile = writeFile(t, dir, "client.pem", clientPEM)
+	withClientCert.ClientKeyFile = writeFile(t, dir, "client-key.pem", clientKeyPEM)
+	wrongServerName := withClientCert
+	wrongServerName.ServerName = "other.internal"
+
+	tests := []struct {
+		desc     string
+		settings Settings
+		wantErr  bool
+	}{
+		{desc: "client certificate", settings: withClientCert},
+		{desc: "missing client certificate", settings: base, wantErr: true},
+		{desc: "wrong server name", settings: wrongServerName, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, err := dialTLS(&test.settings)
+			if err != nil {
+				t.Fatalf("dialTLS() returned error: %s", err)
+			}
+			defer conn.Close()
+			_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(false))
+			if test.wantErr && err == nil {
+				t.Errorf("Check() succeeded, expected error")
+			} else if !test.wantErr && err != nil {
+				t.Errorf("Check() returned error: %s", err)
+			}
+		})
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		desc     string
+		settings Settings
+	}{
+		{
+			desc:     "missing CA file",
+			settings: Settings{CAFile: filepath.Join(dir, "missing.pem")},
+		},
+		{
+			desc:     "CA file without certificates",
+			settings: Settings{CAFile: writeFile(t, dir, "empty.pem", []byte("not a certificate"))},
+		},
+		{
+			desc:     "client certificate without key",
+			settings: Settings{ClientCertFile: filepath.Join(dir, "client.pem")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := test.settings.tlsConfig(); err == nil {
+				t.Errorf("tlsConfig() succeeded, expected error")
+			}
+		})
+	}
+}
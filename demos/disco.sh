@@ -111,7 +111,7 @@ registry vocabulary intersection projects/$PROJECT/locations/global/apis/-/versi
 
 # We can also save this to a property.
 registry vocabulary intersection projects/$PROJECT/locations/global/apis/-/versions/-/specs/-/artifacts/vocabulary \
-	--filter "api_id.contains('speech')" --output projects/$PROJECT/locations/global/artifacts/speech-common
+	--filter "api_id.contains('speech')" --output-artifact projects/$PROJECT/locations/global/artifacts/speech-common
 
 # We can then read it directly or export it to a Google Sheet.
 registry get projects/$PROJECT/locations/global/artifacts/speech-common --contents
@@ -119,7 +119,7 @@ registry export sheet projects/$PROJECT/locations/global/artifacts/speech-common
 
 # To see a larger vocabulary, let's now compute the union of all the vocabularies in our project.
 registry vocabulary union projects/$PROJECT/locations/global/apis/-/versions/-/specs/-/artifacts/vocabulary \
-	--output projects/$PROJECT/locations/global/artifacts/vocabulary
+	--output-artifact projects/$PROJECT/locations/global/artifacts/vocabulary
 
 # We can also export this with `registry get` but it's easier to view this as a sheet:
 registry export sheet projects/$PROJECT/locations/global/artifacts/vocabulary
@@ -134,7 +134,7 @@ registry vocabulary intersection projects/$PROJECT/locations/global/apis/-/versi
 # We can also save this to a property.
 registry vocabulary intersection projects/$PROJECT/locations/global/apis/-/versions/-/specs/-/artifacts/vocabulary \
   --filter "api_id.startsWith('googleapis')" \
-  --output projects/$PROJECT/locations/global/artifacts/google-common
+  --output-artifact projects/$PROJECT/locations/global/artifacts/google-common
 
 # We can then read it directly or export it to a Google Sheet.
 registry get projects/$PROJECT/locations/global/artifacts/google-common
@@ -38,12 +38,14 @@ var newAdminClientHook clientHook
 
 // AdminCallOptions contains the retry settings for each method of AdminClient.
 type AdminCallOptions struct {
-	GetStatus     []gax.CallOption
-	ListProjects  []gax.CallOption
-	GetProject    []gax.CallOption
-	CreateProject []gax.CallOption
-	UpdateProject []gax.CallOption
-	DeleteProject []gax.CallOption
+	GetStatus      []gax.CallOption
+	ListProjects   []gax.CallOption
+	GetProject     []gax.CallOption
+	CreateProject  []gax.CallOption
+	UpdateProject  []gax.CallOption
+	DeleteProject  []gax.CallOption
+	ListLocations  []gax.CallOption
+	PruneRevisions []gax.CallOption
 }
 
 func defaultAdminGRPCClientOptions() []option.ClientOption {
@@ -61,12 +63,14 @@ func defaultAdminGRPCClientOptions() []option.ClientOption {
 
 func defaultAdminCallOptions() *AdminCallOptions {
 	return &AdminCallOptions{
-		GetStatus:     []gax.CallOption{},
-		ListProjects:  []gax.CallOption{},
-		GetProject:    []gax.CallOption{},
-		CreateProject: []gax.CallOption{},
-		UpdateProject: []gax.CallOption{},
-		DeleteProject: []gax.CallOption{},
+		GetStatus:      []gax.CallOption{},
+		ListProjects:   []gax.CallOption{},
+		GetProject:     []gax.CallOption{},
+		CreateProject:  []gax.CallOption{},
+		UpdateProject:  []gax.CallOption{},
+		DeleteProject:  []gax.CallOption{},
+		ListLocations:  []gax.CallOption{},
+		PruneRevisions: []gax.CallOption{},
 	}
 }
 
@@ -81,6 +85,8 @@ type internalAdminClient interface {
 	CreateProject(context.Context, *rpcpb.CreateProjectRequest, ...gax.CallOption) (*rpcpb.Project, error)
 	UpdateProject(context.Context, *rpcpb.UpdateProjectRequest, ...gax.CallOption) (*rpcpb.Project, error)
 	DeleteProject(context.Context, *rpcpb.DeleteProjectRequest, ...gax.CallOption) error
+	ListLocations(context.Context, *rpcpb.ListLocationsRequest, ...gax.CallOption) (*rpcpb.ListLocationsResponse, error)
+	PruneRevisions(context.Context, *rpcpb.PruneRevisionsRequest, ...gax.CallOption) (*rpcpb.PruneRevisionsResponse, error)
 }
 
 // AdminClient is a client for interacting with .
@@ -163,6 +169,28 @@ func (c *AdminClient) DeleteProject(ctx context.Context, req *rpcpb.DeleteProjec
 	return c.internalClient.DeleteProject(ctx, req, opts...)
 }
 
+// ListLocations listLocations returns the locations of a project.
+// (– api-linter: core::0132::request-unknown-fields=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): the number of locations is small. –)
+// (– api-linter: core::0158::request-page-size-field=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): the number of locations is small. –)
+// (– api-linter: core::0158::request-page-token-field=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): the number of locations is small. –)
+// (– api-linter: core::0158::response-next-page-token-field=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): the number of locations is small. –)
+func (c *AdminClient) ListLocations(ctx context.Context, req *rpcpb.ListLocationsRequest, opts ...gax.CallOption) (*rpcpb.ListLocationsResponse, error) {
+	return c.internalClient.ListLocations(ctx, req, opts...)
+}
+
+// PruneRevisions pruneRevisions deletes the spec and deployment revisions of a project
+// that are not kept by its retention policies. Pruning also runs
+// periodically in the background when it is enabled in the server.
+// (– api-linter: core::0136::http-uri-suffix=disabled
+// aip.dev/not-precedent (at http://aip.dev/not-precedent): Not in the official API. –)
+func (c *AdminClient) PruneRevisions(ctx context.Context, req *rpcpb.PruneRevisionsRequest, opts ...gax.CallOption) (*rpcpb.PruneRevisionsResponse, error) {
+	return c.internalClient.PruneRevisions(ctx, req, opts...)
+}
+
 // adminGRPCClient is a client for interacting with  over gRPC transport.
 //
 // Methods, except Close, may be called concurrently. However, fields must not be modified concurrently with method calls.
@@ -361,6 +389,38 @@ func (c *adminGRPCClient) DeleteProject(ctx context.Context, req *rpcpb.DeletePr
 	return err
 }
 
+func (c *adminGRPCClient) ListLocations(ctx context.Context, req *rpcpb.ListLocationsRequest, opts ...gax.CallOption) (*rpcpb.ListLocationsResponse, error) {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "parent", url.QueryEscape(req.GetParent())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).ListLocations[0:len((*c.CallOptions).ListLocations):len((*c.CallOptions).ListLocations)], opts...)
+	var resp *rpcpb.ListLocationsResponse
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.ListLocations(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *adminGRPCClient) PruneRevisions(ctx context.Context, req *rpcpb.PruneRevisionsRequest, opts ...gax.CallOption) (*rpcpb.PruneRevisionsResponse, error) {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).PruneRevisions[0:len((*c.CallOptions).PruneRevisions):len((*c.CallOptions).PruneRevisions)], opts...)
+	var resp *rpcpb.PruneRevisionsResponse
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.adminClient.PruneRevisions(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // ProjectIterator manages a stream of *rpcpb.Project.
 type ProjectIterator struct {
 	items    []*rpcpb.Project
@@ -75,6 +75,19 @@ type RegistryCallOptions struct {
 	CreateArtifact              []gax.CallOption
 	ReplaceArtifact             []gax.CallOption
 	DeleteArtifact              []gax.CallOption
+	TagArtifactRevision         []gax.CallOption
+	ListArtifactRevisions       []gax.CallOption
+	RollbackArtifact            []gax.CallOption
+	DeleteArtifactRevision      []gax.CallOption
+	ListArtifactTypes           []gax.CallOption
+	GetArtifactType             []gax.CallOption
+	CreateArtifactType          []gax.CallOption
+	DeleteArtifactType          []gax.CallOption
+	ListRelationships           []gax.CallOption
+	GetRelationship             []gax.CallOption
+	CreateRelationship          []gax.CallOption
+	UpdateRelationship          []gax.CallOption
+	DeleteRelationship          []gax.CallOption
 }
 
 func defaultRegistryGRPCClientOptions() []option.ClientOption {
@@ -477,6 +490,149 @@ func defaultRegistryCallOptions() *RegistryCallOptions {
 				})
 			}),
 		},
+		TagArtifactRevision: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		ListArtifactRevisions: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		RollbackArtifact: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		DeleteArtifactRevision: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		ListArtifactTypes: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		GetArtifactType: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		CreateArtifactType: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		DeleteArtifactType: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		ListRelationships: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		GetRelationship: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		CreateRelationship: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		UpdateRelationship: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
+		DeleteRelationship: []gax.CallOption{
+			gax.WithRetry(func() gax.Retryer {
+				return gax.OnCodes([]codes.Code{
+					codes.Unavailable,
+				}, gax.Backoff{
+					Initial:    200 * time.Millisecond,
+					Max:        10000 * time.Millisecond,
+					Multiplier: 1.30,
+				})
+			}),
+		},
 	}
 }
 
@@ -520,6 +676,19 @@ type internalRegistryClient interface {
 	CreateArtifact(context.Context, *rpcpb.CreateArtifactRequest, ...gax.CallOption) (*rpcpb.Artifact, error)
 	ReplaceArtifact(context.Context, *rpcpb.ReplaceArtifactRequest, ...gax.CallOption) (*rpcpb.Artifact, error)
 	DeleteArtifact(context.Context, *rpcpb.DeleteArtifactRequest, ...gax.CallOption) error
+	TagArtifactRevision(context.Context, *rpcpb.TagArtifactRevisionRequest, ...gax.CallOption) (*rpcpb.Artifact, error)
+	ListArtifactRevisions(context.Context, *rpcpb.ListArtifactRevisionsRequest, ...gax.CallOption) *ArtifactIterator
+	RollbackArtifact(context.Context, *rpcpb.RollbackArtifactRequest, ...gax.CallOption) (*rpcpb.Artifact, error)
+	DeleteArtifactRevision(context.Context, *rpcpb.DeleteArtifactRevisionRequest, ...gax.CallOption) (*rpcpb.Artifact, error)
+	ListArtifactTypes(context.Context, *rpcpb.ListArtifactTypesRequest, ...gax.CallOption) *ArtifactTypeIterator
+	GetArtifactType(context.Context, *rpcpb.GetArtifactTypeRequest, ...gax.CallOption) (*rpcpb.ArtifactType, error)
+	CreateArtifactType(context.Context, *rpcpb.CreateArtifactTypeRequest, ...gax.CallOption) (*rpcpb.ArtifactType, error)
+	DeleteArtifactType(context.Context, *rpcpb.DeleteArtifactTypeRequest, ...gax.CallOption) error
+	ListRelationships(context.Context, *rpcpb.ListRelationshipsRequest, ...gax.CallOption) *RelationshipIterator
+	GetRelationship(context.Context, *rpcpb.GetRelationshipRequest, ...gax.CallOption) (*rpcpb.Relationship, error)
+	CreateRelationship(context.Context, *rpcpb.CreateRelationshipRequest, ...gax.CallOption) (*rpcpb.Relationship, error)
+	UpdateRelationship(context.Context, *rpcpb.UpdateRelationshipRequest, ...gax.CallOption) (*rpcpb.Relationship, error)
+	DeleteRelationship(context.Context, *rpcpb.DeleteRelationshipRequest, ...gax.CallOption) error
 }
 
 // RegistryClient is a client for interacting with .
@@ -746,6 +915,76 @@ func (c *RegistryClient) DeleteArtifact(ctx context.Context, req *rpcpb.DeleteAr
 	return c.internalClient.DeleteArtifact(ctx, req, opts...)
 }
 
+// TagArtifactRevision tagArtifactRevision adds a tag to a specified revision of an artifact.
+func (c *RegistryClient) TagArtifactRevision(ctx context.Context, req *rpcpb.TagArtifactRevisionRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	return c.internalClient.TagArtifactRevision(ctx, req, opts...)
+}
+
+// ListArtifactRevisions listArtifactRevisions lists all revisions of an artifact.
+// Revisions are returned in descending order of revision creation time.
+func (c *RegistryClient) ListArtifactRevisions(ctx context.Context, req *rpcpb.ListArtifactRevisionsRequest, opts ...gax.CallOption) *ArtifactIterator {
+	return c.internalClient.ListArtifactRevisions(ctx, req, opts...)
+}
+
+// RollbackArtifact rollbackArtifact sets the current revision to a specified prior revision.
+// Note that this creates a new revision with a new revision ID.
+func (c *RegistryClient) RollbackArtifact(ctx context.Context, req *rpcpb.RollbackArtifactRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	return c.internalClient.RollbackArtifact(ctx, req, opts...)
+}
+
+// DeleteArtifactRevision deleteArtifactRevision deletes a revision of an artifact.
+func (c *RegistryClient) DeleteArtifactRevision(ctx context.Context, req *rpcpb.DeleteArtifactRevisionRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	return c.internalClient.DeleteArtifactRevision(ctx, req, opts...)
+}
+
+// ListArtifactTypes listArtifactTypes returns matching artifact types.
+func (c *RegistryClient) ListArtifactTypes(ctx context.Context, req *rpcpb.ListArtifactTypesRequest, opts ...gax.CallOption) *ArtifactTypeIterator {
+	return c.internalClient.ListArtifactTypes(ctx, req, opts...)
+}
+
+// GetArtifactType getArtifactType returns a specified artifact type.
+func (c *RegistryClient) GetArtifactType(ctx context.Context, req *rpcpb.GetArtifactTypeRequest, opts ...gax.CallOption) (*rpcpb.ArtifactType, error) {
+	return c.internalClient.GetArtifactType(ctx, req, opts...)
+}
+
+// CreateArtifactType createArtifactType registers a schema for artifact contents.
+func (c *RegistryClient) CreateArtifactType(ctx context.Context, req *rpcpb.CreateArtifactTypeRequest, opts ...gax.CallOption) (*rpcpb.ArtifactType, error) {
+	return c.internalClient.CreateArtifactType(ctx, req, opts...)
+}
+
+// DeleteArtifactType deleteArtifactType removes a specified artifact type.
+// Existing artifacts of the type are not affected.
+func (c *RegistryClient) DeleteArtifactType(ctx context.Context, req *rpcpb.DeleteArtifactTypeRequest, opts ...gax.CallOption) error {
+	return c.internalClient.DeleteArtifactType(ctx, req, opts...)
+}
+
+// ListRelationships listRelationships returns matching relationships.
+// Relationships to a resource can be found by listing the relationships
+// of all APIs (with “-” as the API ID) and filtering on their targets.
+func (c *RegistryClient) ListRelationships(ctx context.Context, req *rpcpb.ListRelationshipsRequest, opts ...gax.CallOption) *RelationshipIterator {
+	return c.internalClient.ListRelationships(ctx, req, opts...)
+}
+
+// GetRelationship getRelationship returns a specified relationship.
+func (c *RegistryClient) GetRelationship(ctx context.Context, req *rpcpb.GetRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	return c.internalClient.GetRelationship(ctx, req, opts...)
+}
+
+// CreateRelationship createRelationship creates a specified relationship.
+func (c *RegistryClient) CreateRelationship(ctx context.Context, req *rpcpb.CreateRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	return c.internalClient.CreateRelationship(ctx, req, opts...)
+}
+
+// UpdateRelationship updateRelationship can be used to modify a specified relationship.
+func (c *RegistryClient) UpdateRelationship(ctx context.Context, req *rpcpb.UpdateRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	return c.internalClient.UpdateRelationship(ctx, req, opts...)
+}
+
+// DeleteRelationship deleteRelationship removes a specified relationship.
+func (c *RegistryClient) DeleteRelationship(ctx context.Context, req *rpcpb.DeleteRelationshipRequest, opts ...gax.CallOption) error {
+	return c.internalClient.DeleteRelationship(ctx, req, opts...)
+}
+
 // registryGRPCClient is a client for interacting with  over gRPC transport.
 //
 // Methods, except Close, may be called concurrently. However, fields must not be modified concurrently with method calls.
@@ -1702,6 +1941,340 @@ func (c *registryGRPCClient) DeleteArtifact(ctx context.Context, req *rpcpb.Dele
 	return err
 }
 
+func (c *registryGRPCClient) TagArtifactRevision(ctx context.Context, req *rpcpb.TagArtifactRevisionRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).TagArtifactRevision[0:len((*c.CallOptions).TagArtifactRevision):len((*c.CallOptions).TagArtifactRevision)], opts...)
+	var resp *rpcpb.Artifact
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.TagArtifactRevision(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) ListArtifactRevisions(ctx context.Context, req *rpcpb.ListArtifactRevisionsRequest, opts ...gax.CallOption) *ArtifactIterator {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).ListArtifactRevisions[0:len((*c.CallOptions).ListArtifactRevisions):len((*c.CallOptions).ListArtifactRevisions)], opts...)
+	it := &ArtifactIterator{}
+	req = proto.Clone(req).(*rpcpb.ListArtifactRevisionsRequest)
+	it.InternalFetch = func(pageSize int, pageToken string) ([]*rpcpb.Artifact, string, error) {
+		resp := &rpcpb.ListArtifactRevisionsResponse{}
+		if pageToken != "" {
+			req.PageToken = pageToken
+		}
+		if pageSize > math.MaxInt32 {
+			req.PageSize = math.MaxInt32
+		} else if pageSize != 0 {
+			req.PageSize = int32(pageSize)
+		}
+		err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+			var err error
+			resp, err = c.registryClient.ListArtifactRevisions(ctx, req, settings.GRPC...)
+			return err
+		}, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		it.Response = resp
+		return resp.GetArtifacts(), resp.GetNextPageToken(), nil
+	}
+	fetch := func(pageSize int, pageToken string) (string, error) {
+		items, nextPageToken, err := it.InternalFetch(pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		it.items = append(it.items, items...)
+		return nextPageToken, nil
+	}
+
+	it.pageInfo, it.nextFunc = iterator.NewPageInfo(fetch, it.bufLen, it.takeBuf)
+	it.pageInfo.MaxSize = int(req.GetPageSize())
+	it.pageInfo.Token = req.GetPageToken()
+
+	return it
+}
+
+func (c *registryGRPCClient) RollbackArtifact(ctx context.Context, req *rpcpb.RollbackArtifactRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).RollbackArtifact[0:len((*c.CallOptions).RollbackArtifact):len((*c.CallOptions).RollbackArtifact)], opts...)
+	var resp *rpcpb.Artifact
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.RollbackArtifact(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) DeleteArtifactRevision(ctx context.Context, req *rpcpb.DeleteArtifactRevisionRequest, opts ...gax.CallOption) (*rpcpb.Artifact, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).DeleteArtifactRevision[0:len((*c.CallOptions).DeleteArtifactRevision):len((*c.CallOptions).DeleteArtifactRevision)], opts...)
+	var resp *rpcpb.Artifact
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.DeleteArtifactRevision(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) ListArtifactTypes(ctx context.Context, req *rpcpb.ListArtifactTypesRequest, opts ...gax.CallOption) *ArtifactTypeIterator {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "parent", url.QueryEscape(req.GetParent())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).ListArtifactTypes[0:len((*c.CallOptions).ListArtifactTypes):len((*c.CallOptions).ListArtifactTypes)], opts...)
+	it := &ArtifactTypeIterator{}
+	req = proto.Clone(req).(*rpcpb.ListArtifactTypesRequest)
+	it.InternalFetch = func(pageSize int, pageToken string) ([]*rpcpb.ArtifactType, string, error) {
+		resp := &rpcpb.ListArtifactTypesResponse{}
+		if pageToken != "" {
+			req.PageToken = pageToken
+		}
+		if pageSize > math.MaxInt32 {
+			req.PageSize = math.MaxInt32
+		} else if pageSize != 0 {
+			req.PageSize = int32(pageSize)
+		}
+		err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+			var err error
+			resp, err = c.registryClient.ListArtifactTypes(ctx, req, settings.GRPC...)
+			return err
+		}, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		it.Response = resp
+		return resp.GetArtifactTypes(), resp.GetNextPageToken(), nil
+	}
+	fetch := func(pageSize int, pageToken string) (string, error) {
+		items, nextPageToken, err := it.InternalFetch(pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		it.items = append(it.items, items...)
+		return nextPageToken, nil
+	}
+
+	it.pageInfo, it.nextFunc = iterator.NewPageInfo(fetch, it.bufLen, it.takeBuf)
+	it.pageInfo.MaxSize = int(req.GetPageSize())
+	it.pageInfo.Token = req.GetPageToken()
+
+	return it
+}
+
+func (c *registryGRPCClient) GetArtifactType(ctx context.Context, req *rpcpb.GetArtifactTypeRequest, opts ...gax.CallOption) (*rpcpb.ArtifactType, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).GetArtifactType[0:len((*c.CallOptions).GetArtifactType):len((*c.CallOptions).GetArtifactType)], opts...)
+	var resp *rpcpb.ArtifactType
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.GetArtifactType(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) CreateArtifactType(ctx context.Context, req *rpcpb.CreateArtifactTypeRequest, opts ...gax.CallOption) (*rpcpb.ArtifactType, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "parent", url.QueryEscape(req.GetParent())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).CreateArtifactType[0:len((*c.CallOptions).CreateArtifactType):len((*c.CallOptions).CreateArtifactType)], opts...)
+	var resp *rpcpb.ArtifactType
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.CreateArtifactType(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) DeleteArtifactType(ctx context.Context, req *rpcpb.DeleteArtifactTypeRequest, opts ...gax.CallOption) error {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).DeleteArtifactType[0:len((*c.CallOptions).DeleteArtifactType):len((*c.CallOptions).DeleteArtifactType)], opts...)
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		_, err = c.registryClient.DeleteArtifactType(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	return err
+}
+
+func (c *registryGRPCClient) ListRelationships(ctx context.Context, req *rpcpb.ListRelationshipsRequest, opts ...gax.CallOption) *RelationshipIterator {
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "parent", url.QueryEscape(req.GetParent())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).ListRelationships[0:len((*c.CallOptions).ListRelationships):len((*c.CallOptions).ListRelationships)], opts...)
+	it := &RelationshipIterator{}
+	req = proto.Clone(req).(*rpcpb.ListRelationshipsRequest)
+	it.InternalFetch = func(pageSize int, pageToken string) ([]*rpcpb.Relationship, string, error) {
+		resp := &rpcpb.ListRelationshipsResponse{}
+		if pageToken != "" {
+			req.PageToken = pageToken
+		}
+		if pageSize > math.MaxInt32 {
+			req.PageSize = math.MaxInt32
+		} else if pageSize != 0 {
+			req.PageSize = int32(pageSize)
+		}
+		err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+			var err error
+			resp, err = c.registryClient.ListRelationships(ctx, req, settings.GRPC...)
+			return err
+		}, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		it.Response = resp
+		return resp.GetRelationships(), resp.GetNextPageToken(), nil
+	}
+	fetch := func(pageSize int, pageToken string) (string, error) {
+		items, nextPageToken, err := it.InternalFetch(pageSize, pageToken)
+		if err != nil {
+			return "", err
+		}
+		it.items = append(it.items, items...)
+		return nextPageToken, nil
+	}
+
+	it.pageInfo, it.nextFunc = iterator.NewPageInfo(fetch, it.bufLen, it.takeBuf)
+	it.pageInfo.MaxSize = int(req.GetPageSize())
+	it.pageInfo.Token = req.GetPageToken()
+
+	return it
+}
+
+func (c *registryGRPCClient) GetRelationship(ctx context.Context, req *rpcpb.GetRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).GetRelationship[0:len((*c.CallOptions).GetRelationship):len((*c.CallOptions).GetRelationship)], opts...)
+	var resp *rpcpb.Relationship
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.GetRelationship(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) CreateRelationship(ctx context.Context, req *rpcpb.CreateRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "parent", url.QueryEscape(req.GetParent())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).CreateRelationship[0:len((*c.CallOptions).CreateRelationship):len((*c.CallOptions).CreateRelationship)], opts...)
+	var resp *rpcpb.Relationship
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.CreateRelationship(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) UpdateRelationship(ctx context.Context, req *rpcpb.UpdateRelationshipRequest, opts ...gax.CallOption) (*rpcpb.Relationship, error) {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "relationship.name", url.QueryEscape(req.GetRelationship().GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).UpdateRelationship[0:len((*c.CallOptions).UpdateRelationship):len((*c.CallOptions).UpdateRelationship)], opts...)
+	var resp *rpcpb.Relationship
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		resp, err = c.registryClient.UpdateRelationship(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *registryGRPCClient) DeleteRelationship(ctx context.Context, req *rpcpb.DeleteRelationshipRequest, opts ...gax.CallOption) error {
+	if _, ok := ctx.Deadline(); !ok && !c.disableDeadlines {
+		cctx, cancel := context.WithTimeout(ctx, 10000*time.Millisecond)
+		defer cancel()
+		ctx = cctx
+	}
+	md := metadata.Pairs("x-goog-request-params", fmt.Sprintf("%s=%v", "name", url.QueryEscape(req.GetName())))
+	ctx = insertMetadata(ctx, c.xGoogMetadata, md)
+	opts = append((*c.CallOptions).DeleteRelationship[0:len((*c.CallOptions).DeleteRelationship):len((*c.CallOptions).DeleteRelationship)], opts...)
+	err := gax.Invoke(ctx, func(ctx context.Context, settings gax.CallSettings) error {
+		var err error
+		_, err = c.registryClient.DeleteRelationship(ctx, req, settings.GRPC...)
+		return err
+	}, opts...)
+	return err
+}
+
 // ApiDeploymentIterator manages a stream of *rpcpb.ApiDeployment.
 type ApiDeploymentIterator struct {
 	items    []*rpcpb.ApiDeployment
@@ -1937,6 +2510,100 @@ func (it *ArtifactIterator) takeBuf() interface{} {
 	return b
 }
 
+// ArtifactTypeIterator manages a stream of *rpcpb.ArtifactType.
+type ArtifactTypeIterator struct {
+	items    []*rpcpb.ArtifactType
+	pageInfo *iterator.PageInfo
+	nextFunc func() error
+
+	// Response is the raw response for the current page.
+	// It must be cast to the RPC response type.
+	// Calling Next() or InternalFetch() updates this value.
+	Response interface{}
+
+	// InternalFetch is for use by the Google Cloud Libraries only.
+	// It is not part of the stable interface of this package.
+	//
+	// InternalFetch returns results from a single call to the underlying RPC.
+	// The number of results is no greater than pageSize.
+	// If there are no more results, nextPageToken is empty and err is nil.
+	InternalFetch func(pageSize int, pageToken string) (results []*rpcpb.ArtifactType, nextPageToken string, err error)
+}
+
+// PageInfo supports pagination. See the google.golang.org/api/iterator package for details.
+func (it *ArtifactTypeIterator) PageInfo() *iterator.PageInfo {
+	return it.pageInfo
+}
+
+// Next returns the next result. Its second return value is iterator.Done if there are no more
+// results. Once Next returns Done, all subsequent calls will return Done.
+func (it *ArtifactTypeIterator) Next() (*rpcpb.ArtifactType, error) {
+	var item *rpcpb.ArtifactType
+	if err := it.nextFunc(); err != nil {
+		return item, err
+	}
+	item = it.items[0]
+	it.items = it.items[1:]
+	return item, nil
+}
+
+func (it *ArtifactTypeIterator) bufLen() int {
+	return len(it.items)
+}
+
+func (it *ArtifactTypeIterator) takeBuf() interface{} {
+	b := it.items
+	it.items = nil
+	return b
+}
+
+// RelationshipIterator manages a stream of *rpcpb.Relationship.
+type RelationshipIterator struct {
+	items    []*rpcpb.Relationship
+	pageInfo *iterator.PageInfo
+	nextFunc func() error
+
+	// Response is the raw response for the current page.
+	// It must be cast to the RPC response type.
+	// Calling Next() or InternalFetch() updates this value.
+	Response interface{}
+
+	// InternalFetch is for use by the Google Cloud Libraries only.
+	// It is not part of the stable interface of this package.
+	//
+	// InternalFetch returns results from a single call to the underlying RPC.
+	// The number of results is no greater than pageSize.
+	// If there are no more results, nextPageToken is empty and err is nil.
+	InternalFetch func(pageSize int, pageToken string) (results []*rpcpb.Relationship, nextPageToken string, err error)
+}
+
+// PageInfo supports pagination. See the google.golang.org/api/iterator package for details.
+func (it *RelationshipIterator) PageInfo() *iterator.PageInfo {
+	return it.pageInfo
+}
+
+// Next returns the next result. Its second return value is iterator.Done if there are no more
+// results. Once Next returns Done, all subsequent calls will return Done.
+func (it *RelationshipIterator) Next() (*rpcpb.Relationship, error) {
+	var item *rpcpb.Relationship
+	if err := it.nextFunc(); err != nil {
+		return item, err
+	}
+	item = it.items[0]
+	it.items = it.items[1:]
+	return item, nil
+}
+
+func (it *RelationshipIterator) bufLen() int {
+	return len(it.items)
+}
+
+func (it *RelationshipIterator) takeBuf() interface{} {
+	b := it.items
+	it.items = nil
+	return b
+}
+
 func (c *RegistryClient) GrpcClient() rpcpb.RegistryClient {
 	return c.internalClient.(*registryGRPCClient).registryClient
 }
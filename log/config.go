@@ -0,0 +1,67 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"sync"
+
+	apex "github.com/apex/log"
+)
+
+// Config holds a level and format that can be shared by loggers and changed
+// while they are in use.
+type Config struct {
+	mu      sync.RWMutex
+	level   apex.Level
+	handler apex.Handler
+}
+
+// NewConfig returns a configuration with the provided options applied to the defaults.
+func NewConfig(opts ...Option) *Config {
+	c := &Config{}
+	c.Set(opts...)
+	return c
+}
+
+// Set applies the provided options to the defaults and replaces the
+// configuration of all loggers that use it.
+func (c *Config) Set(opts ...Option) {
+	l := newApexLogger(append(defaultOptions, opts...)...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.level = l.entry.Logger.Level
+	c.handler = l.entry.Logger.Handler
+}
+
+// Option configures a logger to use the configuration.
+// It overrides any level or format options that precede it.
+func (c *Config) Option() Option {
+	return func(l *apexAdapter) {
+		l.entry.Logger.Level = apex.DebugLevel
+		l.entry.Logger.Handler = c
+	}
+}
+
+// HandleLog implements apex.Handler by filtering entries with the configured
+// level and passing them to the configured handler.
+func (c *Config) HandleLog(e *apex.Entry) error {
+	c.mu.RLock()
+	level, handler := c.level, c.handler
+	c.mu.RUnlock()
+	if e.Level < level {
+		return nil
+	}
+	return handler.HandleLog(e)
+}
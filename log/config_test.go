@@ -0,0 +1,46 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/log"
+)
+
+func TestConfigSet(t *testing.T) {
+	var buf bytes.Buffer
+	conf := log.NewConfig(log.JSONFormat(&buf), log.WarnLevel)
+	logger := log.NewLogger(conf.Option()).WithField("key", "value")
+
+	logger.Info("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Info() wrote %q at warn level, expected nothing", buf.String())
+	}
+
+	logger.Warn("shown")
+	if !strings.HasPrefix(buf.String(), "{") {
+		t.Errorf("Warn() wrote %q, expected JSON entry", buf.String())
+	}
+
+	buf.Reset()
+	conf.Set(log.TextFormat(&buf), log.DebugLevel)
+	logger.Debug("shown")
+	if got := buf.String(); strings.HasPrefix(got, "{") || !strings.Contains(got, "shown") {
+		t.Errorf("Debug() wrote %q after reconfiguration, expected text entry", got)
+	}
+}
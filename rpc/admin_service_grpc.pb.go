@@ -47,6 +47,22 @@ type AdminClient interface {
 	// DeleteProject removes a specified project and all of the resources that it
 	// owns.
 	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListLocations returns the locations of a project.
+	// (-- api-linter: core::0132::request-unknown-fields=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::request-page-size-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::request-page-token-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::response-next-page-token-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	ListLocations(ctx context.Context, in *ListLocationsRequest, opts ...grpc.CallOption) (*ListLocationsResponse, error)
+	// PruneRevisions deletes the spec and deployment revisions of a project
+	// that are not kept by its retention policies. Pruning also runs
+	// periodically in the background when it is enabled in the server.
+	// (-- api-linter: core::0136::http-uri-suffix=disabled
+	//     aip.dev/not-precedent: Not in the official API. --)
+	PruneRevisions(ctx context.Context, in *PruneRevisionsRequest, opts ...grpc.CallOption) (*PruneRevisionsResponse, error)
 }
 
 type adminClient struct {
@@ -111,6 +127,24 @@ func (c *adminClient) DeleteProject(ctx context.Context, in *DeleteProjectReques
 	return out, nil
 }
 
+func (c *adminClient) ListLocations(ctx context.Context, in *ListLocationsRequest, opts ...grpc.CallOption) (*ListLocationsResponse, error) {
+	out := new(ListLocationsResponse)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Admin/ListLocations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) PruneRevisions(ctx context.Context, in *PruneRevisionsRequest, opts ...grpc.CallOption) (*PruneRevisionsResponse, error) {
+	out := new(PruneRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Admin/PruneRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServer is the server API for Admin service.
 // All implementations must embed UnimplementedAdminServer
 // for forward compatibility
@@ -142,6 +176,22 @@ type AdminServer interface {
 	// DeleteProject removes a specified project and all of the resources that it
 	// owns.
 	DeleteProject(context.Context, *DeleteProjectRequest) (*emptypb.Empty, error)
+	// ListLocations returns the locations of a project.
+	// (-- api-linter: core::0132::request-unknown-fields=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::request-page-size-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::request-page-token-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	// (-- api-linter: core::0158::response-next-page-token-field=disabled
+	//     aip.dev/not-precedent: the number of locations is small. --)
+	ListLocations(context.Context, *ListLocationsRequest) (*ListLocationsResponse, error)
+	// PruneRevisions deletes the spec and deployment revisions of a project
+	// that are not kept by its retention policies. Pruning also runs
+	// periodically in the background when it is enabled in the server.
+	// (-- api-linter: core::0136::http-uri-suffix=disabled
+	//     aip.dev/not-precedent: Not in the official API. --)
+	PruneRevisions(context.Context, *PruneRevisionsRequest) (*PruneRevisionsResponse, error)
 	mustEmbedUnimplementedAdminServer()
 }
 
@@ -167,6 +217,12 @@ func (UnimplementedAdminServer) UpdateProject(context.Context, *UpdateProjectReq
 func (UnimplementedAdminServer) DeleteProject(context.Context, *DeleteProjectRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
 }
+func (UnimplementedAdminServer) ListLocations(context.Context, *ListLocationsRequest) (*ListLocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLocations not implemented")
+}
+func (UnimplementedAdminServer) PruneRevisions(context.Context, *PruneRevisionsRequest) (*PruneRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneRevisions not implemented")
+}
 func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
 
 // UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
@@ -288,6 +344,42 @@ func _Admin_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Admin_ListLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Admin/ListLocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListLocations(ctx, req.(*ListLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_PruneRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).PruneRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Admin/PruneRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).PruneRevisions(ctx, req.(*PruneRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -319,6 +411,14 @@ var Admin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProject",
 			Handler:    _Admin_DeleteProject_Handler,
 		},
+		{
+			MethodName: "ListLocations",
+			Handler:    _Admin_ListLocations_Handler,
+		},
+		{
+			MethodName: "PruneRevisions",
+			Handler:    _Admin_PruneRevisions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "google/cloud/apigeeregistry/v1/admin_service.proto",
@@ -106,6 +106,37 @@ type RegistryClient interface {
 	ReplaceArtifact(ctx context.Context, in *ReplaceArtifactRequest, opts ...grpc.CallOption) (*Artifact, error)
 	// DeleteArtifact removes a specified artifact.
 	DeleteArtifact(ctx context.Context, in *DeleteArtifactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// TagArtifactRevision adds a tag to a specified revision of an artifact.
+	TagArtifactRevision(ctx context.Context, in *TagArtifactRevisionRequest, opts ...grpc.CallOption) (*Artifact, error)
+	// ListArtifactRevisions lists all revisions of an artifact.
+	// Revisions are returned in descending order of revision creation time.
+	ListArtifactRevisions(ctx context.Context, in *ListArtifactRevisionsRequest, opts ...grpc.CallOption) (*ListArtifactRevisionsResponse, error)
+	// RollbackArtifact sets the current revision to a specified prior revision.
+	// Note that this creates a new revision with a new revision ID.
+	RollbackArtifact(ctx context.Context, in *RollbackArtifactRequest, opts ...grpc.CallOption) (*Artifact, error)
+	// DeleteArtifactRevision deletes a revision of an artifact.
+	DeleteArtifactRevision(ctx context.Context, in *DeleteArtifactRevisionRequest, opts ...grpc.CallOption) (*Artifact, error)
+	// ListArtifactTypes returns matching artifact types.
+	ListArtifactTypes(ctx context.Context, in *ListArtifactTypesRequest, opts ...grpc.CallOption) (*ListArtifactTypesResponse, error)
+	// GetArtifactType returns a specified artifact type.
+	GetArtifactType(ctx context.Context, in *GetArtifactTypeRequest, opts ...grpc.CallOption) (*ArtifactType, error)
+	// CreateArtifactType registers a schema for artifact contents.
+	CreateArtifactType(ctx context.Context, in *CreateArtifactTypeRequest, opts ...grpc.CallOption) (*ArtifactType, error)
+	// DeleteArtifactType removes a specified artifact type.
+	// Existing artifacts of the type are not affected.
+	DeleteArtifactType(ctx context.Context, in *DeleteArtifactTypeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListRelationships returns matching relationships.
+	// Relationships to a resource can be found by listing the relationships
+	// of all APIs (with "-" as the API ID) and filtering on their targets.
+	ListRelationships(ctx context.Context, in *ListRelationshipsRequest, opts ...grpc.CallOption) (*ListRelationshipsResponse, error)
+	// GetRelationship returns a specified relationship.
+	GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error)
+	// CreateRelationship creates a specified relationship.
+	CreateRelationship(ctx context.Context, in *CreateRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error)
+	// UpdateRelationship can be used to modify a specified relationship.
+	UpdateRelationship(ctx context.Context, in *UpdateRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error)
+	// DeleteRelationship removes a specified relationship.
+	DeleteRelationship(ctx context.Context, in *DeleteRelationshipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type registryClient struct {
@@ -431,6 +462,123 @@ func (c *registryClient) DeleteArtifact(ctx context.Context, in *DeleteArtifactR
 	return out, nil
 }
 
+func (c *registryClient) TagArtifactRevision(ctx context.Context, in *TagArtifactRevisionRequest, opts ...grpc.CallOption) (*Artifact, error) {
+	out := new(Artifact)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/TagArtifactRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListArtifactRevisions(ctx context.Context, in *ListArtifactRevisionsRequest, opts ...grpc.CallOption) (*ListArtifactRevisionsResponse, error) {
+	out := new(ListArtifactRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/ListArtifactRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) RollbackArtifact(ctx context.Context, in *RollbackArtifactRequest, opts ...grpc.CallOption) (*Artifact, error) {
+	out := new(Artifact)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/RollbackArtifact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteArtifactRevision(ctx context.Context, in *DeleteArtifactRevisionRequest, opts ...grpc.CallOption) (*Artifact, error) {
+	out := new(Artifact)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/DeleteArtifactRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListArtifactTypes(ctx context.Context, in *ListArtifactTypesRequest, opts ...grpc.CallOption) (*ListArtifactTypesResponse, error) {
+	out := new(ListArtifactTypesResponse)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/ListArtifactTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetArtifactType(ctx context.Context, in *GetArtifactTypeRequest, opts ...grpc.CallOption) (*ArtifactType, error) {
+	out := new(ArtifactType)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/GetArtifactType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) CreateArtifactType(ctx context.Context, in *CreateArtifactTypeRequest, opts ...grpc.CallOption) (*ArtifactType, error) {
+	out := new(ArtifactType)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/CreateArtifactType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteArtifactType(ctx context.Context, in *DeleteArtifactTypeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/DeleteArtifactType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListRelationships(ctx context.Context, in *ListRelationshipsRequest, opts ...grpc.CallOption) (*ListRelationshipsResponse, error) {
+	out := new(ListRelationshipsResponse)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/ListRelationships", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error) {
+	out := new(Relationship)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/GetRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) CreateRelationship(ctx context.Context, in *CreateRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error) {
+	out := new(Relationship)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/CreateRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) UpdateRelationship(ctx context.Context, in *UpdateRelationshipRequest, opts ...grpc.CallOption) (*Relationship, error) {
+	out := new(Relationship)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/UpdateRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteRelationship(ctx context.Context, in *DeleteRelationshipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/google.cloud.apigeeregistry.v1.Registry/DeleteRelationship", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RegistryServer is the server API for Registry service.
 // All implementations must embed UnimplementedRegistryServer
 // for forward compatibility
@@ -520,6 +668,37 @@ type RegistryServer interface {
 	ReplaceArtifact(context.Context, *ReplaceArtifactRequest) (*Artifact, error)
 	// DeleteArtifact removes a specified artifact.
 	DeleteArtifact(context.Context, *DeleteArtifactRequest) (*emptypb.Empty, error)
+	// TagArtifactRevision adds a tag to a specified revision of an artifact.
+	TagArtifactRevision(context.Context, *TagArtifactRevisionRequest) (*Artifact, error)
+	// ListArtifactRevisions lists all revisions of an artifact.
+	// Revisions are returned in descending order of revision creation time.
+	ListArtifactRevisions(context.Context, *ListArtifactRevisionsRequest) (*ListArtifactRevisionsResponse, error)
+	// RollbackArtifact sets the current revision to a specified prior revision.
+	// Note that this creates a new revision with a new revision ID.
+	RollbackArtifact(context.Context, *RollbackArtifactRequest) (*Artifact, error)
+	// DeleteArtifactRevision deletes a revision of an artifact.
+	DeleteArtifactRevision(context.Context, *DeleteArtifactRevisionRequest) (*Artifact, error)
+	// ListArtifactTypes returns matching artifact types.
+	ListArtifactTypes(context.Context, *ListArtifactTypesRequest) (*ListArtifactTypesResponse, error)
+	// GetArtifactType returns a specified artifact type.
+	GetArtifactType(context.Context, *GetArtifactTypeRequest) (*ArtifactType, error)
+	// CreateArtifactType registers a schema for artifact contents.
+	CreateArtifactType(context.Context, *CreateArtifactTypeRequest) (*ArtifactType, error)
+	// DeleteArtifactType removes a specified artifact type.
+	// Existing artifacts of the type are not affected.
+	DeleteArtifactType(context.Context, *DeleteArtifactTypeRequest) (*emptypb.Empty, error)
+	// ListRelationships returns matching relationships.
+	// Relationships to a resource can be found by listing the relationships
+	// of all APIs (with "-" as the API ID) and filtering on their targets.
+	ListRelationships(context.Context, *ListRelationshipsRequest) (*ListRelationshipsResponse, error)
+	// GetRelationship returns a specified relationship.
+	GetRelationship(context.Context, *GetRelationshipRequest) (*Relationship, error)
+	// CreateRelationship creates a specified relationship.
+	CreateRelationship(context.Context, *CreateRelationshipRequest) (*Relationship, error)
+	// UpdateRelationship can be used to modify a specified relationship.
+	UpdateRelationship(context.Context, *UpdateRelationshipRequest) (*Relationship, error)
+	// DeleteRelationship removes a specified relationship.
+	DeleteRelationship(context.Context, *DeleteRelationshipRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedRegistryServer()
 }
 
@@ -632,6 +811,45 @@ func (UnimplementedRegistryServer) ReplaceArtifact(context.Context, *ReplaceArti
 func (UnimplementedRegistryServer) DeleteArtifact(context.Context, *DeleteArtifactRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteArtifact not implemented")
 }
+func (UnimplementedRegistryServer) TagArtifactRevision(context.Context, *TagArtifactRevisionRequest) (*Artifact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagArtifactRevision not implemented")
+}
+func (UnimplementedRegistryServer) ListArtifactRevisions(context.Context, *ListArtifactRevisionsRequest) (*ListArtifactRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtifactRevisions not implemented")
+}
+func (UnimplementedRegistryServer) RollbackArtifact(context.Context, *RollbackArtifactRequest) (*Artifact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackArtifact not implemented")
+}
+func (UnimplementedRegistryServer) DeleteArtifactRevision(context.Context, *DeleteArtifactRevisionRequest) (*Artifact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteArtifactRevision not implemented")
+}
+func (UnimplementedRegistryServer) ListArtifactTypes(context.Context, *ListArtifactTypesRequest) (*ListArtifactTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtifactTypes not implemented")
+}
+func (UnimplementedRegistryServer) GetArtifactType(context.Context, *GetArtifactTypeRequest) (*ArtifactType, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifactType not implemented")
+}
+func (UnimplementedRegistryServer) CreateArtifactType(context.Context, *CreateArtifactTypeRequest) (*ArtifactType, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateArtifactType not implemented")
+}
+func (UnimplementedRegistryServer) DeleteArtifactType(context.Context, *DeleteArtifactTypeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteArtifactType not implemented")
+}
+func (UnimplementedRegistryServer) ListRelationships(context.Context, *ListRelationshipsRequest) (*ListRelationshipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRelationships not implemented")
+}
+func (UnimplementedRegistryServer) GetRelationship(context.Context, *GetRelationshipRequest) (*Relationship, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationship not implemented")
+}
+func (UnimplementedRegistryServer) CreateRelationship(context.Context, *CreateRelationshipRequest) (*Relationship, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRelationship not implemented")
+}
+func (UnimplementedRegistryServer) UpdateRelationship(context.Context, *UpdateRelationshipRequest) (*Relationship, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRelationship not implemented")
+}
+func (UnimplementedRegistryServer) DeleteRelationship(context.Context, *DeleteRelationshipRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRelationship not implemented")
+}
 func (UnimplementedRegistryServer) mustEmbedUnimplementedRegistryServer() {}
 
 // UnsafeRegistryServer may be embedded to opt out of forward compatibility for this service.
@@ -1275,6 +1493,240 @@ func _Registry_DeleteArtifact_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_TagArtifactRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagArtifactRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).TagArtifactRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/TagArtifactRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).TagArtifactRevision(ctx, req.(*TagArtifactRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListArtifactRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtifactRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListArtifactRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/ListArtifactRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListArtifactRevisions(ctx, req.(*ListArtifactRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_RollbackArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackArtifactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).RollbackArtifact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/RollbackArtifact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).RollbackArtifact(ctx, req.(*RollbackArtifactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DeleteArtifactRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArtifactRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DeleteArtifactRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/DeleteArtifactRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DeleteArtifactRevision(ctx, req.(*DeleteArtifactRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListArtifactTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtifactTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListArtifactTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/ListArtifactTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListArtifactTypes(ctx, req.(*ListArtifactTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetArtifactType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtifactTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetArtifactType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/GetArtifactType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetArtifactType(ctx, req.(*GetArtifactTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_CreateArtifactType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateArtifactTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).CreateArtifactType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/CreateArtifactType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).CreateArtifactType(ctx, req.(*CreateArtifactTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DeleteArtifactType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArtifactTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DeleteArtifactType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/DeleteArtifactType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DeleteArtifactType(ctx, req.(*DeleteArtifactTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRelationshipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/ListRelationships",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListRelationships(ctx, req.(*ListRelationshipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/GetRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetRelationship(ctx, req.(*GetRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_CreateRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).CreateRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/CreateRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).CreateRelationship(ctx, req.(*CreateRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_UpdateRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).UpdateRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/UpdateRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).UpdateRelationship(ctx, req.(*UpdateRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DeleteRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DeleteRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.cloud.apigeeregistry.v1.Registry/DeleteRelationship",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DeleteRelationship(ctx, req.(*DeleteRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Registry_ServiceDesc is the grpc.ServiceDesc for Registry service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1422,6 +1874,58 @@ var Registry_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteArtifact",
 			Handler:    _Registry_DeleteArtifact_Handler,
 		},
+		{
+			MethodName: "TagArtifactRevision",
+			Handler:    _Registry_TagArtifactRevision_Handler,
+		},
+		{
+			MethodName: "ListArtifactRevisions",
+			Handler:    _Registry_ListArtifactRevisions_Handler,
+		},
+		{
+			MethodName: "RollbackArtifact",
+			Handler:    _Registry_RollbackArtifact_Handler,
+		},
+		{
+			MethodName: "DeleteArtifactRevision",
+			Handler:    _Registry_DeleteArtifactRevision_Handler,
+		},
+		{
+			MethodName: "ListArtifactTypes",
+			Handler:    _Registry_ListArtifactTypes_Handler,
+		},
+		{
+			MethodName: "GetArtifactType",
+			Handler:    _Registry_GetArtifactType_Handler,
+		},
+		{
+			MethodName: "CreateArtifactType",
+			Handler:    _Registry_CreateArtifactType_Handler,
+		},
+		{
+			MethodName: "DeleteArtifactType",
+			Handler:    _Registry_DeleteArtifactType_Handler,
+		},
+		{
+			MethodName: "ListRelationships",
+			Handler:    _Registry_ListRelationships_Handler,
+		},
+		{
+			MethodName: "GetRelationship",
+			Handler:    _Registry_GetRelationship_Handler,
+		},
+		{
+			MethodName: "CreateRelationship",
+			Handler:    _Registry_CreateRelationship_Handler,
+		},
+		{
+			MethodName: "UpdateRelationship",
+			Handler:    _Registry_UpdateRelationship_Handler,
+		},
+		{
+			MethodName: "DeleteRelationship",
+			Handler:    _Registry_DeleteRelationship_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "google/cloud/apigeeregistry/v1/registry_service.proto",
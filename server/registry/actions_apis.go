@@ -29,7 +29,7 @@ import (
 
 // CreateApi handles the corresponding API request.
 func (s *RegistryServer) CreateApi(ctx context.Context, req *rpc.CreateApiRequest) (*rpc.Api, error) {
-	parent, err := names.ParseProjectWithLocation(req.GetParent())
+	parent, err := names.ParseLocation(req.GetParent())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -59,7 +59,16 @@ func (s *RegistryServer) createApi(ctx context.Context, name names.Api, body *rp
 	}
 
 	// Creation should only succeed when the parent exists.
-	if _, err := db.GetProject(ctx, name.Project()); err != nil {
+	lifecycle, _, err := projectLifecycles(ctx, db, name.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLocation(name.Location()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuotas(ctx, db, name.Project(), storage.ProjectUsage{Apis: 1}); err != nil {
 		return nil, err
 	}
 
@@ -68,16 +77,34 @@ func (s *RegistryServer) createApi(ctx context.Context, name names.Api, body *rp
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.checkApiReferences(ctx, db, name, &models.Api{}, api); err != nil {
+		return nil, err
+	}
+
+	change, err := apiChange(name, &models.Api{}, api)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLifecycle(ctx, db, lifecycle, change); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveApi(ctx, api); err != nil {
 		return nil, err
 	}
 
+	transition, err := recordTransition(ctx, db, lifecycle, change, models.NewApiTransition(name, change.from, change.to))
+	if err != nil {
+		return nil, err
+	}
+
 	message, err := api.Message()
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	s.notify(ctx, rpc.Notification_CREATED, name.String())
+	s.notifyTransition(ctx, rpc.Notification_CREATED, name.String(), transition)
 	return message, nil
 }
 
@@ -99,6 +126,10 @@ func (s *RegistryServer) DeleteApi(ctx context.Context, req *rpc.DeleteApiReques
 		return nil, err
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteApi(ctx, name); err != nil {
 		return nil, err
 	}
@@ -120,7 +151,17 @@ func (s *RegistryServer) GetApi(ctx context.Context, req *rpc.GetApiRequest) (*r
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	api, err := db.GetApi(ctx, name)
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var api *models.Api
+	if at.IsZero() {
+		api, err = db.GetApi(ctx, name)
+	} else {
+		api, err = db.GetApiAt(ctx, name, at)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +171,18 @@ func (s *RegistryServer) GetApi(ctx context.Context, req *rpc.GetApiRequest) (*r
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	transitions, err := db.ListApiTransitions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range transitions {
+		if !at.IsZero() && t.TransitionTime.After(at) {
+			break
+		}
+		message.AvailabilityHistory = append(message.AvailabilityHistory, t.Message())
+	}
+
 	return message, nil
 }
 
@@ -149,16 +202,28 @@ func (s *RegistryServer) ListApis(ctx context.Context, req *rpc.ListApisRequest)
 		req.PageSize = 50
 	}
 
-	parent, err := names.ParseProjectWithLocation(req.GetParent())
+	parent, err := names.ParseLocation(req.GetParent())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	listing, err := db.ListApis(ctx, parent, storage.PageOptions{
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
 		Token:  req.GetPageToken(),
-	})
+	}
+
+	var listing storage.ApiList
+	if at.IsZero() {
+		listing, err = db.ListApis(ctx, parent, opts)
+	} else {
+		listing, err = db.ListApisAt(ctx, parent, opts, at)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -217,19 +282,52 @@ func (s *RegistryServer) UpdateApi(ctx context.Context, req *rpc.UpdateApiReques
 		return nil, err
 	}
 
+	old := *api
 	if err := api.Update(req.GetApi(), models.ExpandMask(req.GetApi(), req.GetUpdateMask())); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if err := s.checkApiReferences(ctx, db, name, &old, api); err != nil {
+		return nil, err
+	}
+
+	lifecycle, _, err := projectLifecycles(ctx, db, name.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := apiChange(name, &old, api)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLifecycle(ctx, db, lifecycle, change); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveApi(ctx, api); err != nil {
 		return nil, err
 	}
 
+	transition, err := recordTransition(ctx, db, lifecycle, change, models.NewApiTransition(name, change.from, change.to))
+	if err != nil {
+		return nil, err
+	}
+
 	message, err := api.Message()
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	s.notify(ctx, rpc.Notification_UPDATED, name.String())
+	s.notifyTransition(ctx, rpc.Notification_UPDATED, name.String(), transition)
 	return message, nil
 }
+
+// checkApiReferences returns an error if a reference that changed between
+// old and api is invalid.
+func (s *RegistryServer) checkApiReferences(ctx context.Context, db *storage.Client, name names.Api, old, api *models.Api) error {
+	if err := s.checkReference(ctx, db, name.Project(), recommendedVersionField, old.RecommendedVersion, api.RecommendedVersion); err != nil {
+		return err
+	}
+	return s.checkReference(ctx, db, name.Project(), recommendedDeploymentField, old.RecommendedDeployment, api.RecommendedDeployment)
+}
@@ -0,0 +1,447 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const testArtifact = "projects/my-project/locations/global/apis/my-api/artifacts/complexity"
+
+// seedArtifactRevisions creates an artifact with one revision for each of
+// the provided contents and returns the revisions in order of creation.
+func seedArtifactRevisions(ctx context.Context, t *testing.T, server *RegistryServer, contents ...string) []*rpc.Artifact {
+	t.Helper()
+	if err := seeder.SeedRegistry(ctx, server, &rpc.Artifact{
+		Name:     testArtifact,
+		MimeType: "text/plain",
+		Contents: []byte(contents[0]),
+	}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	first, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: testArtifact})
+	if err != nil {
+		t.Fatalf("Setup: GetArtifact(%q) returned error: %s", testArtifact, err)
+	}
+
+	revisions := []*rpc.Artifact{first}
+	for _, c := range contents[1:] {
+		req := &rpc.ReplaceArtifactRequest{
+			Artifact: &rpc.Artifact{
+				Name:     testArtifact,
+				MimeType: "text/plain",
+				Contents: []byte(c),
+			},
+		}
+
+		revision, err := server.ReplaceArtifact(ctx, req)
+		if err != nil {
+			t.Fatalf("Setup: ReplaceArtifact(%+v) returned error: %s", req, err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions
+}
+
+func TestReplaceArtifactRevisions(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	revisions := seedArtifactRevisions(ctx, t, server, "one", "two")
+
+	if revisions[0].GetRevisionId() == revisions[1].GetRevisionId() {
+		t.Errorf("ReplaceArtifact() with new contents kept revision ID %q, expected a new revision", revisions[1].GetRevisionId())
+	}
+
+	t.Run("unchanged contents", func(t *testing.T) {
+		req := &rpc.ReplaceArtifactRequest{
+			Artifact: &rpc.Artifact{
+				Name:     testArtifact,
+				MimeType: "text/plain",
+				Contents: []byte("two"),
+			},
+		}
+
+		got, err := server.ReplaceArtifact(ctx, req)
+		if err != nil {
+			t.Fatalf("ReplaceArtifact(%+v) returned error: %s", req, err)
+		}
+
+		if got.GetRevisionId() != revisions[1].GetRevisionId() {
+			t.Errorf("ReplaceArtifact(%+v) returned revision ID %q, want %q", req, got.GetRevisionId(), revisions[1].GetRevisionId())
+		}
+	})
+
+	t.Run("revision contents", func(t *testing.T) {
+		for i, want := range []string{"one", "two"} {
+			req := &rpc.GetArtifactContentsRequest{
+				Name: fmt.Sprintf("%s@%s", testArtifact, revisions[i].GetRevisionId()),
+			}
+
+			got, err := server.GetArtifactContents(ctx, req)
+			if err != nil {
+				t.Fatalf("GetArtifactContents(%+v) returned error: %s", req, err)
+			}
+
+			if string(got.GetData()) != want {
+				t.Errorf("GetArtifactContents(%+v) returned %q, want %q", req, got.GetData(), want)
+			}
+		}
+	})
+
+	t.Run("revision name", func(t *testing.T) {
+		req := &rpc.ReplaceArtifactRequest{
+			Artifact: &rpc.Artifact{
+				Name:     fmt.Sprintf("%s@%s", testArtifact, revisions[0].GetRevisionId()),
+				Contents: []byte("three"),
+			},
+		}
+
+		if _, err := server.ReplaceArtifact(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ReplaceArtifact(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.InvalidArgument, err)
+		}
+	})
+
+	t.Run("ListArtifacts", func(t *testing.T) {
+		req := &rpc.ListArtifactsRequest{
+			Parent: "projects/my-project/locations/global/apis/my-api",
+		}
+
+		got, err := server.ListArtifacts(ctx, req)
+		if err != nil {
+			t.Fatalf("ListArtifacts(%+v) returned error: %s", req, err)
+		}
+
+		if len(got.GetArtifacts()) != 1 {
+			t.Fatalf("ListArtifacts(%+v) returned %d artifacts, want 1", req, len(got.GetArtifacts()))
+		}
+
+		if got.Artifacts[0].GetRevisionId() != revisions[1].GetRevisionId() {
+			t.Errorf("ListArtifacts(%+v) returned revision ID %q, want %q", req, got.Artifacts[0].GetRevisionId(), revisions[1].GetRevisionId())
+		}
+	})
+}
+
+func TestListArtifactRevisions(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	revisions := seedArtifactRevisions(ctx, t, server, "one", "two", "three")
+
+	opts := cmp.Options{
+		protocmp.Transform(),
+		protocmp.IgnoreFields(new(rpc.Artifact), "name", "update_time", "revision_update_time"),
+		protocmp.IgnoreFields(new(rpc.ListArtifactRevisionsResponse), "next_page_token"),
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		req := &rpc.ListArtifactRevisionsRequest{
+			Name:     testArtifact,
+			PageSize: 2,
+		}
+
+		got, err := server.ListArtifactRevisions(ctx, req)
+		if err != nil {
+			t.Fatalf("ListArtifactRevisions(%+v) returned error: %s", req, err)
+		}
+
+		want := &rpc.ListArtifactRevisionsResponse{
+			Artifacts: []*rpc.Artifact{revisions[2], revisions[1]},
+		}
+
+		if !cmp.Equal(want, got, opts) {
+			t.Errorf("ListArtifactRevisions(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts))
+		}
+
+		if got.GetNextPageToken() == "" {
+			t.Fatalf("ListArtifactRevisions(%+v) returned empty next_page_token, expected another page", req)
+		}
+
+		req = &rpc.ListArtifactRevisionsRequest{
+			Name:      testArtifact,
+			PageSize:  2,
+			PageToken: got.GetNextPageToken(),
+		}
+
+		got, err = server.ListArtifactRevisions(ctx, req)
+		if err != nil {
+			t.Fatalf("ListArtifactRevisions(%+v) returned error: %s", req, err)
+		}
+
+		want = &rpc.ListArtifactRevisionsResponse{
+			Artifacts: []*rpc.Artifact{revisions[0]},
+		}
+
+		if !cmp.Equal(want, got, opts) {
+			t.Errorf("ListArtifactRevisions(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts))
+		}
+	})
+
+	t.Run("revision names", func(t *testing.T) {
+		req := &rpc.ListArtifactRevisionsRequest{
+			Name: testArtifact,
+		}
+
+		got, err := server.ListArtifactRevisions(ctx, req)
+		if err != nil {
+			t.Fatalf("ListArtifactRevisions(%+v) returned error: %s", req, err)
+		}
+
+		for _, revision := range got.GetArtifacts() {
+			if want := fmt.Sprintf("%s@%s", testArtifact, revision.GetRevisionId()); revision.GetName() != want {
+				t.Errorf("ListArtifactRevisions(%+v) returned name %q, want %q", req, revision.GetName(), want)
+			}
+		}
+	})
+}
+
+func TestListArtifactRevisionsResponseCodes(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedArtifactRevisions(ctx, t, server, "one")
+
+	tests := []struct {
+		desc string
+		req  *rpc.ListArtifactRevisionsRequest
+		want codes.Code
+	}{
+		{
+			desc: "artifact not found",
+			req:  &rpc.ListArtifactRevisionsRequest{Name: "projects/my-project/locations/global/apis/my-api/artifacts/missing"},
+			want: codes.OK,
+		},
+		{
+			desc: "revision name",
+			req:  &rpc.ListArtifactRevisionsRequest{Name: testArtifact + "@abc"},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "invalid name",
+			req:  &rpc.ListArtifactRevisionsRequest{Name: "projects/my-project/locations/global/artifacts"},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "negative page size",
+			req:  &rpc.ListArtifactRevisionsRequest{Name: testArtifact, PageSize: -1},
+			want: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := server.ListArtifactRevisions(ctx, test.req); status.Code(err) != test.want {
+				t.Errorf("ListArtifactRevisions(%+v) returned status code %q, want %q: %v", test.req, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestTagArtifactRevision(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	revisions := seedArtifactRevisions(ctx, t, server, "one", "two")
+
+	req := &rpc.TagArtifactRevisionRequest{
+		Name: fmt.Sprintf("%s@%s", testArtifact, revisions[0].GetRevisionId()),
+		Tag:  "baseline",
+	}
+
+	got, err := server.TagArtifactRevision(ctx, req)
+	if err != nil {
+		t.Fatalf("TagArtifactRevision(%+v) returned error: %s", req, err)
+	}
+
+	if want := testArtifact + "@baseline"; got.GetName() != want {
+		t.Errorf("TagArtifactRevision(%+v) returned name %q, want %q", req, got.GetName(), want)
+	}
+
+	if want := []string{"baseline"}; !cmp.Equal(want, got.GetRevisionTags()) {
+		t.Errorf("TagArtifactRevision(%+v) returned tags %v, want %v", req, got.GetRevisionTags(), want)
+	}
+
+	t.Run("GetArtifact", func(t *testing.T) {
+		req := &rpc.GetArtifactRequest{Name: testArtifact + "@baseline"}
+		got, err := server.GetArtifact(ctx, req)
+		if err != nil {
+			t.Fatalf("GetArtifact(%+v) returned error: %s", req, err)
+		}
+
+		if got.GetRevisionId() != revisions[0].GetRevisionId() {
+			t.Errorf("GetArtifact(%+v) returned revision ID %q, want %q", req, got.GetRevisionId(), revisions[0].GetRevisionId())
+		}
+	})
+
+	t.Run("GetArtifactContents", func(t *testing.T) {
+		req := &rpc.GetArtifactContentsRequest{Name: testArtifact + "@baseline"}
+		got, err := server.GetArtifactContents(ctx, req)
+		if err != nil {
+			t.Fatalf("GetArtifactContents(%+v) returned error: %s", req, err)
+		}
+
+		if want := "one"; string(got.GetData()) != want {
+			t.Errorf("GetArtifactContents(%+v) returned %q, want %q", req, got.GetData(), want)
+		}
+	})
+
+	t.Run("retag through tag", func(t *testing.T) {
+		req := &rpc.TagArtifactRevisionRequest{
+			Name: testArtifact + "@baseline",
+			Tag:  "approved",
+		}
+
+		got, err := server.TagArtifactRevision(ctx, req)
+		if err != nil {
+			t.Fatalf("TagArtifactRevision(%+v) returned error: %s", req, err)
+		}
+
+		if got.GetRevisionId() != revisions[0].GetRevisionId() {
+			t.Errorf("TagArtifactRevision(%+v) returned revision ID %q, want %q", req, got.GetRevisionId(), revisions[0].GetRevisionId())
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		tests := []*rpc.TagArtifactRevisionRequest{
+			{Name: testArtifact, Tag: "missing-revision"},
+			{Name: req.GetName(), Tag: ""},
+			{Name: req.GetName(), Tag: "this-tag-is-much-longer-than-forty-characters"},
+		}
+		for _, req := range tests {
+			if _, err := server.TagArtifactRevision(ctx, req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("TagArtifactRevision(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.InvalidArgument, err)
+			}
+		}
+	})
+}
+
+func TestRollbackArtifact(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	revisions := seedArtifactRevisions(ctx, t, server, "one", "two")
+
+	req := &rpc.RollbackArtifactRequest{
+		Name:       testArtifact,
+		RevisionId: revisions[0].GetRevisionId(),
+	}
+
+	rollback, err := server.RollbackArtifact(ctx, req)
+	if err != nil {
+		t.Fatalf("RollbackArtifact(%+v) returned error: %s", req, err)
+	}
+
+	if rollback.GetRevisionId() == revisions[0].GetRevisionId() || rollback.GetRevisionId() == revisions[1].GetRevisionId() {
+		t.Errorf("RollbackArtifact(%+v) returned existing revision ID %q, expected a new revision", req, rollback.GetRevisionId())
+	}
+
+	if rollback.GetHash() != revisions[0].GetHash() {
+		t.Errorf("RollbackArtifact(%+v) returned hash %q, want %q", req, rollback.GetHash(), revisions[0].GetHash())
+	}
+
+	t.Run("GetArtifactContents", func(t *testing.T) {
+		req := &rpc.GetArtifactContentsRequest{Name: testArtifact}
+		got, err := server.GetArtifactContents(ctx, req)
+		if err != nil {
+			t.Fatalf("GetArtifactContents(%+v) returned error: %s", req, err)
+		}
+
+		if want := "one"; string(got.GetData()) != want {
+			t.Errorf("GetArtifactContents(%+v) returned %q, want %q", req, got.GetData(), want)
+		}
+	})
+
+	t.Run("missing revision", func(t *testing.T) {
+		req := &rpc.RollbackArtifactRequest{
+			Name:       testArtifact,
+			RevisionId: "missing",
+		}
+
+		if _, err := server.RollbackArtifact(ctx, req); status.Code(err) != codes.NotFound {
+			t.Errorf("RollbackArtifact(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.NotFound, err)
+		}
+	})
+
+	t.Run("empty revision", func(t *testing.T) {
+		req := &rpc.RollbackArtifactRequest{
+			Name: testArtifact,
+		}
+
+		if _, err := server.RollbackArtifact(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("RollbackArtifact(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.InvalidArgument, err)
+		}
+	})
+}
+
+func TestDeleteArtifactRevision(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	revisions := seedArtifactRevisions(ctx, t, server, "one", "two")
+
+	t.Run("current revision", func(t *testing.T) {
+		req := &rpc.DeleteArtifactRevisionRequest{
+			Name: fmt.Sprintf("%s@%s", testArtifact, revisions[1].GetRevisionId()),
+		}
+
+		got, err := server.DeleteArtifactRevision(ctx, req)
+		if err != nil {
+			t.Fatalf("DeleteArtifactRevision(%+v) returned error: %s", req, err)
+		}
+
+		if got.GetRevisionId() != revisions[0].GetRevisionId() {
+			t.Errorf("DeleteArtifactRevision(%+v) returned revision ID %q, want %q", req, got.GetRevisionId(), revisions[0].GetRevisionId())
+		}
+
+		if _, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: req.GetName()}); status.Code(err) != codes.NotFound {
+			t.Errorf("GetArtifact(%q) returned status code %q, want %q: %v", req.GetName(), status.Code(err), codes.NotFound, err)
+		}
+	})
+
+	t.Run("only remaining revision", func(t *testing.T) {
+		req := &rpc.DeleteArtifactRevisionRequest{
+			Name: fmt.Sprintf("%s@%s", testArtifact, revisions[0].GetRevisionId()),
+		}
+
+		if _, err := server.DeleteArtifactRevision(ctx, req); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("DeleteArtifactRevision(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("artifact name", func(t *testing.T) {
+		req := &rpc.DeleteArtifactRevisionRequest{
+			Name: testArtifact,
+		}
+
+		if _, err := server.DeleteArtifactRevision(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("DeleteArtifactRevision(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.InvalidArgument, err)
+		}
+	})
+
+	t.Run("DeleteArtifact with revision name", func(t *testing.T) {
+		req := &rpc.DeleteArtifactRequest{
+			Name: fmt.Sprintf("%s@%s", testArtifact, revisions[0].GetRevisionId()),
+		}
+
+		if _, err := server.DeleteArtifact(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("DeleteArtifact(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.InvalidArgument, err)
+		}
+	})
+}
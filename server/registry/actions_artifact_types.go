@@ -0,0 +1,250 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateArtifactType handles the corresponding API request.
+func (s *RegistryServer) CreateArtifactType(ctx context.Context, req *rpc.CreateArtifactTypeRequest) (*rpc.ArtifactType, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	parent, err := names.ParseLocation(req.GetParent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.GetArtifactType() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid artifact type %+v: body must be provided", req.GetArtifactType())
+	}
+
+	name := parent.ArtifactType(req.GetArtifactTypeId())
+	if _, err := db.GetArtifactType(ctx, name); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "artifact type %q already exists", name)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	if err := name.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Creation should only succeed when the parent exists.
+	if _, err := db.GetProject(ctx, name.Project()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLocation(name.Location()); err != nil {
+		return nil, err
+	}
+
+	t, err := models.NewArtifactType(name, req.GetArtifactType())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Each MIME type can only be described by one type in a location.
+	if existing, err := db.GetArtifactTypeForMimeType(ctx, name.Location(), t.MimeType); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "artifact type %q already describes %q", existing.Name(), t.MimeType)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	if err := db.SaveArtifactType(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, rpc.Notification_CREATED, name.String())
+	return t.Message(), nil
+}
+
+// DeleteArtifactType handles the corresponding API request.
+func (s *RegistryServer) DeleteArtifactType(ctx context.Context, req *rpc.DeleteArtifactTypeRequest) (*emptypb.Empty, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseArtifactType(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Deletion should only succeed on artifact types that currently exist.
+	if _, err := db.GetArtifactType(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if err := db.DeleteArtifactType(ctx, name); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, rpc.Notification_DELETED, name.String())
+	return &emptypb.Empty{}, nil
+}
+
+// GetArtifactType handles the corresponding API request.
+func (s *RegistryServer) GetArtifactType(ctx context.Context, req *rpc.GetArtifactTypeRequest) (*rpc.ArtifactType, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseArtifactType(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var t *models.ArtifactType
+	if at.IsZero() {
+		t, err = db.GetArtifactType(ctx, name)
+	} else {
+		t, err = db.GetArtifactTypeAt(ctx, name, at)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Message(), nil
+}
+
+// ListArtifactTypes handles the corresponding API request.
+func (s *RegistryServer) ListArtifactTypes(ctx context.Context, req *rpc.ListArtifactTypesRequest) (*rpc.ListArtifactTypesResponse, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %d: must not be negative", req.GetPageSize())
+	} else if req.GetPageSize() > 1000 {
+		req.PageSize = 1000
+	} else if req.GetPageSize() == 0 {
+		req.PageSize = 50
+	}
+
+	parent, err := names.ParseLocation(req.GetParent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
+		Size:   req.GetPageSize(),
+		Filter: req.GetFilter(),
+		Token:  req.GetPageToken(),
+	}
+
+	var listing storage.ArtifactTypeList
+	if at.IsZero() {
+		listing, err = db.ListArtifactTypes(ctx, parent, opts)
+	} else {
+		listing, err = db.ListArtifactTypesAt(ctx, parent, opts, at)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rpc.ListArtifactTypesResponse{
+		ArtifactTypes: make([]*rpc.ArtifactType, len(listing.ArtifactTypes)),
+		NextPageToken: listing.Token,
+	}
+
+	for i, t := range listing.ArtifactTypes {
+		response.ArtifactTypes[i] = t.Message()
+	}
+
+	return response, nil
+}
+
+// artifactType returns the type registered for an artifact's MIME type, or nil if there is none.
+func artifactType(ctx context.Context, db *storage.Client, artifact *models.Artifact) (*models.ArtifactType, error) {
+	parent := names.Location{ProjectID: artifact.ProjectID, LocationID: artifact.LocationID}
+	t, err := db.GetArtifactTypeForMimeType(ctx, parent, artifact.MimeType)
+	if isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// checkArtifactContents returns an error if an artifact's contents don't match its registered type.
+func checkArtifactContents(ctx context.Context, db *storage.Client, artifact *models.Artifact, contents []byte) error {
+	t, err := artifactType(ctx, db, artifact)
+	if err != nil || t == nil {
+		return err
+	}
+
+	if _, err := artifact.Decode(t, contents); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid contents for artifact type %q: %s", t.Name(), err)
+	}
+
+	return nil
+}
+
+// artifactContentsAsJSON returns the JSON representation of an artifact's contents.
+// Only artifacts with registered types can be converted.
+func artifactContentsAsJSON(ctx context.Context, db *storage.Client, artifact *models.Artifact, contents []byte) (*httpbody.HttpBody, error) {
+	t, err := artifactType(ctx, db, artifact)
+	if err != nil {
+		return nil, err
+	} else if t == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "artifact %q has no registered type: contents can't be returned as JSON", artifact.Name())
+	}
+
+	value, err := artifact.Decode(t, contents)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "artifact %q doesn't match type %q: %s", artifact.Name(), t.Name(), err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "application/json",
+		Data:        data,
+	}, nil
+}
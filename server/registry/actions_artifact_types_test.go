@@ -0,0 +1,480 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	lintMimeType  = "application/octet-stream;type=google.cloud.apigeeregistry.applications.v1alpha1.Lint"
+	ownerMimeType = "application/json;type=owner"
+	ownerSchema   = `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["team"],
+		"properties": {
+			"team": {"type": "string"},
+			"size": {"$ref": "#/$defs/size"}
+		},
+		"if": {"properties": {"team": {"const": "platform"}}},
+		"then": {"required": ["size"]},
+		"$defs": {
+			"size": {"type": ["integer", "null"]}
+		}
+	}`
+)
+
+func lintDescriptorSet(t *testing.T) []byte {
+	t.Helper()
+	fds := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(rpc.File_google_cloud_apigeeregistry_applications_v1alpha1_registry_lint_proto),
+		},
+	}
+
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatalf("Setup: failed to marshal descriptor set: %s", err)
+	}
+	return b
+}
+
+// seedArtifactTypes seeds a project and the provided resources, then
+// creates types for lint results and JSON owner records in the project.
+func seedArtifactTypes(ctx context.Context, t *testing.T, server *RegistryServer, resources ...seeder.RegistryResource) {
+	t.Helper()
+	resources = append([]seeder.RegistryResource{&rpc.Project{Name: "projects/my-project"}}, resources...)
+	if err := seeder.SeedRegistry(ctx, server, resources...); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	for id, body := range map[string]*rpc.ArtifactType{
+		"lint": {
+			MimeType: lintMimeType,
+			Schema:   &rpc.ArtifactType_FileDescriptorSet{FileDescriptorSet: lintDescriptorSet(t)},
+		},
+		"owner": {
+			MimeType: ownerMimeType,
+			Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+		},
+	} {
+		req := &rpc.CreateArtifactTypeRequest{
+			Parent:         "projects/my-project/locations/global",
+			ArtifactTypeId: id,
+			ArtifactType:   body,
+		}
+
+		if _, err := server.CreateArtifactType(ctx, req); err != nil {
+			t.Fatalf("Setup: CreateArtifactType(%+v) returned error: %s", req, err)
+		}
+	}
+}
+
+func TestCreateArtifactType(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedArtifactTypes(ctx, t, server)
+
+	req := &rpc.GetArtifactTypeRequest{
+		Name: "projects/my-project/locations/global/artifactTypes/owner",
+	}
+
+	got, err := server.GetArtifactType(ctx, req)
+	if err != nil {
+		t.Fatalf("GetArtifactType(%+v) returned error: %s", req, err)
+	}
+
+	want := &rpc.ArtifactType{
+		Name:     "projects/my-project/locations/global/artifactTypes/owner",
+		MimeType: ownerMimeType,
+		Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+	}
+
+	opts := cmp.Options{
+		protocmp.Transform(),
+		protocmp.IgnoreFields(new(rpc.ArtifactType), "create_time"),
+	}
+
+	if !cmp.Equal(want, got, opts) {
+		t.Errorf("GetArtifactType(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts))
+	}
+}
+
+func TestCreateArtifactTypeResponseCodes(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *rpc.CreateArtifactTypeRequest
+		want codes.Code
+	}{
+		{
+			desc: "missing mime type",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "missing",
+				ArtifactType: &rpc.ArtifactType{
+					Schema: &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+				},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "missing schema",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "missing",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: "application/json;type=missing",
+				},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "invalid json schema",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "invalid",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: "application/json;type=invalid",
+					Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: "{"},
+				},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "message missing from descriptor set",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "missing",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: "application/octet-stream;type=google.cloud.apigeeregistry.applications.v1alpha1.Missing",
+					Schema:   &rpc.ArtifactType_FileDescriptorSet{FileDescriptorSet: lintDescriptorSet(t)},
+				},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "existing name",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "owner",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: "application/json;type=other",
+					Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+				},
+			},
+			want: codes.AlreadyExists,
+		},
+		{
+			desc: "existing mime type",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/my-project/locations/global",
+				ArtifactTypeId: "other",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: "application/json; type=owner",
+					Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+				},
+			},
+			want: codes.AlreadyExists,
+		},
+		{
+			desc: "missing project",
+			req: &rpc.CreateArtifactTypeRequest{
+				Parent:         "projects/other-project/locations/global",
+				ArtifactTypeId: "owner",
+				ArtifactType: &rpc.ArtifactType{
+					MimeType: ownerMimeType,
+					Schema:   &rpc.ArtifactType_JsonSchema{JsonSchema: ownerSchema},
+				},
+			},
+			want: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			seedArtifactTypes(ctx, t, server)
+
+			if _, err := server.CreateArtifactType(ctx, test.req); status.Code(err) != test.want {
+				t.Errorf("CreateArtifactType(%+v) returned status code %q, want %q: %v", test.req, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestListAndDeleteArtifactTypes(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedArtifactTypes(ctx, t, server)
+
+	list := &rpc.ListArtifactTypesRequest{
+		Parent: "projects/my-project/locations/global",
+		Filter: "mime_type.startsWith('application/json')",
+	}
+
+	got, err := server.ListArtifactTypes(ctx, list)
+	if err != nil {
+		t.Fatalf("ListArtifactTypes(%+v) returned error: %s", list, err)
+	}
+
+	if len(got.GetArtifactTypes()) != 1 || got.GetArtifactTypes()[0].GetName() != "projects/my-project/locations/global/artifactTypes/owner" {
+		t.Errorf("ListArtifactTypes(%+v) returned %+v, want only the owner type", list, got.GetArtifactTypes())
+	}
+
+	del := &rpc.DeleteArtifactTypeRequest{
+		Name: "projects/my-project/locations/global/artifactTypes/owner",
+	}
+
+	if _, err := server.DeleteArtifactType(ctx, del); err != nil {
+		t.Fatalf("DeleteArtifactType(%+v) returned error: %s", del, err)
+	}
+
+	if _, err := server.GetArtifactType(ctx, &rpc.GetArtifactTypeRequest{Name: del.GetName()}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetArtifactType(%q) returned status code %q, want %q: %v", del.GetName(), status.Code(err), codes.NotFound, err)
+	}
+
+	if _, err := server.DeleteArtifactType(ctx, del); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteArtifactType(%+v) returned status code %q, want %q: %v", del, status.Code(err), codes.NotFound, err)
+	}
+
+	// Artifacts of deleted types are no longer validated.
+	req := &rpc.CreateArtifactRequest{
+		Parent:     "projects/my-project/locations/global",
+		ArtifactId: "owner",
+		Artifact: &rpc.Artifact{
+			MimeType: ownerMimeType,
+			Contents: []byte("not json"),
+		},
+	}
+
+	if _, err := server.CreateArtifact(ctx, req); err != nil {
+		t.Errorf("CreateArtifact(%+v) returned error: %s", req, err)
+	}
+}
+
+func TestArtifactContentsValidation(t *testing.T) {
+	lint, err := proto.Marshal(&rpc.Lint{Name: "sample"})
+	if err != nil {
+		t.Fatalf("Setup: failed to marshal lint: %s", err)
+	}
+
+	tests := []struct {
+		desc     string
+		mimeType string
+		contents []byte
+		want     codes.Code
+	}{
+		{
+			desc:     "valid json",
+			mimeType: ownerMimeType,
+			contents: []byte(`{"team": "apis", "size": 3}`),
+			want:     codes.OK,
+		},
+		{
+			desc:     "json with null property",
+			mimeType: ownerMimeType,
+			contents: []byte(`{"team": "apis", "size": null}`),
+			want:     codes.OK,
+		},
+		{
+			desc:     "json missing conditionally required property",
+			mimeType: ownerMimeType,
+			contents: []byte(`{"team": "platform"}`),
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "json missing required property",
+			mimeType: ownerMimeType,
+			contents: []byte(`{"size": 3}`),
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "json with wrong property type",
+			mimeType: ownerMimeType,
+			contents: []byte(`{"team": 3}`),
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "not json",
+			mimeType: ownerMimeType,
+			contents: []byte("team: apis"),
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "valid proto",
+			mimeType: lintMimeType,
+			contents: lint,
+			want:     codes.OK,
+		},
+		{
+			desc:     "invalid proto",
+			mimeType: lintMimeType,
+			contents: []byte(`{"team": "apis"}`),
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "unregistered type",
+			mimeType: "text/plain",
+			contents: []byte("anything"),
+			want:     codes.OK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			seed := &rpc.Artifact{
+				Name:     "projects/my-project/locations/global/artifacts/other",
+				MimeType: "text/plain",
+			}
+			seedArtifactTypes(ctx, t, server, seed)
+
+			create := &rpc.CreateArtifactRequest{
+				Parent:     "projects/my-project/locations/global",
+				ArtifactId: "my-artifact",
+				Artifact: &rpc.Artifact{
+					MimeType: test.mimeType,
+					Contents: test.contents,
+				},
+			}
+
+			if _, err := server.CreateArtifact(ctx, create); status.Code(err) != test.want {
+				t.Errorf("CreateArtifact(%+v) returned status code %q, want %q: %v", create, status.Code(err), test.want, err)
+			}
+
+			// Replacement contents are validated in the same way.
+			replace := &rpc.ReplaceArtifactRequest{
+				Artifact: &rpc.Artifact{
+					Name:     seed.GetName(),
+					MimeType: test.mimeType,
+					Contents: test.contents,
+				},
+			}
+
+			if _, err := server.ReplaceArtifact(ctx, replace); status.Code(err) != test.want {
+				t.Errorf("ReplaceArtifact(%+v) returned status code %q, want %q: %v", replace, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestGetArtifactContentsAsJSON(t *testing.T) {
+	lint, err := proto.Marshal(&rpc.Lint{Name: "sample", Files: []*rpc.LintFile{{FilePath: "openapi.yaml"}}})
+	if err != nil {
+		t.Fatalf("Setup: failed to marshal lint: %s", err)
+	}
+
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedArtifactTypes(ctx, t, server,
+		&rpc.Artifact{
+			Name:     "projects/my-project/locations/global/artifacts/lint",
+			MimeType: lintMimeType,
+			Contents: lint,
+		},
+		&rpc.Artifact{
+			Name:     "projects/my-project/locations/global/artifacts/text",
+			MimeType: "text/plain",
+			Contents: []byte("text"),
+		},
+	)
+
+	req := &rpc.GetArtifactContentsRequest{
+		Name:   "projects/my-project/locations/global/artifacts/lint",
+		Accept: "application/json",
+	}
+
+	got, err := server.GetArtifactContents(ctx, req)
+	if err != nil {
+		t.Fatalf("GetArtifactContents(%+v) returned error: %s", req, err)
+	}
+
+	if got.GetContentType() != "application/json" {
+		t.Errorf("GetArtifactContents(%+v) returned content type %q, want %q", req, got.GetContentType(), "application/json")
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(got.GetData(), &value); err != nil {
+		t.Fatalf("GetArtifactContents(%+v) returned invalid JSON: %s", req, err)
+	}
+
+	want := map[string]interface{}{
+		"name":  "sample",
+		"files": []interface{}{map[string]interface{}{"filePath": "openapi.yaml"}},
+	}
+	if !cmp.Equal(want, value) {
+		t.Errorf("GetArtifactContents(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, value))
+	}
+
+	req = &rpc.GetArtifactContentsRequest{
+		Name:   "projects/my-project/locations/global/artifacts/text",
+		Accept: "application/json",
+	}
+
+	if _, err := server.GetArtifactContents(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("GetArtifactContents(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.FailedPrecondition, err)
+	}
+}
+
+func TestListArtifactsByContents(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedArtifactTypes(ctx, t, server,
+		&rpc.Artifact{
+			Name:     "projects/my-project/locations/global/artifacts/small",
+			MimeType: ownerMimeType,
+			Contents: []byte(`{"team": "apis", "size": 3}`),
+		},
+		&rpc.Artifact{
+			Name:     "projects/my-project/locations/global/artifacts/large",
+			MimeType: ownerMimeType,
+			Contents: []byte(`{"team": "apis", "size": 30}`),
+		},
+		&rpc.Artifact{
+			Name:     "projects/my-project/locations/global/artifacts/text",
+			MimeType: "text/plain",
+			Contents: []byte("text"),
+		},
+	)
+
+	req := &rpc.ListArtifactsRequest{
+		Parent: "projects/my-project/locations/global",
+		Filter: "has(contents.size) && contents.size > 10.0",
+	}
+
+	got, err := server.ListArtifacts(ctx, req)
+	if err != nil {
+		t.Fatalf("ListArtifacts(%+v) returned error: %s", req, err)
+	}
+
+	if len(got.GetArtifacts()) != 1 || got.GetArtifacts()[0].GetName() != "projects/my-project/locations/global/artifacts/large" {
+		t.Errorf("ListArtifacts(%+v) returned %+v, want only the large artifact", req, got.GetArtifacts())
+	}
+}
@@ -29,6 +29,7 @@ import (
 )
 
 type artifactParent interface {
+	names.Name
 	Artifact(string) names.Artifact
 }
 
@@ -39,8 +40,8 @@ func parseArtifactParent(name string) (artifactParent, error) {
 		return v, nil
 	} else if a, err := names.ParseApi(name); err == nil {
 		return a, nil
-	} else if p, err := names.ParseProjectWithLocation(name); err == nil {
-		return p, nil
+	} else if l, err := names.ParseLocation(name); err == nil {
+		return l, nil
 	}
 
 	return nil, fmt.Errorf("invalid artifact parent %q", name)
@@ -76,8 +77,11 @@ func (s *RegistryServer) CreateArtifact(ctx context.Context, req *rpc.CreateArti
 
 	// Creation should only succeed when the parent exists.
 	switch parent := parent.(type) {
-	case names.Project:
-		if _, err := db.GetProject(ctx, parent); err != nil {
+	case names.Location:
+		if _, err := db.GetProject(ctx, parent.Project()); err != nil {
+			return nil, err
+		}
+		if err := s.checkLocation(parent); err != nil {
 			return nil, err
 		}
 	case names.Api:
@@ -98,16 +102,32 @@ func (s *RegistryServer) CreateArtifact(ctx context.Context, req *rpc.CreateArti
 	if err != nil {
 		return nil, err
 	}
-	if err := db.SaveArtifact(ctx, artifact); err != nil {
+
+	if err := checkArtifactContents(ctx, db, artifact, req.Artifact.GetContents()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuotas(ctx, db, names.Project{ProjectID: name.ProjectID()}, storage.ProjectUsage{
+		BlobBytes: int64(artifact.SizeInBytes),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := db.SaveArtifactRevision(ctx, artifact); err != nil {
 		return nil, err
 	}
 
-	if err := db.SaveArtifactContents(ctx, artifact, req.Artifact.GetContents()); err != nil {
+	if err := db.SaveArtifactRevisionContents(ctx, artifact, req.Artifact.GetContents()); err != nil {
 		return nil, err
 	}
 
-	s.notify(ctx, rpc.Notification_CREATED, name.String())
-	return artifact.Message(), nil
+	message, err := artifact.Message(name.String(), []string{})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.notify(ctx, rpc.Notification_CREATED, artifact.RevisionName())
+	return message, nil
 }
 
 // DeleteArtifact handles the corresponding API request.
@@ -121,6 +141,8 @@ func (s *RegistryServer) DeleteArtifact(ctx context.Context, req *rpc.DeleteArti
 	name, err := names.ParseArtifact(req.GetName())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if name.RevisionID() != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid artifact name %q: use DeleteArtifactRevision to delete a revision", name)
 	}
 
 	// Deletion should only succeed on artifacts that currently exist.
@@ -149,12 +171,41 @@ func (s *RegistryServer) GetArtifact(ctx context.Context, req *rpc.GetArtifactRe
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	artifact, err := db.GetArtifact(ctx, name)
+	at, err := readTime(req.GetReadTime())
 	if err != nil {
 		return nil, err
 	}
 
-	return artifact.Message(), nil
+	var (
+		artifact *models.Artifact
+		tags     []string
+	)
+	if at.IsZero() {
+		artifact, err = db.GetArtifact(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err = artifactRevisionTags(ctx, db, name.Revision(artifact.RevisionID))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var allTags map[string]string
+		artifact, allTags, err = db.GetArtifactAt(ctx, name, at)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = revisionTagsAt(allTags, artifact.RevisionID)
+	}
+
+	message, err := artifact.Message(name.String(), tags)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
 }
 
 // GetArtifactContents handles the corresponding API request.
@@ -175,11 +226,15 @@ func (s *RegistryServer) GetArtifactContents(ctx context.Context, req *rpc.GetAr
 		return nil, err
 	}
 
-	blob, err := db.GetArtifactContents(ctx, name)
+	blob, err := db.GetArtifactRevisionContents(ctx, name.Revision(artifact.RevisionID))
 	if err != nil {
 		return nil, err
 	}
 
+	if req.GetAccept() == "application/json" {
+		return artifactContentsAsJSON(ctx, db, artifact, blob.Contents)
+	}
+
 	return &httpbody.HttpBody{
 		ContentType: artifact.MimeType,
 		Data:        blob.Contents,
@@ -207,32 +262,31 @@ func (s *RegistryServer) ListArtifacts(ctx context.Context, req *rpc.ListArtifac
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
+		Size:   req.GetPageSize(),
+		Filter: req.GetFilter(),
+		Token:  req.GetPageToken(),
+	}
+
 	var listing storage.ArtifactList
-	switch parent := parent.(type) {
-	case names.Project:
-		listing, err = db.ListProjectArtifacts(ctx, parent, storage.PageOptions{
-			Size:   req.GetPageSize(),
-			Filter: req.GetFilter(),
-			Token:  req.GetPageToken(),
-		})
-	case names.Api:
-		listing, err = db.ListApiArtifacts(ctx, parent, storage.PageOptions{
-			Size:   req.GetPageSize(),
-			Filter: req.GetFilter(),
-			Token:  req.GetPageToken(),
-		})
-	case names.Version:
-		listing, err = db.ListVersionArtifacts(ctx, parent, storage.PageOptions{
-			Size:   req.GetPageSize(),
-			Filter: req.GetFilter(),
-			Token:  req.GetPageToken(),
-		})
-	case names.Spec:
-		listing, err = db.ListSpecArtifacts(ctx, parent, storage.PageOptions{
-			Size:   req.GetPageSize(),
-			Filter: req.GetFilter(),
-			Token:  req.GetPageToken(),
-		})
+	if !at.IsZero() {
+		listing, err = db.ListArtifactsAt(ctx, parent, opts, at)
+	} else {
+		switch parent := parent.(type) {
+		case names.Location:
+			listing, err = db.ListProjectArtifacts(ctx, parent, opts)
+		case names.Api:
+			listing, err = db.ListApiArtifacts(ctx, parent, opts)
+		case names.Version:
+			listing, err = db.ListVersionArtifacts(ctx, parent, opts)
+		case names.Spec:
+			listing, err = db.ListSpecArtifacts(ctx, parent, opts)
+		}
 	}
 	if err != nil {
 		return nil, err
@@ -244,13 +298,17 @@ func (s *RegistryServer) ListArtifacts(ctx context.Context, req *rpc.ListArtifac
 	}
 
 	for i, artifact := range listing.Artifacts {
-		response.Artifacts[i] = artifact.Message()
+		response.Artifacts[i], err = artifact.Message(artifact.Name(), nil)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	return response, nil
 }
 
 // ReplaceArtifact handles the corresponding API request.
+// A new revision is created if the contents of the artifact have changed.
 func (s *RegistryServer) ReplaceArtifact(ctx context.Context, req *rpc.ReplaceArtifactRequest) (*rpc.Artifact, error) {
 	db, err := s.getStorageClient(ctx)
 	if err != nil {
@@ -261,25 +319,68 @@ func (s *RegistryServer) ReplaceArtifact(ctx context.Context, req *rpc.ReplaceAr
 	name, err := names.ParseArtifact(req.Artifact.GetName())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if name.RevisionID() != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid artifact name %q: revisions cannot be replaced", name)
 	}
 
 	// Replacement should only succeed on artifacts that currently exist.
-	if _, err := db.GetArtifact(ctx, name); err != nil {
+	artifact, err := db.GetArtifact(ctx, name)
+	if err != nil {
 		return nil, err
 	}
 
-	artifact, err := models.NewArtifact(name, req.GetArtifact())
-	if err != nil {
+	revisionID, size := artifact.RevisionID, artifact.SizeInBytes
+	if err := artifact.Update(req.GetArtifact()); err != nil {
+		return nil, err
+	}
+
+	if err := checkArtifactContents(ctx, db, artifact, req.Artifact.GetContents()); err != nil {
+		return nil, err
+	}
+
+	// Replacing the contents of the current revision releases its previous contents.
+	usage := storage.ProjectUsage{BlobBytes: int64(artifact.SizeInBytes)}
+	if artifact.RevisionID == revisionID {
+		usage.BlobBytes -= int64(size)
+	}
+	if err := s.checkQuotas(ctx, db, names.Project{ProjectID: name.ProjectID()}, usage); err != nil {
 		return nil, err
 	}
-	if err := db.SaveArtifact(ctx, artifact); err != nil {
+
+	if err := db.SaveArtifactRevision(ctx, artifact); err != nil {
 		return nil, err
 	}
 
-	if err := db.SaveArtifactContents(ctx, artifact, req.Artifact.GetContents()); err != nil {
+	if err := db.SaveArtifactRevisionContents(ctx, artifact, req.Artifact.GetContents()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	tags, err := artifactRevisionTags(ctx, db, name.Revision(artifact.RevisionID))
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := artifact.Message(name.String(), tags)
+	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	s.notify(ctx, rpc.Notification_UPDATED, name.String())
-	return artifact.Message(), nil
+	s.notify(ctx, rpc.Notification_UPDATED, artifact.RevisionName())
+	return message, nil
+}
+
+func artifactRevisionTags(ctx context.Context, db *storage.Client, name names.Artifact) ([]string, error) {
+	allTags, err := db.GetArtifactTags(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0)
+	for _, tag := range allTags {
+		if tag.RevisionID == name.RevisionID() {
+			tags = append(tags, tag.Tag)
+		}
+	}
+
+	return tags, nil
 }
@@ -77,7 +77,7 @@ func TestCreateArtifact(t *testing.T) {
 
 			opts := cmp.Options{
 				protocmp.Transform(),
-				protocmp.IgnoreFields(new(rpc.Artifact), "create_time", "update_time"),
+				protocmp.IgnoreFields(new(rpc.Artifact), "revision_id", "create_time", "update_time", "revision_create_time", "revision_update_time"),
 			}
 
 			if !cmp.Equal(test.want, created, opts) {
@@ -310,7 +310,7 @@ func TestGetArtifact(t *testing.T) {
 
 			opts := cmp.Options{
 				protocmp.Transform(),
-				protocmp.IgnoreFields(new(rpc.Artifact), "create_time", "update_time"),
+				protocmp.IgnoreFields(new(rpc.Artifact), "revision_id", "create_time", "update_time", "revision_create_time", "revision_update_time"),
 			}
 
 			if !cmp.Equal(test.want, got, opts) {
@@ -550,6 +550,31 @@ func TestListArtifacts(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "label filtering",
+			seed: []*rpc.Artifact{
+				{
+					Name:   "projects/my-project/locations/global/apis/my-api/versions/v1/artifacts/artifact1",
+					Labels: map[string]string{"generated-by": "controller"},
+				},
+				{
+					Name:   "projects/my-project/locations/global/apis/my-api/versions/v1/artifacts/artifact2",
+					Labels: map[string]string{"generated-by": "user"},
+				},
+			},
+			req: &rpc.ListArtifactsRequest{
+				Parent: "projects/my-project/locations/global/apis/my-api/versions/v1",
+				Filter: "labels['generated-by'] == 'controller'",
+			},
+			want: &rpc.ListArtifactsResponse{
+				Artifacts: []*rpc.Artifact{
+					{
+						Name:   "projects/my-project/locations/global/apis/my-api/versions/v1/artifacts/artifact1",
+						Labels: map[string]string{"generated-by": "controller"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -568,7 +593,7 @@ func TestListArtifacts(t *testing.T) {
 			opts := cmp.Options{
 				protocmp.Transform(),
 				protocmp.IgnoreFields(new(rpc.ListArtifactsResponse), "next_page_token"),
-				protocmp.IgnoreFields(new(rpc.Artifact), "create_time", "update_time"),
+				protocmp.IgnoreFields(new(rpc.Artifact), "revision_id", "create_time", "update_time", "revision_create_time", "revision_update_time"),
 				protocmp.SortRepeated(func(a, b *rpc.Artifact) bool {
 					return a.GetName() < b.GetName()
 				}),
@@ -749,7 +774,7 @@ func TestListArtifactsSequence(t *testing.T) {
 
 	opts := cmp.Options{
 		protocmp.Transform(),
-		protocmp.IgnoreFields(new(rpc.Artifact), "create_time", "update_time"),
+		protocmp.IgnoreFields(new(rpc.Artifact), "revision_id", "create_time", "update_time", "revision_create_time", "revision_update_time"),
 		cmpopts.SortSlices(func(a, b *rpc.Artifact) bool {
 			return a.GetName() < b.GetName()
 		}),
@@ -841,7 +866,7 @@ func TestReplaceArtifact(t *testing.T) {
 
 			opts := cmp.Options{
 				protocmp.Transform(),
-				protocmp.IgnoreFields(new(rpc.Artifact), "create_time", "update_time"),
+				protocmp.IgnoreFields(new(rpc.Artifact), "revision_id", "create_time", "update_time", "revision_create_time", "revision_update_time"),
 			}
 
 			if !cmp.Equal(test.want, updated, opts) {
@@ -100,6 +100,10 @@ func (s *RegistryServer) DeleteApiDeploymentRevision(ctx context.Context, req *r
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteDeploymentRevision(ctx, name); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -191,6 +195,14 @@ func (s *RegistryServer) RollbackApiDeployment(ctx context.Context, req *rpc.Rol
 		return nil, err
 	}
 
+	if err := s.checkQuotas(ctx, db, parent.Project(), storage.ProjectUsage{Revisions: 1}); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkReference(ctx, db, parent.Project(), apiSpecRevisionField, "", target.ApiSpecRevision); err != nil {
+		return nil, err
+	}
+
 	// Save a new rollback revision based on the target revision.
 	rollback := target.NewRevision()
 	if err := db.SaveDeploymentRevision(ctx, rollback); err != nil {
@@ -17,6 +17,7 @@ package registry
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/internal/storage"
@@ -68,6 +69,14 @@ func (s *RegistryServer) createDeployment(ctx context.Context, name names.Deploy
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.checkReference(ctx, db, name.Project(), apiSpecRevisionField, "", deployment.ApiSpecRevision); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuotas(ctx, db, name.Project(), storage.ProjectUsage{Revisions: 1}); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveDeploymentRevision(ctx, deployment); err != nil {
 		return nil, err
 	}
@@ -99,6 +108,10 @@ func (s *RegistryServer) DeleteApiDeployment(ctx context.Context, req *rpc.Delet
 		return nil, err
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteDeployment(ctx, name); err != nil {
 		return nil, err
 	}
@@ -109,9 +122,20 @@ func (s *RegistryServer) DeleteApiDeployment(ctx context.Context, req *rpc.Delet
 
 // GetApiDeployment handles the corresponding API request.
 func (s *RegistryServer) GetApiDeployment(ctx context.Context, req *rpc.GetApiDeploymentRequest) (*rpc.ApiDeployment, error) {
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
 	if name, err := names.ParseDeployment(req.GetName()); err == nil {
+		if !at.IsZero() {
+			return s.getApiDeploymentAt(ctx, name, "", at)
+		}
 		return s.getApiDeployment(ctx, name)
 	} else if name, err := names.ParseDeploymentRevision(req.GetName()); err == nil {
+		if !at.IsZero() {
+			return s.getApiDeploymentAt(ctx, name.Deployment(), name.RevisionID, at)
+		}
 		return s.getApiDeploymentRevision(ctx, name)
 	}
 
@@ -143,6 +167,31 @@ func (s *RegistryServer) getApiDeployment(ctx context.Context, name names.Deploy
 	return message, nil
 }
 
+func (s *RegistryServer) getApiDeploymentAt(ctx context.Context, name names.Deployment, revision string, at time.Time) (*rpc.ApiDeployment, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	deployment, tags, err := db.GetDeploymentAt(ctx, name, revision, at)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := name.String()
+	if revision != "" {
+		requested = name.Revision(revision).String()
+	}
+
+	message, err := deployment.BasicMessage(requested, revisionTagsAt(tags, deployment.RevisionID))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
+
 func (s *RegistryServer) getApiDeploymentRevision(ctx context.Context, name names.DeploymentRevision) (*rpc.ApiDeployment, error) {
 	db, err := s.getStorageClient(ctx)
 	if err != nil {
@@ -189,11 +238,22 @@ func (s *RegistryServer) ListApiDeployments(ctx context.Context, req *rpc.ListAp
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	listing, err := db.ListDeployments(ctx, parent, storage.PageOptions{
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
 		Token:  req.GetPageToken(),
-	})
+	}
+
+	if !at.IsZero() {
+		return s.listApiDeploymentsAt(ctx, db, parent, opts, at)
+	}
+
+	listing, err := db.ListDeployments(ctx, parent, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +279,33 @@ func (s *RegistryServer) ListApiDeployments(ctx context.Context, req *rpc.ListAp
 	return response, nil
 }
 
+// listApiDeploymentsAt lists API deployments as they were at a time, with the revision tags
+// recorded in each deployment's history.
+func (s *RegistryServer) listApiDeploymentsAt(ctx context.Context, db *storage.Client, parent names.Api, opts storage.PageOptions, at time.Time) (*rpc.ListApiDeploymentsResponse, error) {
+	listing, snapshots, err := db.ListDeploymentsAt(ctx, parent, opts, at)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rpc.ListApiDeploymentsResponse{
+		ApiDeployments: make([]*rpc.ApiDeployment, len(listing.Deployments)),
+		NextPageToken:  listing.Token,
+	}
+
+	for i, deployment := range listing.Deployments {
+		tags, err := snapshots[i].TagsMap()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.ApiDeployments[i], err = deployment.BasicMessage(deployment.Name(), revisionTagsAt(tags, deployment.RevisionID))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
 var updateDeploymentMutex sync.Mutex
 
 // UpdateApiDeployment handles the corresponding API request.
@@ -259,11 +346,22 @@ func (s *RegistryServer) UpdateApiDeployment(ctx context.Context, req *rpc.Updat
 	}
 
 	// Apply the update to the deployment - possibly changing the revision ID.
+	revisionID, specRevision := deployment.RevisionID, deployment.ApiSpecRevision
 	maskExpansion := models.ExpandMask(req.GetApiDeployment(), req.GetUpdateMask())
 	if err := deployment.Update(req.GetApiDeployment(), maskExpansion); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if err := s.checkReference(ctx, db, name.Project(), apiSpecRevisionField, specRevision, deployment.ApiSpecRevision); err != nil {
+		return nil, err
+	}
+
+	if deployment.RevisionID != revisionID {
+		if err := s.checkQuotas(ctx, db, name.Project(), storage.ProjectUsage{Revisions: 1}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save the updated/current deployment. This creates a new revision or updates the previous one.
 	if err := db.SaveDeploymentRevision(ctx, deployment); err != nil {
 		return nil, err
@@ -304,6 +402,7 @@ func deploymentTagsByRevision(tags []*models.DeploymentRevisionTag) map[string][
 	for _, tag := range tags {
 		rev := names.DeploymentRevision{
 			ProjectID:    tag.ProjectID,
+			LocationID:   tag.LocationID,
 			ApiID:        tag.ApiID,
 			DeploymentID: tag.DeploymentID,
 			RevisionID:   tag.RevisionID,
@@ -0,0 +1,103 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListLocations handles the corresponding API request.
+// The response includes all configured locations and any other locations
+// that contain resources of the project. If no locations are configured,
+// the default location is always included.
+func (s *RegistryServer) ListLocations(ctx context.Context, req *rpc.ListLocationsRequest) (*rpc.ListLocationsResponse, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	parent, err := names.ParseProject(req.GetParent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := db.GetProject(ctx, parent); err != nil {
+		return nil, err
+	}
+
+	used, err := db.ListLocationIDs(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	configured := s.getLocations()
+	ids := map[string]bool{}
+	if len(configured) == 0 {
+		ids[names.DefaultLocation] = true
+	}
+	for _, id := range configured {
+		ids[id] = true
+	}
+	for _, id := range used {
+		ids[id] = true
+	}
+
+	response := &rpc.ListLocationsResponse{
+		Locations: make([]*rpc.Location, 0, len(ids)),
+	}
+	for id := range ids {
+		location := parent.Location(id)
+		response.Locations = append(response.Locations, &rpc.Location{
+			Name:       location.String(),
+			LocationId: id,
+			Enabled:    s.checkLocation(location) == nil,
+		})
+	}
+	sort.Slice(response.Locations, func(i, j int) bool {
+		return response.Locations[i].GetLocationId() < response.Locations[j].GetLocationId()
+	})
+
+	return response, nil
+}
+
+func (s *RegistryServer) getLocations() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.locations
+}
+
+// checkLocation returns an InvalidArgument error if resources cannot be
+// created in a location.
+func (s *RegistryServer) checkLocation(name names.Location) error {
+	configured := s.getLocations()
+	if len(configured) == 0 {
+		return nil
+	}
+
+	for _, id := range configured {
+		if id == name.LocationID {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.InvalidArgument, "invalid location %q: must be one of %v", name, configured)
+}
@@ -0,0 +1,271 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestListLocations(t *testing.T) {
+	tests := []struct {
+		desc      string
+		locations []string
+		seed      []*rpc.Api
+		want      []*rpc.Location
+	}{
+		{
+			desc: "unconfigured without resources",
+			want: []*rpc.Location{
+				{Name: "projects/my-project/locations/global", LocationId: "global", Enabled: true},
+			},
+		},
+		{
+			desc: "unconfigured with resources",
+			seed: []*rpc.Api{
+				{Name: "projects/my-project/locations/us-central1/apis/my-api"},
+			},
+			want: []*rpc.Location{
+				{Name: "projects/my-project/locations/global", LocationId: "global", Enabled: true},
+				{Name: "projects/my-project/locations/us-central1", LocationId: "us-central1", Enabled: true},
+			},
+		},
+		{
+			desc:      "configured",
+			locations: []string{"us-central1", "europe-west1"},
+			seed: []*rpc.Api{
+				{Name: "projects/my-project/locations/us-central1/apis/my-api"},
+			},
+			want: []*rpc.Location{
+				{Name: "projects/my-project/locations/europe-west1", LocationId: "europe-west1", Enabled: true},
+				{Name: "projects/my-project/locations/us-central1", LocationId: "us-central1", Enabled: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			server.Reload(Config{Locations: test.locations})
+			seed := []seeder.RegistryResource{&rpc.Project{Name: "projects/my-project"}}
+			for _, api := range test.seed {
+				seed = append(seed, api)
+			}
+			if err := seeder.SeedRegistry(ctx, server, seed...); err != nil {
+				t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+			}
+
+			req := &rpc.ListLocationsRequest{Parent: "projects/my-project"}
+			got, err := server.ListLocations(ctx, req)
+			if err != nil {
+				t.Fatalf("ListLocations(%+v) returned error: %s", req, err)
+			}
+
+			if !cmp.Equal(test.want, got.GetLocations(), protocmp.Transform()) {
+				t.Errorf("ListLocations(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(test.want, got.GetLocations(), protocmp.Transform()))
+			}
+		})
+	}
+}
+
+func TestListLocationsResponseCodes(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *rpc.ListLocationsRequest
+		want codes.Code
+	}{
+		{
+			desc: "invalid parent",
+			req:  &rpc.ListLocationsRequest{Parent: "projects/my-project/locations/global"},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "missing project",
+			req:  &rpc.ListLocationsRequest{Parent: "projects/other-project"},
+			want: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			if _, err := server.ListLocations(ctx, test.req); status.Code(err) != test.want {
+				t.Errorf("ListLocations(%+v) returned status code %q, want %q: %v", test.req, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestConfiguredLocations(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.Reload(Config{Locations: []string{"us-central1"}})
+	if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	tests := []struct {
+		desc   string
+		parent string
+		want   codes.Code
+	}{
+		{
+			desc:   "configured location",
+			parent: "projects/my-project/locations/us-central1",
+			want:   codes.OK,
+		},
+		{
+			desc:   "unconfigured location",
+			parent: "projects/my-project/locations/global",
+			want:   codes.InvalidArgument,
+		},
+		{
+			desc:   "wildcard location",
+			parent: "projects/my-project/locations/-",
+			want:   codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			req := &rpc.CreateApiRequest{
+				Parent: test.parent,
+				ApiId:  "my-api",
+				Api:    &rpc.Api{},
+			}
+			if _, err := server.CreateApi(ctx, req); status.Code(err) != test.want {
+				t.Errorf("CreateApi(%+v) returned status code %q, want %q: %v", req, status.Code(err), test.want, err)
+			}
+
+			artifact := &rpc.CreateArtifactRequest{
+				Parent:     test.parent,
+				ArtifactId: "my-artifact",
+				Artifact:   &rpc.Artifact{},
+			}
+			if _, err := server.CreateArtifact(ctx, artifact); status.Code(err) != test.want {
+				t.Errorf("CreateArtifact(%+v) returned status code %q, want %q: %v", artifact, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestLocationPartitioning(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seed := []*rpc.ApiSpec{
+		{Name: "projects/my-project/locations/global/apis/my-api/versions/v1/specs/my-spec"},
+		{Name: "projects/my-project/locations/us-central1/apis/my-api/versions/v1/specs/my-spec"},
+		{Name: "projects/my-project/locations/europe-west1/apis/other-api/versions/v1/specs/my-spec"},
+	}
+	artifacts := []*rpc.Artifact{
+		{Name: "projects/my-project/locations/global/artifacts/my-artifact"},
+		{Name: "projects/my-project/locations/us-central1/artifacts/my-artifact"},
+	}
+	if err := seeder.SeedRegistry(ctx, server, seed[0], seed[1], seed[2], artifacts[0], artifacts[1]); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	listApis := func(t *testing.T, parent string) []string {
+		t.Helper()
+		req := &rpc.ListApisRequest{Parent: parent}
+		resp, err := server.ListApis(ctx, req)
+		if err != nil {
+			t.Fatalf("ListApis(%+v) returned error: %s", req, err)
+		}
+		var got []string
+		for _, api := range resp.GetApis() {
+			got = append(got, api.GetName())
+		}
+		return got
+	}
+
+	sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+
+	t.Run("ListApis", func(t *testing.T) {
+		got := listApis(t, "projects/my-project/locations/us-central1")
+		want := []string{"projects/my-project/locations/us-central1/apis/my-api"}
+		if !cmp.Equal(want, got) {
+			t.Errorf("ListApis returned unexpected diff (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("ListApisAcrossLocations", func(t *testing.T) {
+		got := listApis(t, "projects/my-project/locations/-")
+		want := []string{
+			"projects/my-project/locations/europe-west1/apis/other-api",
+			"projects/my-project/locations/global/apis/my-api",
+			"projects/my-project/locations/us-central1/apis/my-api",
+		}
+		if !cmp.Equal(want, got, sortStrings) {
+			t.Errorf("ListApis returned unexpected diff (-want +got):\n%s", cmp.Diff(want, got, sortStrings))
+		}
+	})
+
+	t.Run("ListApiSpecsAcrossLocations", func(t *testing.T) {
+		req := &rpc.ListApiSpecsRequest{Parent: "projects/my-project/locations/-/apis/my-api/versions/-"}
+		resp, err := server.ListApiSpecs(ctx, req)
+		if err != nil {
+			t.Fatalf("ListApiSpecs(%+v) returned error: %s", req, err)
+		}
+		var got []string
+		for _, spec := range resp.GetApiSpecs() {
+			got = append(got, spec.GetName())
+		}
+		want := []string{
+			"projects/my-project/locations/global/apis/my-api/versions/v1/specs/my-spec",
+			"projects/my-project/locations/us-central1/apis/my-api/versions/v1/specs/my-spec",
+		}
+		if !cmp.Equal(want, got, sortStrings) {
+			t.Errorf("ListApiSpecs(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, sortStrings))
+		}
+	})
+
+	t.Run("ListArtifacts", func(t *testing.T) {
+		req := &rpc.ListArtifactsRequest{Parent: "projects/my-project/locations/us-central1"}
+		resp, err := server.ListArtifacts(ctx, req)
+		if err != nil {
+			t.Fatalf("ListArtifacts(%+v) returned error: %s", req, err)
+		}
+		if len(resp.GetArtifacts()) != 1 || resp.GetArtifacts()[0].GetName() != artifacts[1].GetName() {
+			t.Errorf("ListArtifacts(%+v) returned %v, want only %q", req, resp.GetArtifacts(), artifacts[1].GetName())
+		}
+	})
+
+	t.Run("DeleteApi", func(t *testing.T) {
+		req := &rpc.DeleteApiRequest{Name: "projects/my-project/locations/us-central1/apis/my-api"}
+		if _, err := server.DeleteApi(ctx, req); err != nil {
+			t.Fatalf("DeleteApi(%+v) returned error: %s", req, err)
+		}
+
+		spec := &rpc.GetApiSpecRequest{Name: seed[0].GetName()}
+		if _, err := server.GetApiSpec(ctx, spec); err != nil {
+			t.Errorf("GetApiSpec(%+v) returned error after deleting API in another location: %s", spec, err)
+		}
+		spec = &rpc.GetApiSpecRequest{Name: seed[1].GetName()}
+		if _, err := server.GetApiSpec(ctx, spec); status.Code(err) != codes.NotFound {
+			t.Errorf("GetApiSpec(%+v) returned status code %q, want %q: %v", spec, status.Code(err), codes.NotFound, err)
+		}
+	})
+}
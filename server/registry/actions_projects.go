@@ -53,13 +53,34 @@ func (s *RegistryServer) createProject(ctx context.Context, name names.Project,
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	project := models.NewProject(name, body)
+	if err := validateRetentionPolicies(name, body.GetRetentionPolicies()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := validateLifecycle("api_lifecycle", body.GetApiLifecycle()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := validateLifecycle("version_lifecycle", body.GetVersionLifecycle()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	project, err := models.NewProject(name, body)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	if err := db.SaveProject(ctx, project); err != nil {
 		return nil, err
 	}
 
+	message, err := project.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	s.notify(ctx, rpc.Notification_CREATED, name.String())
-	return project.Message(), nil
+	return message, nil
 }
 
 // DeleteProject handles the corresponding API request.
@@ -101,12 +122,34 @@ func (s *RegistryServer) GetProject(ctx context.Context, req *rpc.GetProjectRequ
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	project, err := db.GetProject(ctx, name)
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var project *models.Project
+	if at.IsZero() {
+		project, err = db.GetProject(ctx, name)
+	} else {
+		project, err = db.GetProjectAt(ctx, name, at)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return project.Message(), nil
+	message, err := project.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Quota usage is only reported for the current state of a project.
+	if at.IsZero() {
+		if message.Quotas, err = s.projectQuotas(ctx, db, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return message, nil
 }
 
 // ListProjects handles the corresponding API request.
@@ -125,11 +168,23 @@ func (s *RegistryServer) ListProjects(ctx context.Context, req *rpc.ListProjects
 		req.PageSize = 50
 	}
 
-	listing, err := db.ListProjects(ctx, storage.PageOptions{
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
 		Token:  req.GetPageToken(),
-	})
+	}
+
+	var listing storage.ProjectList
+	if at.IsZero() {
+		listing, err = db.ListProjects(ctx, opts)
+	} else {
+		listing, err = db.ListProjectsAt(ctx, opts, at)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +195,10 @@ func (s *RegistryServer) ListProjects(ctx context.Context, req *rpc.ListProjects
 	}
 
 	for i, project := range listing.Projects {
-		response.Projects[i] = project.Message()
+		response.Projects[i], err = project.Message()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	return response, nil
@@ -185,11 +243,35 @@ func (s *RegistryServer) UpdateProject(ctx context.Context, req *rpc.UpdateProje
 		return nil, err
 	}
 
-	project.Update(req.GetProject(), models.ExpandMask(req.GetProject(), req.GetUpdateMask()))
+	mask := models.ExpandMask(req.GetProject(), req.GetUpdateMask())
+	for _, field := range mask.GetPaths() {
+		var err error
+		switch field {
+		case "retention_policies":
+			err = validateRetentionPolicies(name, req.GetProject().GetRetentionPolicies())
+		case "api_lifecycle":
+			err = validateLifecycle(field, req.GetProject().GetApiLifecycle())
+		case "version_lifecycle":
+			err = validateLifecycle(field, req.GetProject().GetVersionLifecycle())
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if err := project.Update(req.GetProject(), mask); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	if err := db.SaveProject(ctx, project); err != nil {
 		return nil, err
 	}
 
+	message, err := project.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	s.notify(ctx, rpc.Notification_UPDATED, name.String())
-	return project.Message(), nil
+	return message, nil
 }
@@ -0,0 +1,265 @@
+// Copyright 2020 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateRelationship handles the corresponding API request.
+func (s *RegistryServer) CreateRelationship(ctx context.Context, req *rpc.CreateRelationshipRequest) (*rpc.Relationship, error) {
+	parent, err := names.ParseApi(req.GetParent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.GetRelationship() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid relationship %+v: body must be provided", req.GetRelationship())
+	}
+
+	return s.createRelationship(ctx, parent.Relationship(req.GetRelationshipId()), req.GetRelationship())
+}
+
+func (s *RegistryServer) createRelationship(ctx context.Context, name names.Relationship, body *rpc.Relationship) (*rpc.Relationship, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if _, err := db.GetRelationship(ctx, name); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "relationship %q already exists", name)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	if err := name.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Creation should only succeed when the parent exists.
+	if _, err := db.GetApi(ctx, name.Api()); err != nil {
+		return nil, err
+	}
+
+	relationship, err := models.NewRelationship(name, body)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := checkRelationship(ctx, db, name, relationship); err != nil {
+		return nil, err
+	}
+
+	if err := db.SaveRelationship(ctx, relationship); err != nil {
+		return nil, err
+	}
+
+	message, err := relationship.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.notify(ctx, rpc.Notification_CREATED, name.String())
+	return message, nil
+}
+
+// DeleteRelationship handles the corresponding API request.
+func (s *RegistryServer) DeleteRelationship(ctx context.Context, req *rpc.DeleteRelationshipRequest) (*emptypb.Empty, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseRelationship(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Deletion should only succeed on relationships that currently exist.
+	if _, err := db.GetRelationship(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if err := db.DeleteRelationship(ctx, name); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, rpc.Notification_DELETED, name.String())
+	return &emptypb.Empty{}, nil
+}
+
+// GetRelationship handles the corresponding API request.
+func (s *RegistryServer) GetRelationship(ctx context.Context, req *rpc.GetRelationshipRequest) (*rpc.Relationship, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseRelationship(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var relationship *models.Relationship
+	if at.IsZero() {
+		relationship, err = db.GetRelationship(ctx, name)
+	} else {
+		relationship, err = db.GetRelationshipAt(ctx, name, at)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := relationship.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
+
+// ListRelationships handles the corresponding API request.
+func (s *RegistryServer) ListRelationships(ctx context.Context, req *rpc.ListRelationshipsRequest) (*rpc.ListRelationshipsResponse, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %d: must not be negative", req.GetPageSize())
+	} else if req.GetPageSize() > 1000 {
+		req.PageSize = 1000
+	} else if req.GetPageSize() == 0 {
+		req.PageSize = 50
+	}
+
+	parent, err := names.ParseApi(req.GetParent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
+		Size:   req.GetPageSize(),
+		Filter: req.GetFilter(),
+		Token:  req.GetPageToken(),
+	}
+
+	var listing storage.RelationshipList
+	if at.IsZero() {
+		listing, err = db.ListRelationships(ctx, parent, opts)
+	} else {
+		listing, err = db.ListRelationshipsAt(ctx, parent, opts, at)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rpc.ListRelationshipsResponse{
+		Relationships: make([]*rpc.Relationship, len(listing.Relationships)),
+		NextPageToken: listing.Token,
+	}
+
+	for i, relationship := range listing.Relationships {
+		response.Relationships[i], err = relationship.Message()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
+var updateRelationshipMutex sync.Mutex
+
+// UpdateRelationship handles the corresponding API request.
+func (s *RegistryServer) UpdateRelationship(ctx context.Context, req *rpc.UpdateRelationshipRequest) (*rpc.Relationship, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	if req.GetRelationship() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid relationship %+v: body must be provided", req.GetRelationship())
+	} else if err := models.ValidateMask(req.GetRelationship(), req.GetUpdateMask()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid update_mask %v: %s", req.GetUpdateMask(), err)
+	}
+
+	name, err := names.ParseRelationship(req.Relationship.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.GetAllowMissing() {
+		// Prevent a race condition that can occur when two updates are made
+		// to the same non-existent resource. The db.Get...() call returns
+		// NotFound for both updates, and after one creates the resource,
+		// the other creation fails. The lock() prevents this by serializing
+		// the get and create operations. Future updates could improve this
+		// with improvements closer to the database level.
+		updateRelationshipMutex.Lock()
+		defer updateRelationshipMutex.Unlock()
+	}
+
+	relationship, err := db.GetRelationship(ctx, name)
+	if req.GetAllowMissing() && isNotFound(err) {
+		return s.createRelationship(ctx, name, req.GetRelationship())
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := relationship.Update(req.GetRelationship(), models.ExpandMask(req.GetRelationship(), req.GetUpdateMask())); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := checkRelationship(ctx, db, name, relationship); err != nil {
+		return nil, err
+	}
+
+	if err := db.SaveRelationship(ctx, relationship); err != nil {
+		return nil, err
+	}
+
+	message, err := relationship.Message()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.notify(ctx, rpc.Notification_UPDATED, name.String())
+	return message, nil
+}
@@ -100,6 +100,10 @@ func (s *RegistryServer) DeleteApiSpecRevision(ctx context.Context, req *rpc.Del
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteSpecRevision(ctx, name); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -191,6 +195,13 @@ func (s *RegistryServer) RollbackApiSpec(ctx context.Context, req *rpc.RollbackA
 		return nil, err
 	}
 
+	if err := s.checkQuotas(ctx, db, parent.Project(), storage.ProjectUsage{
+		Revisions: 1,
+		BlobBytes: int64(target.SizeInBytes),
+	}); err != nil {
+		return nil, err
+	}
+
 	// Save a new rollback revision based on the target revision.
 	rollback := target.NewRevision()
 	if err := db.SaveSpecRevision(ctx, rollback); err != nil {
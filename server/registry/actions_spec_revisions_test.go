@@ -324,6 +324,7 @@ func TestListApiSpecRevisions(t *testing.T) {
 		Name:               fmt.Sprintf("%s@%s", secondRevision.GetName(), secondRevision.GetRevisionId()),
 		Hash:               secondRevision.GetHash(),
 		SizeBytes:          secondRevision.GetSizeBytes(),
+		MimeType:           "application/x.openapi;version=3",
 		CreateTime:         secondRevision.GetCreateTime(),
 		RevisionCreateTime: secondRevision.GetRevisionCreateTime(),
 		RevisionUpdateTime: secondRevision.GetRevisionUpdateTime(),
@@ -450,6 +451,7 @@ func TestUpdateApiSpecRevisions(t *testing.T) {
 		want := proto.Clone(created).(*rpc.ApiSpec)
 		want.SizeBytes = int32(len(req.ApiSpec.GetContents()))
 		want.Hash = sha256hash(req.ApiSpec.GetContents())
+		want.MimeType = "application/x.openapi;version=3"
 
 		got, err := server.UpdateApiSpec(ctx, req)
 		if err != nil {
@@ -18,10 +18,12 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/internal/storage"
 	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/mimetypes"
 	"github.com/apigee/registry/server/registry/names"
 	"google.golang.org/genproto/googleapis/api/httpbody"
 	"google.golang.org/grpc/codes"
@@ -66,11 +68,24 @@ func (s *RegistryServer) createSpec(ctx context.Context, name names.Spec, body *
 		return nil, err
 	}
 
+	body.MimeType, err = s.specMimeType(body.GetMimeType(), body.GetFilename(), body.GetContents())
+	if err != nil {
+		return nil, err
+	}
+
 	spec, err := models.NewSpec(name, body)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.checkQuotas(ctx, db, name.Project(), storage.ProjectUsage{
+		Specs:     1,
+		Revisions: 1,
+		BlobBytes: int64(spec.SizeInBytes),
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveSpecRevision(ctx, spec); err != nil {
 		return nil, err
 	}
@@ -106,6 +121,10 @@ func (s *RegistryServer) DeleteApiSpec(ctx context.Context, req *rpc.DeleteApiSp
 		return nil, err
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteSpec(ctx, name); err != nil {
 		return nil, err
 	}
@@ -116,9 +135,20 @@ func (s *RegistryServer) DeleteApiSpec(ctx context.Context, req *rpc.DeleteApiSp
 
 // GetApiSpec handles the corresponding API request.
 func (s *RegistryServer) GetApiSpec(ctx context.Context, req *rpc.GetApiSpecRequest) (*rpc.ApiSpec, error) {
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
 	if name, err := names.ParseSpec(req.GetName()); err == nil {
+		if !at.IsZero() {
+			return s.getApiSpecAt(ctx, name, "", at)
+		}
 		return s.getApiSpec(ctx, name)
 	} else if name, err := names.ParseSpecRevision(req.GetName()); err == nil {
+		if !at.IsZero() {
+			return s.getApiSpecAt(ctx, name.Spec(), name.RevisionID, at)
+		}
 		return s.getApiSpecRevision(ctx, name)
 	}
 
@@ -150,6 +180,31 @@ func (s *RegistryServer) getApiSpec(ctx context.Context, name names.Spec) (*rpc.
 	return message, nil
 }
 
+func (s *RegistryServer) getApiSpecAt(ctx context.Context, name names.Spec, revision string, at time.Time) (*rpc.ApiSpec, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	spec, tags, err := db.GetSpecAt(ctx, name, revision, at)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := name.String()
+	if revision != "" {
+		requested = name.Revision(revision).String()
+	}
+
+	message, err := spec.BasicMessage(requested, revisionTagsAt(tags, spec.RevisionID))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
+
 func (s *RegistryServer) getApiSpecRevision(ctx context.Context, name names.SpecRevision) (*rpc.ApiSpec, error) {
 	db, err := s.getStorageClient(ctx)
 	if err != nil {
@@ -203,13 +258,21 @@ func (s *RegistryServer) GetApiSpecContents(ctx context.Context, req *rpc.GetApi
 	if err != nil {
 		return nil, err
 	}
-	if strings.Contains(spec.MimeType, "+gzip") {
+	// MIME types stored before they were validated may not parse, so their
+	// compression is recognized by their suffix.
+	contentType, compressed := spec.MimeType, strings.Contains(spec.MimeType, "+gzip")
+	if mimeType, err := mimetypes.Parse(spec.MimeType); err == nil {
+		contentType, compressed = mimeType.WithoutCompression().String(), mimeType.Compression == mimetypes.GZip
+	} else if compressed {
+		contentType = strings.Replace(spec.MimeType, "+gzip", "", 1)
+	}
+	if compressed {
 		contents, err := models.GUnzippedBytes(blob.Contents)
 		if err != nil {
 			return nil, status.Errorf(codes.FailedPrecondition, "failed to unzip contents with gzip MIME type: %s", err)
 		}
 		return &httpbody.HttpBody{
-			ContentType: strings.Replace(spec.MimeType, "+gzip", "", 1),
+			ContentType: contentType,
 			Data:        contents,
 		}, nil
 	}
@@ -240,11 +303,22 @@ func (s *RegistryServer) ListApiSpecs(ctx context.Context, req *rpc.ListApiSpecs
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	listing, err := db.ListSpecs(ctx, parent, storage.PageOptions{
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
 		Token:  req.GetPageToken(),
-	})
+	}
+
+	if !at.IsZero() {
+		return s.listApiSpecsAt(ctx, db, parent, opts, at)
+	}
+
+	listing, err := db.ListSpecs(ctx, parent, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +344,33 @@ func (s *RegistryServer) ListApiSpecs(ctx context.Context, req *rpc.ListApiSpecs
 	return response, nil
 }
 
+// listApiSpecsAt lists API specs as they were at a time, with the revision tags
+// recorded in each spec's history.
+func (s *RegistryServer) listApiSpecsAt(ctx context.Context, db *storage.Client, parent names.Version, opts storage.PageOptions, at time.Time) (*rpc.ListApiSpecsResponse, error) {
+	listing, snapshots, err := db.ListSpecsAt(ctx, parent, opts, at)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rpc.ListApiSpecsResponse{
+		ApiSpecs:      make([]*rpc.ApiSpec, len(listing.Specs)),
+		NextPageToken: listing.Token,
+	}
+
+	for i, spec := range listing.Specs {
+		tags, err := snapshots[i].TagsMap()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.ApiSpecs[i], err = spec.BasicMessage(spec.Name(), revisionTagsAt(tags, spec.RevisionID))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
 var updateSpecMutex sync.Mutex
 
 // UpdateApiSpec handles the corresponding API request.
@@ -310,11 +411,42 @@ func (s *RegistryServer) UpdateApiSpec(ctx context.Context, req *rpc.UpdateApiSp
 	}
 
 	// Apply the update to the spec - possibly changing the revision ID.
+	revisionID := spec.RevisionID
 	maskExpansion := models.ExpandMask(req.GetApiSpec(), req.GetUpdateMask())
+
+	// Normalize a new MIME type, or detect one for new contents if the spec
+	// wouldn't have one.
+	body, paths := req.GetApiSpec(), maskExpansion.GetPaths()
+	if containsString(paths, "mime_type") || (spec.MimeType == "" && containsString(paths, "contents")) {
+		filename := spec.FileName
+		if containsString(paths, "filename") {
+			filename = body.GetFilename()
+		}
+		var contents []byte
+		if containsString(paths, "contents") {
+			contents = body.GetContents()
+		}
+		if body.MimeType, err = s.specMimeType(body.GetMimeType(), filename, contents); err != nil {
+			return nil, err
+		}
+		if !containsString(paths, "mime_type") {
+			maskExpansion.Paths = append(maskExpansion.Paths, "mime_type")
+		}
+	}
+
 	if err := spec.Update(req.GetApiSpec(), maskExpansion); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if spec.RevisionID != revisionID {
+		if err := s.checkQuotas(ctx, db, name.Project(), storage.ProjectUsage{
+			Revisions: 1,
+			BlobBytes: int64(spec.SizeInBytes),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save the updated/current spec. This creates a new revision or updates the previous one.
 	if err := db.SaveSpecRevision(ctx, spec); err != nil {
 		return nil, err
@@ -362,6 +494,7 @@ func specTagsByRevision(tags []*models.SpecRevisionTag) map[string][]string {
 	for _, tag := range tags {
 		rev := names.SpecRevision{
 			ProjectID:  tag.ProjectID,
+			LocationID: tag.LocationID,
 			ApiID:      tag.ApiID,
 			VersionID:  tag.VersionID,
 			SpecID:     tag.SpecID,
@@ -373,3 +506,26 @@ func specTagsByRevision(tags []*models.SpecRevisionTag) map[string][]string {
 
 	return revTags
 }
+
+// specMimeType returns the normal form of a spec's MIME type, or if it is
+// empty, the MIME type detected from the spec's filename and contents.
+// MIME types that can't be parsed are returned unchanged unless the server
+// requires strict MIME types.
+func (s *RegistryServer) specMimeType(mimeType, filename string, contents []byte) (string, error) {
+	if mimeType == "" {
+		return mimetypes.Detect(filename, contents), nil
+	}
+
+	normal, err := mimetypes.Normalize(mimeType)
+	if err != nil {
+		s.mu.RLock()
+		strict := s.strictMime
+		s.mu.RUnlock()
+		if !strict {
+			return mimeType, nil
+		}
+		return "", status.Errorf(codes.InvalidArgument, "invalid mime_type %q: %s", mimeType, err)
+	}
+
+	return normal, nil
+}
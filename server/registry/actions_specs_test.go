@@ -15,6 +15,8 @@
 package registry
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"fmt"
@@ -530,6 +532,7 @@ func TestListApiSpecs(t *testing.T) {
 						Name:      "projects/my-project/locations/global/apis/my-api/versions/v1/specs/spec1",
 						Hash:      sha256hash(specContents),
 						SizeBytes: int32(len(specContents)),
+						MimeType:  "application/x.openapi;version=3",
 					},
 					{
 						Name: "projects/my-project/locations/global/apis/my-api/versions/v1/specs/spec2",
@@ -1255,3 +1258,120 @@ func TestDeleteApiSpecResponseCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestApiSpecMimeTypes(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	if err := seeder.SeedVersions(ctx, server, &rpc.ApiVersion{Name: "projects/my-project/locations/global/apis/my-api/versions/v1"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	const parent = "projects/my-project/locations/global/apis/my-api/versions/v1"
+	tests := []struct {
+		id     string
+		spec   *rpc.ApiSpec
+		strict bool
+		want   string
+		code   codes.Code
+	}{
+		{
+			id:   "normalized",
+			spec: &rpc.ApiSpec{MimeType: "Application/X.OpenAPI; Version=3"},
+			want: "application/x.openapi;version=3",
+		},
+		{
+			id:   "detected",
+			spec: &rpc.ApiSpec{Filename: "swagger.json", Contents: []byte(`{"swagger": "2.0"}`)},
+			want: "application/x.openapi;version=2",
+		},
+		{
+			id:   "undetected",
+			spec: &rpc.ApiSpec{Contents: []byte("hello")},
+			want: "",
+		},
+		{
+			id:   "unparsed",
+			spec: &rpc.ApiSpec{MimeType: "application/x.openapi+gzip+zip"},
+			want: "application/x.openapi+gzip+zip",
+		},
+		{
+			id:     "invalid",
+			spec:   &rpc.ApiSpec{MimeType: "application/x.openapi+gzip+zip"},
+			strict: true,
+			code:   codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			server.Reload(Config{StrictMimeTypes: test.strict})
+			got, err := server.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+				Parent:    parent,
+				ApiSpecId: test.id,
+				ApiSpec:   test.spec,
+			})
+			if status.Code(err) != test.code {
+				t.Fatalf("CreateApiSpec(%+v) returned status code %q, want %q: %v", test.spec, status.Code(err), test.code, err)
+			} else if err == nil && got.GetMimeType() != test.want {
+				t.Errorf("CreateApiSpec(%+v) returned mime_type %q, want %q", test.spec, got.GetMimeType(), test.want)
+			}
+		})
+	}
+
+	server.Reload(Config{})
+	updated, err := server.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec:    &rpc.ApiSpec{Name: parent + "/specs/undetected", Contents: specContents},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"contents"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateApiSpec() returned error: %s", err)
+	}
+	if want := "application/x.openapi;version=3"; updated.GetMimeType() != want {
+		t.Errorf("UpdateApiSpec() returned mime_type %q, want %q", updated.GetMimeType(), want)
+	}
+
+	// Contents with a MIME type that doesn't parse are still uncompressed.
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	if _, err := zw.Write(specContents); err != nil {
+		t.Fatalf("Setup: Failed to compress contents: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Setup: Failed to compress contents: %s", err)
+	}
+	legacy, err := server.CreateApiSpec(ctx, &rpc.CreateApiSpecRequest{
+		Parent:    parent,
+		ApiSpecId: "legacy",
+		ApiSpec:   &rpc.ApiSpec{MimeType: "openapi+gzip", Contents: gzipped.Bytes()},
+	})
+	if err != nil {
+		t.Fatalf("CreateApiSpec() returned error: %s", err)
+	}
+	contents, err := server.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: legacy.GetName()})
+	if err != nil {
+		t.Fatalf("GetApiSpecContents() returned error: %s", err)
+	}
+	if want := "openapi"; contents.GetContentType() != want {
+		t.Errorf("GetApiSpecContents() returned content type %q, want %q", contents.GetContentType(), want)
+	}
+	if !bytes.Equal(contents.GetData(), specContents) {
+		t.Errorf("GetApiSpecContents() returned compressed contents")
+	}
+
+	listing, err := server.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{
+		Parent: parent,
+		Filter: `mime_type.format == "openapi" && mime_type.version == "3"`,
+	})
+	if err != nil {
+		t.Fatalf("ListApiSpecs() returned error: %s", err)
+	}
+
+	var names []string
+	for _, spec := range listing.GetApiSpecs() {
+		names = append(names, spec.GetName())
+	}
+	want := []string{parent + "/specs/normalized", parent + "/specs/undetected"}
+	if diff := cmp.Diff(want, names, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("ListApiSpecs() returned unexpected specs (-want +got):\n%s", diff)
+	}
+}
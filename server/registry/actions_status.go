@@ -16,15 +16,70 @@ package registry
 
 import (
 	"context"
+	"runtime"
+	"runtime/debug"
+	"time"
 
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // GetStatus handles the corresponding API request.
 func (s *RegistryServer) GetStatus(ctx context.Context, req *emptypb.Empty) (*rpc.Status, error) {
 	status := &rpc.Status{
-		Message: "running",
+		Message:       "running",
+		SchemaVersion: storage.SchemaVersion,
+		Build:         buildInfo(),
+		Uptime:        durationpb.New(time.Since(s.startTime)),
+		Storage:       s.checkStorage(ctx),
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.lastReload != nil {
+		status.LastReload = proto.Clone(s.lastReload).(*rpc.ReloadStatus)
 	}
 	return status, nil
 }
+
+func buildInfo() *rpc.BuildInfo {
+	info := &rpc.BuildInfo{
+		GoVersion: runtime.Version(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Path = build.Main.Path
+		info.Version = build.Main.Version
+	}
+	return info
+}
+
+// checkStorage reports the connectivity of the database and blob store.
+func (s *RegistryServer) checkStorage(ctx context.Context) []*rpc.StorageStatus {
+	database := &rpc.StorageStatus{Name: "database"}
+	blobs := &rpc.StorageStatus{Name: "blobs"}
+
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		database.Message = err.Error()
+		blobs.Message = err.Error()
+		return []*rpc.StorageStatus{database, blobs}
+	}
+	defer db.Close()
+
+	if err := db.CheckDatabase(ctx); err != nil {
+		database.Message = err.Error()
+	} else {
+		database.Connected = true
+	}
+
+	if err := db.CheckBlobs(ctx); err != nil {
+		blobs.Message = err.Error()
+	} else {
+		blobs.Connected = true
+	}
+
+	return []*rpc.StorageStatus{database, blobs}
+}
@@ -16,9 +16,12 @@ package registry
 
 import (
 	"context"
+	"errors"
+	"runtime"
 	"testing"
 
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -30,7 +33,15 @@ func TestGetStatus(t *testing.T) {
 
 	req := &emptypb.Empty{}
 	want := &rpc.Status{
-		Message: "running",
+		Message:       "running",
+		SchemaVersion: storage.SchemaVersion,
+		Build: &rpc.BuildInfo{
+			GoVersion: runtime.Version(),
+		},
+		Storage: []*rpc.StorageStatus{
+			{Name: "database", Connected: true},
+			{Name: "blobs", Connected: true},
+		},
 	}
 
 	got, err := server.GetStatus(ctx, req)
@@ -38,7 +49,63 @@ func TestGetStatus(t *testing.T) {
 		t.Fatalf("GetStatus(%+v) returned error: %s", req, err)
 	}
 
-	if !cmp.Equal(want, got, protocmp.Transform()) {
-		t.Errorf("GetStatus(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, protocmp.Transform()))
+	if got.GetUptime().AsDuration() <= 0 {
+		t.Errorf("GetStatus(%+v) returned non-positive uptime %v", req, got.GetUptime().AsDuration())
+	}
+
+	opts := cmp.Options{
+		protocmp.Transform(),
+		protocmp.IgnoreFields(&rpc.Status{}, "uptime"),
+		protocmp.IgnoreFields(&rpc.BuildInfo{}, "path", "version"),
+	}
+
+	if !cmp.Equal(want, got, opts) {
+		t.Errorf("GetStatus(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts))
+	}
+}
+
+func TestGetStatusAfterReload(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	tests := []struct {
+		desc string
+		err  error
+		want *rpc.ReloadStatus
+	}{
+		{
+			desc: "success",
+			want: &rpc.ReloadStatus{Success: true, Message: "configuration reloaded"},
+		},
+		{
+			desc: "failure",
+			err:  errors.New("invalid logging.level"),
+			want: &rpc.ReloadStatus{Success: false, Message: "invalid logging.level"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			server.Reload(Config{})
+			server.RecordReload(test.err)
+
+			got, err := server.GetStatus(ctx, &emptypb.Empty{})
+			if err != nil {
+				t.Fatalf("GetStatus() returned error: %s", err)
+			}
+
+			if got.GetLastReload().GetTime() == nil {
+				t.Errorf("GetStatus() returned reload status without time")
+			}
+
+			opts := cmp.Options{
+				protocmp.Transform(),
+				protocmp.IgnoreFields(&rpc.ReloadStatus{}, "time"),
+			}
+
+			if !cmp.Equal(test.want, got.GetLastReload(), opts) {
+				t.Errorf("GetStatus() returned unexpected diff (-want +got):\n%s", cmp.Diff(test.want, got.GetLastReload(), opts))
+			}
+		})
 	}
 }
@@ -68,16 +68,35 @@ func (s *RegistryServer) createApiVersion(ctx context.Context, name names.Versio
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	_, lifecycle, err := projectLifecycles(ctx, db, name.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := versionChange(name, &models.Version{}, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLifecycle(ctx, db, lifecycle, change); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveVersion(ctx, version); err != nil {
 		return nil, err
 	}
 
+	transition, err := recordTransition(ctx, db, lifecycle, change, models.NewVersionTransition(name, change.from, change.to))
+	if err != nil {
+		return nil, err
+	}
+
 	message, err := version.Message()
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	s.notify(ctx, rpc.Notification_CREATED, name.String())
+	s.notifyTransition(ctx, rpc.Notification_CREATED, name.String(), transition)
 	return message, nil
 }
 
@@ -99,6 +118,10 @@ func (s *RegistryServer) DeleteApiVersion(ctx context.Context, req *rpc.DeleteAp
 		return nil, err
 	}
 
+	if err := s.applyDeletePolicy(ctx, db, name); err != nil {
+		return nil, err
+	}
+
 	if err := db.DeleteVersion(ctx, name); err != nil {
 		return nil, err
 	}
@@ -120,7 +143,17 @@ func (s *RegistryServer) GetApiVersion(ctx context.Context, req *rpc.GetApiVersi
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	version, err := db.GetVersion(ctx, name)
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	var version *models.Version
+	if at.IsZero() {
+		version, err = db.GetVersion(ctx, name)
+	} else {
+		version, err = db.GetVersionAt(ctx, name, at)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +163,18 @@ func (s *RegistryServer) GetApiVersion(ctx context.Context, req *rpc.GetApiVersi
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	transitions, err := db.ListVersionTransitions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range transitions {
+		if !at.IsZero() && t.TransitionTime.After(at) {
+			break
+		}
+		message.StateHistory = append(message.StateHistory, t.Message())
+	}
+
 	return message, nil
 }
 
@@ -154,11 +199,23 @@ func (s *RegistryServer) ListApiVersions(ctx context.Context, req *rpc.ListApiVe
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	listing, err := db.ListVersions(ctx, parent, storage.PageOptions{
+	at, err := readTime(req.GetReadTime())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.PageOptions{
 		Size:   req.GetPageSize(),
 		Filter: req.GetFilter(),
 		Token:  req.GetPageToken(),
-	})
+	}
+
+	var listing storage.VersionList
+	if at.IsZero() {
+		listing, err = db.ListVersions(ctx, parent, opts)
+	} else {
+		listing, err = db.ListVersionsAt(ctx, parent, opts, at)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -217,19 +274,39 @@ func (s *RegistryServer) UpdateApiVersion(ctx context.Context, req *rpc.UpdateAp
 		return nil, err
 	}
 
+	old := *version
 	if err := version.Update(req.GetApiVersion(), models.ExpandMask(req.GetApiVersion(), req.GetUpdateMask())); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	_, lifecycle, err := projectLifecycles(ctx, db, name.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := versionChange(name, &old, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLifecycle(ctx, db, lifecycle, change); err != nil {
+		return nil, err
+	}
+
 	if err := db.SaveVersion(ctx, version); err != nil {
 		return nil, err
 	}
 
+	transition, err := recordTransition(ctx, db, lifecycle, change, models.NewVersionTransition(name, change.from, change.to))
+	if err != nil {
+		return nil, err
+	}
+
 	message, err := version.Message()
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	s.notify(ctx, rpc.Notification_UPDATED, name.String())
+	s.notifyTransition(ctx, rpc.Notification_UPDATED, name.String(), transition)
 	return message, nil
 }
@@ -0,0 +1,57 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ServiceNames are the names of the gRPC services implemented by RegistryServer.
+var ServiceNames = []string{
+	rpc.Registry_ServiceDesc.ServiceName,
+	rpc.Admin_ServiceDesc.ServiceName,
+}
+
+// CheckReady returns an error if the server can't currently handle requests,
+// which is the case when its database or blob store can't be reached.
+func (s *RegistryServer) CheckReady(ctx context.Context) error {
+	for _, storage := range s.checkStorage(ctx) {
+		if !storage.GetConnected() {
+			return fmt.Errorf("%s unavailable: %s", storage.GetName(), storage.GetMessage())
+		}
+	}
+	return nil
+}
+
+// UpdateHealth sets the serving status of each service in ServiceNames on a
+// health server, based on the result of CheckReady. The overall status of the
+// server (the empty service name) is left unchanged so that it can be used
+// to report liveness independently of storage.
+func (s *RegistryServer) UpdateHealth(ctx context.Context, h *health.Server) error {
+	err := s.CheckReady(ctx)
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	for _, name := range ServiceNames {
+		h.SetServingStatus(name, servingStatus)
+	}
+	return err
+}
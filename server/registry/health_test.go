@@ -0,0 +1,75 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestUpdateHealth(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	h := health.NewServer()
+
+	if err := server.UpdateHealth(ctx, h); err != nil {
+		t.Fatalf("UpdateHealth() returned error: %s", err)
+	}
+
+	for _, name := range ServiceNames {
+		req := &healthpb.HealthCheckRequest{Service: name}
+		resp, err := h.Check(ctx, req)
+		if err != nil {
+			t.Fatalf("Check(%+v) returned error: %s", req, err)
+		}
+		if got, want := resp.GetStatus(), healthpb.HealthCheckResponse_SERVING; got != want {
+			t.Errorf("Check(%+v) returned status %s, want %s", req, got, want)
+		}
+	}
+}
+
+func TestUpdateHealthUnavailableStorage(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.database = "unsupported"
+	h := health.NewServer()
+
+	if err := server.UpdateHealth(ctx, h); err == nil {
+		t.Fatalf("UpdateHealth() succeeded with unavailable storage, expected error")
+	}
+
+	for _, name := range ServiceNames {
+		req := &healthpb.HealthCheckRequest{Service: name}
+		resp, err := h.Check(ctx, req)
+		if err != nil {
+			t.Fatalf("Check(%+v) returned error: %s", req, err)
+		}
+		if got, want := resp.GetStatus(), healthpb.HealthCheckResponse_NOT_SERVING; got != want {
+			t.Errorf("Check(%+v) returned status %s, want %s", req, got, want)
+		}
+	}
+
+	req := &healthpb.HealthCheckRequest{}
+	resp, err := h.Check(ctx, req)
+	if err != nil {
+		t.Fatalf("Check(%+v) returned error: %s", req, err)
+	}
+	if got, want := resp.GetStatus(), healthpb.HealthCheckResponse_SERVING; got != want {
+		t.Errorf("Check(%+v) returned status %s, want %s", req, got, want)
+	}
+}
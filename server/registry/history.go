@@ -0,0 +1,69 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordChange saves the state of a changed resource in its history so that
+// it can be read as it was at later times. Failures are logged because the
+// change has already been made.
+func (s *RegistryServer) recordChange(ctx context.Context, change rpc.Notification_Change, resource string) {
+	logger := log.FromContext(ctx)
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to record change of %s", resource)
+		return
+	}
+	defer db.Close()
+
+	if err := db.RecordChange(ctx, resource, change == rpc.Notification_DELETED); err != nil {
+		logger.WithError(err).Errorf("Failed to record change of %s", resource)
+	}
+}
+
+// readTime returns the time of a point-in-time read, or an error if the
+// read_time of a request is invalid. It returns the zero time if a request
+// doesn't have a read_time.
+func readTime(t *timestamppb.Timestamp) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, nil
+	} else if err := t.CheckValid(); err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid read_time %v: %s", t, err)
+	}
+	return t.AsTime(), nil
+}
+
+// revisionTagsAt returns the tags of a revision from the revision tags that a
+// resource had at a time.
+func revisionTagsAt(tags map[string]string, revisionID string) []string {
+	result := make([]string, 0)
+	for tag, id := range tags {
+		if id == revisionID {
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,286 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// checkpoint returns a time that is strictly between the changes made before
+// and after it.
+func checkpoint() *timestamppb.Timestamp {
+	time.Sleep(2 * time.Millisecond)
+	defer time.Sleep(2 * time.Millisecond)
+	return timestamppb.Now()
+}
+
+func TestReadTimeApi(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	const (
+		parent = "projects/my-project/locations/global"
+		name   = "projects/my-project/locations/global/apis/my-api"
+	)
+
+	if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+	beforeCreate := checkpoint()
+
+	if _, err := server.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: parent,
+		ApiId:  "my-api",
+		Api:    &rpc.Api{DisplayName: "First"},
+	}); err != nil {
+		t.Fatalf("Setup: CreateApi(%q) returned error: %s", name, err)
+	}
+	afterCreate := checkpoint()
+
+	if _, err := server.UpdateApi(ctx, &rpc.UpdateApiRequest{
+		Api:        &rpc.Api{Name: name, DisplayName: "Second"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name"}},
+	}); err != nil {
+		t.Fatalf("Setup: UpdateApi(%q) returned error: %s", name, err)
+	}
+	afterUpdate := checkpoint()
+
+	if _, err := server.DeleteApi(ctx, &rpc.DeleteApiRequest{Name: name}); err != nil {
+		t.Fatalf("Setup: DeleteApi(%q) returned error: %s", name, err)
+	}
+	afterDelete := checkpoint()
+
+	tests := []struct {
+		desc     string
+		readTime *timestamppb.Timestamp
+		want     string
+		code     codes.Code
+	}{
+		{desc: "before creation", readTime: beforeCreate, code: codes.NotFound},
+		{desc: "after creation", readTime: afterCreate, want: "First"},
+		{desc: "after update", readTime: afterUpdate, want: "Second"},
+		{desc: "after deletion", readTime: afterDelete, code: codes.NotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: name, ReadTime: test.readTime})
+			if status.Code(err) != test.code {
+				t.Fatalf("GetApi(%q) returned status code %q, want %q: %v", name, status.Code(err), test.code, err)
+			} else if err == nil && got.GetDisplayName() != test.want {
+				t.Errorf("GetApi(%q) returned display name %q, want %q", name, got.GetDisplayName(), test.want)
+			}
+
+			listing, err := server.ListApis(ctx, &rpc.ListApisRequest{Parent: parent, ReadTime: test.readTime})
+			if err != nil {
+				t.Fatalf("ListApis(%q) returned error: %s", parent, err)
+			}
+
+			var names []string
+			for _, api := range listing.GetApis() {
+				names = append(names, api.GetName())
+			}
+			var want []string
+			if test.code == codes.OK {
+				want = []string{name}
+			}
+			if diff := cmp.Diff(want, names); diff != "" {
+				t.Errorf("ListApis(%q) returned unexpected names (-want +got):\n%s", parent, diff)
+			}
+		})
+	}
+}
+
+func TestReadTimeSpecTags(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	const name = "projects/my-project/locations/global/apis/my-api/versions/v1/specs/my-spec"
+	if err := seeder.SeedSpecs(ctx, server, &rpc.ApiSpec{Name: name, Contents: []byte("first")}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	first, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name})
+	if err != nil {
+		t.Fatalf("Setup: GetApiSpec(%q) returned error: %s", name, err)
+	}
+
+	second, err := server.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec:    &rpc.ApiSpec{Name: name, Contents: []byte("second")},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"contents"}},
+	})
+	if err != nil {
+		t.Fatalf("Setup: UpdateApiSpec(%q) returned error: %s", name, err)
+	}
+
+	if _, err := server.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{
+		Name: name + "@" + first.GetRevisionId(),
+		Tag:  "prod",
+	}); err != nil {
+		t.Fatalf("Setup: TagApiSpecRevision() returned error: %s", err)
+	}
+	firstTagged := checkpoint()
+
+	if _, err := server.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{
+		Name: name + "@" + second.GetRevisionId(),
+		Tag:  "prod",
+	}); err != nil {
+		t.Fatalf("Setup: TagApiSpecRevision() returned error: %s", err)
+	}
+	secondTagged := checkpoint()
+
+	tests := []struct {
+		desc     string
+		readTime *timestamppb.Timestamp
+		revision string
+		tags     []string
+	}{
+		{desc: "tag on first revision", readTime: firstTagged, revision: first.GetRevisionId(), tags: []string{}},
+		{desc: "tag on second revision", readTime: secondTagged, revision: second.GetRevisionId(), tags: []string{"prod"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name + "@prod", ReadTime: test.readTime})
+			if err != nil {
+				t.Fatalf("GetApiSpec(%q) returned error: %s", name+"@prod", err)
+			}
+			if got.GetRevisionId() != test.revision {
+				t.Errorf("GetApiSpec(%q) returned revision %q, want %q", name+"@prod", got.GetRevisionId(), test.revision)
+			}
+			if !cmp.Equal(got.GetRevisionTags(), []string{"prod"}) {
+				t.Errorf("GetApiSpec(%q) returned tags %v, want [prod]", name+"@prod", got.GetRevisionTags())
+			}
+
+			listing, err := server.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{
+				Parent:   "projects/my-project/locations/global/apis/my-api/versions/v1",
+				ReadTime: test.readTime,
+			})
+			if err != nil {
+				t.Fatalf("ListApiSpecs() returned error: %s", err)
+			}
+			if len(listing.GetApiSpecs()) != 1 {
+				t.Fatalf("ListApiSpecs() returned %d specs, want 1", len(listing.GetApiSpecs()))
+			}
+			if diff := cmp.Diff(test.tags, listing.GetApiSpecs()[0].GetRevisionTags()); diff != "" {
+				t.Errorf("ListApiSpecs() returned unexpected tags for the current revision (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadTimeArtifactRevisions(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	const name = "projects/my-project/locations/global/artifacts/my-artifact"
+	if err := seeder.SeedArtifacts(ctx, server, &rpc.Artifact{Name: name, Contents: []byte("first")}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+	first, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name})
+	if err != nil {
+		t.Fatalf("Setup: GetArtifact(%q) returned error: %s", name, err)
+	}
+	afterFirst := checkpoint()
+
+	if _, err := server.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{
+		Artifact: &rpc.Artifact{Name: name, Contents: []byte("second")},
+	}); err != nil {
+		t.Fatalf("Setup: ReplaceArtifact(%q) returned error: %s", name, err)
+	}
+
+	got, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: name, ReadTime: afterFirst})
+	if err != nil {
+		t.Fatalf("GetArtifact(%q) returned error: %s", name, err)
+	}
+	if got.GetRevisionId() != first.GetRevisionId() {
+		t.Errorf("GetArtifact(%q) returned revision %q, want %q", name, got.GetRevisionId(), first.GetRevisionId())
+	}
+
+	listing, err := server.ListArtifacts(ctx, &rpc.ListArtifactsRequest{
+		Parent:   "projects/my-project/locations/global",
+		ReadTime: afterFirst,
+	})
+	if err != nil {
+		t.Fatalf("ListArtifacts() returned error: %s", err)
+	}
+	if len(listing.GetArtifacts()) != 1 || listing.GetArtifacts()[0].GetRevisionId() != first.GetRevisionId() {
+		t.Errorf("ListArtifacts() returned %v, want revision %q of %q", listing.GetArtifacts(), first.GetRevisionId(), name)
+	}
+}
+
+func TestReadTimeDeletedProject(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	const (
+		project = "projects/my-project"
+		spec    = "projects/my-project/locations/global/apis/my-api/versions/v1/specs/my-spec"
+	)
+	if err := seeder.SeedSpecs(ctx, server, &rpc.ApiSpec{Name: spec}); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+	beforeDelete := checkpoint()
+
+	if _, err := server.DeleteProject(ctx, &rpc.DeleteProjectRequest{Name: project}); err != nil {
+		t.Fatalf("Setup: DeleteProject(%q) returned error: %s", project, err)
+	}
+	afterDelete := checkpoint()
+
+	if _, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: spec, ReadTime: beforeDelete}); err != nil {
+		t.Errorf("GetApiSpec(%q) before deletion returned error: %s", spec, err)
+	}
+	if _, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: spec, ReadTime: afterDelete}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetApiSpec(%q) after deletion returned status code %q, want %q: %v", spec, status.Code(err), codes.NotFound, err)
+	}
+
+	listing, err := server.ListProjects(ctx, &rpc.ListProjectsRequest{ReadTime: beforeDelete})
+	if err != nil {
+		t.Fatalf("ListProjects() returned error: %s", err)
+	}
+	if len(listing.GetProjects()) != 1 || listing.GetProjects()[0].GetName() != project {
+		t.Errorf("ListProjects() before deletion returned %v, want only %q", listing.GetProjects(), project)
+	}
+
+	if _, err := server.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{
+		Parent:   "projects/my-project/locations/global/apis/my-api",
+		ReadTime: afterDelete,
+	}); status.Code(err) != codes.NotFound {
+		t.Errorf("ListApiVersions() after deletion returned status code %q, want %q: %v", status.Code(err), codes.NotFound, err)
+	}
+}
+
+func TestReadTimeInvalid(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+
+	invalid := &timestamppb.Timestamp{Seconds: -1 << 62}
+	if _, err := server.GetProject(ctx, &rpc.GetProjectRequest{Name: "projects/my-project", ReadTime: invalid}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetProject() with invalid read_time returned status code %q, want %q: %v", status.Code(err), codes.InvalidArgument, err)
+	}
+	if _, err := server.ListApis(ctx, &rpc.ListApisRequest{Parent: "projects/my-project/locations/global", ReadTime: invalid}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListApis() with invalid read_time returned status code %q, want %q: %v", status.Code(err), codes.InvalidArgument, err)
+	}
+}
@@ -35,6 +35,7 @@ type ApiList struct {
 var apiFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
 	{Name: "description", Type: filtering.String},
@@ -42,10 +43,11 @@ var apiFields = []filtering.Field{
 	{Name: "update_time", Type: filtering.Timestamp},
 	{Name: "availability", Type: filtering.String},
 	{Name: "recommended_version", Type: filtering.String},
+	{Name: "recommended_deployment", Type: filtering.String},
 	{Name: "labels", Type: filtering.StringMap},
 }
 
-func (d *Client) ListApis(ctx context.Context, parent names.Project, opts PageOptions) (ApiList, error) {
+func (d *Client) ListApis(ctx context.Context, parent names.Location, opts PageOptions) (ApiList, error) {
 	q := d.NewQuery(gorm.ApiEntityName)
 
 	token, err := decodeToken(opts.Token)
@@ -63,10 +65,13 @@ func (d *Client) ListApis(ctx context.Context, parent names.Project, opts PageOp
 
 	if parent.ProjectID != "-" {
 		q = q.Require("ProjectID", parent.ProjectID)
-		if _, err := d.GetProject(ctx, parent); err != nil {
+		if _, err := d.GetProject(ctx, parent.Project()); err != nil {
 			return ApiList{}, err
 		}
 	}
+	if parent.LocationID != "-" {
+		q = q.Require("LocationID", parent.LocationID)
+	}
 
 	filter, err := filtering.NewFilter(opts.Filter, apiFields)
 	if err != nil {
@@ -119,16 +124,18 @@ func apiMap(api models.Api) (map[string]interface{}, error) {
 	}
 
 	return map[string]interface{}{
-		"name":                api.Name(),
-		"project_id":          api.ProjectID,
-		"api_id":              api.ApiID,
-		"display_name":        api.DisplayName,
-		"description":         api.Description,
-		"create_time":         api.CreateTime,
-		"update_time":         api.UpdateTime,
-		"availability":        api.Availability,
-		"recommended_version": api.RecommendedVersion,
-		"labels":              labels,
+		"name":                   api.Name(),
+		"project_id":             api.ProjectID,
+		"location_id":            api.LocationID,
+		"api_id":                 api.ApiID,
+		"display_name":           api.DisplayName,
+		"description":            api.Description,
+		"create_time":            api.CreateTime,
+		"update_time":            api.UpdateTime,
+		"availability":           api.Availability,
+		"recommended_version":    api.RecommendedVersion,
+		"recommended_deployment": api.RecommendedDeployment,
+		"labels":                 labels,
 	}, nil
 }
 
@@ -160,10 +167,14 @@ func (d *Client) DeleteApi(ctx context.Context, name names.Api) error {
 		gorm.SpecEntityName,
 		gorm.SpecRevisionTagEntityName,
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
+		gorm.LifecycleTransitionEntityName,
+		gorm.RelationshipEntityName,
 		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		if err := d.Delete(ctx, q); err != nil {
 			return status.Error(codes.Internal, err.Error())
@@ -172,3 +183,25 @@ func (d *Client) DeleteApi(ctx context.Context, name names.Api) error {
 
 	return nil
 }
+
+// ListApisForProject returns all APIs in a project.
+func (d *Client) ListApisForProject(ctx context.Context, name names.Project) ([]models.Api, error) {
+	q := d.NewQuery(gorm.ApiEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+
+	var (
+		apis = make([]models.Api, 0)
+		api  = new(models.Api)
+		it   = d.Run(ctx, q)
+		err  error
+	)
+
+	for _, err = it.Next(api); err == nil; _, err = it.Next(api) {
+		apis = append(apis, *api)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return apis, nil
+}
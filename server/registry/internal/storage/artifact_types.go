@@ -0,0 +1,170 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage/filtering"
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ArtifactTypeList contains a page of artifact type resources.
+type ArtifactTypeList struct {
+	ArtifactTypes []models.ArtifactType
+	Token         string
+}
+
+var artifactTypeFields = []filtering.Field{
+	{Name: "name", Type: filtering.String},
+	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
+	{Name: "artifact_type_id", Type: filtering.String},
+	{Name: "description", Type: filtering.String},
+	{Name: "create_time", Type: filtering.Timestamp},
+	{Name: "mime_type", Type: filtering.String},
+}
+
+func (d *Client) ListArtifactTypes(ctx context.Context, parent names.Location, opts PageOptions) (ArtifactTypeList, error) {
+	q := d.NewQuery(gorm.ArtifactTypeEntityName)
+
+	token, err := decodeToken(opts.Token)
+	if err != nil {
+		return ArtifactTypeList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
+	}
+
+	if err := token.ValidateFilter(opts.Filter); err != nil {
+		return ArtifactTypeList{}, status.Errorf(codes.InvalidArgument, "invalid filter %q: %s", opts.Filter, err)
+	} else {
+		token.Filter = opts.Filter
+	}
+
+	q = q.ApplyOffset(token.Offset)
+
+	if parent.ProjectID != "-" {
+		q = q.Require("ProjectID", parent.ProjectID)
+		if _, err := d.GetProject(ctx, parent.Project()); err != nil {
+			return ArtifactTypeList{}, err
+		}
+	}
+	if parent.LocationID != "-" {
+		q = q.Require("LocationID", parent.LocationID)
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, artifactTypeFields)
+	if err != nil {
+		return ArtifactTypeList{}, err
+	}
+
+	it := d.Run(ctx, q)
+	response := ArtifactTypeList{
+		ArtifactTypes: make([]models.ArtifactType, 0, opts.Size),
+	}
+
+	t := new(models.ArtifactType)
+	for _, err = it.Next(t); err == nil; _, err = it.Next(t) {
+		match, err := filter.Matches(artifactTypeMap(*t))
+		if err != nil {
+			return response, err
+		} else if !match {
+			token.Offset++
+			continue
+		} else if len(response.ArtifactTypes) == int(opts.Size) {
+			break
+		}
+
+		response.ArtifactTypes = append(response.ArtifactTypes, *t)
+		token.Offset++
+	}
+	if err != nil && err != iterator.Done {
+		return response, status.Error(codes.Internal, err.Error())
+	}
+
+	if err == nil {
+		response.Token, err = encodeToken(token)
+		if err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
+func artifactTypeMap(t models.ArtifactType) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             t.Name(),
+		"project_id":       t.ProjectID,
+		"location_id":      t.LocationID,
+		"artifact_type_id": t.ArtifactTypeID,
+		"description":      t.Description,
+		"create_time":      t.CreateTime,
+		"mime_type":        t.MimeType,
+	}
+}
+
+func (d *Client) GetArtifactType(ctx context.Context, name names.ArtifactType) (*models.ArtifactType, error) {
+	t := new(models.ArtifactType)
+	k := d.NewKey(gorm.ArtifactTypeEntityName, name.String())
+	if err := d.Get(ctx, k, t); d.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "artifact type %q not found in database", name)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return t, nil
+}
+
+// GetArtifactTypeForMimeType returns the artifact type registered in a location for a MIME type.
+func (d *Client) GetArtifactTypeForMimeType(ctx context.Context, parent names.Location, mimeType string) (*models.ArtifactType, error) {
+	q := d.NewQuery(gorm.ArtifactTypeEntityName)
+	q = q.Require("ProjectID", parent.ProjectID)
+	q = q.Require("LocationID", parent.LocationID)
+	q = q.Require("MimeType", models.ArtifactTypeMimeType(mimeType))
+
+	t := new(models.ArtifactType)
+	if _, err := d.Run(ctx, q).Next(t); err == iterator.Done {
+		return nil, status.Errorf(codes.NotFound, "no artifact type for %q in %q", mimeType, parent)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return t, nil
+}
+
+func (d *Client) SaveArtifactType(ctx context.Context, t *models.ArtifactType) error {
+	k := d.NewKey(gorm.ArtifactTypeEntityName, t.Name())
+	if _, err := d.Put(ctx, k, t); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+func (d *Client) DeleteArtifactType(ctx context.Context, name names.ArtifactType) error {
+	q := d.NewQuery(gorm.ArtifactTypeEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
+	q = q.Require("ArtifactTypeID", name.ArtifactTypeID)
+	if err := d.Delete(ctx, q); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
@@ -16,6 +16,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/apigee/registry/server/registry/internal/storage/filtering"
 	"github.com/apigee/registry/server/registry/internal/storage/gorm"
@@ -35,14 +36,19 @@ type ArtifactList struct {
 var artifactFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "spec_id", Type: filtering.String},
 	{Name: "artifact_id", Type: filtering.String},
 	{Name: "create_time", Type: filtering.Timestamp},
 	{Name: "update_time", Type: filtering.Timestamp},
+	{Name: "revision_create_time", Type: filtering.Timestamp},
+	{Name: "revision_update_time", Type: filtering.Timestamp},
 	{Name: "mime_type", Type: filtering.String},
 	{Name: "size_bytes", Type: filtering.Int},
+	{Name: "labels", Type: filtering.StringMap},
+	{Name: "contents", Type: filtering.Dynamic},
 }
 
 func (d *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts PageOptions) (ArtifactList, error) {
@@ -64,6 +70,9 @@ func (d *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts
 	if id := parent.ProjectID; id != "-" {
 		q = q.Require("ProjectID", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		q = q.Require("LocationID", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		q = q.Require("ApiID", id)
 	}
@@ -74,15 +83,15 @@ func (d *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts
 		q = q.Require("SpecID", id)
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID != "-" {
 		if _, err := d.GetSpec(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" && parent.SpecID == "-" {
 		if _, err := d.GetVersion(ctx, parent.Version()); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" && parent.SpecID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" && parent.SpecID == "-" {
 		if _, err := d.GetApi(ctx, parent.Api()); err != nil {
 			return ArtifactList{}, err
 		}
@@ -92,7 +101,7 @@ func (d *Client) ListSpecArtifacts(ctx context.Context, parent names.Spec, opts
 		}
 	}
 
-	return d.listArtifacts(ctx, d.Run(ctx, q), opts, func(a *models.Artifact) bool {
+	return d.listArtifacts(ctx, d.GetRecentArtifactRevisions(ctx, q), opts, func(a *models.Artifact) bool {
 		return a.ProjectID != "" && a.ApiID != "" && a.VersionID != "" && a.SpecID != ""
 	})
 }
@@ -117,6 +126,9 @@ func (d *Client) ListVersionArtifacts(ctx context.Context, parent names.Version,
 	if id := parent.ProjectID; id != "-" {
 		q = q.Require("ProjectID", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		q = q.Require("LocationID", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		q = q.Require("ApiID", id)
 	}
@@ -124,11 +136,11 @@ func (d *Client) ListVersionArtifacts(ctx context.Context, parent names.Version,
 		q = q.Require("VersionID", id)
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
 		if _, err := d.GetVersion(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
 		if _, err := d.GetApi(ctx, parent.Api()); err != nil {
 			return ArtifactList{}, err
 		}
@@ -138,7 +150,7 @@ func (d *Client) ListVersionArtifacts(ctx context.Context, parent names.Version,
 		}
 	}
 
-	return d.listArtifacts(ctx, d.Run(ctx, q), opts, func(a *models.Artifact) bool {
+	return d.listArtifacts(ctx, d.GetRecentArtifactRevisions(ctx, q), opts, func(a *models.Artifact) bool {
 		return a.ProjectID != "" && a.ApiID != "" && a.VersionID != ""
 	})
 }
@@ -164,11 +176,14 @@ func (d *Client) ListApiArtifacts(ctx context.Context, parent names.Api, opts Pa
 	if id := parent.ProjectID; id != "-" {
 		q = q.Require("ProjectID", id)
 	}
+	if id := parent.LocationID; id != "-" {
+		q = q.Require("LocationID", id)
+	}
 	if id := parent.ApiID; id != "-" {
 		q = q.Require("ApiID", id)
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := d.GetApi(ctx, parent); err != nil {
 			return ArtifactList{}, err
 		}
@@ -178,12 +193,12 @@ func (d *Client) ListApiArtifacts(ctx context.Context, parent names.Api, opts Pa
 		}
 	}
 
-	return d.listArtifacts(ctx, d.Run(ctx, q), opts, func(a *models.Artifact) bool {
+	return d.listArtifacts(ctx, d.GetRecentArtifactRevisions(ctx, q), opts, func(a *models.Artifact) bool {
 		return a.ProjectID != "" && a.ApiID != ""
 	})
 }
 
-func (d *Client) ListProjectArtifacts(ctx context.Context, parent names.Project, opts PageOptions) (ArtifactList, error) {
+func (d *Client) ListProjectArtifacts(ctx context.Context, parent names.Location, opts PageOptions) (ArtifactList, error) {
 	q := d.NewQuery(gorm.ArtifactEntityName)
 	q = q.Require("ApiID", "")
 	q = q.Require("VersionID", "")
@@ -204,12 +219,15 @@ func (d *Client) ListProjectArtifacts(ctx context.Context, parent names.Project,
 
 	if id := parent.ProjectID; id != "-" {
 		q = q.Require("ProjectID", id)
-		if _, err := d.GetProject(ctx, parent); err != nil {
+		if _, err := d.GetProject(ctx, parent.Project()); err != nil {
 			return ArtifactList{}, err
 		}
 	}
+	if id := parent.LocationID; id != "-" {
+		q = q.Require("LocationID", id)
+	}
 
-	return d.listArtifacts(ctx, d.Run(ctx, q), opts, func(a *models.Artifact) bool {
+	return d.listArtifacts(ctx, d.GetRecentArtifactRevisions(ctx, q), opts, func(a *models.Artifact) bool {
 		return a.ProjectID != ""
 	})
 }
@@ -231,6 +249,9 @@ func (d *Client) listArtifacts(ctx context.Context, it *gorm.Iterator, opts Page
 		Artifacts: make([]models.Artifact, 0, opts.Size),
 	}
 
+	// Artifact types are cached by MIME type while decoding contents for filtering.
+	types := make(map[string]*models.ArtifactType)
+
 	artifact := new(models.Artifact)
 	for _, err = it.Next(artifact); err == nil; _, err = it.Next(artifact) {
 		artifactMap, err := artifactMap(*artifact)
@@ -238,6 +259,13 @@ func (d *Client) listArtifacts(ctx context.Context, it *gorm.Iterator, opts Page
 			return response, status.Error(codes.Internal, err.Error())
 		}
 
+		if filter.References("contents") {
+			artifactMap["contents"], err = d.decodedArtifactContents(ctx, *artifact, types)
+			if err != nil {
+				return response, err
+			}
+		}
+
 		match, err := filter.Matches(artifactMap)
 		if err != nil {
 			return response, err
@@ -266,73 +294,106 @@ func (d *Client) listArtifacts(ctx context.Context, it *gorm.Iterator, opts Page
 }
 
 func artifactMap(artifact models.Artifact) (map[string]interface{}, error) {
+	labels, err := artifact.LabelsMap()
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
-		"name":        artifact.Name(),
-		"project_id":  artifact.ProjectID,
-		"api_id":      artifact.ApiID,
-		"version_id":  artifact.VersionID,
-		"spec_id":     artifact.SpecID,
-		"artifact_id": artifact.ArtifactID,
-		"create_time": artifact.CreateTime,
-		"update_time": artifact.UpdateTime,
-		"mime_type":   artifact.MimeType,
-		"size_bytes":  artifact.SizeInBytes,
+		"name":                 artifact.Name(),
+		"project_id":           artifact.ProjectID,
+		"location_id":          artifact.LocationID,
+		"api_id":               artifact.ApiID,
+		"version_id":           artifact.VersionID,
+		"spec_id":              artifact.SpecID,
+		"artifact_id":          artifact.ArtifactID,
+		"revision_id":          artifact.RevisionID,
+		"create_time":          artifact.CreateTime,
+		"update_time":          artifact.RevisionUpdateTime,
+		"revision_create_time": artifact.RevisionCreateTime,
+		"revision_update_time": artifact.RevisionUpdateTime,
+		"mime_type":            artifact.MimeType,
+		"size_bytes":           artifact.SizeInBytes,
+		"labels":               labels,
 	}, nil
 }
 
-func (d *Client) SaveArtifact(ctx context.Context, artifact *models.Artifact) error {
-	k := d.NewKey(gorm.ArtifactEntityName, artifact.Name())
-	if _, err := d.Put(ctx, k, artifact); err != nil {
-		return status.Error(codes.Internal, err.Error())
+// decodedArtifactContents returns the contents of an artifact decoded with its registered type.
+// Contents of artifacts without a type, or that don't match their type, are represented as an empty map.
+func (d *Client) decodedArtifactContents(ctx context.Context, artifact models.Artifact, types map[string]*models.ArtifactType) (interface{}, error) {
+	empty := map[string]interface{}{}
+
+	key := fmt.Sprintf("%s/%s/%s", artifact.ProjectID, artifact.LocationID, models.ArtifactTypeMimeType(artifact.MimeType))
+	t, ok := types[key]
+	if !ok {
+		parent := names.Location{ProjectID: artifact.ProjectID, LocationID: artifact.LocationID}
+		var err error
+		if t, err = d.GetArtifactTypeForMimeType(ctx, parent, artifact.MimeType); err != nil && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+		types[key] = t
+	}
+	if t == nil {
+		return empty, nil
 	}
 
-	return nil
-}
+	blob := new(models.Blob)
+	k := d.NewKey(gorm.BlobEntityName, artifact.RevisionName())
+	if err := d.Get(ctx, k, blob); d.IsNotFound(err) {
+		return empty, nil
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 
-func (d *Client) SaveArtifactContents(ctx context.Context, artifact *models.Artifact, contents []byte) error {
-	blob := models.NewBlobForArtifact(artifact, contents)
-	k := d.NewKey(gorm.BlobEntityName, artifact.Name())
-	if _, err := d.Put(ctx, k, blob); err != nil {
-		return status.Error(codes.Internal, err.Error())
+	value, err := artifact.Decode(t, blob.Contents)
+	if err != nil {
+		return empty, nil
 	}
 
-	return nil
+	return value, nil
 }
 
 func (d *Client) GetArtifact(ctx context.Context, name names.Artifact) (*models.Artifact, error) {
-	artifact := new(models.Artifact)
-	k := d.NewKey(gorm.ArtifactEntityName, name.String())
-	if err := d.Get(ctx, k, artifact); d.IsNotFound(err) {
-		return nil, status.Errorf(codes.NotFound, "artifact %q not found in database", name)
-	} else if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	name = name.Normal()
+	if name.RevisionID() != "" {
+		return d.GetArtifactRevision(ctx, name)
 	}
 
-	return artifact, nil
-}
-
-func (d *Client) GetArtifactContents(ctx context.Context, name names.Artifact) (*models.Blob, error) {
-	blob := new(models.Blob)
-	k := d.NewKey(gorm.BlobEntityName, name.String())
-	if err := d.Get(ctx, k, blob); d.IsNotFound(err) {
-		return nil, status.Errorf(codes.NotFound, "artifact contents %q not found", name)
+	q := d.NewQuery(gorm.ArtifactEntityName)
+	q = q.Require("ProjectID", name.ProjectID())
+	q = q.Require("LocationID", name.LocationID())
+	q = q.Require("ApiID", name.ApiID())
+	q = q.Require("VersionID", name.VersionID())
+	q = q.Require("SpecID", name.SpecID())
+	q = q.Require("DeploymentID", name.DeploymentID())
+	q = q.Require("ArtifactID", name.ArtifactID())
+	q = q.Descending("RevisionCreateTime")
+
+	it := d.Run(ctx, q)
+	artifact := &models.Artifact{}
+	if _, err := it.Next(artifact); err == iterator.Done {
+		return nil, status.Errorf(codes.NotFound, "artifact %q not found in database", name)
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return blob, nil
+	return artifact, nil
 }
 
 func (d *Client) DeleteArtifact(ctx context.Context, name names.Artifact) error {
+	name = name.Normal()
 	for _, entityName := range []string{
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
 		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID())
+		q = q.Require("LocationID", name.LocationID())
 		q = q.Require("ApiID", name.ApiID())
 		q = q.Require("VersionID", name.VersionID())
 		q = q.Require("SpecID", name.SpecID())
+		q = q.Require("DeploymentID", name.DeploymentID())
 		q = q.Require("ArtifactID", name.ArtifactID())
 		if err := d.Delete(ctx, q); err != nil {
 			return status.Error(codes.Internal, err.Error())
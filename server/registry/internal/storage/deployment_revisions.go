@@ -28,6 +28,7 @@ import (
 func (d *Client) ListDeploymentRevisions(ctx context.Context, parent names.Deployment, opts PageOptions) (DeploymentList, error) {
 	q := d.NewQuery(gorm.DeploymentEntityName)
 	q = q.Require("ProjectID", parent.ProjectID)
+	q = q.Require("LocationID", parent.LocationID)
 	q = q.Require("ApiID", parent.ApiID)
 	q = q.Require("DeploymentID", parent.DeploymentID)
 	q = q.Descending("RevisionCreateTime")
@@ -109,6 +110,7 @@ func (d *Client) DeleteDeploymentRevision(ctx context.Context, name names.Deploy
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		q = q.Require("DeploymentID", name.DeploymentID)
 		q = q.Require("RevisionID", name.RevisionID)
@@ -139,3 +141,49 @@ func (d *Client) unwrapDeploymentRevisionTag(ctx context.Context, name names.Dep
 
 	return name.Deployment().Revision(tag.RevisionID), nil
 }
+
+// ListDeploymentRevisionsForProject returns all revisions of the deployments in a project,
+// ordered from the most recently created.
+func (d *Client) ListDeploymentRevisionsForProject(ctx context.Context, name names.Project) ([]models.Deployment, error) {
+	q := d.NewQuery(gorm.DeploymentEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Descending("RevisionCreateTime")
+
+	var (
+		revisions = make([]models.Deployment, 0)
+		revision  = new(models.Deployment)
+		it        = d.Run(ctx, q)
+		err       error
+	)
+
+	for _, err = it.Next(revision); err == nil; _, err = it.Next(revision) {
+		revisions = append(revisions, *revision)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return revisions, nil
+}
+
+// ListDeploymentRevisionTagsForProject returns all revision tags of the deployments in a project.
+func (d *Client) ListDeploymentRevisionTagsForProject(ctx context.Context, name names.Project) ([]models.DeploymentRevisionTag, error) {
+	q := d.NewQuery(gorm.DeploymentRevisionTagEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+
+	var (
+		tags = make([]models.DeploymentRevisionTag, 0)
+		tag  = new(models.DeploymentRevisionTag)
+		it   = d.Run(ctx, q)
+		err  error
+	)
+
+	for _, err = it.Next(tag); err == nil; _, err = it.Next(tag) {
+		tags = append(tags, *tag)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return tags, nil
+}
@@ -35,6 +35,7 @@ type DeploymentList struct {
 var deploymentFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "deployment_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
@@ -62,7 +63,7 @@ func (d *Client) ListDeployments(ctx context.Context, parent names.Api, opts Pag
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := d.GetApi(ctx, parent); err != nil {
 			return DeploymentList{}, err
 		}
@@ -77,7 +78,7 @@ func (d *Client) ListDeployments(ctx context.Context, parent names.Api, opts Pag
 		return DeploymentList{}, err
 	}
 
-	it := d.GetRecentDeploymentRevisions(ctx, token.Offset, parent.ProjectID, parent.ApiID)
+	it := d.GetRecentDeploymentRevisions(ctx, token.Offset, parent.ProjectID, parent.LocationID, parent.ApiID)
 	response := DeploymentList{
 		Deployments: make([]models.Deployment, 0, opts.Size),
 	}
@@ -125,6 +126,7 @@ func deploymentMap(deployment models.Deployment) (map[string]interface{}, error)
 	return map[string]interface{}{
 		"name":                 deployment.Name(),
 		"project_id":           deployment.ProjectID,
+		"location_id":          deployment.LocationID,
 		"api_id":               deployment.ApiID,
 		"deployment_id":        deployment.DeploymentID,
 		"revision_id":          deployment.RevisionID,
@@ -146,6 +148,7 @@ func (d *Client) GetDeployment(ctx context.Context, name names.Deployment) (*mod
 	normal := name.Normal()
 	q := d.NewQuery(gorm.DeploymentEntityName)
 	q = q.Require("ProjectID", normal.ProjectID)
+	q = q.Require("LocationID", normal.LocationID)
 	q = q.Require("ApiID", normal.ApiID)
 	q = q.Require("DeploymentID", normal.DeploymentID)
 	q = q.Descending("RevisionCreateTime")
@@ -164,9 +167,12 @@ func (d *Client) DeleteDeployment(ctx context.Context, name names.Deployment) er
 		gorm.DeploymentEntityName,
 		gorm.DeploymentRevisionTagEntityName,
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
+		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		q = q.Require("DeploymentID", name.DeploymentID)
 		if err := d.Delete(ctx, q); err != nil {
@@ -180,6 +186,7 @@ func (d *Client) DeleteDeployment(ctx context.Context, name names.Deployment) er
 func (d *Client) GetDeploymentTags(ctx context.Context, name names.Deployment) ([]*models.DeploymentRevisionTag, error) {
 	q := d.NewQuery(gorm.DeploymentRevisionTagEntityName)
 	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
 	q = q.Require("ApiID", name.ApiID)
 	if name.DeploymentID != "-" {
 		q = q.Require("DeploymentID", name.DeploymentID)
@@ -201,3 +208,22 @@ func (d *Client) GetDeploymentTags(ctx context.Context, name names.Deployment) (
 
 	return tags, nil
 }
+
+// ListDeploymentsForProject returns the current revisions of all deployments in a project.
+func (d *Client) ListDeploymentsForProject(ctx context.Context, name names.Project) ([]models.Deployment, error) {
+	var (
+		deployments = make([]models.Deployment, 0)
+		deployment  = new(models.Deployment)
+		it          = d.GetRecentDeploymentRevisions(ctx, 0, name.ProjectID, "-", "-")
+		err         error
+	)
+
+	for _, err = it.Next(deployment); err == nil; _, err = it.Next(deployment) {
+		deployments = append(deployments, *deployment)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return deployments, nil
+}
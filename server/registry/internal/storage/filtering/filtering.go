@@ -31,6 +31,7 @@ const (
 	Int       FieldType = iota
 	Timestamp FieldType = iota
 	StringMap FieldType = iota
+	Dynamic   FieldType = iota
 )
 
 type Field struct {
@@ -39,7 +40,14 @@ type Field struct {
 }
 
 type Filter struct {
-	program cel.Program
+	program    cel.Program
+	references map[string]bool
+}
+
+// References returns true if the filter expression refers to the named field.
+// Fields that are expensive to compute can be omitted from models when they aren't referenced.
+func (f *Filter) References(name string) bool {
+	return f.references[name]
 }
 
 func (f *Filter) Matches(model map[string]interface{}) (bool, error) {
@@ -76,6 +84,8 @@ func NewFilter(filter string, fields []Field) (Filter, error) {
 			declarations = append(declarations, decls.NewConst(field.Name, decls.Timestamp, nil))
 		case StringMap:
 			declarations = append(declarations, decls.NewConst(field.Name, decls.NewMapType(decls.String, decls.String), nil))
+		case Dynamic:
+			declarations = append(declarations, decls.NewConst(field.Name, decls.Dyn, nil))
 		default:
 			return Filter{}, status.Errorf(codes.InvalidArgument, "unknown filter argument type")
 		}
@@ -96,5 +106,15 @@ func NewFilter(filter string, fields []Field) (Filter, error) {
 		return Filter{}, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	return Filter{program: prg}, nil
+	checked, err := cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return Filter{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	references := make(map[string]bool)
+	for _, ref := range checked.GetReferenceMap() {
+		references[ref.GetName()] = true
+	}
+
+	return Filter{program: prg, references: references}, nil
 }
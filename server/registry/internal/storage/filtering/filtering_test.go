@@ -191,6 +191,31 @@ func TestFilter_Matches(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:   "nested Dynamic value",
+			filter: `contents.problems.exists(p, p.severity == "ERROR")`,
+			fields: []Field{
+				{
+					Name: "contents",
+					Type: Dynamic,
+				},
+			},
+			positive: map[string]interface{}{
+				"contents": map[string]interface{}{
+					"problems": []interface{}{
+						map[string]interface{}{"severity": "WARNING"},
+						map[string]interface{}{"severity": "ERROR"},
+					},
+				},
+			},
+			negative: map[string]interface{}{
+				"contents": map[string]interface{}{
+					"problems": []interface{}{
+						map[string]interface{}{"severity": "WARNING"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -214,3 +239,31 @@ func TestFilter_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_References(t *testing.T) {
+	fields := []Field{
+		{Name: "name", Type: String},
+		{Name: "contents", Type: Dynamic},
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: "", want: false},
+		{filter: `name == "a"`, want: false},
+		{filter: `has(contents.count) && contents.count > 1.0`, want: true},
+		{filter: `name == "a" || contents.name == "a"`, want: true},
+	}
+
+	for _, test := range tests {
+		f, err := NewFilter(test.filter, fields)
+		if err != nil {
+			t.Fatalf("NewFilter(%q) returned error: %s", test.filter, err)
+		}
+
+		if got := f.References("contents"); got != test.want {
+			t.Errorf("NewFilter(%q).References(%q) returned %t, want %t", test.filter, "contents", got, test.want)
+		}
+	}
+}
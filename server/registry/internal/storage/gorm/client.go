@@ -26,6 +26,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// SchemaVersion identifies the layout of the tables created by EnsureTables.
+// It should be incremented whenever that layout changes.
+const SchemaVersion = "9"
+
 // Client represents a connection to a storage provider.
 type Client struct {
 	db *gorm.DB
@@ -105,18 +109,19 @@ func (c *Client) close() {
 	sqlDB.Close()
 }
 
+// ensureTable creates the table of an entity, or adds the columns that are
+// missing from a table that was created by an earlier version.
 func (c *Client) ensureTable(v interface{}) error {
 	lock()
 	defer unlock()
 	if !c.db.Migrator().HasTable(v) {
-		if err := c.db.Migrator().CreateTable(v); err != nil {
-			return err
-		}
+		return c.db.Migrator().CreateTable(v)
 	}
-	return nil
+	return c.addMissingColumns(v)
 }
 
-// EnsureTables ensures that all necessary tables exist in the database.
+// EnsureTables ensures that all necessary tables exist in the database and
+// have the columns of the current schema.
 func (c *Client) EnsureTables() error {
 	entities := []interface{}{
 		&models.Project{},
@@ -127,6 +132,11 @@ func (c *Client) EnsureTables() error {
 		&models.Deployment{},
 		&models.DeploymentRevisionTag{},
 		&models.Artifact{},
+		&models.ArtifactRevisionTag{},
+		&models.ArtifactType{},
+		&models.LifecycleTransition{},
+		&models.Relationship{},
+		&models.Snapshot{},
 		&models.Blob{},
 	}
 	for _, entity := range entities {
@@ -134,7 +144,26 @@ func (c *Client) EnsureTables() error {
 			return err
 		}
 	}
-	return nil
+	return c.migrateArtifactRevisions()
+}
+
+// Ping verifies that the database can be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	lock()
+	defer unlock()
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PingTable verifies that the table holding an entity can be read.
+func (c *Client) PingTable(ctx context.Context, v interface{}) error {
+	lock()
+	defer unlock()
+	var keys []string
+	return c.db.WithContext(ctx).Model(v).Limit(1).Pluck("key", &keys).Error
 }
 
 // IsNotFound returns true if an error is due to an entity not being found.
@@ -170,6 +199,16 @@ func (c *Client) Put(ctx context.Context, k *Key, v interface{}) (*Key, error) {
 		r.Key = k.Name
 	case *models.Artifact:
 		r.Key = k.Name
+	case *models.ArtifactRevisionTag:
+		r.Key = k.Name
+	case *models.ArtifactType:
+		r.Key = k.Name
+	case *models.LifecycleTransition:
+		r.Key = k.Name
+	case *models.Relationship:
+		r.Key = k.Name
+	case *models.Snapshot:
+		r.Key = k.Name
 	case *models.Blob:
 		r.Key = k.Name
 	}
@@ -188,7 +227,7 @@ func (c *Client) Put(ctx context.Context, k *Key, v interface{}) (*Key, error) {
 func (c *Client) Delete(ctx context.Context, q *Query) error {
 	op := c.db
 	for _, r := range q.Requirements {
-		op = op.Where(r.Name+" = ?", r.Value)
+		op = op.Where(r.clause(), r.Value)
 	}
 	switch q.Kind {
 	case "Project":
@@ -207,6 +246,16 @@ func (c *Client) Delete(ctx context.Context, q *Query) error {
 		return op.Delete(models.DeploymentRevisionTag{}).Error
 	case "Artifact":
 		return op.Delete(models.Artifact{}).Error
+	case "ArtifactRevisionTag":
+		return op.Delete(models.ArtifactRevisionTag{}).Error
+	case "ArtifactType":
+		return op.Delete(models.ArtifactType{}).Error
+	case "LifecycleTransition":
+		return op.Delete(models.LifecycleTransition{}).Error
+	case "Relationship":
+		return op.Delete(models.Relationship{}).Error
+	case "Snapshot":
+		return op.Delete(models.Snapshot{}).Error
 	case "Blob":
 		return op.Delete(models.Blob{}).Error
 	}
@@ -225,7 +274,7 @@ func (c *Client) Run(ctx context.Context, q *Query) *Iterator {
 	// that behavior until we improve our iterator implementation.
 	op := c.db.Offset(q.Offset).Limit(100000)
 	for _, r := range q.Requirements {
-		op = op.Where(r.Name+" = ?", r.Value)
+		op = op.Where(r.clause(), r.Value)
 	}
 
 	if order := q.Order; order != "" {
@@ -271,12 +320,129 @@ func (c *Client) Run(ctx context.Context, q *Query) *Iterator {
 		var v []models.Artifact
 		_ = op.Find(&v).Error
 		return &Iterator{Client: c, Values: v, Index: 0}
+	case "ArtifactRevisionTag":
+		var v []models.ArtifactRevisionTag
+		_ = op.Find(&v).Error
+		return &Iterator{Client: c, Values: v, Index: 0}
+	case "ArtifactType":
+		var v []models.ArtifactType
+		_ = op.Find(&v).Error
+		return &Iterator{Client: c, Values: v, Index: 0}
+	case "LifecycleTransition":
+		var v []models.LifecycleTransition
+		_ = op.Find(&v).Error
+		return &Iterator{Client: c, Values: v, Index: 0}
+	case "Relationship":
+		var v []models.Relationship
+		_ = op.Find(&v).Error
+		return &Iterator{Client: c, Values: v, Index: 0}
+	case "Snapshot":
+		var v []models.Snapshot
+		_ = op.Find(&v).Error
+		return &Iterator{Client: c, Values: v, Index: 0}
 	default:
 		return nil
 	}
 }
 
-func (c *Client) GetRecentSpecRevisions(ctx context.Context, offset int32, projectID, apiID, versionID string) *Iterator {
+// model returns an empty model for entities of the specified kind.
+func model(kind string) (interface{}, error) {
+	switch kind {
+	case "Project":
+		return &models.Project{}, nil
+	case "Api":
+		return &models.Api{}, nil
+	case "Version":
+		return &models.Version{}, nil
+	case "Spec":
+		return &models.Spec{}, nil
+	case "SpecRevisionTag":
+		return &models.SpecRevisionTag{}, nil
+	case "Deployment":
+		return &models.Deployment{}, nil
+	case "DeploymentRevisionTag":
+		return &models.DeploymentRevisionTag{}, nil
+	case "Artifact":
+		return &models.Artifact{}, nil
+	case "ArtifactRevisionTag":
+		return &models.ArtifactRevisionTag{}, nil
+	case "ArtifactType":
+		return &models.ArtifactType{}, nil
+	case "LifecycleTransition":
+		return &models.LifecycleTransition{}, nil
+	case "Relationship":
+		return &models.Relationship{}, nil
+	case "Snapshot":
+		return &models.Snapshot{}, nil
+	case "Blob":
+		return &models.Blob{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+}
+
+// Count returns the number of entities matching a query. If distinct fields
+// are specified, entities with the same values of those fields are counted once.
+func (c *Client) Count(ctx context.Context, q *Query, distinct ...string) (int64, error) {
+	lock()
+	defer unlock()
+	v, err := model(q.Kind)
+	if err != nil {
+		return 0, err
+	}
+	op := c.db.WithContext(ctx).Model(v)
+	for _, r := range q.Requirements {
+		op = op.Where(r.clause(), r.Value)
+	}
+	if len(distinct) > 0 {
+		columns := make([]string, len(distinct))
+		for i, field := range distinct {
+			columns[i] = columnName(field)
+		}
+		op = c.db.WithContext(ctx).Table("(?) AS d", op.Distinct(columns))
+	}
+	var count int64
+	err = op.Count(&count).Error
+	return count, err
+}
+
+// Sum returns the sum of a numeric field over all entities matching a query.
+func (c *Client) Sum(ctx context.Context, q *Query, field string) (int64, error) {
+	lock()
+	defer unlock()
+	v, err := model(q.Kind)
+	if err != nil {
+		return 0, err
+	}
+	op := c.db.WithContext(ctx).Model(v)
+	for _, r := range q.Requirements {
+		op = op.Where(r.clause(), r.Value)
+	}
+	var sum int64
+	err = op.Select("COALESCE(SUM(" + columnName(field) + "), 0)").Row().Scan(&sum)
+	return sum, err
+}
+
+// Values returns the distinct values of a string field over all entities
+// matching a query, in ascending order.
+func (c *Client) Values(ctx context.Context, q *Query, field string) ([]string, error) {
+	lock()
+	defer unlock()
+	v, err := model(q.Kind)
+	if err != nil {
+		return nil, err
+	}
+	op := c.db.WithContext(ctx).Model(v)
+	for _, r := range q.Requirements {
+		op = op.Where(r.clause(), r.Value)
+	}
+	column := columnName(field)
+	var values []string
+	err = op.Distinct(column).Order(column).Pluck(column, &values).Error
+	return values, err
+}
+
+func (c *Client) GetRecentSpecRevisions(ctx context.Context, offset int32, projectID, locationID, apiID, versionID string) *Iterator {
 	lock()
 	defer unlock()
 
@@ -285,13 +451,13 @@ func (c *Client) GetRecentSpecRevisions(ctx context.Context, offset int32, proje
 	op := c.db.Select("specs.*").
 		Table("specs").
 		// Join missing columns that couldn't be selected in the subquery.
-		Joins("JOIN (?) AS grp ON specs.project_id = grp.project_id AND specs.api_id = grp.api_id AND specs.version_id = grp.version_id AND specs.spec_id = grp.spec_id AND specs.revision_create_time = grp.recent_create_time",
+		Joins("JOIN (?) AS grp ON specs.project_id = grp.project_id AND specs.location_id = grp.location_id AND specs.api_id = grp.api_id AND specs.version_id = grp.version_id AND specs.spec_id = grp.spec_id AND specs.revision_create_time = grp.recent_create_time",
 			// Select spec names and only their most recent revision_create_time
 			// This query cannot select all the columns we want.
 			// See: https://stackoverflow.com/questions/7745609/sql-select-only-rows-with-max-value-on-a-column
-			c.db.Select("project_id, api_id, version_id, spec_id, MAX(revision_create_time) AS recent_create_time").
+			c.db.Select("project_id, location_id, api_id, version_id, spec_id, MAX(revision_create_time) AS recent_create_time").
 				Table("specs").
-				Group("project_id, api_id, version_id, spec_id")).
+				Group("project_id, location_id, api_id, version_id, spec_id")).
 		Order("key").
 		Offset(int(offset)).
 		Limit(100000)
@@ -299,6 +465,9 @@ func (c *Client) GetRecentSpecRevisions(ctx context.Context, offset int32, proje
 	if projectID != "-" {
 		op = op.Where("specs.project_id = ?", projectID)
 	}
+	if locationID != "-" {
+		op = op.Where("specs.location_id = ?", locationID)
+	}
 	if apiID != "-" {
 		op = op.Where("specs.api_id = ?", apiID)
 	}
@@ -311,7 +480,7 @@ func (c *Client) GetRecentSpecRevisions(ctx context.Context, offset int32, proje
 	return &Iterator{Client: c, Values: v, Index: 0}
 }
 
-func (c *Client) GetRecentDeploymentRevisions(ctx context.Context, offset int32, projectID, apiID string) *Iterator {
+func (c *Client) GetRecentDeploymentRevisions(ctx context.Context, offset int32, projectID, locationID, apiID string) *Iterator {
 	lock()
 	defer unlock()
 
@@ -320,13 +489,13 @@ func (c *Client) GetRecentDeploymentRevisions(ctx context.Context, offset int32,
 	op := c.db.Select("deployments.*").
 		Table("deployments").
 		// Join missing columns that couldn't be selected in the subquery.
-		Joins("JOIN (?) AS grp ON deployments.project_id = grp.project_id AND deployments.api_id = grp.api_id AND deployments.deployment_id = grp.deployment_id AND deployments.revision_create_time = grp.recent_create_time",
+		Joins("JOIN (?) AS grp ON deployments.project_id = grp.project_id AND deployments.location_id = grp.location_id AND deployments.api_id = grp.api_id AND deployments.deployment_id = grp.deployment_id AND deployments.revision_create_time = grp.recent_create_time",
 			// Select deployment names and only their most recent revision_create_time
 			// This query cannot select all the columns we want.
 			// See: https://stackoverflow.com/questions/7745609/sql-select-only-rows-with-max-value-on-a-column
-			c.db.Select("project_id, api_id, deployment_id, MAX(revision_create_time) AS recent_create_time").
+			c.db.Select("project_id, location_id, api_id, deployment_id, MAX(revision_create_time) AS recent_create_time").
 				Table("deployments").
-				Group("project_id, api_id, deployment_id")).
+				Group("project_id, location_id, api_id, deployment_id")).
 		Order("key").
 		Offset(int(offset)).
 		Limit(100000)
@@ -334,6 +503,9 @@ func (c *Client) GetRecentDeploymentRevisions(ctx context.Context, offset int32,
 	if projectID != "-" {
 		op = op.Where("deployments.project_id = ?", projectID)
 	}
+	if locationID != "-" {
+		op = op.Where("deployments.location_id = ?", locationID)
+	}
 	if apiID != "-" {
 		op = op.Where("deployments.api_id = ?", apiID)
 	}
@@ -342,3 +514,32 @@ func (c *Client) GetRecentDeploymentRevisions(ctx context.Context, offset int32,
 	_ = op.Scan(&v).Error
 	return &Iterator{Client: c, Values: v, Index: 0}
 }
+
+func (c *Client) GetRecentArtifactRevisions(ctx context.Context, q *Query) *Iterator {
+	lock()
+	defer unlock()
+
+	// Select all columns from `artifacts` table specifically.
+	// We do not want to select duplicates from the joined subquery result.
+	op := c.db.Select("artifacts.*").
+		Table("artifacts").
+		// Join missing columns that couldn't be selected in the subquery.
+		Joins("JOIN (?) AS grp ON artifacts.project_id = grp.project_id AND artifacts.location_id = grp.location_id AND artifacts.api_id = grp.api_id AND artifacts.version_id = grp.version_id AND artifacts.spec_id = grp.spec_id AND artifacts.deployment_id = grp.deployment_id AND artifacts.artifact_id = grp.artifact_id AND artifacts.revision_create_time = grp.recent_create_time",
+			// Select artifact names and only their most recent revision_create_time
+			// This query cannot select all the columns we want.
+			// See: https://stackoverflow.com/questions/7745609/sql-select-only-rows-with-max-value-on-a-column
+			c.db.Select("project_id, location_id, api_id, version_id, spec_id, deployment_id, artifact_id, MAX(revision_create_time) AS recent_create_time").
+				Table("artifacts").
+				Group("project_id, location_id, api_id, version_id, spec_id, deployment_id, artifact_id")).
+		Order("key").
+		Offset(q.Offset).
+		Limit(100000)
+
+	for _, r := range q.Requirements {
+		op = op.Where("artifacts."+r.Name+" = ?", r.Value)
+	}
+
+	var v []models.Artifact
+	_ = op.Scan(&v).Error
+	return &Iterator{Client: c, Values: v, Index: 0}
+}
@@ -31,6 +31,16 @@ const (
 	DeploymentRevisionTagEntityName = "DeploymentRevisionTag"
 	// ArtifactEntityName is the storage entity name for artifact resources.
 	ArtifactEntityName = "Artifact"
+	// ArtifactRevisionTagEntityName is the storage entity name for artifact revision tag resources.
+	ArtifactRevisionTagEntityName = "ArtifactRevisionTag"
+	// ArtifactTypeEntityName is the storage entity name for artifact type resources.
+	ArtifactTypeEntityName = "ArtifactType"
+	// LifecycleTransitionEntityName is the storage entity name for lifecycle transition resources.
+	LifecycleTransitionEntityName = "LifecycleTransition"
+	// RelationshipEntityName is the storage entity name for relationship resources.
+	RelationshipEntityName = "Relationship"
+	// SnapshotEntityName is the storage entity name for records of changes to resources.
+	SnapshotEntityName = "Snapshot"
 	// BlobEntityName is the storage entity name for blob resources.
 	BlobEntityName = "Blob"
 )
@@ -167,3 +167,54 @@ func TestLoad(t *testing.T) {
 		c.Close()
 	}
 }
+
+func TestCountAndSum(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := NewClient(ctx, "sqlite3", t.TempDir()+"/testing.db")
+	if err != nil {
+		t.Fatalf("NewClient returned error: %s", err)
+	}
+	defer c.Close()
+	if err := c.EnsureTables(); err != nil {
+		t.Fatalf("EnsureTables returned error: %s", err)
+	}
+
+	for _, b := range []*models.Blob{
+		{ProjectID: "demo", SpecID: "a", RevisionID: "1", SizeInBytes: 10},
+		{ProjectID: "demo", SpecID: "a", RevisionID: "2", SizeInBytes: 20},
+		{ProjectID: "demo", SpecID: "b", RevisionID: "1", SizeInBytes: 30},
+		{ProjectID: "other", SpecID: "a", RevisionID: "1", SizeInBytes: 40},
+	} {
+		k := c.NewKey(BlobEntityName, fmt.Sprintf("%s/%s@%s", b.ProjectID, b.SpecID, b.RevisionID))
+		if _, err := c.Put(ctx, k, b); err != nil {
+			t.Fatalf("Setup: Put(%q) returned error: %s", k, err)
+		}
+	}
+
+	q := c.NewQuery(BlobEntityName).Require("ProjectID", "demo")
+	if got, err := c.Count(ctx, q); err != nil {
+		t.Errorf("Count() returned error: %s", err)
+	} else if got != 3 {
+		t.Errorf("Count() returned %d, expected 3", got)
+	}
+
+	if got, err := c.Count(ctx, q, "SpecID"); err != nil {
+		t.Errorf("Count(SpecID) returned error: %s", err)
+	} else if got != 2 {
+		t.Errorf("Count(SpecID) returned %d, expected 2", got)
+	}
+
+	if got, err := c.Sum(ctx, q, "SizeInBytes"); err != nil {
+		t.Errorf("Sum() returned error: %s", err)
+	} else if got != 60 {
+		t.Errorf("Sum() returned %d, expected 60", got)
+	}
+
+	empty := c.NewQuery(BlobEntityName).Require("ProjectID", "missing")
+	if got, err := c.Sum(ctx, empty, "SizeInBytes"); err != nil {
+		t.Errorf("Sum() returned error: %s", err)
+	} else if got != 0 {
+		t.Errorf("Sum() returned %d for no entities, expected 0", got)
+	}
+}
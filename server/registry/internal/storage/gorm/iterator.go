@@ -102,6 +102,51 @@ func (it *Iterator) Next(v interface{}) (*Key, error) {
 			return it.Client.NewKey("Artifact", x.Key), nil
 		}
 		return nil, iterator.Done
+	case *models.ArtifactRevisionTag:
+		values := it.Values.([]models.ArtifactRevisionTag)
+		if it.Index < len(values) {
+			*x = values[it.Index]
+			it.Cursor = x.Key
+			it.Index++
+			return it.Client.NewKey("ArtifactRevisionTag", x.Key), nil
+		}
+		return nil, iterator.Done
+	case *models.ArtifactType:
+		values := it.Values.([]models.ArtifactType)
+		if it.Index < len(values) {
+			*x = values[it.Index]
+			it.Cursor = x.Key
+			it.Index++
+			return it.Client.NewKey("ArtifactType", x.Key), nil
+		}
+		return nil, iterator.Done
+	case *models.LifecycleTransition:
+		values := it.Values.([]models.LifecycleTransition)
+		if it.Index < len(values) {
+			*x = values[it.Index]
+			it.Cursor = x.Key
+			it.Index++
+			return it.Client.NewKey("LifecycleTransition", x.Key), nil
+		}
+		return nil, iterator.Done
+	case *models.Relationship:
+		values := it.Values.([]models.Relationship)
+		if it.Index < len(values) {
+			*x = values[it.Index]
+			it.Cursor = x.Key
+			it.Index++
+			return it.Client.NewKey("Relationship", x.Key), nil
+		}
+		return nil, iterator.Done
+	case *models.Snapshot:
+		values := it.Values.([]models.Snapshot)
+		if it.Index < len(values) {
+			*x = values[it.Index]
+			it.Cursor = x.Key
+			it.Index++
+			return it.Client.NewKey("Snapshot", x.Key), nil
+		}
+		return nil, iterator.Done
 	case *models.SpecRevisionTag:
 		values := it.Values.([]models.SpecRevisionTag)
 		if it.Index < len(values) {
@@ -0,0 +1,140 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gorm
+
+import (
+	"time"
+
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// addMissingColumns adds the columns of an entity that are missing from its
+// table. Rows that existed before a column was added are given the column's
+// default value or the zero value of its type, so that they are matched by
+// queries in the same way as new rows. Callers must hold the lock.
+func (c *Client) addMissingColumns(v interface{}) error {
+	stmt := &gorm.Statement{DB: c.db}
+	if err := stmt.Parse(v); err != nil {
+		return err
+	}
+
+	m := c.db.Migrator()
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || m.HasColumn(v, field.DBName) {
+			continue
+		}
+		if err := m.AddColumn(v, field.DBName); err != nil {
+			return err
+		}
+
+		value, ok := initialValue(field)
+		if !ok {
+			continue
+		}
+		err := c.db.Table(stmt.Schema.Table).
+			Where(field.DBName+" IS NULL").
+			Update(field.DBName, value).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initialValue returns the value of a new column in existing rows. Bytes and
+// times are left NULL, which is read as their zero values.
+func initialValue(field *schema.Field) (interface{}, bool) {
+	if field.HasDefaultValue && field.DefaultValueInterface != nil {
+		return field.DefaultValueInterface, true
+	}
+	switch field.DataType {
+	case schema.String:
+		return "", true
+	case schema.Bool:
+		return false, true
+	case schema.Int, schema.Uint, schema.Float:
+		return 0, true
+	default:
+		return nil, false
+	}
+}
+
+// legacyArtifact is an artifact that was stored before artifacts had
+// revisions. Its key is its name, and so is the key of its contents.
+type legacyArtifact struct {
+	Key          string
+	DeploymentID string
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+// migrateArtifactRevisions makes each artifact that was stored before
+// artifacts had revisions the only revision of that artifact. The revision
+// was created and last updated when the artifact was last updated, and the
+// artifact and its contents are stored under the name of the revision.
+func (c *Client) migrateArtifactRevisions() error {
+	lock()
+	defer unlock()
+
+	types, err := c.db.Migrator().ColumnTypes(&models.Artifact{})
+	if err != nil {
+		return err
+	}
+	columns := "key, deployment_id, create_time"
+	for _, t := range types {
+		if t.Name() == "update_time" {
+			columns += ", update_time"
+		}
+	}
+	var artifacts []legacyArtifact
+	err = c.db.Table("artifacts").
+		Select(columns).
+		Where("revision_id IS NULL OR revision_id = ''").
+		Find(&artifacts).Error
+	if err != nil {
+		return err
+	}
+
+	for _, a := range artifacts {
+		revisionID := models.NewRevisionID()
+		revisionTime := a.UpdateTime
+		if revisionTime.IsZero() {
+			revisionTime = a.CreateTime
+		}
+		err := c.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Table("artifacts").Where("key = ?", a.Key).Updates(map[string]interface{}{
+				"key":                  a.Key + "@" + revisionID,
+				"revision_id":          revisionID,
+				"revision_create_time": revisionTime,
+				"revision_update_time": revisionTime,
+			}).Error
+			if err != nil {
+				return err
+			}
+			// Contents didn't record the deployments of their artifacts.
+			return tx.Table("blobs").Where("key = ?", a.Key).Updates(map[string]interface{}{
+				"key":           a.Key + "@" + revisionID,
+				"revision_id":   revisionID,
+				"deployment_id": a.DeploymentID,
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
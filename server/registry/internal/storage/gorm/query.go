@@ -22,10 +22,20 @@ type Query struct {
 	Requirements []*Requirement
 }
 
-// Requirement adds an equality filter to a query.
+// Requirement adds a filter to a query.
 type Requirement struct {
 	Name  string
 	Value interface{}
+	// Op is the comparison operator; if empty, the filter is an equality.
+	Op string
+}
+
+// clause returns the condition of a requirement.
+func (r *Requirement) clause() string {
+	if r.Op == "" {
+		return r.Name + " = ?"
+	}
+	return r.Name + " " + r.Op + " ?"
 }
 
 // NewQuery creates a new query.
@@ -37,23 +47,64 @@ func (c *Client) NewQuery(kind string) *Query {
 
 // Require adds a filter to a query that requires a field to have a specified value.
 func (q *Query) Require(name string, value interface{}) *Query {
-	switch name {
+	q.Requirements = append(q.Requirements, &Requirement{Name: columnName(name), Value: value})
+	return q
+}
+
+// RequireAtMost adds a filter to a query that requires a field to be less
+// than or equal to a specified value.
+func (q *Query) RequireAtMost(name string, value interface{}) *Query {
+	q.Requirements = append(q.Requirements, &Requirement{Name: columnName(name), Value: value, Op: "<="})
+	return q
+}
+
+// columnName returns the name of the column that stores a model field.
+func columnName(field string) string {
+	switch field {
+	case "Key":
+		return "key"
+	case "Kind":
+		return "kind"
+	case "Name":
+		return "name"
 	case "ProjectID":
-		name = "project_id"
+		return "project_id"
+	case "LocationID":
+		return "location_id"
 	case "ApiID":
-		name = "api_id"
+		return "api_id"
 	case "VersionID":
-		name = "version_id"
+		return "version_id"
 	case "SpecID":
-		name = "spec_id"
+		return "spec_id"
 	case "DeploymentID":
-		name = "deployment_id"
+		return "deployment_id"
 	case "RevisionID":
-		name = "revision_id"
+		return "revision_id"
 	case "ArtifactID":
-		name = "artifact_id"
+		return "artifact_id"
+	case "ArtifactTypeID":
+		return "artifact_type_id"
+	case "RelationshipID":
+		return "relationship_id"
+	case "ChangeTime":
+		return "change_time"
+	case "MimeType":
+		return "mime_type"
+	case "SizeInBytes":
+		return "size_in_bytes"
 	}
-	q.Requirements = append(q.Requirements, &Requirement{Name: name, Value: value})
+	return field
+}
+
+func (q *Query) Ascending(field string) *Query {
+	switch field {
+	case "TransitionTime":
+		q.Order = "transition_time"
+	case "ChangeTime":
+		q.Order = "change_time, key"
+	}
+
 	return q
 }
 
@@ -0,0 +1,956 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apigee/registry/server/registry/internal/storage/filtering"
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SaveSnapshot records the state of a resource after a change.
+func (d *Client) SaveSnapshot(ctx context.Context, snapshot *models.Snapshot) error {
+	k := d.NewKey(gorm.SnapshotEntityName, snapshot.String())
+	if _, err := d.Put(ctx, k, snapshot); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// ListSnapshotHistory returns the snapshots of a resource that were recorded
+// at or before a time, oldest first. Snapshots of deleted revisions are
+// included when name is a revision name.
+func (d *Client) ListSnapshotHistory(ctx context.Context, kind, name string, t time.Time) ([]models.Snapshot, error) {
+	q := d.NewQuery(gorm.SnapshotEntityName)
+	q = q.Require("Kind", kind)
+	q = q.Require("Name", name)
+	return d.listSnapshots(ctx, q, t)
+}
+
+// GetSnapshot returns the state of a resource at a time. It returns NotFound
+// if the resource or one of its parents did not exist at that time.
+func (d *Client) GetSnapshot(ctx context.Context, kind, name string, t time.Time) (*models.Snapshot, error) {
+	history, err := d.ListSnapshotHistory(ctx, kind, name, t)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := status.Errorf(codes.NotFound, "%q not found at %s", name, t.Format(time.RFC3339Nano))
+	if len(history) == 0 {
+		return nil, notFound
+	}
+
+	deletions, err := d.deletionTimes(ctx, history[0].ProjectID, t)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := history[len(history)-1]
+	if !alive(latest, deletions) {
+		return nil, notFound
+	}
+
+	return &latest, nil
+}
+
+// listSnapshotsAt returns the states at a time of the resources of a kind
+// that are children of parent, ordered by name. Parent IDs of "-" match all
+// resources, and an empty parent matches projects.
+func (d *Client) listSnapshotsAt(ctx context.Context, kind, parent string, t time.Time) ([]models.Snapshot, error) {
+	projectID := "-"
+	if segments := strings.Split(parent, "/"); len(segments) > 1 {
+		projectID = segments[1]
+	}
+
+	q := d.NewQuery(gorm.SnapshotEntityName)
+	q = q.Require("Kind", kind)
+	if projectID != "-" {
+		q = q.Require("ProjectID", projectID)
+	}
+
+	history, err := d.listSnapshots(ctx, q, t)
+	if err != nil {
+		return nil, err
+	}
+
+	deletions, err := d.deletionTimes(ctx, projectID, t)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]models.Snapshot)
+	for _, s := range history {
+		if childOf(parent, s.Name) {
+			latest[s.Name] = s
+		}
+	}
+
+	snapshots := make([]models.Snapshot, 0, len(latest))
+	for _, s := range latest {
+		if alive(s, deletions) {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+func (d *Client) listSnapshots(ctx context.Context, q *gorm.Query, t time.Time) ([]models.Snapshot, error) {
+	q = q.RequireAtMost("ChangeTime", t)
+	q = q.Ascending("ChangeTime")
+
+	var (
+		snapshots = make([]models.Snapshot, 0)
+		snapshot  = new(models.Snapshot)
+		it        = d.Run(ctx, q)
+		err       error
+	)
+
+	for _, err = it.Next(snapshot); err == nil; _, err = it.Next(snapshot) {
+		snapshots = append(snapshots, *snapshot)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return snapshots, nil
+}
+
+// deletionTimes returns the latest time at or before t that each resource in
+// a project was deleted.
+func (d *Client) deletionTimes(ctx context.Context, projectID string, t time.Time) (map[string]time.Time, error) {
+	q := d.NewQuery(gorm.SnapshotEntityName)
+	q = q.Require("Deleted", true)
+	if projectID != "-" {
+		q = q.Require("ProjectID", projectID)
+	}
+
+	deletions, err := d.listSnapshots(ctx, q, t)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[string]time.Time, len(deletions))
+	for _, s := range deletions {
+		times[s.Name] = s.ChangeTime
+	}
+	return times, nil
+}
+
+// alive returns true if the resource recorded by a snapshot was not deleted
+// afterwards, either directly or by the deletion of one of its parents.
+func alive(s models.Snapshot, deletions map[string]time.Time) bool {
+	if s.Deleted {
+		return false
+	}
+
+	segments := strings.Split(s.Name, "/")
+	for i := 2; i < len(segments); i += 2 {
+		if deleted, ok := deletions[strings.Join(segments[:i], "/")]; ok && deleted.After(s.ChangeTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// childOf returns true if name is the name of a direct child of parent.
+// Parent IDs of "-" match any ID.
+func childOf(parent, name string) bool {
+	if strings.Contains(name, "@") {
+		return false
+	}
+
+	segments := strings.Split(name, "/")
+	if len(segments) < 2 {
+		return false
+	}
+	segments = segments[:len(segments)-2]
+
+	var pattern []string
+	if parent != "" {
+		pattern = strings.Split(parent, "/")
+	}
+	if len(pattern) != len(segments) {
+		return false
+	}
+
+	for i := range pattern {
+		if pattern[i] != segments[i] && !(i%2 == 1 && pattern[i] == "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// pageSnapshots returns a page of the snapshots that match the filter of a
+// list request. The fields of each snapshot are returned by fieldMap.
+func pageSnapshots(snapshots []models.Snapshot, opts PageOptions, fields []filtering.Field, fieldMap func(models.Snapshot) (map[string]interface{}, error)) ([]models.Snapshot, string, error) {
+	token, err := decodeToken(opts.Token)
+	if err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
+	}
+
+	if err := token.ValidateFilter(opts.Filter); err != nil {
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid filter %q: %s", opts.Filter, err)
+	} else {
+		token.Filter = opts.Filter
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, fields)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := make([]models.Snapshot, 0, opts.Size)
+	for i := int(token.Offset); i < len(snapshots); i++ {
+		m, err := fieldMap(snapshots[i])
+		if err != nil {
+			return nil, "", status.Error(codes.Internal, err.Error())
+		}
+
+		match, err := filter.Matches(m)
+		if err != nil {
+			return nil, "", err
+		} else if !match {
+			token.Offset++
+			continue
+		} else if len(page) == int(opts.Size) {
+			next, err := encodeToken(token)
+			if err != nil {
+				return nil, "", status.Error(codes.Internal, err.Error())
+			}
+			return page, next, nil
+		}
+
+		page = append(page, snapshots[i])
+		token.Offset++
+	}
+
+	return page, "", nil
+}
+
+// ListProjectsAt lists projects as they were at a time.
+func (d *Client) ListProjectsAt(ctx context.Context, opts PageOptions, t time.Time) (ProjectList, error) {
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.ProjectEntityName, "", t)
+	if err != nil {
+		return ProjectList{}, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, projectFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var p models.Project
+		if err := s.Decode(&p); err != nil {
+			return nil, err
+		}
+		return projectMap(p), nil
+	})
+	if err != nil {
+		return ProjectList{}, err
+	}
+
+	response := ProjectList{Projects: make([]models.Project, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Projects[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// ListApisAt lists APIs as they were at a time.
+func (d *Client) ListApisAt(ctx context.Context, parent names.Location, opts PageOptions, t time.Time) (ApiList, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return ApiList{}, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.ApiEntityName, parent.String(), t)
+	if err != nil {
+		return ApiList{}, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, apiFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var api models.Api
+		if err := s.Decode(&api); err != nil {
+			return nil, err
+		}
+		return apiMap(api)
+	})
+	if err != nil {
+		return ApiList{}, err
+	}
+
+	response := ApiList{Apis: make([]models.Api, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Apis[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// ListVersionsAt lists API versions as they were at a time.
+func (d *Client) ListVersionsAt(ctx context.Context, parent names.Api, opts PageOptions, t time.Time) (VersionList, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return VersionList{}, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.VersionEntityName, parent.String(), t)
+	if err != nil {
+		return VersionList{}, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, versionFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var version models.Version
+		if err := s.Decode(&version); err != nil {
+			return nil, err
+		}
+		return versionMap(version)
+	})
+	if err != nil {
+		return VersionList{}, err
+	}
+
+	response := VersionList{Versions: make([]models.Version, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Versions[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// ListSpecsAt lists API specs as they were at a time. Each snapshot's
+// revision tags are returned with its spec.
+func (d *Client) ListSpecsAt(ctx context.Context, parent names.Version, opts PageOptions, t time.Time) (SpecList, []models.Snapshot, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return SpecList{}, nil, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.SpecEntityName, parent.String(), t)
+	if err != nil {
+		return SpecList{}, nil, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, specFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var spec models.Spec
+		if err := s.Decode(&spec); err != nil {
+			return nil, err
+		}
+		return specMap(spec)
+	})
+	if err != nil {
+		return SpecList{}, nil, err
+	}
+
+	response := SpecList{Specs: make([]models.Spec, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Specs[i]); err != nil {
+			return response, nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, page, nil
+}
+
+// ListDeploymentsAt lists API deployments as they were at a time. Each
+// snapshot's revision tags are returned with its deployment.
+func (d *Client) ListDeploymentsAt(ctx context.Context, parent names.Api, opts PageOptions, t time.Time) (DeploymentList, []models.Snapshot, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return DeploymentList{}, nil, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.DeploymentEntityName, parent.String(), t)
+	if err != nil {
+		return DeploymentList{}, nil, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, deploymentFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var deployment models.Deployment
+		if err := s.Decode(&deployment); err != nil {
+			return nil, err
+		}
+		return deploymentMap(deployment)
+	})
+	if err != nil {
+		return DeploymentList{}, nil, err
+	}
+
+	response := DeploymentList{Deployments: make([]models.Deployment, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Deployments[i]); err != nil {
+			return response, nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, page, nil
+}
+
+// ListArtifactsAt lists the artifacts of a project, API, version, spec, or
+// deployment as they were at a time.
+func (d *Client) ListArtifactsAt(ctx context.Context, parent names.Name, opts PageOptions, t time.Time) (ArtifactList, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return ArtifactList{}, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.ArtifactEntityName, parent.String(), t)
+	if err != nil {
+		return ArtifactList{}, err
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, artifactFields)
+	if err != nil {
+		return ArtifactList{}, err
+	}
+
+	// Artifact types are cached by MIME type while decoding contents for filtering.
+	types := make(map[string]*models.ArtifactType)
+
+	page, token, err := pageSnapshots(snapshots, opts, artifactFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var artifact models.Artifact
+		if err := s.Decode(&artifact); err != nil {
+			return nil, err
+		}
+		m, err := artifactMap(artifact)
+		if err != nil || !filter.References("contents") {
+			return m, err
+		}
+		m["contents"], err = d.decodedArtifactContents(ctx, artifact, types)
+		return m, err
+	})
+	if err != nil {
+		return ArtifactList{}, err
+	}
+
+	response := ArtifactList{Artifacts: make([]models.Artifact, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Artifacts[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// ListArtifactTypesAt lists artifact types as they were at a time.
+func (d *Client) ListArtifactTypesAt(ctx context.Context, parent names.Location, opts PageOptions, t time.Time) (ArtifactTypeList, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return ArtifactTypeList{}, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.ArtifactTypeEntityName, parent.String(), t)
+	if err != nil {
+		return ArtifactTypeList{}, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, artifactTypeFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var artifactType models.ArtifactType
+		if err := s.Decode(&artifactType); err != nil {
+			return nil, err
+		}
+		return artifactTypeMap(artifactType), nil
+	})
+	if err != nil {
+		return ArtifactTypeList{}, err
+	}
+
+	response := ArtifactTypeList{ArtifactTypes: make([]models.ArtifactType, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.ArtifactTypes[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// ListRelationshipsAt lists relationships as they were at a time.
+func (d *Client) ListRelationshipsAt(ctx context.Context, parent names.Api, opts PageOptions, t time.Time) (RelationshipList, error) {
+	if err := d.checkParentAt(ctx, parent, t); err != nil {
+		return RelationshipList{}, err
+	}
+
+	snapshots, err := d.listSnapshotsAt(ctx, gorm.RelationshipEntityName, parent.String(), t)
+	if err != nil {
+		return RelationshipList{}, err
+	}
+
+	page, token, err := pageSnapshots(snapshots, opts, relationshipFields, func(s models.Snapshot) (map[string]interface{}, error) {
+		var relationship models.Relationship
+		if err := s.Decode(&relationship); err != nil {
+			return nil, err
+		}
+		return relationshipMap(relationship)
+	})
+	if err != nil {
+		return RelationshipList{}, err
+	}
+
+	response := RelationshipList{Relationships: make([]models.Relationship, len(page)), Token: token}
+	for i, s := range page {
+		if err := s.Decode(&response.Relationships[i]); err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
+
+// checkParentAt returns NotFound if the parent of a list did not exist at a
+// time. Parents with "-" IDs are checked up to their first wildcard.
+func (d *Client) checkParentAt(ctx context.Context, parent names.Name, t time.Time) error {
+	segments := strings.Split(parent.String(), "/")
+	for i := 1; i < len(segments); i += 2 {
+		if segments[i] == "-" {
+			segments = segments[:i-1]
+			break
+		}
+	}
+
+	// Locations aren't resources with histories.
+	if len(segments) == 4 && segments[2] == "locations" {
+		segments = segments[:2]
+	}
+
+	kinds := map[string]string{
+		"projects":    gorm.ProjectEntityName,
+		"apis":        gorm.ApiEntityName,
+		"versions":    gorm.VersionEntityName,
+		"specs":       gorm.SpecEntityName,
+		"deployments": gorm.DeploymentEntityName,
+	}
+
+	if len(segments) < 2 {
+		return nil
+	}
+	kind, ok := kinds[segments[len(segments)-2]]
+	if !ok {
+		return nil
+	}
+
+	_, err := d.GetSnapshot(ctx, kind, strings.Join(segments, "/"), t)
+	return err
+}
+
+// decodeSnapshot returns the state of a resource at a time.
+func (d *Client) decodeSnapshot(ctx context.Context, kind, name string, t time.Time, v interface{}) error {
+	snapshot, err := d.GetSnapshot(ctx, kind, name, t)
+	if err != nil {
+		return err
+	}
+
+	if err := snapshot.Decode(v); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// GetProjectAt returns a project as it was at a time.
+func (d *Client) GetProjectAt(ctx context.Context, name names.Project, t time.Time) (*models.Project, error) {
+	project := new(models.Project)
+	return project, d.decodeSnapshot(ctx, gorm.ProjectEntityName, name.String(), t, project)
+}
+
+// GetApiAt returns an API as it was at a time.
+func (d *Client) GetApiAt(ctx context.Context, name names.Api, t time.Time) (*models.Api, error) {
+	api := new(models.Api)
+	return api, d.decodeSnapshot(ctx, gorm.ApiEntityName, name.String(), t, api)
+}
+
+// GetVersionAt returns an API version as it was at a time.
+func (d *Client) GetVersionAt(ctx context.Context, name names.Version, t time.Time) (*models.Version, error) {
+	version := new(models.Version)
+	return version, d.decodeSnapshot(ctx, gorm.VersionEntityName, name.String(), t, version)
+}
+
+// GetArtifactTypeAt returns an artifact type as it was at a time.
+func (d *Client) GetArtifactTypeAt(ctx context.Context, name names.ArtifactType, t time.Time) (*models.ArtifactType, error) {
+	artifactType := new(models.ArtifactType)
+	return artifactType, d.decodeSnapshot(ctx, gorm.ArtifactTypeEntityName, name.String(), t, artifactType)
+}
+
+// GetRelationshipAt returns a relationship as it was at a time.
+func (d *Client) GetRelationshipAt(ctx context.Context, name names.Relationship, t time.Time) (*models.Relationship, error) {
+	relationship := new(models.Relationship)
+	return relationship, d.decodeSnapshot(ctx, gorm.RelationshipEntityName, name.String(), t, relationship)
+}
+
+// GetSpecAt returns a spec as it was at a time. If revision is not empty,
+// the spec revision with that ID or tag is returned. The revision tags of
+// the spec at that time are also returned.
+func (d *Client) GetSpecAt(ctx context.Context, name names.Spec, revision string, t time.Time) (*models.Spec, map[string]string, error) {
+	spec := new(models.Spec)
+	tags, err := d.getRevisionAt(ctx, gorm.SpecEntityName, name.String(), revision, t, func(s models.Snapshot) (string, error) {
+		*spec = models.Spec{}
+		err := s.Decode(spec)
+		return spec.RevisionID, err
+	})
+	return spec, tags, err
+}
+
+// GetDeploymentAt returns a deployment as it was at a time. If revision is
+// not empty, the deployment revision with that ID or tag is returned. The
+// revision tags of the deployment at that time are also returned.
+func (d *Client) GetDeploymentAt(ctx context.Context, name names.Deployment, revision string, t time.Time) (*models.Deployment, map[string]string, error) {
+	deployment := new(models.Deployment)
+	tags, err := d.getRevisionAt(ctx, gorm.DeploymentEntityName, name.String(), revision, t, func(s models.Snapshot) (string, error) {
+		*deployment = models.Deployment{}
+		err := s.Decode(deployment)
+		return deployment.RevisionID, err
+	})
+	return deployment, tags, err
+}
+
+// GetArtifactAt returns an artifact as it was at a time. If the name has a
+// revision ID or tag, that revision is returned. The revision tags of the
+// artifact at that time are also returned.
+func (d *Client) GetArtifactAt(ctx context.Context, name names.Artifact, t time.Time) (*models.Artifact, map[string]string, error) {
+	artifact := new(models.Artifact)
+	tags, err := d.getRevisionAt(ctx, gorm.ArtifactEntityName, name.Revision("").String(), name.RevisionID(), t, func(s models.Snapshot) (string, error) {
+		*artifact = models.Artifact{}
+		err := s.Decode(artifact)
+		return artifact.RevisionID, err
+	})
+	return artifact, tags, err
+}
+
+// getRevisionAt finds the snapshot of a resource at a time, or if revision is
+// not empty, the latest earlier snapshot in which that revision was current.
+// The snapshot is passed to decode, which returns the revision ID that it
+// records. The revision tags at the time are returned.
+func (d *Client) getRevisionAt(ctx context.Context, kind, name, revision string, t time.Time, decode func(models.Snapshot) (string, error)) (map[string]string, error) {
+	current, err := d.GetSnapshot(ctx, kind, name, t)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := current.TagsMap()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if revision == "" {
+		if _, err := decode(*current); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return tags, nil
+	}
+
+	if id, ok := tags[revision]; ok {
+		revision = id
+	}
+
+	notFound := status.Errorf(codes.NotFound, "%q not found at %s", name+"@"+revision, t.Format(time.RFC3339Nano))
+	if deletions, err := d.ListSnapshotHistory(ctx, kind, name+"@"+revision, t); err != nil {
+		return nil, err
+	} else if len(deletions) > 0 {
+		return nil, notFound
+	}
+
+	history, err := d.ListSnapshotHistory(ctx, kind, name, t)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		id, err := decode(history[i])
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		} else if id == revision && !history[i].Deleted {
+			return tags, nil
+		}
+	}
+
+	return nil, notFound
+}
+
+// RecordChange saves a snapshot of a resource after a change. If the change
+// deleted the resource, a deletion is recorded instead. Changes to revisions
+// are recorded as changes to their resources; deleted revisions are also
+// recorded by their revision names.
+func (d *Client) RecordChange(ctx context.Context, resource string, deleted bool) error {
+	kind, projectID, name, revision, err := parseChanged(resource)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if deleted {
+		if err := d.SaveSnapshot(ctx, models.NewDeletion(kind, projectID, resource)); err != nil || revision == "" {
+			return err
+		}
+	}
+
+	snapshot, err := d.snapshot(ctx, kind, projectID, name)
+	if isNotFound(err) {
+		// Deleting the only revision of a resource deletes it.
+		if revision != "" {
+			return d.SaveSnapshot(ctx, models.NewDeletion(kind, projectID, name))
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return d.SaveSnapshot(ctx, snapshot)
+}
+
+// SeedHistory records the state of resources that have no recorded history,
+// such as resources that were stored before history was recorded, so that
+// reads at later times find them. Each resource is recorded as it is now at
+// the time it was created; each stored revision of a resource is recorded
+// at the time the revision was created, and the current revision tags are
+// recorded with the current revision.
+func (d *Client) SeedHistory(ctx context.Context) error {
+	kinds := []string{
+		gorm.ProjectEntityName,
+		gorm.ApiEntityName,
+		gorm.VersionEntityName,
+		gorm.SpecEntityName,
+		gorm.DeploymentEntityName,
+		gorm.ArtifactEntityName,
+		gorm.ArtifactTypeEntityName,
+		gorm.RelationshipEntityName,
+	}
+	for _, kind := range kinds {
+		if err := d.seedHistory(ctx, kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Client) seedHistory(ctx context.Context, kind string) error {
+	recorded, err := d.Values(ctx, d.NewQuery(gorm.SnapshotEntityName).Require("Kind", kind), "Name")
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	seeded := make(map[string]bool, len(recorded))
+	for _, name := range recorded {
+		seeded[name] = true
+	}
+
+	// Resources with revisions are stored once for each revision.
+	keys, err := d.Values(ctx, d.NewQuery(kind), "Key")
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	var (
+		resources []string
+		projects  = make(map[string]string)
+		revisions = make(map[string][]string)
+	)
+	for _, key := range keys {
+		_, projectID, name, revision, err := parseChanged(key)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		} else if seeded[name] {
+			continue
+		}
+		if _, ok := projects[name]; !ok {
+			resources = append(resources, name)
+			projects[name] = projectID
+		}
+		if revision != "" {
+			revisions[name] = append(revisions[name], key)
+		}
+	}
+
+	for _, name := range resources {
+		if err := d.seedResource(ctx, kind, projects[name], name, revisions[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedResource records the current state of a resource, and the earlier
+// stored revisions of resources with revisions.
+func (d *Client) seedResource(ctx context.Context, kind, projectID, name string, revisions []string) error {
+	current, err := d.snapshot(ctx, kind, projectID, name)
+	if isNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	times := new(struct {
+		CreateTime         time.Time
+		RevisionCreateTime time.Time
+		RevisionID         string
+	})
+	if err := current.Decode(times); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	current.ChangeTime = times.CreateTime
+	if len(revisions) == 0 {
+		return d.SaveSnapshot(ctx, current)
+	}
+	current.ChangeTime = times.RevisionCreateTime
+
+	for _, key := range revisions {
+		if strings.HasSuffix(key, "@"+times.RevisionID) {
+			continue
+		}
+		value, err := d.revision(ctx, kind, key)
+		if isNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		s, err := models.NewSnapshot(kind, projectID, name, value, nil)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		} else if err := s.Decode(times); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		s.ChangeTime = times.RevisionCreateTime
+		if err := d.SaveSnapshot(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	return d.SaveSnapshot(ctx, current)
+}
+
+// revision returns the stored revision of a resource with a revision name.
+func (d *Client) revision(ctx context.Context, kind, name string) (interface{}, error) {
+	switch kind {
+	case gorm.SpecEntityName:
+		n, err := names.ParseSpecRevision(name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return d.GetSpecRevision(ctx, n)
+	case gorm.DeploymentEntityName:
+		n, err := names.ParseDeploymentRevision(name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return d.GetDeploymentRevision(ctx, n)
+	case gorm.ArtifactEntityName:
+		n, err := names.ParseArtifact(name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return d.GetArtifactRevision(ctx, n)
+	}
+	return nil, status.Errorf(codes.Internal, "%s has no revisions", kind)
+}
+
+// parseChanged returns the kind, project ID, name, and revision of a
+// changed resource.
+func parseChanged(resource string) (kind, projectID, name, revision string, err error) {
+	if n, err := names.ParseProject(resource); err == nil {
+		return gorm.ProjectEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseApi(resource); err == nil {
+		return gorm.ApiEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseVersion(resource); err == nil {
+		return gorm.VersionEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseSpec(resource); err == nil {
+		return gorm.SpecEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseSpecRevision(resource); err == nil {
+		return gorm.SpecEntityName, n.ProjectID, n.Spec().String(), n.RevisionID, nil
+	} else if n, err := names.ParseDeployment(resource); err == nil {
+		return gorm.DeploymentEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseDeploymentRevision(resource); err == nil {
+		return gorm.DeploymentEntityName, n.ProjectID, n.Deployment().String(), n.RevisionID, nil
+	} else if n, err := names.ParseArtifact(resource); err == nil {
+		return gorm.ArtifactEntityName, n.ProjectID(), n.Revision("").String(), n.RevisionID(), nil
+	} else if n, err := names.ParseArtifactType(resource); err == nil {
+		return gorm.ArtifactTypeEntityName, n.ProjectID, n.String(), "", nil
+	} else if n, err := names.ParseRelationship(resource); err == nil {
+		return gorm.RelationshipEntityName, n.ProjectID, n.String(), "", nil
+	}
+
+	return "", "", "", "", fmt.Errorf("unsupported resource %q", resource)
+}
+
+// snapshot returns a snapshot of the current state of a resource.
+func (d *Client) snapshot(ctx context.Context, kind, projectID, name string) (*models.Snapshot, error) {
+	var (
+		value interface{}
+		tags  = make(map[string]string)
+		err   error
+	)
+
+	switch kind {
+	case gorm.ProjectEntityName:
+		n, _ := names.ParseProject(name)
+		value, err = d.GetProject(ctx, n)
+	case gorm.ApiEntityName:
+		n, _ := names.ParseApi(name)
+		value, err = d.GetApi(ctx, n)
+	case gorm.VersionEntityName:
+		n, _ := names.ParseVersion(name)
+		value, err = d.GetVersion(ctx, n)
+	case gorm.SpecEntityName:
+		n, _ := names.ParseSpec(name)
+		if value, err = d.GetSpec(ctx, n); err == nil {
+			var revisionTags []*models.SpecRevisionTag
+			revisionTags, err = d.GetSpecTags(ctx, n)
+			for _, t := range revisionTags {
+				tags[t.Tag] = t.RevisionID
+			}
+		}
+	case gorm.DeploymentEntityName:
+		n, _ := names.ParseDeployment(name)
+		if value, err = d.GetDeployment(ctx, n); err == nil {
+			var revisionTags []*models.DeploymentRevisionTag
+			revisionTags, err = d.GetDeploymentTags(ctx, n)
+			for _, t := range revisionTags {
+				tags[t.Tag] = t.RevisionID
+			}
+		}
+	case gorm.ArtifactEntityName:
+		n, _ := names.ParseArtifact(name)
+		if value, err = d.GetArtifact(ctx, n); err == nil {
+			var revisionTags []*models.ArtifactRevisionTag
+			revisionTags, err = d.GetArtifactTags(ctx, n)
+			for _, t := range revisionTags {
+				tags[t.Tag] = t.RevisionID
+			}
+		}
+	case gorm.ArtifactTypeEntityName:
+		n, _ := names.ParseArtifactType(name)
+		value, err = d.GetArtifactType(ctx, n)
+	case gorm.RelationshipEntityName:
+		n, _ := names.ParseRelationship(name)
+		value, err = d.GetRelationship(ctx, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := models.NewSnapshot(kind, projectID, name, value, tags)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return snapshot, nil
+}
+
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
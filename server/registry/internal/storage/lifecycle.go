@@ -0,0 +1,76 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SaveLifecycleTransition records a lifecycle transition.
+func (d *Client) SaveLifecycleTransition(ctx context.Context, transition *models.LifecycleTransition) error {
+	k := d.NewKey(gorm.LifecycleTransitionEntityName, transition.String())
+	if _, err := d.Put(ctx, k, transition); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// ListApiTransitions returns the availability transitions of an API, oldest first.
+func (d *Client) ListApiTransitions(ctx context.Context, name names.Api) ([]models.LifecycleTransition, error) {
+	q := d.NewQuery(gorm.LifecycleTransitionEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
+	q = q.Require("ApiID", name.ApiID)
+	q = q.Require("VersionID", "")
+	return d.listTransitions(ctx, q)
+}
+
+// ListVersionTransitions returns the state transitions of a version, oldest first.
+func (d *Client) ListVersionTransitions(ctx context.Context, name names.Version) ([]models.LifecycleTransition, error) {
+	q := d.NewQuery(gorm.LifecycleTransitionEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
+	q = q.Require("ApiID", name.ApiID)
+	q = q.Require("VersionID", name.VersionID)
+	return d.listTransitions(ctx, q)
+}
+
+func (d *Client) listTransitions(ctx context.Context, q *gorm.Query) ([]models.LifecycleTransition, error) {
+	q = q.Ascending("TransitionTime")
+
+	var (
+		transitions = make([]models.LifecycleTransition, 0)
+		transition  = new(models.LifecycleTransition)
+		it          = d.Run(ctx, q)
+		err         error
+	)
+
+	for _, err = it.Next(transition); err == nil; _, err = it.Next(transition) {
+		transitions = append(transitions, *transition)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListLocationIDs returns the IDs of all locations that contain APIs or
+// artifacts of a project, in ascending order.
+func (d *Client) ListLocationIDs(ctx context.Context, parent names.Project) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, kind := range []string{gorm.ApiEntityName, gorm.ArtifactEntityName} {
+		q := d.NewQuery(kind).Require("ProjectID", parent.ProjectID)
+		ids, err := d.Values(ctx, q, "LocationID")
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, id := range ids {
+			seen[id] = true
+		}
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -28,43 +28,57 @@ import (
 
 // Artifact is the storage-side representation of an artifact.
 type Artifact struct {
-	Key          string    `gorm:"primaryKey"`
-	ProjectID    string    // Project associated with artifact (required).
-	ApiID        string    // Api associated with artifact (if appropriate).
-	VersionID    string    // Version associated with artifact (if appropriate).
-	SpecID       string    // Spec associated with artifact (if appropriate).
-	DeploymentID string    // Deployment associated with artifact (if appropriate).
-	ArtifactID   string    // Artifact identifier (required).
-	CreateTime   time.Time // Creation time.
-	UpdateTime   time.Time // Time of last change.
-	MimeType     string    // MIME type of artifact
-	SizeInBytes  int32     // Size of the spec.
-	Hash         string    // A hash of the spec.
+	Key                string    `gorm:"primaryKey"`
+	ProjectID          string    // Project associated with artifact (required).
+	LocationID         string    `gorm:"default:global"` // Uniquely identifies a location within a project.
+	ApiID              string    // Api associated with artifact (if appropriate).
+	VersionID          string    // Version associated with artifact (if appropriate).
+	SpecID             string    // Spec associated with artifact (if appropriate).
+	DeploymentID       string    // Deployment associated with artifact (if appropriate).
+	ArtifactID         string    // Artifact identifier (required).
+	RevisionID         string    // Uniquely identifies a revision of an artifact.
+	CreateTime         time.Time // Creation time.
+	RevisionCreateTime time.Time // Revision creation time.
+	RevisionUpdateTime time.Time // Time of last change.
+	MimeType           string    // MIME type of artifact
+	SizeInBytes        int32     // Size of the spec.
+	Hash               string    // A hash of the spec.
+	Labels             []byte    // Serialized labels.
+	Annotations        []byte    // Serialized annotations.
 }
 
 // NewArtifact initializes a new resource.
 func NewArtifact(name names.Artifact, body *rpc.Artifact) (artifact *Artifact, err error) {
 	now := time.Now().Round(time.Microsecond)
 	artifact = &Artifact{
-		ProjectID:    name.ProjectID(),
-		ApiID:        name.ApiID(),
-		VersionID:    name.VersionID(),
-		SpecID:       name.SpecID(),
-		DeploymentID: name.DeploymentID(),
-		ArtifactID:   name.ArtifactID(),
-		CreateTime:   now,
-		UpdateTime:   now,
-		MimeType:     body.GetMimeType(),
+		ProjectID:          name.ProjectID(),
+		LocationID:         name.LocationID(),
+		ApiID:              name.ApiID(),
+		VersionID:          name.VersionID(),
+		SpecID:             name.SpecID(),
+		DeploymentID:       name.DeploymentID(),
+		ArtifactID:         name.ArtifactID(),
+		RevisionID:         NewRevisionID(),
+		CreateTime:         now,
+		RevisionCreateTime: now,
+		RevisionUpdateTime: now,
+		MimeType:           body.GetMimeType(),
+	}
+
+	artifact.Labels, err = bytesForMap(body.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	artifact.Annotations, err = bytesForMap(body.GetAnnotations())
+	if err != nil {
+		return nil, err
 	}
 
 	if body.GetContents() != nil {
-		contents := body.GetContents()
-		// if contents are gzipped, uncompress before computing size and hash.
-		if strings.Contains(artifact.MimeType, "+gzip") && len(contents) > 0 {
-			contents, err = GUnzippedBytes(contents)
-			if err != nil {
-				return nil, status.Error(codes.InvalidArgument, err.Error())
-			}
+		contents, err := artifact.uncompressed(body.GetContents())
+		if err != nil {
+			return nil, err
 		}
 		artifact.SizeInBytes = int32(len(contents))
 		artifact.Hash = hashForBytes(contents)
@@ -73,37 +87,199 @@ func NewArtifact(name names.Artifact, body *rpc.Artifact) (artifact *Artifact, e
 	return artifact, nil
 }
 
+// NewRevision returns a new revision based on the artifact.
+func (artifact *Artifact) NewRevision() *Artifact {
+	now := time.Now().Round(time.Microsecond)
+	return &Artifact{
+		ProjectID:          artifact.ProjectID,
+		LocationID:         artifact.LocationID,
+		ApiID:              artifact.ApiID,
+		VersionID:          artifact.VersionID,
+		SpecID:             artifact.SpecID,
+		DeploymentID:       artifact.DeploymentID,
+		ArtifactID:         artifact.ArtifactID,
+		RevisionID:         NewRevisionID(),
+		CreateTime:         artifact.CreateTime,
+		RevisionCreateTime: now,
+		RevisionUpdateTime: now,
+		MimeType:           artifact.MimeType,
+		SizeInBytes:        artifact.SizeInBytes,
+		Hash:               artifact.Hash,
+		Labels:             artifact.Labels,
+		Annotations:        artifact.Annotations,
+	}
+}
+
+// Update replaces the contents of an artifact using the contents of a message.
+// A new revision is started if the contents have changed.
+func (artifact *Artifact) Update(body *rpc.Artifact) error {
+	now := time.Now().Round(time.Microsecond)
+	artifact.RevisionUpdateTime = now
+	artifact.MimeType = body.GetMimeType()
+
+	var err error
+	if artifact.Labels, err = bytesForMap(body.GetLabels()); err != nil {
+		return err
+	}
+	if artifact.Annotations, err = bytesForMap(body.GetAnnotations()); err != nil {
+		return err
+	}
+
+	contents, err := artifact.uncompressed(body.GetContents())
+	if err != nil {
+		return err
+	}
+
+	if hash := hashForBytes(contents); hash != artifact.Hash {
+		artifact.Hash = hash
+		artifact.SizeInBytes = int32(len(contents))
+		artifact.RevisionID = NewRevisionID()
+		artifact.RevisionCreateTime = now
+	}
+
+	return nil
+}
+
+// uncompressed returns the contents of an artifact, uncompressing them if they are gzipped.
+func (artifact *Artifact) uncompressed(contents []byte) ([]byte, error) {
+	if strings.Contains(artifact.MimeType, "+gzip") && len(contents) > 0 {
+		contents, err := GUnzippedBytes(contents)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return contents, nil
+	}
+	return contents, nil
+}
+
+// Decode parses the contents of an artifact using the schema of its type.
+func (artifact *Artifact) Decode(t *ArtifactType, contents []byte) (interface{}, error) {
+	contents, err := artifact.uncompressed(contents)
+	if err != nil {
+		return nil, err
+	}
+	return t.Decode(contents)
+}
+
 // Name returns the resource name of the artifact.
 func (artifact *Artifact) Name() string {
 	switch {
 	case artifact.SpecID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.VersionID, artifact.SpecID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.VersionID, artifact.SpecID, artifact.ArtifactID)
 	case artifact.VersionID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.VersionID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.VersionID, artifact.ArtifactID)
 	case artifact.DeploymentID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/deployments/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.DeploymentID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.DeploymentID, artifact.ArtifactID)
 	case artifact.ApiID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/apis/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ApiID, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ApiID, artifact.ArtifactID)
 	case artifact.ProjectID != "":
 		return fmt.Sprintf("projects/%s/locations/%s/artifacts/%s",
-			artifact.ProjectID, names.Location, artifact.ArtifactID)
+			artifact.ProjectID, artifact.LocationID, artifact.ArtifactID)
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// RevisionName generates the resource name of the artifact revision.
+func (artifact *Artifact) RevisionName() string {
+	return fmt.Sprintf("%s@%s", artifact.Name(), artifact.RevisionID)
+}
+
 // Message returns an RPC message representing the artifact.
-func (artifact *Artifact) Message() *rpc.Artifact {
-	return &rpc.Artifact{
-		Name:       artifact.Name(),
-		MimeType:   artifact.MimeType,
-		SizeBytes:  artifact.SizeInBytes,
-		Hash:       artifact.Hash,
-		CreateTime: timestamppb.New(artifact.CreateTime),
-		UpdateTime: timestamppb.New(artifact.UpdateTime),
+func (artifact *Artifact) Message(name string, tags []string) (message *rpc.Artifact, err error) {
+	message = &rpc.Artifact{
+		Name:               name,
+		MimeType:           artifact.MimeType,
+		SizeBytes:          artifact.SizeInBytes,
+		Hash:               artifact.Hash,
+		RevisionId:         artifact.RevisionID,
+		RevisionTags:       tags,
+		CreateTime:         timestamppb.New(artifact.CreateTime),
+		UpdateTime:         timestamppb.New(artifact.RevisionUpdateTime),
+		RevisionCreateTime: timestamppb.New(artifact.RevisionCreateTime),
+		RevisionUpdateTime: timestamppb.New(artifact.RevisionUpdateTime),
+	}
+
+	message.Labels, err = mapForBytes(artifact.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Annotations, err = mapForBytes(artifact.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// LabelsMap returns a map representation of stored labels.
+func (artifact *Artifact) LabelsMap() (map[string]string, error) {
+	return mapForBytes(artifact.Labels)
+}
+
+// ArtifactRevisionTag is the storage-side representation of an artifact revision tag.
+type ArtifactRevisionTag struct {
+	Key          string    `gorm:"primaryKey"`
+	ProjectID    string    // Project associated with artifact (required).
+	LocationID   string    `gorm:"default:global"` // Uniquely identifies a location within a project.
+	ApiID        string    // Api associated with artifact (if appropriate).
+	VersionID    string    // Version associated with artifact (if appropriate).
+	SpecID       string    // Spec associated with artifact (if appropriate).
+	DeploymentID string    // Deployment associated with artifact (if appropriate).
+	ArtifactID   string    // Artifact identifier (required).
+	RevisionID   string    // Uniquely identifies a revision of an artifact.
+	Tag          string    // The tag to use for the revision.
+	CreateTime   time.Time // Creation time.
+	UpdateTime   time.Time // Time of last change.
+}
+
+// NewArtifactRevisionTag initializes a new revision tag from a given revision name and tag string.
+func NewArtifactRevisionTag(name names.Artifact, tag string) *ArtifactRevisionTag {
+	now := time.Now().Round(time.Microsecond)
+	return &ArtifactRevisionTag{
+		ProjectID:    name.ProjectID(),
+		LocationID:   name.LocationID(),
+		ApiID:        name.ApiID(),
+		VersionID:    name.VersionID(),
+		SpecID:       name.SpecID(),
+		DeploymentID: name.DeploymentID(),
+		ArtifactID:   name.ArtifactID(),
+		RevisionID:   name.RevisionID(),
+		Tag:          tag,
+		CreateTime:   now,
+		UpdateTime:   now,
+	}
+}
+
+// RevisionName returns the name of the tagged artifact revision.
+func (t *ArtifactRevisionTag) RevisionName() string {
+	a := Artifact{
+		ProjectID:    t.ProjectID,
+		LocationID:   t.LocationID,
+		ApiID:        t.ApiID,
+		VersionID:    t.VersionID,
+		SpecID:       t.SpecID,
+		DeploymentID: t.DeploymentID,
+		ArtifactID:   t.ArtifactID,
+		RevisionID:   t.RevisionID,
+	}
+	return a.RevisionName()
+}
+
+func (t *ArtifactRevisionTag) String() string {
+	a := Artifact{
+		ProjectID:    t.ProjectID,
+		LocationID:   t.LocationID,
+		ApiID:        t.ApiID,
+		VersionID:    t.VersionID,
+		SpecID:       t.SpecID,
+		DeploymentID: t.DeploymentID,
+		ArtifactID:   t.ArtifactID,
 	}
+	return fmt.Sprintf("%s@%s", a.Name(), t.Tag)
 }
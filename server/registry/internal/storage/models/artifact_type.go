@@ -0,0 +1,221 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ArtifactType is the storage-side representation of an artifact type.
+type ArtifactType struct {
+	Key               string    `gorm:"primaryKey"`
+	ProjectID         string    // Uniquely identifies a project.
+	LocationID        string    `gorm:"default:global"` // Uniquely identifies a location within a project.
+	ArtifactTypeID    string    // Uniquely identifies an artifact type within a location.
+	Description       string    // A detailed description.
+	CreateTime        time.Time // Creation time.
+	MimeType          string    // Normalized MIME type of artifacts of this type.
+	FileDescriptorSet []byte    // Serialized descriptors of Protocol Buffer types.
+	JSONSchema        string    // JSON Schema of JSON types.
+}
+
+// NewArtifactType initializes a new resource.
+// It returns an error if the message does not describe a usable schema.
+func NewArtifactType(name names.ArtifactType, body *rpc.ArtifactType) (*ArtifactType, error) {
+	if body.GetMimeType() == "" {
+		return nil, fmt.Errorf("mime_type is required")
+	}
+
+	t := &ArtifactType{
+		ProjectID:         name.ProjectID,
+		LocationID:        name.LocationID,
+		ArtifactTypeID:    name.ArtifactTypeID,
+		Description:       body.GetDescription(),
+		CreateTime:        time.Now().Round(time.Microsecond),
+		MimeType:          ArtifactTypeMimeType(body.GetMimeType()),
+		FileDescriptorSet: body.GetFileDescriptorSet(),
+		JSONSchema:        body.GetJsonSchema(),
+	}
+
+	switch {
+	case len(t.FileDescriptorSet) > 0:
+		if _, err := t.messageType(); err != nil {
+			return nil, err
+		}
+	case t.JSONSchema != "":
+		if _, err := t.schema(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("one of file_descriptor_set or json_schema is required")
+	}
+
+	return t, nil
+}
+
+// ArtifactTypeMimeType returns the form of a MIME type that is used to match artifacts to types.
+// Compression is ignored and parameters are formatted consistently.
+func ArtifactTypeMimeType(mimeType string) string {
+	mimeType = strings.Replace(mimeType, "+gzip", "", 1)
+	mediaType, params, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return strings.TrimSpace(mimeType)
+	}
+	return strings.ReplaceAll(mime.FormatMediaType(mediaType, params), "; ", ";")
+}
+
+// Name returns the resource name of the artifact type.
+func (t *ArtifactType) Name() string {
+	return names.ArtifactType{
+		ProjectID:      t.ProjectID,
+		LocationID:     t.LocationID,
+		ArtifactTypeID: t.ArtifactTypeID,
+	}.String()
+}
+
+// Message returns a message representing an artifact type.
+func (t *ArtifactType) Message() *rpc.ArtifactType {
+	message := &rpc.ArtifactType{
+		Name:        t.Name(),
+		Description: t.Description,
+		CreateTime:  timestamppb.New(t.CreateTime),
+		MimeType:    t.MimeType,
+	}
+
+	if len(t.FileDescriptorSet) > 0 {
+		message.Schema = &rpc.ArtifactType_FileDescriptorSet{FileDescriptorSet: t.FileDescriptorSet}
+	} else {
+		message.Schema = &rpc.ArtifactType_JsonSchema{JsonSchema: t.JSONSchema}
+	}
+
+	return message
+}
+
+// Decode parses uncompressed artifact contents and returns their JSON representation
+// as generic Go values. It returns an error if the contents don't match the type's schema.
+func (t *ArtifactType) Decode(contents []byte) (interface{}, error) {
+	var data []byte
+	if len(t.FileDescriptorSet) > 0 {
+		messageType, err := t.messageType()
+		if err != nil {
+			return nil, err
+		}
+
+		m := dynamicpb.NewMessage(messageType)
+		if err := proto.Unmarshal(contents, m); err != nil {
+			return nil, err
+		} else if unknown := m.GetUnknown(); len(unknown) > 0 {
+			return nil, fmt.Errorf("contents include fields that are not defined in %s", messageType.FullName())
+		}
+
+		data, err = protojson.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		data = contents
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	if t.JSONSchema != "" {
+		schema, err := t.schema()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := schema.Validate(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// messageType returns the descriptor of the Protocol Buffer message named by the type's MIME type.
+func (t *ArtifactType) messageType() (protoreflect.MessageDescriptor, error) {
+	_, params, err := mime.ParseMediaType(t.MimeType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mime_type %q: %s", t.MimeType, err)
+	} else if params["type"] == "" {
+		return nil, fmt.Errorf("invalid mime_type %q: Protocol Buffer types must specify a type parameter", t.MimeType)
+	}
+
+	fds := new(descriptorpb.FileDescriptorSet)
+	if err := proto.Unmarshal(t.FileDescriptorSet, fds); err != nil {
+		return nil, fmt.Errorf("invalid file_descriptor_set: %s", err)
+	}
+
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file_descriptor_set: %s", err)
+	}
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(params["type"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid file_descriptor_set: message %q not found", params["type"])
+	}
+
+	messageType, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("invalid file_descriptor_set: %q is not a message", params["type"])
+	}
+
+	return messageType, nil
+}
+
+// schemaURL identifies the JSON Schema of an artifact type while it is compiled.
+const schemaURL = "artifact-type.json"
+
+// schema returns the compiled JSON Schema of the type. Schemas use draft
+// 2020-12 unless their $schema keyword names another draft, and can only
+// refer to their own definitions.
+func (t *ArtifactType) schema() (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("references to other documents are not supported: %s", url)
+	}
+
+	if err := compiler.AddResource(schemaURL, strings.NewReader(t.JSONSchema)); err != nil {
+		return nil, fmt.Errorf("invalid json_schema: %s", err)
+	}
+
+	schema, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json_schema: %s", err)
+	}
+
+	return schema, nil
+}
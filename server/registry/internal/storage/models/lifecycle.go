@@ -0,0 +1,103 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LifecycleTransition is the storage-side representation of a change of an
+// API's availability or an API version's state.
+type LifecycleTransition struct {
+	Key            string    `gorm:"primaryKey"`
+	ProjectID      string    // Uniquely identifies a project.
+	LocationID     string    `gorm:"default:global"` // Uniquely identifies a location within a project.
+	ApiID          string    // Uniquely identifies an api within a project.
+	VersionID      string    // Uniquely identifies a version within an api (empty for API transitions).
+	FromState      string    // The state before the transition.
+	ToState        string    // The state after the transition.
+	TransitionTime time.Time // Time of the transition.
+}
+
+// NewApiTransition initializes a new transition of an API's availability.
+func NewApiTransition(name names.Api, from, to string) *LifecycleTransition {
+	return &LifecycleTransition{
+		ProjectID:      name.ProjectID,
+		LocationID:     name.LocationID,
+		ApiID:          name.ApiID,
+		FromState:      from,
+		ToState:        to,
+		TransitionTime: time.Now().Round(time.Microsecond),
+	}
+}
+
+// NewVersionTransition initializes a new transition of a version's state.
+func NewVersionTransition(name names.Version, from, to string) *LifecycleTransition {
+	return &LifecycleTransition{
+		ProjectID:      name.ProjectID,
+		LocationID:     name.LocationID,
+		ApiID:          name.ApiID,
+		VersionID:      name.VersionID,
+		FromState:      from,
+		ToState:        to,
+		TransitionTime: time.Now().Round(time.Microsecond),
+	}
+}
+
+// String returns a unique key for the transition.
+func (t *LifecycleTransition) String() string {
+	name := names.Api{
+		ProjectID:  t.ProjectID,
+		LocationID: t.LocationID,
+		ApiID:      t.ApiID,
+	}.String()
+	if t.VersionID != "" {
+		name += "/versions/" + t.VersionID
+	}
+	return fmt.Sprintf("%s/transitions/%d", name, t.TransitionTime.UnixNano())
+}
+
+// Message returns a message representing a transition.
+func (t *LifecycleTransition) Message() *rpc.LifecycleTransition {
+	return &rpc.LifecycleTransition{
+		FromState:      t.FromState,
+		ToState:        t.ToState,
+		TransitionTime: timestamppb.New(t.TransitionTime),
+	}
+}
+
+func bytesForLifecycle(lifecycle *rpc.Lifecycle) ([]byte, error) {
+	if len(lifecycle.GetStates()) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(lifecycle)
+}
+
+func lifecycleForBytes(b []byte) (*rpc.Lifecycle, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	m := &rpc.Lifecycle{}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -31,18 +32,41 @@ type Project struct {
 	Description string    // A detailed description.
 	CreateTime  time.Time // Creation time.
 	UpdateTime  time.Time // Time of last change.
+	// Serialized retention policies of the project.
+	RetentionPolicies []byte
+	// Serialized lifecycles of API availability and version state.
+	ApiLifecycle     []byte
+	VersionLifecycle []byte
 }
 
 // NewProject initializes a new resource.
-func NewProject(name names.Project, body *rpc.Project) *Project {
+func NewProject(name names.Project, body *rpc.Project) (*Project, error) {
 	now := time.Now().Round(time.Microsecond)
-	return &Project{
+	project := &Project{
 		ProjectID:   name.ProjectID,
 		Description: body.GetDescription(),
 		DisplayName: body.GetDisplayName(),
 		CreateTime:  now,
 		UpdateTime:  now,
 	}
+
+	var err error
+	project.RetentionPolicies, err = bytesForRetentionPolicies(body.GetRetentionPolicies())
+	if err != nil {
+		return nil, err
+	}
+
+	project.ApiLifecycle, err = bytesForLifecycle(body.GetApiLifecycle())
+	if err != nil {
+		return nil, err
+	}
+
+	project.VersionLifecycle, err = bytesForLifecycle(body.GetVersionLifecycle())
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
 }
 
 // Name returns the resource name of the project.
@@ -53,18 +77,36 @@ func (p *Project) Name() string {
 }
 
 // Message returns a message representing a project.
-func (p *Project) Message() *rpc.Project {
-	return &rpc.Project{
-		Name:        p.Name(),
-		DisplayName: p.DisplayName,
-		Description: p.Description,
-		CreateTime:  timestamppb.New(p.CreateTime),
-		UpdateTime:  timestamppb.New(p.UpdateTime),
+func (p *Project) Message() (*rpc.Project, error) {
+	policies, err := p.Policies()
+	if err != nil {
+		return nil, err
+	}
+
+	apiLifecycle, err := p.ApiLifecycleMessage()
+	if err != nil {
+		return nil, err
 	}
+
+	versionLifecycle, err := p.VersionLifecycleMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.Project{
+		Name:              p.Name(),
+		DisplayName:       p.DisplayName,
+		Description:       p.Description,
+		CreateTime:        timestamppb.New(p.CreateTime),
+		UpdateTime:        timestamppb.New(p.UpdateTime),
+		RetentionPolicies: policies,
+		ApiLifecycle:      apiLifecycle,
+		VersionLifecycle:  versionLifecycle,
+	}, nil
 }
 
 // Update modifies a project using the contents of a message.
-func (p *Project) Update(message *rpc.Project, mask *fieldmaskpb.FieldMask) {
+func (p *Project) Update(message *rpc.Project, mask *fieldmaskpb.FieldMask) error {
 	p.UpdateTime = time.Now().Round(time.Microsecond)
 	for _, field := range mask.GetPaths() {
 		switch field {
@@ -72,6 +114,55 @@ func (p *Project) Update(message *rpc.Project, mask *fieldmaskpb.FieldMask) {
 			p.DisplayName = message.GetDisplayName()
 		case "description":
 			p.Description = message.GetDescription()
+		case "retention_policies":
+			var err error
+			if p.RetentionPolicies, err = bytesForRetentionPolicies(message.GetRetentionPolicies()); err != nil {
+				return err
+			}
+		case "api_lifecycle":
+			var err error
+			if p.ApiLifecycle, err = bytesForLifecycle(message.GetApiLifecycle()); err != nil {
+				return err
+			}
+		case "version_lifecycle":
+			var err error
+			if p.VersionLifecycle, err = bytesForLifecycle(message.GetVersionLifecycle()); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
+}
+
+// Policies returns the retention policies of the project.
+func (p *Project) Policies() ([]*rpc.RetentionPolicy, error) {
+	return retentionPoliciesForBytes(p.RetentionPolicies)
+}
+
+// ApiLifecycleMessage returns the lifecycle of API availability, or nil if
+// availability is free-form.
+func (p *Project) ApiLifecycleMessage() (*rpc.Lifecycle, error) {
+	return lifecycleForBytes(p.ApiLifecycle)
+}
+
+// VersionLifecycleMessage returns the lifecycle of version state, or nil if
+// state is free-form.
+func (p *Project) VersionLifecycleMessage() (*rpc.Lifecycle, error) {
+	return lifecycleForBytes(p.VersionLifecycle)
+}
+
+func bytesForRetentionPolicies(policies []*rpc.RetentionPolicy) ([]byte, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&rpc.RetentionPolicies{Policies: policies})
+}
+
+func retentionPoliciesForBytes(b []byte) ([]*rpc.RetentionPolicy, error) {
+	m := &rpc.RetentionPolicies{}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m.Policies, nil
 }
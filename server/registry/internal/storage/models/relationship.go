@@ -0,0 +1,135 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Relationship is the storage-side representation of a relationship.
+type Relationship struct {
+	Key            string    `gorm:"primaryKey"`
+	ProjectID      string    // Uniquely identifies a project.
+	LocationID     string    `gorm:"default:global"` // Uniquely identifies a location within a project.
+	ApiID          string    // Uniquely identifies an api within a project.
+	RelationshipID string    // Uniquely identifies a relationship within an api.
+	Source         string    // The resource that the relationship is from.
+	Target         string    // The resource that the relationship is to.
+	Kind           string    // The kind of relationship.
+	Description    string    // A detailed description.
+	CreateTime     time.Time // Creation time.
+	UpdateTime     time.Time // Time of last change.
+	Labels         []byte    // Serialized labels.
+}
+
+// NewRelationship initializes a new resource.
+func NewRelationship(name names.Relationship, body *rpc.Relationship) (relationship *Relationship, err error) {
+	now := time.Now().Round(time.Microsecond)
+	relationship = &Relationship{
+		ProjectID:      name.ProjectID,
+		LocationID:     name.LocationID,
+		ApiID:          name.ApiID,
+		RelationshipID: name.RelationshipID,
+		Source:         body.GetSource(),
+		Target:         body.GetTarget(),
+		Kind:           body.GetKind(),
+		Description:    body.GetDescription(),
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+
+	relationship.Labels, err = bytesForMap(body.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	return relationship, nil
+}
+
+// Name returns the resource name of the relationship.
+func (r *Relationship) Name() string {
+	return names.Relationship{
+		ProjectID:      r.ProjectID,
+		LocationID:     r.LocationID,
+		ApiID:          r.ApiID,
+		RelationshipID: r.RelationshipID,
+	}.String()
+}
+
+// SourceName returns the name of the resource that the relationship is from.
+func (r *Relationship) SourceName() string {
+	if r.Source != "" {
+		return r.Source
+	}
+	return names.Api{
+		ProjectID:  r.ProjectID,
+		LocationID: r.LocationID,
+		ApiID:      r.ApiID,
+	}.String()
+}
+
+// Message returns a message representing a relationship.
+func (r *Relationship) Message() (message *rpc.Relationship, err error) {
+	message = &rpc.Relationship{
+		Name:        r.Name(),
+		Source:      r.SourceName(),
+		Target:      r.Target,
+		Kind:        r.Kind,
+		Description: r.Description,
+		CreateTime:  timestamppb.New(r.CreateTime),
+		UpdateTime:  timestamppb.New(r.UpdateTime),
+	}
+
+	message.Labels, err = r.LabelsMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// Update modifies a relationship using the contents of a message.
+func (r *Relationship) Update(message *rpc.Relationship, mask *fieldmaskpb.FieldMask) error {
+	r.UpdateTime = time.Now().Round(time.Microsecond)
+	for _, field := range mask.Paths {
+		switch field {
+		case "source":
+			r.Source = message.GetSource()
+		case "target":
+			r.Target = message.GetTarget()
+		case "kind":
+			r.Kind = message.GetKind()
+		case "description":
+			r.Description = message.GetDescription()
+		case "labels":
+			var err error
+			if r.Labels, err = bytesForMap(message.GetLabels()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LabelsMap returns a map representation of stored labels.
+func (r *Relationship) LabelsMap() (map[string]string, error) {
+	return mapForBytes(r.Labels)
+}
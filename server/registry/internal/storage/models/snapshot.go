@@ -0,0 +1,88 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Snapshot is the storage-side record of the state of a resource after a
+// change. Snapshots are kept for every change so that resources can be read
+// as they were at earlier times.
+type Snapshot struct {
+	Key        string    `gorm:"primaryKey"`
+	ProjectID  string    // Uniquely identifies a project.
+	Kind       string    // The storage entity name of the resource.
+	Name       string    // The name of the resource; revision names record deleted revisions.
+	ChangeTime time.Time // Time of the change.
+	Deleted    bool      // True if the change deleted the resource.
+	Value      []byte    // JSON encoding of the stored resource.
+	Tags       []byte    // JSON encoding of revision tags and the revision IDs they name.
+}
+
+// NewSnapshot initializes a record of the state of a resource.
+func NewSnapshot(kind, projectID, name string, value interface{}, tags map[string]string) (*Snapshot, error) {
+	s := newSnapshot(kind, projectID, name)
+
+	var err error
+	if s.Value, err = json.Marshal(value); err != nil {
+		return nil, err
+	}
+
+	if len(tags) > 0 {
+		if s.Tags, err = json.Marshal(tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// NewDeletion initializes a record of the deletion of a resource.
+func NewDeletion(kind, projectID, name string) *Snapshot {
+	s := newSnapshot(kind, projectID, name)
+	s.Deleted = true
+	return s
+}
+
+func newSnapshot(kind, projectID, name string) *Snapshot {
+	return &Snapshot{
+		ProjectID:  projectID,
+		Kind:       kind,
+		Name:       name,
+		ChangeTime: time.Now().Round(time.Microsecond),
+	}
+}
+
+// String returns the key of the snapshot.
+func (s *Snapshot) String() string {
+	return fmt.Sprintf("%s/changes/%d", s.Name, s.ChangeTime.UnixNano())
+}
+
+// Decode stores the recorded resource in the value pointed to by v.
+func (s *Snapshot) Decode(v interface{}) error {
+	return json.Unmarshal(s.Value, v)
+}
+
+// TagsMap returns the recorded revision tags and the revision IDs they name.
+func (s *Snapshot) TagsMap() (map[string]string, error) {
+	tags := make(map[string]string)
+	if len(s.Tags) == 0 {
+		return tags, nil
+	}
+	return tags, json.Unmarshal(s.Tags, &tags)
+}
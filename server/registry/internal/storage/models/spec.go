@@ -17,10 +17,10 @@ package models
 import (
 	"crypto/sha256"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/mimetypes"
 	"github.com/apigee/registry/server/registry/names"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -33,6 +33,7 @@ import (
 type Spec struct {
 	Key                string    `gorm:"primaryKey"`
 	ProjectID          string    // Uniquely identifies a project.
+	LocationID         string    `gorm:"default:global"` // Uniquely identifies a location within a project.
 	ApiID              string    // Uniquely identifies an api within a project.
 	VersionID          string    // Uniquely identifies a version within a api.
 	SpecID             string    // Uniquely identifies a spec within a version.
@@ -55,6 +56,7 @@ func NewSpec(name names.Spec, body *rpc.ApiSpec) (spec *Spec, err error) {
 	now := time.Now().Round(time.Microsecond)
 	spec = &Spec{
 		ProjectID:          name.ProjectID,
+		LocationID:         name.LocationID,
 		ApiID:              name.ApiID,
 		VersionID:          name.VersionID,
 		SpecID:             name.SpecID,
@@ -65,7 +67,7 @@ func NewSpec(name names.Spec, body *rpc.ApiSpec) (spec *Spec, err error) {
 		CreateTime:         now,
 		RevisionCreateTime: now,
 		RevisionUpdateTime: now,
-		RevisionID:         newRevisionID(),
+		RevisionID:         NewRevisionID(),
 	}
 
 	spec.Labels, err = bytesForMap(body.GetLabels())
@@ -81,7 +83,7 @@ func NewSpec(name names.Spec, body *rpc.ApiSpec) (spec *Spec, err error) {
 	if body.GetContents() != nil {
 		contents := body.GetContents()
 		// if contents are gzipped, uncompress before computing size and hash.
-		if strings.Contains(spec.MimeType, "+gzip") && len(contents) > 0 {
+		if mimetypes.IsCompressed(spec.MimeType, mimetypes.GZip) && len(contents) > 0 {
 			contents, err = GUnzippedBytes(contents)
 			if err != nil {
 				return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -99,6 +101,7 @@ func (s *Spec) NewRevision() *Spec {
 	now := time.Now().Round(time.Microsecond)
 	return &Spec{
 		ProjectID:          s.ProjectID,
+		LocationID:         s.LocationID,
 		ApiID:              s.ApiID,
 		VersionID:          s.VersionID,
 		SpecID:             s.SpecID,
@@ -111,24 +114,25 @@ func (s *Spec) NewRevision() *Spec {
 		CreateTime:         s.CreateTime,
 		RevisionCreateTime: now,
 		RevisionUpdateTime: now,
-		RevisionID:         newRevisionID(),
+		RevisionID:         NewRevisionID(),
 	}
 }
 
 // Name returns the resource name of the spec.
 func (s *Spec) Name() string {
 	return names.Spec{
-		ProjectID: s.ProjectID,
-		ApiID:     s.ApiID,
-		VersionID: s.VersionID,
-		SpecID:    s.SpecID,
+		ProjectID:  s.ProjectID,
+		LocationID: s.LocationID,
+		ApiID:      s.ApiID,
+		VersionID:  s.VersionID,
+		SpecID:     s.SpecID,
 	}.String()
 }
 
 // RevisionName generates the resource name of the spec revision.
 func (s *Spec) RevisionName() string {
 	return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s",
-		s.ProjectID, names.Location, s.ApiID, s.VersionID, s.SpecID, s.RevisionID)
+		s.ProjectID, s.LocationID, s.ApiID, s.VersionID, s.SpecID, s.RevisionID)
 }
 
 // BasicMessage returns the basic view of the spec resource as an RPC message.
@@ -164,6 +168,13 @@ func (s *Spec) BasicMessage(name string, tags []string) (message *rpc.ApiSpec, e
 // Update modifies a spec using the contents of a message.
 func (s *Spec) Update(message *rpc.ApiSpec, mask *fieldmaskpb.FieldMask) error {
 	s.RevisionUpdateTime = time.Now().Round(time.Microsecond)
+	// The MIME type is updated first because it determines how new contents are read.
+	for _, field := range mask.Paths {
+		if field == "mime_type" {
+			s.MimeType = message.GetMimeType()
+		}
+	}
+
 	for _, field := range mask.Paths {
 		switch field {
 		case "filename":
@@ -173,7 +184,7 @@ func (s *Spec) Update(message *rpc.ApiSpec, mask *fieldmaskpb.FieldMask) error {
 		case "contents":
 			contents := message.GetContents()
 			// if contents are gzipped, uncompress before computing size and hash.
-			if strings.Contains(s.MimeType, "+gzip") && len(contents) > 0 {
+			if mimetypes.IsCompressed(s.MimeType, mimetypes.GZip) && len(contents) > 0 {
 				var err error
 				contents, err = GUnzippedBytes(contents)
 				if err != nil {
@@ -181,8 +192,6 @@ func (s *Spec) Update(message *rpc.ApiSpec, mask *fieldmaskpb.FieldMask) error {
 				}
 			}
 			s.updateContents(contents)
-		case "mime_type":
-			s.MimeType = message.GetMimeType()
 		case "source_uri":
 			s.SourceURI = message.GetSourceUri()
 		case "labels":
@@ -204,7 +213,7 @@ func (s *Spec) Update(message *rpc.ApiSpec, mask *fieldmaskpb.FieldMask) error {
 func (s *Spec) updateContents(contents []byte) {
 	if hash := hashForBytes(contents); hash != s.Hash {
 		s.Hash = hash
-		s.RevisionID = newRevisionID()
+		s.RevisionID = NewRevisionID()
 		s.SizeInBytes = int32(len(contents))
 
 		now := time.Now().Round(time.Microsecond)
@@ -218,7 +227,8 @@ func (s *Spec) LabelsMap() (map[string]string, error) {
 	return mapForBytes(s.Labels)
 }
 
-func newRevisionID() string {
+// NewRevisionID returns a new random revision ID.
+func NewRevisionID() string {
 	s := uuid.New().String()
 	return s[len(s)-8:]
 }
@@ -238,6 +248,7 @@ func hashForBytes(b []byte) string {
 type SpecRevisionTag struct {
 	Key        string    `gorm:"primaryKey"`
 	ProjectID  string    // Uniquely identifies a project.
+	LocationID string    `gorm:"default:global"` // Uniquely identifies a location within a project.
 	ApiID      string    // Uniquely identifies an api within a project.
 	VersionID  string    // Uniquely identifies a version within a api.
 	SpecID     string    // Uniquely identifies a spec within a version.
@@ -252,6 +263,7 @@ func NewSpecRevisionTag(name names.SpecRevision, tag string) *SpecRevisionTag {
 	now := time.Now().Round(time.Microsecond)
 	return &SpecRevisionTag{
 		ProjectID:  name.ProjectID,
+		LocationID: name.LocationID,
 		ApiID:      name.ApiID,
 		VersionID:  name.VersionID,
 		SpecID:     name.SpecID,
@@ -264,5 +276,5 @@ func NewSpecRevisionTag(name names.SpecRevision, tag string) *SpecRevisionTag {
 
 func (t *SpecRevisionTag) String() string {
 	return fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s",
-		t.ProjectID, names.Location, t.ApiID, t.VersionID, t.SpecID, t.Tag)
+		t.ProjectID, t.LocationID, t.ApiID, t.VersionID, t.SpecID, t.Tag)
 }
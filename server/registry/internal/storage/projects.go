@@ -136,6 +136,10 @@ func (d *Client) DeleteProject(ctx context.Context, name names.Project) error {
 		gorm.SpecEntityName,
 		gorm.SpecRevisionTagEntityName,
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
+		gorm.ArtifactTypeEntityName,
+		gorm.LifecycleTransitionEntityName,
+		gorm.RelationshipEntityName,
 		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
@@ -0,0 +1,205 @@
+// Copyright 2020 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage/filtering"
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RelationshipList contains a page of relationship resources.
+type RelationshipList struct {
+	Relationships []models.Relationship
+	Token         string
+}
+
+var relationshipFields = []filtering.Field{
+	{Name: "name", Type: filtering.String},
+	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
+	{Name: "api_id", Type: filtering.String},
+	{Name: "relationship_id", Type: filtering.String},
+	{Name: "source", Type: filtering.String},
+	{Name: "target", Type: filtering.String},
+	{Name: "kind", Type: filtering.String},
+	{Name: "description", Type: filtering.String},
+	{Name: "create_time", Type: filtering.Timestamp},
+	{Name: "update_time", Type: filtering.Timestamp},
+	{Name: "labels", Type: filtering.StringMap},
+}
+
+func (d *Client) ListRelationships(ctx context.Context, parent names.Api, opts PageOptions) (RelationshipList, error) {
+	q := d.NewQuery(gorm.RelationshipEntityName)
+
+	token, err := decodeToken(opts.Token)
+	if err != nil {
+		return RelationshipList{}, status.Errorf(codes.InvalidArgument, "invalid page token %q: %s", opts.Token, err.Error())
+	}
+
+	if err := token.ValidateFilter(opts.Filter); err != nil {
+		return RelationshipList{}, status.Errorf(codes.InvalidArgument, "invalid filter %q: %s", opts.Filter, err)
+	} else {
+		token.Filter = opts.Filter
+	}
+
+	q = q.ApplyOffset(token.Offset)
+
+	if parent.ProjectID != "-" {
+		q = q.Require("ProjectID", parent.ProjectID)
+	}
+	if parent.LocationID != "-" {
+		q = q.Require("LocationID", parent.LocationID)
+	}
+	if parent.ApiID != "-" {
+		q = q.Require("ApiID", parent.ApiID)
+	}
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
+		if _, err := d.GetApi(ctx, parent); err != nil {
+			return RelationshipList{}, err
+		}
+	} else if parent.ProjectID != "-" {
+		if _, err := d.GetProject(ctx, parent.Project()); err != nil {
+			return RelationshipList{}, err
+		}
+	}
+
+	filter, err := filtering.NewFilter(opts.Filter, relationshipFields)
+	if err != nil {
+		return RelationshipList{}, err
+	}
+
+	it := d.Run(ctx, q)
+	response := RelationshipList{
+		Relationships: make([]models.Relationship, 0, opts.Size),
+	}
+
+	relationship := new(models.Relationship)
+	for _, err = it.Next(relationship); err == nil; _, err = it.Next(relationship) {
+		relationshipMap, err := relationshipMap(*relationship)
+		if err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+
+		match, err := filter.Matches(relationshipMap)
+		if err != nil {
+			return response, err
+		} else if !match {
+			token.Offset++
+			continue
+		} else if len(response.Relationships) == int(opts.Size) {
+			break
+		}
+
+		response.Relationships = append(response.Relationships, *relationship)
+		token.Offset++
+	}
+	if err != nil && err != iterator.Done {
+		return response, status.Error(codes.Internal, err.Error())
+	}
+
+	if err == nil {
+		response.Token, err = encodeToken(token)
+		if err != nil {
+			return response, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
+func relationshipMap(relationship models.Relationship) (map[string]interface{}, error) {
+	labels, err := relationship.LabelsMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"name":            relationship.Name(),
+		"project_id":      relationship.ProjectID,
+		"location_id":     relationship.LocationID,
+		"api_id":          relationship.ApiID,
+		"relationship_id": relationship.RelationshipID,
+		"source":          relationship.SourceName(),
+		"target":          relationship.Target,
+		"kind":            relationship.Kind,
+		"description":     relationship.Description,
+		"create_time":     relationship.CreateTime,
+		"update_time":     relationship.UpdateTime,
+		"labels":          labels,
+	}, nil
+}
+
+func (d *Client) GetRelationship(ctx context.Context, name names.Relationship) (*models.Relationship, error) {
+	relationship := new(models.Relationship)
+	k := d.NewKey(gorm.RelationshipEntityName, name.String())
+	if err := d.Get(ctx, k, relationship); d.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "relationship %q not found in database", name)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return relationship, nil
+}
+
+func (d *Client) SaveRelationship(ctx context.Context, relationship *models.Relationship) error {
+	k := d.NewKey(gorm.RelationshipEntityName, relationship.Name())
+	if _, err := d.Put(ctx, k, relationship); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+func (d *Client) DeleteRelationship(ctx context.Context, name names.Relationship) error {
+	q := d.NewQuery(gorm.RelationshipEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
+	q = q.Require("ApiID", name.ApiID)
+	q = q.Require("RelationshipID", name.RelationshipID)
+	if err := d.Delete(ctx, q); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// ListRelationshipsForProject returns all relationships in a project.
+func (d *Client) ListRelationshipsForProject(ctx context.Context, name names.Project) ([]models.Relationship, error) {
+	q := d.NewQuery(gorm.RelationshipEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+
+	var (
+		relationships = make([]models.Relationship, 0)
+		relationship  = new(models.Relationship)
+		it            = d.Run(ctx, q)
+		err           error
+	)
+
+	for _, err = it.Next(relationship); err == nil; _, err = it.Next(relationship) {
+		relationships = append(relationships, *relationship)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return relationships, nil
+}
@@ -28,6 +28,7 @@ import (
 func (d *Client) ListSpecRevisions(ctx context.Context, parent names.Spec, opts PageOptions) (SpecList, error) {
 	q := d.NewQuery(gorm.SpecEntityName)
 	q = q.Require("ProjectID", parent.ProjectID)
+	q = q.Require("LocationID", parent.LocationID)
 	q = q.Require("ApiID", parent.ApiID)
 	q = q.Require("VersionID", parent.VersionID)
 	q = q.Require("SpecID", parent.SpecID)
@@ -138,6 +139,7 @@ func (d *Client) DeleteSpecRevision(ctx context.Context, name names.SpecRevision
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		q = q.Require("VersionID", name.VersionID)
 		q = q.Require("SpecID", name.SpecID)
@@ -169,3 +171,49 @@ func (d *Client) unwrapSpecRevisionTag(ctx context.Context, name names.SpecRevis
 
 	return name.Spec().Revision(tag.RevisionID), nil
 }
+
+// ListSpecRevisionsForProject returns all revisions of the specs in a project,
+// ordered from the most recently created.
+func (d *Client) ListSpecRevisionsForProject(ctx context.Context, name names.Project) ([]models.Spec, error) {
+	q := d.NewQuery(gorm.SpecEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Descending("RevisionCreateTime")
+
+	var (
+		revisions = make([]models.Spec, 0)
+		revision  = new(models.Spec)
+		it        = d.Run(ctx, q)
+		err       error
+	)
+
+	for _, err = it.Next(revision); err == nil; _, err = it.Next(revision) {
+		revisions = append(revisions, *revision)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return revisions, nil
+}
+
+// ListSpecRevisionTagsForProject returns all revision tags of the specs in a project.
+func (d *Client) ListSpecRevisionTagsForProject(ctx context.Context, name names.Project) ([]models.SpecRevisionTag, error) {
+	q := d.NewQuery(gorm.SpecRevisionTagEntityName)
+	q = q.Require("ProjectID", name.ProjectID)
+
+	var (
+		tags = make([]models.SpecRevisionTag, 0)
+		tag  = new(models.SpecRevisionTag)
+		it   = d.Run(ctx, q)
+		err  error
+	)
+
+	for _, err = it.Next(tag); err == nil; _, err = it.Next(tag) {
+		tags = append(tags, *tag)
+	}
+	if err != nil && err != iterator.Done {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return tags, nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/apigee/registry/server/registry/internal/storage/filtering"
 	"github.com/apigee/registry/server/registry/internal/storage/gorm"
 	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/mimetypes"
 	"github.com/apigee/registry/server/registry/names"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
@@ -35,6 +36,7 @@ type SpecList struct {
 var specFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "spec_id", Type: filtering.String},
@@ -44,6 +46,10 @@ var specFields = []filtering.Field{
 	{Name: "revision_create_time", Type: filtering.Timestamp},
 	{Name: "revision_update_time", Type: filtering.Timestamp},
 	{Name: "mime_type", Type: filtering.String},
+	{Name: "mime_type.base", Type: filtering.String},
+	{Name: "mime_type.format", Type: filtering.String},
+	{Name: "mime_type.version", Type: filtering.String},
+	{Name: "mime_type.compression", Type: filtering.String},
 	{Name: "size_bytes", Type: filtering.Int},
 	{Name: "source_uri", Type: filtering.String},
 	{Name: "labels", Type: filtering.StringMap},
@@ -61,11 +67,11 @@ func (d *Client) ListSpecs(ctx context.Context, parent names.Version, opts PageO
 		token.Filter = opts.Filter
 	}
 
-	if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID != "-" {
 		if _, err := d.GetVersion(ctx, parent); err != nil {
 			return SpecList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
+	} else if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" && parent.VersionID == "-" {
 		if _, err := d.GetApi(ctx, parent.Api()); err != nil {
 			return SpecList{}, err
 		}
@@ -80,7 +86,7 @@ func (d *Client) ListSpecs(ctx context.Context, parent names.Version, opts PageO
 		return SpecList{}, err
 	}
 
-	it := d.GetRecentSpecRevisions(ctx, token.Offset, parent.ProjectID, parent.ApiID, parent.VersionID)
+	it := d.GetRecentSpecRevisions(ctx, token.Offset, parent.ProjectID, parent.LocationID, parent.ApiID, parent.VersionID)
 	response := SpecList{
 		Specs: make([]models.Spec, 0, opts.Size),
 	}
@@ -125,23 +131,31 @@ func specMap(spec models.Spec) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	// MIME types that can't be parsed have empty components.
+	mimeType, _ := mimetypes.Parse(spec.MimeType)
+
 	return map[string]interface{}{
-		"name":                 spec.Name(),
-		"project_id":           spec.ProjectID,
-		"api_id":               spec.ApiID,
-		"version_id":           spec.VersionID,
-		"spec_id":              spec.SpecID,
-		"filename":             spec.FileName,
-		"description":          spec.Description,
-		"revision_id":          spec.RevisionID,
-		"create_time":          spec.CreateTime,
-		"revision_create_time": spec.RevisionCreateTime,
-		"revision_update_time": spec.RevisionUpdateTime,
-		"mime_type":            spec.MimeType,
-		"size_bytes":           spec.SizeInBytes,
-		"hash":                 spec.Hash,
-		"source_uri":           spec.SourceURI,
-		"labels":               labels,
+		"name":                  spec.Name(),
+		"project_id":            spec.ProjectID,
+		"location_id":           spec.LocationID,
+		"api_id":                spec.ApiID,
+		"version_id":            spec.VersionID,
+		"spec_id":               spec.SpecID,
+		"filename":              spec.FileName,
+		"description":           spec.Description,
+		"revision_id":           spec.RevisionID,
+		"create_time":           spec.CreateTime,
+		"revision_create_time":  spec.RevisionCreateTime,
+		"revision_update_time":  spec.RevisionUpdateTime,
+		"mime_type":             spec.MimeType,
+		"mime_type.base":        mimeType.Base(),
+		"mime_type.format":      mimeType.Format(),
+		"mime_type.version":     mimeType.Version(),
+		"mime_type.compression": mimeType.Compression,
+		"size_bytes":            spec.SizeInBytes,
+		"hash":                  spec.Hash,
+		"source_uri":            spec.SourceURI,
+		"labels":                labels,
 	}, nil
 }
 
@@ -149,6 +163,7 @@ func (d *Client) GetSpec(ctx context.Context, name names.Spec) (*models.Spec, er
 	normal := name.Normal()
 	q := d.NewQuery(gorm.SpecEntityName)
 	q = q.Require("ProjectID", normal.ProjectID)
+	q = q.Require("LocationID", normal.LocationID)
 	q = q.Require("ApiID", normal.ApiID)
 	q = q.Require("VersionID", normal.VersionID)
 	q = q.Require("SpecID", normal.SpecID)
@@ -168,10 +183,12 @@ func (d *Client) DeleteSpec(ctx context.Context, name names.Spec) error {
 		gorm.SpecEntityName,
 		gorm.SpecRevisionTagEntityName,
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
 		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		q = q.Require("VersionID", name.VersionID)
 		q = q.Require("SpecID", name.SpecID)
@@ -186,6 +203,7 @@ func (d *Client) DeleteSpec(ctx context.Context, name names.Spec) error {
 func (d *Client) GetSpecTags(ctx context.Context, name names.Spec) ([]*models.SpecRevisionTag, error) {
 	q := d.NewQuery(gorm.SpecRevisionTagEntityName)
 	q = q.Require("ProjectID", name.ProjectID)
+	q = q.Require("LocationID", name.LocationID)
 	q = q.Require("ApiID", name.ApiID)
 	q = q.Require("VersionID", name.VersionID)
 	if name.SpecID != "-" {
@@ -0,0 +1,35 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+)
+
+// SchemaVersion is the version of the storage schema used by this package.
+const SchemaVersion = gorm.SchemaVersion
+
+// CheckDatabase returns an error if the database can't be reached.
+func (d *Client) CheckDatabase(ctx context.Context) error {
+	return d.Ping(ctx)
+}
+
+// CheckBlobs returns an error if the blob store can't be read.
+func (d *Client) CheckBlobs(ctx context.Context) error {
+	return d.PingTable(ctx, &models.Blob{})
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/apigee/registry/server/registry/internal/storage/gorm"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProjectUsage describes the storage used by a project.
+type ProjectUsage struct {
+	Apis      int64 // Number of APIs.
+	Specs     int64 // Number of specs.
+	Revisions int64 // Number of spec and deployment revisions.
+	BlobBytes int64 // Total size of spec and artifact contents.
+}
+
+// GetProjectUsage returns the storage used by a project.
+func (d *Client) GetProjectUsage(ctx context.Context, name names.Project) (*ProjectUsage, error) {
+	var (
+		usage = new(ProjectUsage)
+		err   error
+	)
+
+	q := d.NewQuery(gorm.ApiEntityName).Require("ProjectID", name.ProjectID)
+	if usage.Apis, err = d.Count(ctx, q); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	q = d.NewQuery(gorm.SpecEntityName).Require("ProjectID", name.ProjectID)
+	if usage.Specs, err = d.Count(ctx, q, "LocationID", "ApiID", "VersionID", "SpecID"); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	specRevisions, err := d.Count(ctx, q)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	q = d.NewQuery(gorm.DeploymentEntityName).Require("ProjectID", name.ProjectID)
+	deploymentRevisions, err := d.Count(ctx, q)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	usage.Revisions = specRevisions + deploymentRevisions
+
+	q = d.NewQuery(gorm.BlobEntityName).Require("ProjectID", name.ProjectID)
+	if usage.BlobBytes, err = d.Sum(ctx, q, "SizeInBytes"); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return usage, nil
+}
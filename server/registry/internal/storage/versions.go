@@ -35,6 +35,7 @@ type VersionList struct {
 var versionFields = []filtering.Field{
 	{Name: "name", Type: filtering.String},
 	{Name: "project_id", Type: filtering.String},
+	{Name: "location_id", Type: filtering.String},
 	{Name: "api_id", Type: filtering.String},
 	{Name: "version_id", Type: filtering.String},
 	{Name: "display_name", Type: filtering.String},
@@ -64,14 +65,17 @@ func (d *Client) ListVersions(ctx context.Context, parent names.Api, opts PageOp
 	if parent.ProjectID != "-" {
 		q = q.Require("ProjectID", parent.ProjectID)
 	}
+	if parent.LocationID != "-" {
+		q = q.Require("LocationID", parent.LocationID)
+	}
 	if parent.ApiID != "-" {
 		q = q.Require("ApiID", parent.ApiID)
 	}
-	if parent.ProjectID != "-" && parent.ApiID != "-" {
+	if parent.ProjectID != "-" && parent.LocationID != "-" && parent.ApiID != "-" {
 		if _, err := d.GetApi(ctx, parent); err != nil {
 			return VersionList{}, err
 		}
-	} else if parent.ProjectID != "-" && parent.ApiID == "-" {
+	} else if parent.ProjectID != "-" {
 		if _, err := d.GetProject(ctx, parent.Project()); err != nil {
 			return VersionList{}, err
 		}
@@ -130,6 +134,7 @@ func versionMap(version models.Version) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"name":         version.Name(),
 		"project_id":   version.ProjectID,
+		"location_id":  version.LocationID,
 		"version_id":   version.VersionID,
 		"display_name": version.DisplayName,
 		"description":  version.Description,
@@ -167,10 +172,13 @@ func (d *Client) DeleteVersion(ctx context.Context, name names.Version) error {
 		gorm.SpecEntityName,
 		gorm.SpecRevisionTagEntityName,
 		gorm.ArtifactEntityName,
+		gorm.ArtifactRevisionTagEntityName,
+		gorm.LifecycleTransitionEntityName,
 		gorm.BlobEntityName,
 	} {
 		q := d.NewQuery(entityName)
 		q = q.Require("ProjectID", name.ProjectID)
+		q = q.Require("LocationID", name.LocationID)
 		q = q.Require("ApiID", name.ApiID)
 		q = q.Require("VersionID", name.VersionID)
 		if err := d.Delete(ctx, q); err != nil {
@@ -0,0 +1,217 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	availabilityField = "availability"
+	stateField        = "state"
+)
+
+// validateLifecycle returns an error if a lifecycle is not consistent.
+func validateLifecycle(field string, lifecycle *rpc.Lifecycle) error {
+	states := make(map[string]bool, len(lifecycle.GetStates()))
+	for _, state := range lifecycle.GetStates() {
+		if state.GetName() == "" {
+			return fmt.Errorf("invalid %s: state names must not be empty", field)
+		} else if states[state.GetName()] {
+			return fmt.Errorf("invalid %s: state %q is defined more than once", field, state.GetName())
+		}
+		states[state.GetName()] = true
+	}
+
+	for _, state := range lifecycle.GetStates() {
+		for _, next := range state.GetTransitions() {
+			if !states[next] {
+				return fmt.Errorf("invalid %s: state %q has a transition to undefined state %q", field, state.GetName(), next)
+			}
+		}
+		for _, label := range state.GetRequiredLabels() {
+			if label == "" {
+				return fmt.Errorf("invalid %s: state %q requires an empty label key", field, state.GetName())
+			}
+		}
+		for _, id := range state.GetRequiredArtifacts() {
+			if id == "" {
+				return fmt.Errorf("invalid %s: state %q requires an empty artifact ID", field, state.GetName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// lifecycleChange is a change of the lifecycle field of an API or version.
+type lifecycleChange struct {
+	field    string
+	from, to string
+	// Labels of the resource after the change.
+	labels map[string]string
+	// Names the artifacts attached to the resource.
+	artifact func(id string) names.Artifact
+}
+
+// governed returns true if a lifecycle constrains a change.
+func (c lifecycleChange) governed(lifecycle *rpc.Lifecycle) bool {
+	return len(lifecycle.GetStates()) > 0 && c.from != c.to
+}
+
+// checkLifecycle returns an error if a change is not allowed by a lifecycle.
+// A resource that is not in a state of the lifecycle can only enter one of
+// its initial states.
+func checkLifecycle(ctx context.Context, db *storage.Client, lifecycle *rpc.Lifecycle, c lifecycleChange) error {
+	if !c.governed(lifecycle) {
+		return nil
+	}
+
+	var from, to *rpc.LifecycleState
+	for _, state := range lifecycle.GetStates() {
+		switch state.GetName() {
+		case c.from:
+			from = state
+		case c.to:
+			to = state
+		}
+	}
+
+	if to == nil {
+		allowed := make([]string, len(lifecycle.GetStates()))
+		for i, state := range lifecycle.GetStates() {
+			allowed[i] = state.GetName()
+		}
+		return status.Errorf(codes.InvalidArgument, "invalid %s %q: must be one of [%s]", c.field, c.to, strings.Join(allowed, ", "))
+	}
+
+	if from != nil {
+		if !containsString(from.GetTransitions(), c.to) {
+			return status.Errorf(codes.FailedPrecondition, "%s cannot change from %q to %q", c.field, c.from, c.to)
+		}
+	} else if !initialState(lifecycle, to) {
+		return status.Errorf(codes.FailedPrecondition, "%s %q is not an initial state", c.field, c.to)
+	}
+
+	for _, key := range to.GetRequiredLabels() {
+		if _, ok := c.labels[key]; !ok {
+			return status.Errorf(codes.FailedPrecondition, "%s %q requires label %q", c.field, c.to, key)
+		}
+	}
+
+	for _, id := range to.GetRequiredArtifacts() {
+		if _, err := db.GetArtifact(ctx, c.artifact(id)); isNotFound(err) {
+			return status.Errorf(codes.FailedPrecondition, "%s %q requires artifact %q", c.field, c.to, id)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initialState returns true if resources can enter a state from outside of
+// its lifecycle.
+func initialState(lifecycle *rpc.Lifecycle, state *rpc.LifecycleState) bool {
+	for _, s := range lifecycle.GetStates() {
+		if s.GetInitial() {
+			return state.GetInitial()
+		}
+	}
+	return lifecycle.GetStates()[0] == state
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// projectLifecycles returns the lifecycles of API availability and version
+// state in a project.
+func projectLifecycles(ctx context.Context, db *storage.Client, name names.Project) (api, version *rpc.Lifecycle, err error) {
+	project, err := db.GetProject(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if api, err = project.ApiLifecycleMessage(); err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if version, err = project.VersionLifecycleMessage(); err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return api, version, nil
+}
+
+// recordTransition saves a governed lifecycle transition and returns its
+// message, or returns nil if the change is not governed by the lifecycle.
+func recordTransition(ctx context.Context, db *storage.Client, lifecycle *rpc.Lifecycle, c lifecycleChange, transition *models.LifecycleTransition) (*rpc.LifecycleTransition, error) {
+	if !c.governed(lifecycle) {
+		return nil, nil
+	}
+
+	if err := db.SaveLifecycleTransition(ctx, transition); err != nil {
+		return nil, err
+	}
+
+	return transition.Message(), nil
+}
+
+// apiChange describes the change of an API's availability from old to api.
+func apiChange(name names.Api, old, api *models.Api) (lifecycleChange, error) {
+	labels, err := api.LabelsMap()
+	if err != nil {
+		return lifecycleChange{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return lifecycleChange{
+		field:    availabilityField,
+		from:     old.Availability,
+		to:       api.Availability,
+		labels:   labels,
+		artifact: name.Artifact,
+	}, nil
+}
+
+// versionChange describes the change of a version's state from old to version.
+func versionChange(name names.Version, old, version *models.Version) (lifecycleChange, error) {
+	labels, err := version.LabelsMap()
+	if err != nil {
+		return lifecycleChange{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return lifecycleChange{
+		field:    stateField,
+		from:     old.State,
+		to:       version.State,
+		labels:   labels,
+		artifact: name.Artifact,
+	}, nil
+}
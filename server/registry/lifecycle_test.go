@@ -0,0 +1,298 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// governanceLifecycle is a lifecycle in which production requires an owner
+// label and a review artifact.
+func governanceLifecycle() *rpc.Lifecycle {
+	return &rpc.Lifecycle{
+		States: []*rpc.LifecycleState{
+			{Name: "design", Transitions: []string{"preview"}},
+			{Name: "preview", Transitions: []string{"design", "production"}},
+			{
+				Name:              "production",
+				Transitions:       []string{"deprecated"},
+				RequiredLabels:    []string{"owner"},
+				RequiredArtifacts: []string{"review"},
+			},
+			{Name: "deprecated", Transitions: []string{"retired"}},
+			{Name: "retired"},
+		},
+	}
+}
+
+func seedLifecycles(ctx context.Context, t *testing.T, server *RegistryServer) {
+	t.Helper()
+	project := &rpc.Project{
+		Name:             "projects/my-project",
+		ApiLifecycle:     governanceLifecycle(),
+		VersionLifecycle: governanceLifecycle(),
+	}
+	if err := seeder.SeedProjects(ctx, server, project); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+	if _, err := server.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: "projects/my-project/locations/global",
+		ApiId:  "my-api",
+		Api:    &rpc.Api{},
+	}); err != nil {
+		t.Fatalf("Setup: CreateApi returned error: %s", err)
+	}
+}
+
+func TestLifecycleValidation(t *testing.T) {
+	tests := []struct {
+		desc      string
+		lifecycle *rpc.Lifecycle
+		want      codes.Code
+	}{
+		{
+			desc:      "valid lifecycle",
+			lifecycle: governanceLifecycle(),
+			want:      codes.OK,
+		},
+		{
+			desc:      "empty state name",
+			lifecycle: &rpc.Lifecycle{States: []*rpc.LifecycleState{{Name: ""}}},
+			want:      codes.InvalidArgument,
+		},
+		{
+			desc:      "duplicate state",
+			lifecycle: &rpc.Lifecycle{States: []*rpc.LifecycleState{{Name: "design"}, {Name: "design"}}},
+			want:      codes.InvalidArgument,
+		},
+		{
+			desc:      "transition to undefined state",
+			lifecycle: &rpc.Lifecycle{States: []*rpc.LifecycleState{{Name: "design", Transitions: []string{"production"}}}},
+			want:      codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+
+			_, err := server.CreateProject(ctx, &rpc.CreateProjectRequest{
+				ProjectId: "my-project",
+				Project:   &rpc.Project{VersionLifecycle: test.lifecycle},
+			})
+			if status.Code(err) != test.want {
+				t.Errorf("CreateProject(%+v) returned status code %q, want %q: %v", test.lifecycle, status.Code(err), test.want, err)
+			}
+
+			_, err = server.UpdateProject(ctx, &rpc.UpdateProjectRequest{
+				Project:      &rpc.Project{Name: "projects/other-project", ApiLifecycle: test.lifecycle},
+				UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"api_lifecycle"}},
+				AllowMissing: true,
+			})
+			if status.Code(err) != test.want {
+				t.Errorf("UpdateProject(%+v) returned status code %q, want %q: %v", test.lifecycle, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestVersionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedLifecycles(ctx, t, server)
+
+	const name = "projects/my-project/locations/global/apis/my-api/versions/v1"
+	if _, err := server.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       "projects/my-project/locations/global/apis/my-api",
+		ApiVersionId: "v1",
+		ApiVersion:   &rpc.ApiVersion{State: "preview"},
+	}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CreateApiVersion() in a non-initial state returned status code %q, want %q: %v", status.Code(err), codes.FailedPrecondition, err)
+	}
+
+	if _, err := server.CreateApiVersion(ctx, &rpc.CreateApiVersionRequest{
+		Parent:       "projects/my-project/locations/global/apis/my-api",
+		ApiVersionId: "v1",
+		ApiVersion:   &rpc.ApiVersion{State: "design"},
+	}); err != nil {
+		t.Fatalf("CreateApiVersion() returned error: %s", err)
+	}
+
+	steps := []struct {
+		desc    string
+		version *rpc.ApiVersion
+		paths   []string
+		want    codes.Code
+	}{
+		{
+			desc:    "undefined state",
+			version: &rpc.ApiVersion{State: "unknown"},
+			paths:   []string{"state"},
+			want:    codes.InvalidArgument,
+		},
+		{
+			desc:    "cleared state",
+			version: &rpc.ApiVersion{},
+			paths:   []string{"state"},
+			want:    codes.InvalidArgument,
+		},
+		{
+			desc:    "transition that is not allowed",
+			version: &rpc.ApiVersion{State: "production"},
+			paths:   []string{"state"},
+			want:    codes.FailedPrecondition,
+		},
+		{
+			desc:    "allowed transition",
+			version: &rpc.ApiVersion{State: "preview"},
+			paths:   []string{"state"},
+			want:    codes.OK,
+		},
+		{
+			desc:    "missing required label",
+			version: &rpc.ApiVersion{State: "production"},
+			paths:   []string{"state"},
+			want:    codes.FailedPrecondition,
+		},
+		{
+			desc:    "missing required artifact",
+			version: &rpc.ApiVersion{State: "production", Labels: map[string]string{"owner": "team"}},
+			paths:   []string{"state", "labels"},
+			want:    codes.FailedPrecondition,
+		},
+		{
+			desc:    "unchanged state",
+			version: &rpc.ApiVersion{State: "preview", Description: "ready for review"},
+			paths:   []string{"state", "description"},
+			want:    codes.OK,
+		},
+	}
+
+	for _, step := range steps {
+		step.version.Name = name
+		_, err := server.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+			ApiVersion: step.version,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: step.paths},
+		})
+		if status.Code(err) != step.want {
+			t.Fatalf("UpdateApiVersion(%s) returned status code %q, want %q: %v", step.desc, status.Code(err), step.want, err)
+		}
+	}
+
+	if _, err := server.CreateArtifact(ctx, &rpc.CreateArtifactRequest{
+		Parent:     name,
+		ArtifactId: "review",
+		Artifact:   &rpc.Artifact{},
+	}); err != nil {
+		t.Fatalf("Setup: CreateArtifact returned error: %s", err)
+	}
+
+	if _, err := server.UpdateApiVersion(ctx, &rpc.UpdateApiVersionRequest{
+		ApiVersion: &rpc.ApiVersion{Name: name, State: "production", Labels: map[string]string{"owner": "team"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"state", "labels"}},
+	}); err != nil {
+		t.Fatalf("UpdateApiVersion(production) returned error: %s", err)
+	}
+
+	version, err := server.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: name})
+	if err != nil {
+		t.Fatalf("GetApiVersion(%q) returned error: %s", name, err)
+	}
+
+	var got [][2]string
+	for _, transition := range version.GetStateHistory() {
+		got = append(got, [2]string{transition.GetFromState(), transition.GetToState()})
+		if transition.GetTransitionTime() == nil {
+			t.Errorf("Transition %v has no transition time", transition)
+		}
+	}
+	want := [][2]string{{"", "design"}, {"design", "preview"}, {"preview", "production"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetApiVersion(%q) returned unexpected state history (-want +got):\n%s", name, diff)
+	}
+
+	listing, err := server.ListApiVersions(ctx, &rpc.ListApiVersionsRequest{
+		Parent: "projects/my-project/locations/global/apis/my-api",
+		Filter: "state == 'production'",
+	})
+	if err != nil {
+		t.Fatalf("ListApiVersions() returned error: %s", err)
+	}
+	if len(listing.GetApiVersions()) != 1 || len(listing.GetApiVersions()[0].GetStateHistory()) != 0 {
+		t.Errorf("ListApiVersions() returned %v, want only %q without history", listing.GetApiVersions(), name)
+	}
+}
+
+func TestApiLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedLifecycles(ctx, t, server)
+
+	const name = "projects/my-project/locations/global/apis/my-api"
+	steps := []struct {
+		availability string
+		want         codes.Code
+	}{
+		{availability: "preview", want: codes.FailedPrecondition},
+		{availability: "design", want: codes.OK},
+		{availability: "preview", want: codes.OK},
+		{availability: "design", want: codes.OK},
+	}
+
+	for _, step := range steps {
+		_, err := server.UpdateApi(ctx, &rpc.UpdateApiRequest{
+			Api:        &rpc.Api{Name: name, Availability: step.availability},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"availability"}},
+		})
+		if status.Code(err) != step.want {
+			t.Fatalf("UpdateApi(%q) returned status code %q, want %q: %v", step.availability, status.Code(err), step.want, err)
+		}
+	}
+
+	api, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+	if err != nil {
+		t.Fatalf("GetApi(%q) returned error: %s", name, err)
+	}
+	if got := len(api.GetAvailabilityHistory()); got != 3 {
+		t.Errorf("GetApi(%q) returned %d transitions, want 3", name, got)
+	}
+
+	if _, err := server.DeleteApi(ctx, &rpc.DeleteApiRequest{Name: name}); err != nil {
+		t.Fatalf("DeleteApi(%q) returned error: %s", name, err)
+	}
+	if _, err := server.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: "projects/my-project/locations/global",
+		ApiId:  "my-api",
+		Api:    &rpc.Api{},
+	}); err != nil {
+		t.Fatalf("CreateApi(%q) returned error: %s", name, err)
+	}
+	api, err = server.GetApi(ctx, &rpc.GetApiRequest{Name: name})
+	if err != nil {
+		t.Fatalf("GetApi(%q) returned error: %s", name, err)
+	}
+	if got := len(api.GetAvailabilityHistory()); got != 0 {
+		t.Errorf("GetApi(%q) returned %d transitions after re-creation, want 0", name, got)
+	}
+}
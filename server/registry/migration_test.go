@@ -0,0 +1,401 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The baseline types are the tables of databases that were created before
+// locations and artifact revisions were supported.
+
+type baselineProject struct {
+	Key         string `gorm:"primaryKey"`
+	ProjectID   string
+	DisplayName string
+	Description string
+	CreateTime  time.Time
+	UpdateTime  time.Time
+}
+
+func (baselineProject) TableName() string { return "projects" }
+
+type baselineApi struct {
+	Key                string `gorm:"primaryKey"`
+	ProjectID          string
+	ApiID              string
+	DisplayName        string
+	Description        string
+	CreateTime         time.Time
+	UpdateTime         time.Time
+	Availability       string
+	RecommendedVersion string
+	Labels             []byte
+	Annotations        []byte
+}
+
+func (baselineApi) TableName() string { return "apis" }
+
+type baselineVersion struct {
+	Key         string `gorm:"primaryKey"`
+	ProjectID   string
+	ApiID       string
+	VersionID   string
+	DisplayName string
+	Description string
+	CreateTime  time.Time
+	UpdateTime  time.Time
+	State       string
+	Labels      []byte
+	Annotations []byte
+}
+
+func (baselineVersion) TableName() string { return "versions" }
+
+type baselineSpec struct {
+	Key                string `gorm:"primaryKey"`
+	ProjectID          string
+	ApiID              string
+	VersionID          string
+	SpecID             string
+	RevisionID         string
+	Description        string
+	CreateTime         time.Time
+	RevisionCreateTime time.Time
+	RevisionUpdateTime time.Time
+	MimeType           string
+	SizeInBytes        int32
+	Hash               string
+	FileName           string
+	SourceURI          string
+	Labels             []byte
+	Annotations        []byte
+}
+
+func (baselineSpec) TableName() string { return "specs" }
+
+type baselineSpecRevisionTag struct {
+	Key        string `gorm:"primaryKey"`
+	ProjectID  string
+	ApiID      string
+	VersionID  string
+	SpecID     string
+	RevisionID string
+	Tag        string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+func (baselineSpecRevisionTag) TableName() string { return "spec_revision_tags" }
+
+type baselineDeployment struct {
+	Key                string `gorm:"primaryKey"`
+	ProjectID          string
+	ApiID              string
+	DeploymentID       string
+	RevisionID         string
+	DisplayName        string
+	Description        string
+	CreateTime         time.Time
+	RevisionCreateTime time.Time
+	RevisionUpdateTime time.Time
+	ApiSpecRevision    string
+	EndpointURI        string
+	ExternalChannelURI string
+	IntendedAudience   string
+	AccessGuidance     string
+	Labels             []byte
+	Annotations        []byte
+}
+
+func (baselineDeployment) TableName() string { return "deployments" }
+
+type baselineDeploymentRevisionTag struct {
+	Key          string `gorm:"primaryKey"`
+	ProjectID    string
+	ApiID        string
+	DeploymentID string
+	RevisionID   string
+	Tag          string
+	CreateTime   time.Time
+	UpdateTime   time.Time
+}
+
+func (baselineDeploymentRevisionTag) TableName() string { return "deployment_revision_tags" }
+
+type baselineArtifact struct {
+	Key          string `gorm:"primaryKey"`
+	ProjectID    string
+	ApiID        string
+	VersionID    string
+	SpecID       string
+	DeploymentID string
+	ArtifactID   string
+	CreateTime   time.Time
+	UpdateTime   time.Time
+	MimeType     string
+	SizeInBytes  int32
+	Hash         string
+}
+
+func (baselineArtifact) TableName() string { return "artifacts" }
+
+type baselineBlob struct {
+	Key         string `gorm:"primaryKey"`
+	ProjectID   string
+	ApiID       string
+	VersionID   string
+	SpecID      string
+	RevisionID  string
+	ArtifactID  string
+	Hash        string
+	SizeInBytes int32
+	Contents    []byte
+	CreateTime  time.Time
+	UpdateTime  time.Time
+}
+
+func (baselineBlob) TableName() string { return "blobs" }
+
+// baselineDatabase creates a database with the baseline tables and rows.
+func baselineDatabase(t *testing.T, rows ...interface{}) string {
+	t.Helper()
+	path := fmt.Sprintf("%s/registry.db", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Setup: failed to open database: %s", err)
+	}
+
+	tables := []interface{}{
+		&baselineProject{},
+		&baselineApi{},
+		&baselineVersion{},
+		&baselineSpec{},
+		&baselineSpecRevisionTag{},
+		&baselineDeployment{},
+		&baselineDeploymentRevisionTag{},
+		&baselineArtifact{},
+		&baselineBlob{},
+	}
+	if err := db.Migrator().CreateTable(tables...); err != nil {
+		t.Fatalf("Setup: failed to create tables: %s", err)
+	}
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("Setup: failed to create %+v: %s", row, err)
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Setup: failed to get database: %s", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		t.Fatalf("Setup: failed to close database: %s", err)
+	}
+	return path
+}
+
+func TestMigrateBaselineDatabase(t *testing.T) {
+	ctx := context.Background()
+	created := time.Now().Add(-time.Hour).Round(time.Microsecond)
+	updated := created.Add(time.Minute)
+	const (
+		project  = "projects/my-project"
+		api      = "projects/my-project/locations/global/apis/my-api"
+		version  = "projects/my-project/locations/global/apis/my-api/versions/v1"
+		spec     = "projects/my-project/locations/global/apis/my-api/versions/v1/specs/openapi.yaml"
+		artifact = "projects/my-project/locations/global/apis/my-api/artifacts/notes"
+	)
+	contents := []byte("openapi: 3.0.0")
+	notes := []byte("Some notes")
+	path := baselineDatabase(t,
+		&baselineProject{Key: project, ProjectID: "my-project", CreateTime: created, UpdateTime: created},
+		&baselineApi{Key: api, ProjectID: "my-project", ApiID: "my-api", DisplayName: "My API", CreateTime: created, UpdateTime: created},
+		&baselineVersion{Key: version, ProjectID: "my-project", ApiID: "my-api", VersionID: "v1", CreateTime: created, UpdateTime: created},
+		&baselineSpec{Key: spec + "@11111111", ProjectID: "my-project", ApiID: "my-api", VersionID: "v1", SpecID: "openapi.yaml",
+			RevisionID: "11111111", CreateTime: created, RevisionCreateTime: created, RevisionUpdateTime: created,
+			MimeType: "application/x.openapi;version=3", SizeInBytes: int32(len(contents))},
+		&baselineBlob{Key: spec + "@11111111", ProjectID: "my-project", ApiID: "my-api", VersionID: "v1", SpecID: "openapi.yaml",
+			RevisionID: "11111111", Contents: contents, SizeInBytes: int32(len(contents)), CreateTime: created, UpdateTime: created},
+		&baselineSpec{Key: spec + "@22222222", ProjectID: "my-project", ApiID: "my-api", VersionID: "v1", SpecID: "openapi.yaml",
+			RevisionID: "22222222", CreateTime: created, RevisionCreateTime: updated, RevisionUpdateTime: updated,
+			MimeType: "application/x.openapi;version=3", SizeInBytes: int32(len(contents))},
+		&baselineBlob{Key: spec + "@22222222", ProjectID: "my-project", ApiID: "my-api", VersionID: "v1", SpecID: "openapi.yaml",
+			RevisionID: "22222222", Contents: contents, SizeInBytes: int32(len(contents)), CreateTime: updated, UpdateTime: updated},
+		&baselineArtifact{Key: artifact, ProjectID: "my-project", ApiID: "my-api", ArtifactID: "notes",
+			CreateTime: created, UpdateTime: updated, MimeType: "text/plain", SizeInBytes: int32(len(notes))},
+		&baselineBlob{Key: artifact, ProjectID: "my-project", ApiID: "my-api", ArtifactID: "notes",
+			Contents: notes, SizeInBytes: int32(len(notes)), CreateTime: updated, UpdateTime: updated},
+	)
+
+	server, err := New(Config{Database: "sqlite3", DBConfig: path})
+	if err != nil {
+		t.Fatalf("New() with a baseline database returned error: %s", err)
+	}
+	defer server.Close()
+
+	// Resources that haven't changed since the upgrade can be read as they
+	// were at any time after they were created.
+	now := timestamppb.Now()
+	if got, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: api, ReadTime: now}); err != nil {
+		t.Errorf("GetApi(%q) at %s returned error: %s", api, now.AsTime(), err)
+	} else if got.GetDisplayName() != "My API" {
+		t.Errorf("GetApi(%q) at %s returned %+v", api, now.AsTime(), got)
+	}
+
+	if got, err := server.ListApis(ctx, &rpc.ListApisRequest{Parent: "projects/my-project/locations/global", ReadTime: now}); err != nil {
+		t.Errorf("ListApis() at %s returned error: %s", now.AsTime(), err)
+	} else if len(got.GetApis()) != 1 || got.GetApis()[0].GetName() != api {
+		t.Errorf("ListApis() at %s returned %+v, want %q", now.AsTime(), got.GetApis(), api)
+	}
+
+	for _, test := range []struct {
+		name     string
+		readTime time.Time
+		revision string
+	}{
+		{spec, updated, "22222222"},
+		{spec, updated.Add(-time.Second), "11111111"},
+		{spec + "@11111111", now.AsTime(), "11111111"},
+	} {
+		if got, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: test.name, ReadTime: timestamppb.New(test.readTime)}); err != nil {
+			t.Errorf("GetApiSpec(%q) at %s returned error: %s", test.name, test.readTime, err)
+		} else if got.GetRevisionId() != test.revision {
+			t.Errorf("GetApiSpec(%q) at %s returned revision %q, want %q", test.name, test.readTime, got.GetRevisionId(), test.revision)
+		}
+	}
+
+	if _, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: artifact, ReadTime: now}); err != nil {
+		t.Errorf("GetArtifact(%q) at %s returned error: %s", artifact, now.AsTime(), err)
+	}
+
+	before := timestamppb.New(created.Add(-time.Second))
+	if _, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: api, ReadTime: before}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetApi(%q) at %s returned error %v, want NotFound", api, before.AsTime(), err)
+	}
+
+	// Projects without lifecycles, and artifacts without labels, can be read.
+	if _, err := server.GetProject(ctx, &rpc.GetProjectRequest{Name: project}); err != nil {
+		t.Errorf("GetProject(%q) returned error: %s", project, err)
+	}
+
+	if got, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: api}); err != nil {
+		t.Errorf("GetApi(%q) returned error: %s", api, err)
+	} else if got.GetName() != api || got.GetDisplayName() != "My API" {
+		t.Errorf("GetApi(%q) returned %+v", api, got)
+	}
+
+	if got, err := server.ListApis(ctx, &rpc.ListApisRequest{Parent: "projects/my-project/locations/global"}); err != nil {
+		t.Errorf("ListApis() returned error: %s", err)
+	} else if len(got.GetApis()) != 1 || got.GetApis()[0].GetName() != api {
+		t.Errorf("ListApis() returned %+v, want %q", got.GetApis(), api)
+	}
+
+	if got, err := server.GetApiVersion(ctx, &rpc.GetApiVersionRequest{Name: version}); err != nil {
+		t.Errorf("GetApiVersion(%q) returned error: %s", version, err)
+	} else if got.GetName() != version {
+		t.Errorf("GetApiVersion(%q) returned name %q", version, got.GetName())
+	}
+
+	if got, err := server.ListApiSpecs(ctx, &rpc.ListApiSpecsRequest{Parent: version}); err != nil {
+		t.Errorf("ListApiSpecs() returned error: %s", err)
+	} else if len(got.GetApiSpecs()) != 1 || got.GetApiSpecs()[0].GetName() != spec {
+		t.Errorf("ListApiSpecs() returned %+v, want %q", got.GetApiSpecs(), spec)
+	}
+
+	if got, err := server.GetApiSpecContents(ctx, &rpc.GetApiSpecContentsRequest{Name: spec}); err != nil {
+		t.Errorf("GetApiSpecContents(%q) returned error: %s", spec, err)
+	} else if !bytes.Equal(got.GetData(), contents) {
+		t.Errorf("GetApiSpecContents(%q) returned %q, want %q", spec, got.GetData(), contents)
+	}
+
+	// Artifacts become the only revisions of themselves, created when they were last updated.
+	var revisionID string
+	if got, err := server.GetArtifact(ctx, &rpc.GetArtifactRequest{Name: artifact}); err != nil {
+		t.Errorf("GetArtifact(%q) returned error: %s", artifact, err)
+	} else if revisionID = got.GetRevisionId(); revisionID == "" || !got.GetRevisionCreateTime().AsTime().Equal(updated) {
+		t.Errorf("GetArtifact(%q) returned revision %q created at %s, want a revision created at %s",
+			artifact, revisionID, got.GetRevisionCreateTime().AsTime(), updated)
+	}
+
+	if got, err := server.ListArtifacts(ctx, &rpc.ListArtifactsRequest{Parent: api}); err != nil {
+		t.Errorf("ListArtifacts() returned error: %s", err)
+	} else if len(got.GetArtifacts()) != 1 || got.GetArtifacts()[0].GetName() != artifact {
+		t.Errorf("ListArtifacts() returned %+v, want %q", got.GetArtifacts(), artifact)
+	}
+
+	if got, err := server.ListArtifactRevisions(ctx, &rpc.ListArtifactRevisionsRequest{Name: artifact}); err != nil {
+		t.Errorf("ListArtifactRevisions() returned error: %s", err)
+	} else if len(got.GetArtifacts()) != 1 || got.GetArtifacts()[0].GetRevisionId() != revisionID {
+		t.Errorf("ListArtifactRevisions() returned %+v, want revision %q", got.GetArtifacts(), revisionID)
+	}
+
+	for _, name := range []string{artifact, artifact + "@" + revisionID} {
+		if got, err := server.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: name}); err != nil {
+			t.Errorf("GetArtifactContents(%q) returned error: %s", name, err)
+		} else if !bytes.Equal(got.GetData(), notes) {
+			t.Errorf("GetArtifactContents(%q) returned %q, want %q", name, got.GetData(), notes)
+		}
+	}
+
+	// Replacing a migrated artifact makes the replacement its latest revision.
+	replacement := []byte("Other notes")
+	if _, err := server.ReplaceArtifact(ctx, &rpc.ReplaceArtifactRequest{
+		Artifact: &rpc.Artifact{Name: artifact, MimeType: "text/plain", Contents: replacement},
+	}); err != nil {
+		t.Errorf("ReplaceArtifact(%q) returned error: %s", artifact, err)
+	}
+	if got, err := server.GetArtifactContents(ctx, &rpc.GetArtifactContentsRequest{Name: artifact}); err != nil {
+		t.Errorf("GetArtifactContents(%q) returned error: %s", artifact, err)
+	} else if !bytes.Equal(got.GetData(), replacement) {
+		t.Errorf("GetArtifactContents(%q) after replacement returned %q, want %q", artifact, got.GetData(), replacement)
+	}
+
+	// New resources can be created next to migrated ones.
+	if _, err := server.CreateApi(ctx, &rpc.CreateApiRequest{
+		Parent: "projects/my-project/locations/global",
+		ApiId:  "other-api",
+		Api:    &rpc.Api{},
+	}); err != nil {
+		t.Errorf("CreateApi() returned error: %s", err)
+	}
+
+	// Migrations run once; reopening the database leaves migrated rows alone.
+	reopened, err := New(Config{Database: "sqlite3", DBConfig: path})
+	if err != nil {
+		t.Fatalf("New() with a migrated database returned error: %s", err)
+	}
+	defer reopened.Close()
+	if got, err := reopened.ListArtifactRevisions(ctx, &rpc.ListArtifactRevisionsRequest{Name: artifact}); err != nil {
+		t.Errorf("ListArtifactRevisions() returned error: %s", err)
+	} else if len(got.GetArtifacts()) != 2 {
+		t.Errorf("ListArtifactRevisions() after reopening returned %d revisions, want 2", len(got.GetArtifacts()))
+	}
+}
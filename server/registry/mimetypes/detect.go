@@ -0,0 +1,112 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimetypes
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+
+	protoSyntax = regexp.MustCompile(`(?m)^\s*syntax\s*=\s*"proto[23]"\s*;`)
+)
+
+// Detect returns the MIME type of a spec with a filename and contents, or an
+// empty string if the format of the spec can't be determined. Contents may be
+// compressed with GZip or stored in a Zip archive.
+func Detect(filename string, contents []byte) string {
+	switch {
+	case bytes.HasPrefix(contents, gzipMagic):
+		r, err := gzip.NewReader(bytes.NewReader(contents))
+		if err != nil {
+			return ""
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return ""
+		}
+		return detect(strings.TrimSuffix(filename, ".gz"), data, GZip)
+	case bytes.HasPrefix(contents, zipMagic):
+		return detectArchive(contents)
+	default:
+		return detect(filename, contents, "")
+	}
+}
+
+func detect(filename string, contents []byte, compression string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".proto":
+		return ForFormat(Protobuf, compression, "")
+	case ".graphql", ".gql":
+		return ForFormat(GraphQL, compression, "")
+	}
+
+	if protoSyntax.Match(contents) {
+		return ForFormat(Protobuf, compression, "")
+	}
+
+	// YAML is a superset of JSON, so this reads both.
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return ""
+	}
+
+	for _, key := range []string{"openapi", "swagger", "asyncapi"} {
+		if v, ok := doc[key]; ok {
+			format := key
+			if key == "swagger" {
+				format = OpenAPI
+			}
+			return ForFormat(format, compression, majorVersion(v))
+		}
+	}
+
+	if _, ok := doc["discoveryVersion"]; ok {
+		return ForFormat(Discovery, compression, "")
+	}
+
+	return ""
+}
+
+// detectArchive returns the MIME type of a Zip archive of specs.
+func detectArchive(contents []byte) string {
+	r, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		return ""
+	}
+
+	for _, f := range r.File {
+		if strings.HasSuffix(f.Name, ".proto") {
+			return ForFormat(Protobuf, Zip, "")
+		}
+	}
+
+	return ""
+}
+
+func majorVersion(v interface{}) string {
+	return strings.SplitN(fmt.Sprint(v), ".", 2)[0]
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mimetypes parses, formats, and detects the MIME types of API specs,
+// such as application/x.openapi+gzip;version=3.
+package mimetypes
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+)
+
+// Compression suffixes.
+const (
+	GZip = "gzip"
+	Zip  = "zip"
+)
+
+// Spec formats.
+const (
+	AsyncAPI  = "asyncapi"
+	Discovery = "discovery"
+	GraphQL   = "graphql"
+	OpenAPI   = "openapi"
+	Protobuf  = "protobuf"
+)
+
+// MimeType is a parsed MIME type.
+type MimeType struct {
+	Type        string            // Top-level type, e.g. "application".
+	Subtype     string            // Subtype without suffixes, e.g. "x.openapi".
+	Suffix      string            // Structured syntax suffix, e.g. "json".
+	Compression string            // Compression suffix, either "gzip" or "zip".
+	Parameters  map[string]string // Parameters, e.g. "version".
+}
+
+// Parse parses a MIME type. Type, subtype, and parameter names are
+// case-insensitive and are returned in lower case.
+func Parse(s string) (MimeType, error) {
+	mediaType, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return MimeType{}, err
+	}
+
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return MimeType{}, fmt.Errorf("%q must have the form type/subtype", mediaType)
+	}
+
+	m := MimeType{Type: parts[0], Parameters: params}
+	suffixes := strings.Split(parts[1], "+")
+	for _, part := range suffixes {
+		if part == "" {
+			return MimeType{}, fmt.Errorf("%q has an empty subtype or suffix", mediaType)
+		}
+	}
+	m.Subtype, suffixes = suffixes[0], suffixes[1:]
+
+	if n := len(suffixes); n > 0 && (suffixes[n-1] == GZip || suffixes[n-1] == Zip) {
+		m.Compression, suffixes = suffixes[n-1], suffixes[:n-1]
+	}
+
+	switch len(suffixes) {
+	case 0:
+	case 1:
+		m.Suffix = suffixes[0]
+	default:
+		return MimeType{}, fmt.Errorf("%q has more than one suffix", mediaType)
+	}
+
+	if m.Suffix == GZip || m.Suffix == Zip {
+		return MimeType{}, fmt.Errorf("%q has more than one compression suffix", mediaType)
+	}
+
+	return m, nil
+}
+
+// Normalize returns the normal form of a MIME type.
+func Normalize(s string) (string, error) {
+	m, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return m.String(), nil
+}
+
+// String returns the normal form of the MIME type, with parameters sorted by
+// name and no spaces.
+func (m MimeType) String() string {
+	var b strings.Builder
+	b.WriteString(m.Base())
+	if m.Compression != "" {
+		b.WriteString("+" + m.Compression)
+	}
+
+	keys := make([]string, 0, len(m.Parameters))
+	for k := range m.Parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := m.Parameters[k]
+		if !isToken(v) {
+			v = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+		}
+		b.WriteString(";" + k + "=" + v)
+	}
+
+	return b.String()
+}
+
+// Base returns the type and subtype with any structured syntax suffix, but
+// without compression or parameters.
+func (m MimeType) Base() string {
+	if m.Type == "" {
+		return ""
+	}
+	base := m.Type + "/" + m.Subtype
+	if m.Suffix != "" {
+		base += "+" + m.Suffix
+	}
+	return base
+}
+
+// Format returns the name of the format of the MIME type, such as "openapi".
+// Vendor and experimental prefixes of the subtype are removed.
+func (m MimeType) Format() string {
+	format := m.Subtype
+	for _, prefix := range []string{"x.", "x-", "vnd.oai.", "vnd.aai.", "vnd."} {
+		if strings.HasPrefix(format, prefix) {
+			format = strings.TrimPrefix(format, prefix)
+			break
+		}
+	}
+	return format
+}
+
+// Version returns the version parameter of the MIME type.
+func (m MimeType) Version() string {
+	return m.Parameters["version"]
+}
+
+// MajorVersion returns the first component of the version parameter, so
+// that "3.0.0" and "3" both have the major version "3".
+func (m MimeType) MajorVersion() string {
+	return strings.SplitN(m.Version(), ".", 2)[0]
+}
+
+// WithoutCompression returns the MIME type of the uncompressed contents.
+func (m MimeType) WithoutCompression() MimeType {
+	m.Compression = ""
+	return m
+}
+
+// Is returns true if the MIME type has a format and, if version is not
+// empty, a major version.
+func (m MimeType) Is(format, version string) bool {
+	return m.Format() == format && (version == "" || m.MajorVersion() == version)
+}
+
+// Is returns true if s is a valid MIME type with a format and, if version is
+// not empty, a major version.
+func Is(s, format, version string) bool {
+	m, err := Parse(s)
+	return err == nil && m.Is(format, version)
+}
+
+// IsCompressed returns true if s is a valid MIME type with a compression suffix.
+func IsCompressed(s, compression string) bool {
+	m, err := Parse(s)
+	return err == nil && m.Compression == compression
+}
+
+// ForFormat returns the normal form of the MIME type of a spec format.
+func ForFormat(format, compression, version string) string {
+	m := MimeType{Type: "application", Subtype: "x." + format, Compression: compression}
+	if version != "" {
+		m.Parameters = map[string]string{"version": version}
+	}
+	return m.String()
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	return strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?=`, r)
+	}) < 0
+}
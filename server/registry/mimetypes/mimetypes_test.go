@@ -0,0 +1,187 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimetypes
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in          string
+		normal      string
+		format      string
+		version     string
+		compression string
+	}{
+		{
+			in:          "application/x.openapi+gzip;version=3",
+			normal:      "application/x.openapi+gzip;version=3",
+			format:      OpenAPI,
+			version:     "3",
+			compression: GZip,
+		},
+		{
+			in:      "Application/X.OpenAPI; Version=3.0.0",
+			normal:  "application/x.openapi;version=3.0.0",
+			format:  OpenAPI,
+			version: "3",
+		},
+		{
+			in:      "application/vnd.oai.openapi+json;version=3.0",
+			normal:  "application/vnd.oai.openapi+json;version=3.0",
+			format:  OpenAPI,
+			version: "3",
+		},
+		{
+			in:          "application/x.protobuf+zip",
+			normal:      "application/x.protobuf+zip",
+			format:      Protobuf,
+			compression: Zip,
+		},
+		{
+			in:     `application/json; z=1; a="two words"`,
+			normal: `application/json;a="two words";z=1`,
+			format: "json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			m, err := Parse(test.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %s", test.in, err)
+			}
+			if got := m.String(); got != test.normal {
+				t.Errorf("Parse(%q).String() returned %q, want %q", test.in, got, test.normal)
+			}
+			if got := m.Format(); got != test.format {
+				t.Errorf("Parse(%q).Format() returned %q, want %q", test.in, got, test.format)
+			}
+			if got := m.MajorVersion(); got != test.version {
+				t.Errorf("Parse(%q).MajorVersion() returned %q, want %q", test.in, got, test.version)
+			}
+			if m.Compression != test.compression {
+				t.Errorf("Parse(%q) returned compression %q, want %q", test.in, m.Compression, test.compression)
+			}
+			if again, err := Normalize(test.normal); err != nil || again != test.normal {
+				t.Errorf("Normalize(%q) returned (%q, %v), want it unchanged", test.normal, again, err)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"application",
+		"application/",
+		"/openapi",
+		"application/x.openapi+",
+		"application/x.openapi++gzip",
+		"application/x.openapi+gzip+zip",
+		"application/x.openapi+json+yaml",
+		"application/x.openapi;version",
+		"application/x.openapi;version=3;version=2",
+	} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	gzipped := func(b []byte) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, _ = w.Write(b)
+		_ = w.Close()
+		return buf.Bytes()
+	}
+
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	f, _ := w.Create("google/example/v1/example.proto")
+	_, _ = f.Write([]byte(`syntax = "proto3";`))
+	_ = w.Close()
+
+	tests := []struct {
+		desc     string
+		filename string
+		contents []byte
+		want     string
+	}{
+		{
+			desc:     "openapi v3 yaml",
+			filename: "openapi.yaml",
+			contents: []byte("openapi: 3.0.0\ninfo:\n  title: Example\n"),
+			want:     "application/x.openapi;version=3",
+		},
+		{
+			desc:     "swagger json",
+			contents: []byte(`{"swagger": "2.0", "info": {"title": "Example"}}`),
+			want:     "application/x.openapi;version=2",
+		},
+		{
+			desc:     "gzipped swagger",
+			filename: "swagger.yaml.gz",
+			contents: gzipped([]byte("swagger: 2.0\n")),
+			want:     "application/x.openapi+gzip;version=2",
+		},
+		{
+			desc:     "asyncapi",
+			contents: []byte("asyncapi: 2.2.0\n"),
+			want:     "application/x.asyncapi;version=2",
+		},
+		{
+			desc:     "discovery",
+			contents: []byte(`{"kind": "discovery#restDescription", "discoveryVersion": "v1"}`),
+			want:     "application/x.discovery",
+		},
+		{
+			desc:     "proto file",
+			contents: []byte("// Example.\nsyntax = \"proto3\";\n\npackage example;\n"),
+			want:     "application/x.protobuf",
+		},
+		{
+			desc:     "proto archive",
+			contents: archive.Bytes(),
+			want:     "application/x.protobuf+zip",
+		},
+		{
+			desc:     "graphql by filename",
+			filename: "schema.graphql",
+			contents: []byte("type Query { hello: String }"),
+			want:     "application/x.graphql",
+		},
+		{
+			desc:     "unknown",
+			filename: "notes.txt",
+			contents: []byte("hello"),
+			want:     "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := Detect(test.filename, test.contents); got != test.want {
+				t.Errorf("Detect(%q) returned %q, want %q", test.filename, got, test.want)
+			}
+		})
+	}
+}
@@ -20,25 +20,43 @@ import (
 )
 
 var (
-	projectArtifactCollectionRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/artifacts$", identifier, Location))
-	apiArtifactCollectionRegexp        = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/artifacts$", identifier, Location, identifier))
-	versionArtifactCollectionRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/artifacts$", identifier, Location, identifier, identifier))
-	specArtifactCollectionRegexp       = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts$", identifier, Location, identifier, identifier, identifier))
-	deploymentArtifactCollectionRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/deployments/%s/artifacts$", identifier, Location, identifier, identifier))
-
-	projectArtifactRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/artifacts/%s$", identifier, Location, identifier))
-	apiArtifactRegexp        = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/artifacts/%s$", identifier, Location, identifier, identifier))
-	versionArtifactRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/artifacts/%s$", identifier, Location, identifier, identifier, identifier))
-	specArtifactRegexp       = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts/%s$", identifier, Location, identifier, identifier, identifier, identifier))
-	deploymentArtifactRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/deployments/%s/artifacts/%s$", identifier, Location, identifier, identifier, identifier))
+	projectArtifactCollectionRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/artifacts$", identifier, identifier))
+	apiArtifactCollectionRegexp        = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/artifacts$", identifier, identifier, identifier))
+	versionArtifactCollectionRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/artifacts$", identifier, identifier, identifier, identifier))
+	specArtifactCollectionRegexp       = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts$", identifier, identifier, identifier, identifier, identifier))
+	deploymentArtifactCollectionRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/deployments/%s/artifacts$", identifier, identifier, identifier, identifier))
+
+	projectArtifactRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/artifacts/%s$", identifier, identifier, identifier))
+	apiArtifactRegexp        = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/artifacts/%s$", identifier, identifier, identifier, identifier))
+	versionArtifactRegexp    = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/artifacts/%s$", identifier, identifier, identifier, identifier, identifier))
+	specArtifactRegexp       = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts/%s$", identifier, identifier, identifier, identifier, identifier, identifier))
+	deploymentArtifactRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/deployments/%s/artifacts/%s$", identifier, identifier, identifier, identifier, identifier))
+
+	artifactRevisionRegexp = regexp.MustCompile(fmt.Sprintf("^([^@]+)@%s$", revisionTag))
 )
 
 // Artifact represents a resource name for an artifact.
+// It optionally identifies a revision of the artifact.
 type Artifact struct {
 	name interface {
 		String() string
 		Validate() error
 	}
+	revisionID string
+}
+
+// RevisionID returns the artifact's revision ID or tag, or empty string if it doesn't have one.
+func (a Artifact) RevisionID() string {
+	return a.revisionID
+}
+
+// Revision returns the name of a revision of the artifact.
+// An empty revision ID names the artifact itself.
+func (a Artifact) Revision(id string) Artifact {
+	return Artifact{
+		name:       a.name,
+		revisionID: id,
+	}
 }
 
 // ProjectID returns the artifact's project ID, or empty string if it doesn't have one.
@@ -59,6 +77,24 @@ func (a Artifact) ProjectID() string {
 	}
 }
 
+// LocationID returns the artifact's location ID, or empty string if it doesn't have one.
+func (a Artifact) LocationID() string {
+	switch name := a.name.(type) {
+	case projectArtifact:
+		return name.LocationID
+	case apiArtifact:
+		return name.LocationID
+	case versionArtifact:
+		return name.LocationID
+	case specArtifact:
+		return name.LocationID
+	case deploymentArtifact:
+		return name.LocationID
+	default:
+		return ""
+	}
+}
+
 // ApiID returns the artifact's API ID, or empty string if it doesn't have one.
 func (a Artifact) ApiID() string {
 	switch name := a.name.(type) {
@@ -125,9 +161,21 @@ func (a Artifact) ArtifactID() string {
 	}
 }
 
+// Normal returns the artifact name in normalized form.
+func (a Artifact) Normal() Artifact {
+	normal, err := ParseArtifact(a.String())
+	if err != nil {
+		return a
+	}
+	return normal
+}
+
 // Validate returns an error if the resource name is invalid.
 // For backward compatibility, names should only be validated at creation time.
 func (a Artifact) Validate() error {
+	if a.revisionID != "" {
+		return fmt.Errorf("invalid artifact name %q: must not include a revision", a)
+	}
 	return a.name.Validate()
 }
 
@@ -150,11 +198,23 @@ func (a Artifact) Parent() string {
 }
 
 func (a Artifact) String() string {
+	if a.revisionID != "" {
+		return normalize(fmt.Sprintf("%s@%s", a.name, a.revisionID))
+	}
 	return normalize(a.name.String())
 }
 
 // ParseArtifact parses the name of an artifact.
+// The name may identify a revision of the artifact with an "@revision" suffix.
 func ParseArtifact(name string) (Artifact, error) {
+	if m := artifactRevisionRegexp.FindStringSubmatch(name); m != nil {
+		artifact, err := ParseArtifact(m[1])
+		if err != nil {
+			return Artifact{}, err
+		}
+		return artifact.Revision(m[2]), nil
+	}
+
 	if n, err := parseSpecArtifact(name); err == nil {
 		return Artifact{name: n}, nil
 	} else if n, err := parseVersionArtifact(name); err == nil {
@@ -201,6 +261,7 @@ func ParseArtifactCollection(name string) (Artifact, error) {
 
 type projectArtifact struct {
 	ProjectID  string
+	LocationID string
 	ArtifactID string
 }
 
@@ -209,16 +270,27 @@ func (a projectArtifact) Validate() error {
 		return fmt.Errorf("invalid project artifact name %q: must match %q", name, projectArtifactRegexp)
 	}
 
+	if err := a.location().Validate(); err != nil {
+		return err
+	}
+
 	return validateID(a.ArtifactID)
 }
 
+func (a projectArtifact) location() Location {
+	return Location{
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+	}
+}
+
 func (a projectArtifact) Parent() string {
-	return fmt.Sprintf("projects/%s/locations/%s", a.ProjectID, Location)
+	return a.location().String()
 }
 
 func (a projectArtifact) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/artifacts/%s",
-		a.ProjectID, Location, a.ArtifactID))
+		a.ProjectID, a.LocationID, a.ArtifactID))
 }
 
 func parseProjectArtifact(name string) (projectArtifact, error) {
@@ -229,7 +301,8 @@ func parseProjectArtifact(name string) (projectArtifact, error) {
 	m := projectArtifactRegexp.FindStringSubmatch(name)
 	artifact := projectArtifact{
 		ProjectID:  m[1],
-		ArtifactID: m[2],
+		LocationID: m[2],
+		ArtifactID: m[3],
 	}
 
 	return artifact, nil
@@ -243,6 +316,7 @@ func parseProjectArtifactCollection(name string) (projectArtifact, error) {
 	m := projectArtifactCollectionRegexp.FindStringSubmatch(name)
 	artifact := projectArtifact{
 		ProjectID:  m[1],
+		LocationID: m[2],
 		ArtifactID: "",
 	}
 
@@ -251,6 +325,7 @@ func parseProjectArtifactCollection(name string) (projectArtifact, error) {
 
 type apiArtifact struct {
 	ProjectID  string
+	LocationID string
 	ApiID      string
 	ArtifactID string
 }
@@ -265,14 +340,15 @@ func (a apiArtifact) Validate() error {
 
 func (a apiArtifact) Parent() string {
 	return Api{
-		ProjectID: a.ProjectID,
-		ApiID:     a.ApiID,
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+		ApiID:      a.ApiID,
 	}.String()
 }
 
 func (a apiArtifact) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/artifacts/%s",
-		a.ProjectID, Location, a.ApiID, a.ArtifactID))
+		a.ProjectID, a.LocationID, a.ApiID, a.ArtifactID))
 }
 
 func parseApiArtifact(name string) (apiArtifact, error) {
@@ -283,8 +359,9 @@ func parseApiArtifact(name string) (apiArtifact, error) {
 	m := apiArtifactRegexp.FindStringSubmatch(name)
 	artifact := apiArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		ArtifactID: m[3],
+		LocationID: m[2],
+		ApiID:      m[3],
+		ArtifactID: m[4],
 	}
 
 	return artifact, nil
@@ -298,7 +375,8 @@ func parseApiArtifactCollection(name string) (apiArtifact, error) {
 	m := apiArtifactCollectionRegexp.FindStringSubmatch(name)
 	artifact := apiArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
+		LocationID: m[2],
+		ApiID:      m[3],
 		ArtifactID: "",
 	}
 
@@ -307,6 +385,7 @@ func parseApiArtifactCollection(name string) (apiArtifact, error) {
 
 type versionArtifact struct {
 	ProjectID  string
+	LocationID string
 	ApiID      string
 	VersionID  string
 	ArtifactID string
@@ -322,15 +401,16 @@ func (a versionArtifact) Validate() error {
 
 func (a versionArtifact) Parent() string {
 	return Version{
-		ProjectID: a.ProjectID,
-		ApiID:     a.ApiID,
-		VersionID: a.VersionID,
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+		ApiID:      a.ApiID,
+		VersionID:  a.VersionID,
 	}.String()
 }
 
 func (a versionArtifact) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/artifacts/%s",
-		a.ProjectID, Location, a.ApiID, a.VersionID, a.ArtifactID))
+		a.ProjectID, a.LocationID, a.ApiID, a.VersionID, a.ArtifactID))
 }
 
 func parseVersionArtifact(name string) (versionArtifact, error) {
@@ -341,9 +421,10 @@ func parseVersionArtifact(name string) (versionArtifact, error) {
 	m := versionArtifactRegexp.FindStringSubmatch(name)
 	artifact := versionArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		VersionID:  m[3],
-		ArtifactID: m[4],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
+		ArtifactID: m[5],
 	}
 
 	return artifact, nil
@@ -357,8 +438,9 @@ func parseVersionArtifactCollection(name string) (versionArtifact, error) {
 	m := versionArtifactCollectionRegexp.FindStringSubmatch(name)
 	artifact := versionArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		VersionID:  m[3],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
 		ArtifactID: "",
 	}
 
@@ -367,6 +449,7 @@ func parseVersionArtifactCollection(name string) (versionArtifact, error) {
 
 type specArtifact struct {
 	ProjectID  string
+	LocationID string
 	ApiID      string
 	VersionID  string
 	SpecID     string
@@ -383,16 +466,17 @@ func (a specArtifact) Validate() error {
 
 func (a specArtifact) Parent() string {
 	return Spec{
-		ProjectID: a.ProjectID,
-		ApiID:     a.ApiID,
-		VersionID: a.VersionID,
-		SpecID:    a.SpecID,
+		ProjectID:  a.ProjectID,
+		LocationID: a.LocationID,
+		ApiID:      a.ApiID,
+		VersionID:  a.VersionID,
+		SpecID:     a.SpecID,
 	}.String()
 }
 
 func (a specArtifact) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s/artifacts/%s",
-		a.ProjectID, Location, a.ApiID, a.VersionID, a.SpecID, a.ArtifactID))
+		a.ProjectID, a.LocationID, a.ApiID, a.VersionID, a.SpecID, a.ArtifactID))
 }
 
 func parseSpecArtifact(name string) (specArtifact, error) {
@@ -403,10 +487,11 @@ func parseSpecArtifact(name string) (specArtifact, error) {
 	m := specArtifactRegexp.FindStringSubmatch(name)
 	artifact := specArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		VersionID:  m[3],
-		SpecID:     m[4],
-		ArtifactID: m[5],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
+		SpecID:     m[5],
+		ArtifactID: m[6],
 	}
 
 	return artifact, nil
@@ -420,9 +505,10 @@ func parseSpecArtifactCollection(name string) (specArtifact, error) {
 	m := specArtifactCollectionRegexp.FindStringSubmatch(name)
 	artifact := specArtifact{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		VersionID:  m[3],
-		SpecID:     m[4],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
+		SpecID:     m[5],
 		ArtifactID: "",
 	}
 
@@ -431,6 +517,7 @@ func parseSpecArtifactCollection(name string) (specArtifact, error) {
 
 type deploymentArtifact struct {
 	ProjectID    string
+	LocationID   string
 	ApiID        string
 	DeploymentID string
 	ArtifactID   string
@@ -447,6 +534,7 @@ func (a deploymentArtifact) Validate() error {
 func (a deploymentArtifact) Parent() string {
 	return Deployment{
 		ProjectID:    a.ProjectID,
+		LocationID:   a.LocationID,
 		ApiID:        a.ApiID,
 		DeploymentID: a.DeploymentID,
 	}.String()
@@ -454,7 +542,7 @@ func (a deploymentArtifact) Parent() string {
 
 func (a deploymentArtifact) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/deployments/%s/artifacts/%s",
-		a.ProjectID, Location, a.ApiID, a.DeploymentID, a.ArtifactID))
+		a.ProjectID, a.LocationID, a.ApiID, a.DeploymentID, a.ArtifactID))
 }
 
 func parseDeploymentArtifact(name string) (deploymentArtifact, error) {
@@ -465,9 +553,10 @@ func parseDeploymentArtifact(name string) (deploymentArtifact, error) {
 	m := deploymentArtifactRegexp.FindStringSubmatch(name)
 	artifact := deploymentArtifact{
 		ProjectID:    m[1],
-		ApiID:        m[2],
-		DeploymentID: m[3],
-		ArtifactID:   m[4],
+		LocationID:   m[2],
+		ApiID:        m[3],
+		DeploymentID: m[4],
+		ArtifactID:   m[5],
 	}
 
 	return artifact, nil
@@ -481,8 +570,9 @@ func parseDeploymentArtifactCollection(name string) (deploymentArtifact, error)
 	m := deploymentArtifactCollectionRegexp.FindStringSubmatch(name)
 	artifact := deploymentArtifact{
 		ProjectID:    m[1],
-		ApiID:        m[2],
-		DeploymentID: m[3],
+		LocationID:   m[2],
+		ApiID:        m[3],
+		DeploymentID: m[4],
 		ArtifactID:   "",
 	}
 
@@ -0,0 +1,89 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package names
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ArtifactType represents a resource name for an artifact type.
+type ArtifactType struct {
+	ProjectID      string
+	LocationID     string
+	ArtifactTypeID string
+}
+
+// Validate returns an error if the resource name is invalid.
+// For backward compatibility, names should only be validated at creation time.
+func (t ArtifactType) Validate() error {
+	r := artifactTypeRegexp()
+	if name := t.String(); !r.MatchString(name) {
+		return fmt.Errorf("invalid artifact type name %q: must match %q", name, r)
+	}
+
+	if err := t.Location().Validate(); err != nil {
+		return err
+	}
+
+	return validateID(t.ArtifactTypeID)
+}
+
+// Project returns the name of this resource's parent project.
+func (t ArtifactType) Project() Project {
+	return Project{
+		ProjectID: t.ProjectID,
+	}
+}
+
+// Location returns the name of this resource's parent location.
+func (t ArtifactType) Location() Location {
+	return Location{
+		ProjectID:  t.ProjectID,
+		LocationID: t.LocationID,
+	}
+}
+
+// Parent returns this resource's parent location resource name.
+func (t ArtifactType) Parent() string {
+	return t.Location().String()
+}
+
+func (t ArtifactType) String() string {
+	// IDs are normalized individually because the collection ID is mixed-case.
+	return fmt.Sprintf("projects/%s/locations/%s/artifactTypes/%s",
+		normalize(t.ProjectID), normalize(t.LocationID), normalize(t.ArtifactTypeID))
+}
+
+// artifactTypeRegexp returns a regular expression that matches an artifact type resource name.
+func artifactTypeRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/artifactTypes/%s$",
+		identifier, identifier, identifier))
+}
+
+// ParseArtifactType parses the name of an artifact type.
+func ParseArtifactType(name string) (ArtifactType, error) {
+	r := artifactTypeRegexp()
+	if !r.MatchString(name) {
+		return ArtifactType{}, fmt.Errorf("invalid artifact type name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return ArtifactType{
+		ProjectID:      m[1],
+		LocationID:     m[2],
+		ArtifactTypeID: m[3],
+	}, nil
+}
@@ -64,8 +64,10 @@ func normalize(identifier string) string {
 	return strings.ToLower(identifier)
 }
 
-// Location is included in resource names immediately following the project_id.
-const Location = "global"
+// DefaultLocation is the location of resources that are not associated
+// with a specific region. Locations are included in resource names
+// immediately following the project_id.
+const DefaultLocation = "global"
 
 // Name is an interface that represents resource names.
 type Name interface {
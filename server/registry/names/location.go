@@ -0,0 +1,120 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package names
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Location represents a resource name for a location.
+type Location struct {
+	ProjectID  string
+	LocationID string
+}
+
+// Validate returns an error if the resource name is invalid.
+// For backward compatibility, names should only be validated at creation time.
+func (l Location) Validate() error {
+	r := locationRegexp()
+	if name := l.String(); !r.MatchString(name) {
+		return fmt.Errorf("invalid location name %q: must match %q", name, r)
+	}
+
+	return validateID(l.LocationID)
+}
+
+// Project returns the name of this resource's parent project.
+func (l Location) Project() Project {
+	return Project{
+		ProjectID: l.ProjectID,
+	}
+}
+
+// Api returns an API with the provided ID and this resource as its parent.
+func (l Location) Api(id string) Api {
+	return Api{
+		ProjectID:  l.ProjectID,
+		LocationID: l.LocationID,
+		ApiID:      id,
+	}
+}
+
+// Artifact returns an artifact with the provided ID and this resource as its parent.
+func (l Location) Artifact(id string) Artifact {
+	return Artifact{
+		name: projectArtifact{
+			ProjectID:  l.ProjectID,
+			LocationID: l.LocationID,
+			ArtifactID: id,
+		},
+	}
+}
+
+// ArtifactType returns an artifact type with the provided ID and this resource as its parent.
+func (l Location) ArtifactType(id string) ArtifactType {
+	return ArtifactType{
+		ProjectID:      l.ProjectID,
+		LocationID:     l.LocationID,
+		ArtifactTypeID: id,
+	}
+}
+
+// Parent returns this resource's parent project resource name.
+func (l Location) Parent() string {
+	return l.Project().String()
+}
+
+func (l Location) String() string {
+	return normalize(fmt.Sprintf("projects/%s/locations/%s", l.ProjectID, l.LocationID))
+}
+
+// locationCollectionRegexp returns a regular expression that matches a collection of locations.
+func locationCollectionRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations$", identifier))
+}
+
+// locationRegexp returns a regular expression that matches a location resource name.
+func locationRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s$", identifier, identifier))
+}
+
+// ParseLocation parses the name of a location.
+func ParseLocation(name string) (Location, error) {
+	r := locationRegexp()
+	if !r.MatchString(name) {
+		return Location{}, fmt.Errorf("invalid location name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return Location{
+		ProjectID:  m[1],
+		LocationID: m[2],
+	}, nil
+}
+
+// ParseLocationCollection parses the name of a location collection.
+func ParseLocationCollection(name string) (Location, error) {
+	r := locationCollectionRegexp()
+	if !r.MatchString(name) {
+		return Location{}, fmt.Errorf("invalid location collection name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return Location{
+		ProjectID:  m[1],
+		LocationID: "",
+	}, nil
+}
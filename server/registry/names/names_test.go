@@ -55,6 +55,41 @@ func TestResourceNames(t *testing.T) {
 				"-",
 			},
 		},
+		{
+			name: "location collections",
+			check: func(name string) bool {
+				_, err := ParseLocationCollection(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations",
+				"projects/-/locations",
+			},
+			fail: []string{
+				"-",
+				"projects/google",
+				"projects/google/locations/global",
+			},
+		},
+		{
+			name: "location",
+			check: func(name string) bool {
+				_, err := ParseLocation(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations/global",
+				"projects/google/locations/us-central1",
+				"projects/-/locations/-",
+			},
+			fail: []string{
+				"-",
+				"projects/google",
+				"projects/google/locations",
+				"projects/google/locations/",
+				"projects/google/locations/global/apis",
+			},
+		},
 		{
 			name: "api collections",
 			check: func(name string) bool {
@@ -64,6 +99,8 @@ func TestResourceNames(t *testing.T) {
 			pass: []string{
 				"projects/google/locations/global/apis",
 				"projects/-/locations/global/apis",
+				"projects/google/locations/us-central1/apis",
+				"projects/google/locations/-/apis",
 			},
 			fail: []string{
 				"-",
@@ -80,6 +117,8 @@ func TestResourceNames(t *testing.T) {
 				"projects/-/locations/global/apis/-",
 				"projects/123/locations/global/apis/abc",
 				"projects/1-2-3/locations/global/apis/abc",
+				"projects/google/locations/europe-west1/apis/sample",
+				"projects/google/locations/-/apis/-",
 			},
 			fail: []string{
 				"-",
@@ -268,9 +307,51 @@ func TestResourceNames(t *testing.T) {
 				"projects/google/locations/global/apis/sample/versions/v1/artifacts/test-artifact",
 				"projects/google/locations/global/apis/sample/versions/v1/specs/openapi.yaml/artifacts/test-artifact",
 				"projects/google/locations/global/apis/sample/deployments/prod/artifacts/test-artifact",
+				"projects/google/locations/us-central1/artifacts/test-artifact",
+				"projects/google/locations/us-central1/apis/sample/versions/v1/specs/openapi.yaml/artifacts/test-artifact",
+				"projects/google/locations/global/artifacts/test-artifact@1234567890abcdef",
+				"projects/google/locations/global/apis/sample/deployments/prod/artifacts/test-artifact@prod-tag",
 			},
 			fail: []string{
 				"-",
+				"projects/google/locations//artifacts/test-artifact",
+				"projects/google/locations/global/artifacts/test-artifact@",
+				"projects/google/locations/global/artifacts/test-artifact@1234@5678",
+				"projects/google/locations/global/artifacts@1234",
+			},
+		},
+		{
+			name: "artifact type",
+			check: func(name string) bool {
+				_, err := ParseArtifactType(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations/global/artifactTypes/lint",
+				"projects/google/locations/us-central1/artifactTypes/complexity.v1",
+			},
+			fail: []string{
+				"-",
+				"projects/google/locations/global/artifactTypes",
+				"projects/google/locations/global/artifacttypes/lint",
+				"projects/google/locations/global/apis/sample/artifactTypes/lint",
+			},
+		},
+		{
+			name: "relationship",
+			check: func(name string) bool {
+				_, err := ParseRelationship(name)
+				return err == nil
+			},
+			pass: []string{
+				"projects/google/locations/global/apis/sample/relationships/uses-auth",
+				"projects/google/locations/us-central1/apis/sample/relationships/uses-auth",
+			},
+			fail: []string{
+				"-",
+				"projects/google/locations/global/apis/sample/relationships",
+				"projects/google/locations/global/relationships/uses-auth",
+				"projects/google/locations/global/apis/sample/versions/v1/relationships/uses-auth",
 			},
 		},
 	}
@@ -287,3 +368,75 @@ func TestResourceNames(t *testing.T) {
 		}
 	}
 }
+
+func TestLocations(t *testing.T) {
+	spec, err := ParseSpec("projects/google/locations/us-central1/apis/sample/versions/v1/specs/openapi.yaml")
+	if err != nil {
+		t.Fatalf("ParseSpec() returned error: %s", err)
+	}
+
+	if got, want := spec.LocationID, "us-central1"; got != want {
+		t.Errorf("ParseSpec() returned location ID %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		desc string
+		got  string
+		want string
+	}{
+		{"api parent", spec.Api().Parent(), "projects/google/locations/us-central1"},
+		{"version", spec.Version().String(), "projects/google/locations/us-central1/apis/sample/versions/v1"},
+		{"revision", spec.Revision("abc").String(), "projects/google/locations/us-central1/apis/sample/versions/v1/specs/openapi.yaml@abc"},
+		{"deployment", spec.Api().Deployment("prod").String(), "projects/google/locations/us-central1/apis/sample/deployments/prod"},
+		{"location artifact", spec.Api().Location().Artifact("a").String(), "projects/google/locations/us-central1/artifacts/a"},
+		{"location artifact parent", spec.Api().Location().Artifact("a").Parent(), "projects/google/locations/us-central1"},
+		{"spec artifact", spec.Artifact("a").String(), "projects/google/locations/us-central1/apis/sample/versions/v1/specs/openapi.yaml/artifacts/a"},
+		{"project location", spec.Project().Location(DefaultLocation).Api("x").String(), "projects/google/locations/global/apis/x"},
+		{"artifact type", spec.Api().Location().ArtifactType("Lint").String(), "projects/google/locations/us-central1/artifactTypes/lint"},
+		{"relationship", spec.Api().Relationship("uses-auth").String(), "projects/google/locations/us-central1/apis/sample/relationships/uses-auth"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s: got %q, want %q", test.desc, test.got, test.want)
+		}
+	}
+}
+
+func TestArtifactRevisions(t *testing.T) {
+	name, err := ParseArtifact("projects/google/locations/global/apis/sample/artifacts/complexity@abc123")
+	if err != nil {
+		t.Fatalf("ParseArtifact() returned error: %s", err)
+	}
+
+	if got, want := name.RevisionID(), "abc123"; got != want {
+		t.Errorf("ParseArtifact() returned revision ID %q, want %q", got, want)
+	}
+
+	if got, want := name.ArtifactID(), "complexity"; got != want {
+		t.Errorf("ParseArtifact() returned artifact ID %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		desc string
+		got  string
+		want string
+	}{
+		{"revision", name.String(), "projects/google/locations/global/apis/sample/artifacts/complexity@abc123"},
+		{"artifact", name.Revision("").String(), "projects/google/locations/global/apis/sample/artifacts/complexity"},
+		{"other revision", name.Revision("def456").String(), "projects/google/locations/global/apis/sample/artifacts/complexity@def456"},
+		{"parent", name.Parent(), "projects/google/locations/global/apis/sample"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s: got %q, want %q", test.desc, test.got, test.want)
+		}
+	}
+
+	if err := name.Validate(); err == nil {
+		t.Errorf("Validate(%q) succeeded, expected error for revision name", name)
+	}
+
+	if err := name.Revision("").Validate(); err != nil {
+		t.Errorf("Validate(%q) returned error: %s", name.Revision(""), err)
+	}
+}
@@ -35,21 +35,11 @@ func (p Project) Validate() error {
 	return validateID(p.ProjectID)
 }
 
-// Api returns an API with the provided ID and this resource as its parent.
-func (p Project) Api(id string) Api {
-	return Api{
-		ProjectID: p.ProjectID,
-		ApiID:     id,
-	}
-}
-
-// Artifact returns an artifact with the provided ID and this resource as its parent.
-func (p Project) Artifact(id string) Artifact {
-	return Artifact{
-		name: projectArtifact{
-			ProjectID:  p.ProjectID,
-			ArtifactID: id,
-		},
+// Location returns a location with the provided ID and this resource as its parent.
+func (p Project) Location(id string) Location {
+	return Location{
+		ProjectID:  p.ProjectID,
+		LocationID: id,
 	}
 }
 
@@ -67,11 +57,6 @@ func projectRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s$", identifier))
 }
 
-// projectWithLocationRegexp returns a regular expression that matches a project resource name followed by a location.
-func projectWithLocationRegexp() *regexp.Regexp {
-	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s$", identifier, identifier))
-}
-
 // ParseProject parses the name of a project.
 func ParseProject(name string) (Project, error) {
 	r := projectRegexp()
@@ -96,16 +81,3 @@ func ParseProjectCollection(name string) (Project, error) {
 		ProjectID: "",
 	}, nil
 }
-
-// ParseProjectWithLocation parses the name of a project.
-func ParseProjectWithLocation(name string) (Project, error) {
-	r := projectWithLocationRegexp()
-	if !r.MatchString(name) {
-		return Project{}, fmt.Errorf("invalid project name %q: must match %q", name, r)
-	}
-
-	m := r.FindStringSubmatch(name)
-	return Project{
-		ProjectID: m[1],
-	}, nil
-}
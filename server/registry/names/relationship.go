@@ -0,0 +1,85 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package names
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Relationship represents a resource name for a relationship.
+type Relationship struct {
+	ProjectID      string
+	LocationID     string
+	ApiID          string
+	RelationshipID string
+}
+
+// Validate returns an error if the resource name is invalid.
+// For backward compatibility, names should only be validated at creation time.
+func (r Relationship) Validate() error {
+	re := relationshipRegexp()
+	if name := r.String(); !re.MatchString(name) {
+		return fmt.Errorf("invalid relationship name %q: must match %q", name, re)
+	}
+
+	return validateID(r.RelationshipID)
+}
+
+// Project returns the name of this resource's parent project.
+func (r Relationship) Project() Project {
+	return r.Api().Project()
+}
+
+// Api returns the parent API for this resource.
+func (r Relationship) Api() Api {
+	return Api{
+		ProjectID:  r.ProjectID,
+		LocationID: r.LocationID,
+		ApiID:      r.ApiID,
+	}
+}
+
+// Parent returns this resource's parent API resource name.
+func (r Relationship) Parent() string {
+	return r.Api().String()
+}
+
+func (r Relationship) String() string {
+	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/relationships/%s",
+		r.ProjectID, r.LocationID, r.ApiID, r.RelationshipID))
+}
+
+// relationshipRegexp returns a regular expression that matches a relationship resource name.
+func relationshipRegexp() *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/relationships/%s$",
+		identifier, identifier, identifier, identifier))
+}
+
+// ParseRelationship parses the name of a relationship.
+func ParseRelationship(name string) (Relationship, error) {
+	r := relationshipRegexp()
+	if !r.MatchString(name) {
+		return Relationship{}, fmt.Errorf("invalid relationship name %q: must match %q", name, r)
+	}
+
+	m := r.FindStringSubmatch(name)
+	return Relationship{
+		ProjectID:      m[1],
+		LocationID:     m[2],
+		ApiID:          m[3],
+		RelationshipID: m[4],
+	}, nil
+}
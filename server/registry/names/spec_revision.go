@@ -19,11 +19,12 @@ import (
 	"regexp"
 )
 
-var specRevisionRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s$", identifier, Location, identifier, identifier, identifier, revisionTag))
+var specRevisionRegexp = regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s$", identifier, identifier, identifier, identifier, identifier, revisionTag))
 
 // SpecRevision represents a resource name for an API spec revision.
 type SpecRevision struct {
 	ProjectID  string
+	LocationID string
 	ApiID      string
 	VersionID  string
 	SpecID     string
@@ -33,16 +34,17 @@ type SpecRevision struct {
 // Spec returns the parent spec for this resource.
 func (s SpecRevision) Spec() Spec {
 	return Spec{
-		ProjectID: s.ProjectID,
-		ApiID:     s.ApiID,
-		VersionID: s.VersionID,
-		SpecID:    s.SpecID,
+		ProjectID:  s.ProjectID,
+		LocationID: s.LocationID,
+		ApiID:      s.ApiID,
+		VersionID:  s.VersionID,
+		SpecID:     s.SpecID,
 	}
 }
 
 func (s SpecRevision) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s/specs/%s@%s",
-		s.ProjectID, Location, s.ApiID, s.VersionID, s.SpecID, s.RevisionID))
+		s.ProjectID, s.LocationID, s.ApiID, s.VersionID, s.SpecID, s.RevisionID))
 }
 
 // ParseSpecRevision parses the name of a spec.
@@ -54,10 +56,11 @@ func ParseSpecRevision(name string) (SpecRevision, error) {
 	m := specRevisionRegexp.FindStringSubmatch(name)
 	revision := SpecRevision{
 		ProjectID:  m[1],
-		ApiID:      m[2],
-		VersionID:  m[3],
-		SpecID:     m[4],
-		RevisionID: m[5],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
+		SpecID:     m[5],
+		RevisionID: m[6],
 	}
 
 	return revision, nil
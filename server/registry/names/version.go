@@ -21,9 +21,10 @@ import (
 
 // Version represents a resource name for an API version.
 type Version struct {
-	ProjectID string
-	ApiID     string
-	VersionID string
+	ProjectID  string
+	LocationID string
+	ApiID      string
+	VersionID  string
 }
 
 // Validate returns an error if the resource name is invalid.
@@ -45,8 +46,9 @@ func (v Version) Project() Project {
 // Api returns the parent API for this resource.
 func (v Version) Api() Api {
 	return Api{
-		ProjectID: v.ProjectID,
-		ApiID:     v.ApiID,
+		ProjectID:  v.ProjectID,
+		LocationID: v.LocationID,
+		ApiID:      v.ApiID,
 	}
 }
 
@@ -55,6 +57,7 @@ func (v Version) Artifact(id string) Artifact {
 	return Artifact{
 		name: versionArtifact{
 			ProjectID:  v.ProjectID,
+			LocationID: v.LocationID,
 			ApiID:      v.ApiID,
 			VersionID:  v.VersionID,
 			ArtifactID: id,
@@ -65,10 +68,11 @@ func (v Version) Artifact(id string) Artifact {
 // Spec returns an API spec with the provided ID and this resource as its parent.
 func (v Version) Spec(id string) Spec {
 	return Spec{
-		ProjectID: v.ProjectID,
-		ApiID:     v.ApiID,
-		VersionID: v.VersionID,
-		SpecID:    id,
+		ProjectID:  v.ProjectID,
+		LocationID: v.LocationID,
+		ApiID:      v.ApiID,
+		VersionID:  v.VersionID,
+		SpecID:     id,
 	}
 }
 
@@ -79,19 +83,19 @@ func (v Version) Parent() string {
 
 func (v Version) String() string {
 	return normalize(fmt.Sprintf("projects/%s/locations/%s/apis/%s/versions/%s",
-		v.ProjectID, Location, v.ApiID, v.VersionID))
+		v.ProjectID, v.LocationID, v.ApiID, v.VersionID))
 }
 
 // versionCollectionRegexp returns a regular expression that matches a collection of versions.
 func versionCollectionRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions$",
-		identifier, Location, identifier))
+		identifier, identifier, identifier))
 }
 
 // versionRegexp returns a regular expression that matches a version resource name.
 func versionRegexp() *regexp.Regexp {
 	return regexp.MustCompile(fmt.Sprintf("^projects/%s/locations/%s/apis/%s/versions/%s$",
-		identifier, Location, identifier, identifier))
+		identifier, identifier, identifier, identifier))
 }
 
 // ParseVersion parses the name of a version.
@@ -103,9 +107,10 @@ func ParseVersion(name string) (Version, error) {
 
 	m := r.FindStringSubmatch(name)
 	return Version{
-		ProjectID: m[1],
-		ApiID:     m[2],
-		VersionID: m[3],
+		ProjectID:  m[1],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  m[4],
 	}, nil
 }
 
@@ -118,8 +123,9 @@ func ParseVersionCollection(name string) (Version, error) {
 
 	m := r.FindStringSubmatch(name)
 	return Version{
-		ProjectID: m[1],
-		ApiID:     m[2],
-		VersionID: "",
+		ProjectID:  m[1],
+		LocationID: m[2],
+		ApiID:      m[3],
+		VersionID:  "",
 	}, nil
 }
@@ -16,6 +16,7 @@ package registry
 
 import (
 	"context"
+	"sync"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/apigee/registry/log"
@@ -29,25 +30,25 @@ import (
 const TopicName = "registry-events"
 
 func (s *RegistryServer) notify(ctx context.Context, change rpc.Notification_Change, resource string) {
-	if !s.notifyEnabled {
-		return
-	}
+	s.notifyTransition(ctx, change, resource, nil)
+}
 
-	logger := log.FromContext(ctx)
-	if s.projectID == "" {
-		logger.Warn("Notifications are enabled but project ID is not set. Skipping notification.")
-		return
-	}
+// notifyTransition records a change that made a lifecycle transition in the
+// history of the changed resource and publishes a notification of it. If
+// transition is nil, the notification is the same as one published by notify.
+func (s *RegistryServer) notifyTransition(ctx context.Context, change rpc.Notification_Change, resource string, transition *rpc.LifecycleTransition) {
+	s.recordChange(ctx, change, resource)
 
-	client, err := pubsub.NewClient(ctx, s.projectID)
-	if err != nil {
-		logger.WithError(err).Error("Failed to create PubSub client.")
+	s.mu.RLock()
+	n := s.notifier
+	s.mu.RUnlock()
+	if n == nil {
 		return
 	}
-	defer client.Close()
 
-	if _, err := client.CreateTopic(ctx, TopicName); err != nil && status.Code(err) != codes.AlreadyExists {
-		logger.WithError(err).Error("Failed to create PubSub topic.")
+	logger := log.FromContext(ctx)
+	if n.projectID == "" {
+		logger.Warn("Notifications are enabled but project ID is not set. Skipping notification.")
 		return
 	}
 
@@ -55,6 +56,7 @@ func (s *RegistryServer) notify(ctx context.Context, change rpc.Notification_Cha
 		Change:     change,
 		Resource:   resource,
 		ChangeTime: timestamppb.Now(),
+		Transition: transition,
 	}
 
 	msg, err := protojson.Marshal(notification)
@@ -63,18 +65,75 @@ func (s *RegistryServer) notify(ctx context.Context, change rpc.Notification_Cha
 		return
 	}
 
-	topic := client.Topic(TopicName)
-	defer topic.Stop()
+	n.publish(ctx, logger, msg)
+}
+
+// notifier publishes notifications to a Pub/Sub topic.
+// Notifications are published asynchronously; close waits for them to be sent.
+type notifier struct {
+	projectID string
+
+	mu      sync.Mutex
+	client  *pubsub.Client
+	topic   *pubsub.Topic
+	closed  bool
+	pending sync.WaitGroup
+}
 
-	result := topic.Publish(ctx, &pubsub.Message{
+func newNotifier(projectID string) *notifier {
+	return &notifier{projectID: projectID}
+}
+
+func (n *notifier) publish(ctx context.Context, logger log.Logger, msg []byte) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		logger.Warn("Notifications are being reconfigured. Skipping notification.")
+		return
+	}
+
+	if n.topic == nil {
+		client, err := pubsub.NewClient(context.Background(), n.projectID)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create PubSub client.")
+			return
+		}
+
+		if _, err := client.CreateTopic(ctx, TopicName); err != nil && status.Code(err) != codes.AlreadyExists {
+			logger.WithError(err).Error("Failed to create PubSub topic.")
+			client.Close()
+			return
+		}
+
+		n.client, n.topic = client, client.Topic(TopicName)
+	}
+
+	// The request context may end before the message is sent.
+	result := n.topic.Publish(context.Background(), &pubsub.Message{
 		Data: msg,
 	})
 
-	id, err := result.Get(ctx)
-	if err != nil {
-		logger.WithError(err).Error("Failed to publish notification.")
+	n.pending.Add(1)
+	go func() {
+		defer n.pending.Done()
+		id, err := result.Get(context.Background())
+		if err != nil {
+			logger.WithError(err).Error("Failed to publish notification.")
+			return
+		}
+		logger.Infof("Published notification with message ID: %s", id)
+	}()
+}
+
+// close sends all pending notifications and releases the Pub/Sub client.
+func (n *notifier) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closed = true
+	if n.topic == nil {
 		return
 	}
-
-	logger.Infof("Published notification with message ID: %s", id)
+	n.topic.Stop()
+	n.pending.Wait()
+	n.client.Close()
 }
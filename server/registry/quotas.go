@@ -0,0 +1,117 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Quotas limit the storage used by each project.
+// Zero values allow unlimited usage.
+type Quotas struct {
+	MaxApis      int64 // Maximum number of APIs.
+	MaxSpecs     int64 // Maximum number of specs.
+	MaxRevisions int64 // Maximum number of spec and deployment revisions.
+	MaxBlobBytes int64 // Maximum total size of spec and artifact contents.
+}
+
+// quotaUsage describes the usage of a single quota.
+type quotaUsage struct {
+	name         string
+	limit, usage int64
+}
+
+func (q Quotas) usage(u *storage.ProjectUsage) []quotaUsage {
+	return []quotaUsage{
+		{name: "apis", limit: q.MaxApis, usage: u.Apis},
+		{name: "specs", limit: q.MaxSpecs, usage: u.Specs},
+		{name: "revisions", limit: q.MaxRevisions, usage: u.Revisions},
+		{name: "blob_bytes", limit: q.MaxBlobBytes, usage: u.BlobBytes},
+	}
+}
+
+func (s *RegistryServer) getQuotas() Quotas {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.quotas
+}
+
+// projectQuotas returns the quotas of a project and their current usage.
+// It returns nil if no quotas are configured.
+func (s *RegistryServer) projectQuotas(ctx context.Context, db *storage.Client, name names.Project) ([]*rpc.ProjectQuota, error) {
+	limits := s.getQuotas()
+	if limits == (Quotas{}) {
+		return nil, nil
+	}
+
+	u, err := db.GetProjectUsage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas []*rpc.ProjectQuota
+	for _, q := range limits.usage(u) {
+		quotas = append(quotas, &rpc.ProjectQuota{
+			Name:  q.name,
+			Limit: q.limit,
+			Usage: q.usage,
+		})
+	}
+	return quotas, nil
+}
+
+// checkQuotas returns a ResourceExhausted error if adding the specified
+// amounts to the current usage of a project would exceed any of its quotas.
+// Concurrent changes to the project are not serialized, so quotas can be
+// exceeded slightly by simultaneous requests.
+func (s *RegistryServer) checkQuotas(ctx context.Context, db *storage.Client, name names.Project, add storage.ProjectUsage) error {
+	quotas := s.getQuotas()
+	if quotas == (Quotas{}) {
+		return nil
+	}
+
+	u, err := db.GetProjectUsage(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	added := quotas.usage(&add)
+	var violations []*errdetails.QuotaFailure_Violation
+	for i, q := range quotas.usage(u) {
+		if q.limit > 0 && added[i].usage > 0 && q.usage+added[i].usage > q.limit {
+			violations = append(violations, &errdetails.QuotaFailure_Violation{
+				Subject:     name.String(),
+				Description: fmt.Sprintf("%s quota exceeded: limit %d, usage %d, requested %d", q.name, q.limit, q.usage, added[i].usage),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	st := status.Newf(codes.ResourceExhausted, "project %q: %s", name, violations[0].Description)
+	if detailed, err := st.WithDetails(&errdetails.QuotaFailure{Violations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestQuotas(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.Reload(Config{
+		Quotas: Quotas{
+			MaxApis:      1,
+			MaxRevisions: 2,
+			MaxBlobBytes: 10,
+		},
+	})
+
+	seed := &rpc.ApiVersion{Name: "projects/my-project/locations/global/apis/my-api/versions/v1"}
+	if err := seeder.SeedVersions(ctx, server, seed); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	t.Run("CreateApi", func(t *testing.T) {
+		req := &rpc.CreateApiRequest{
+			Parent: "projects/my-project/locations/global",
+			ApiId:  "other-api",
+			Api:    &rpc.Api{},
+		}
+		if _, err := server.CreateApi(ctx, req); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("CreateApi(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.ResourceExhausted, err)
+		}
+	})
+
+	t.Run("CreateApiSpec", func(t *testing.T) {
+		req := &rpc.CreateApiSpecRequest{
+			Parent:    seed.GetName(),
+			ApiSpecId: "large",
+			ApiSpec:   &rpc.ApiSpec{Contents: []byte("more than ten bytes")},
+		}
+		if _, err := server.CreateApiSpec(ctx, req); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("CreateApiSpec(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.ResourceExhausted, err)
+		}
+
+		req = &rpc.CreateApiSpecRequest{
+			Parent:    seed.GetName(),
+			ApiSpecId: "small",
+			ApiSpec:   &rpc.ApiSpec{Contents: []byte("small")},
+		}
+		if _, err := server.CreateApiSpec(ctx, req); err != nil {
+			t.Errorf("CreateApiSpec(%+v) returned error: %s", req, err)
+		}
+	})
+
+	t.Run("GetProject", func(t *testing.T) {
+		req := &rpc.GetProjectRequest{Name: "projects/my-project"}
+		got, err := server.GetProject(ctx, req)
+		if err != nil {
+			t.Fatalf("GetProject(%+v) returned error: %s", req, err)
+		}
+
+		want := []*rpc.ProjectQuota{
+			{Name: "apis", Limit: 1, Usage: 1},
+			{Name: "specs", Limit: 0, Usage: 1},
+			{Name: "revisions", Limit: 2, Usage: 1},
+			{Name: "blob_bytes", Limit: 10, Usage: 5},
+		}
+		if !cmp.Equal(want, got.GetQuotas(), protocmp.Transform()) {
+			t.Errorf("GetProject(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got.GetQuotas(), protocmp.Transform()))
+		}
+	})
+}
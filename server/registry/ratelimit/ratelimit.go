@@ -0,0 +1,292 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit limits the rate of calls to the registry with token
+// buckets keyed by principal, project, and class of method.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Class groups methods that share a rate limit.
+type Class string
+
+const (
+	// Read methods get and list resources.
+	Read Class = "read"
+	// Write methods create, update, and delete resources.
+	Write Class = "write"
+	// Contents methods get spec and artifact contents.
+	Contents Class = "contents"
+)
+
+// Limit configures a token bucket.
+type Limit struct {
+	// Rate is the number of calls allowed per second. If zero, calls are not limited.
+	Rate float64
+	// Burst is the number of calls that can be made at once. If zero, the
+	// rate (rounded up) is used.
+	Burst int
+}
+
+// Config holds the limits for each class of method.
+type Config struct {
+	Read     Limit
+	Write    Limit
+	Contents Limit
+}
+
+func (c Config) limit(class Class) Limit {
+	switch class {
+	case Read:
+		return c.Read
+	case Contents:
+		return c.Contents
+	default:
+		return c.Write
+	}
+}
+
+// sweepInterval is the minimum time between removals of idle buckets.
+const sweepInterval = time.Minute
+
+// Limiter limits the rate of calls to registry services.
+type Limiter struct {
+	mu        sync.Mutex
+	config    Config
+	buckets   map[key]*bucket
+	lastSweep time.Time
+}
+
+type key struct {
+	principal string
+	project   string
+	class     Class
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// New returns a limiter with the specified configuration.
+func New(config Config) *Limiter {
+	return &Limiter{
+		config:    config,
+		buckets:   make(map[key]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetConfig replaces the configuration of the limiter. All buckets are reset.
+func (l *Limiter) SetConfig(config Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+	l.buckets = make(map[key]*bucket)
+}
+
+// UnaryInterceptor returns a gRPC server interceptor that rejects calls to
+// registry services that exceed their limits with a RESOURCE_EXHAUSTED error.
+// Calls to other services, like health checks, are not limited.
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, "/google.cloud.apigeeregistry.") {
+			return handler(ctx, req)
+		}
+
+		class := MethodClass(info.FullMethod)
+		if delay := l.reserve(key{principal: principal(ctx), project: project(req), class: class}, time.Now()); delay > 0 {
+			st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for %s calls, retry in %s", class, delay.Round(time.Millisecond))
+			if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+				st = detailed
+			}
+			return nil, st.Err()
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// reserve takes a token from the bucket for k. If no token is available, it
+// returns the time until one will be.
+func (l *Limiter) reserve(k key, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.config.limit(k.class)
+	if limit.Rate <= 0 {
+		return 0
+	}
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[k]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(limit.Rate))
+		}
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), burst)}
+		l.buckets[k] = b
+	}
+	b.lastUsed = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// sweep removes buckets that have been idle long enough to refill.
+// Removing them has the same effect as keeping them.
+func (l *Limiter) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		refill := time.Duration(float64(b.limiter.Burst()) / float64(b.limiter.Limit()) * float64(time.Second))
+		if now.Sub(b.lastUsed) > refill {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+// MethodClass returns the class of a method from its full name.
+func MethodClass(fullMethod string) Class {
+	method := path.Base(fullMethod)
+	switch {
+	case strings.HasPrefix(method, "Get") && strings.HasSuffix(method, "Contents"):
+		return Contents
+	case strings.HasPrefix(method, "Get"), strings.HasPrefix(method, "List"):
+		return Read
+	default:
+		return Write
+	}
+}
+
+// PeerMetadataKey is the metadata key of the principal of a transcoded HTTP
+// call, which is set by the transcoder with HTTPPrincipal.
+const PeerMetadataKey = "x-registry-peer"
+
+// HTTPPrincipal identifies the caller of an HTTP request like principal
+// identifies the caller of a gRPC call.
+func HTTPPrincipal(req *http.Request) string {
+	if req.TLS != nil {
+		if chains := req.TLS.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+			return "cert:" + chains[0][0].Subject.CommonName
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return "ip:" + req.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// principal identifies the caller. Callers are identified by the common name
+// of their verified client certificate or otherwise by their IP address.
+// Transcoded HTTP calls are made over an in-memory connection and are
+// identified by the principal recorded by the transcoder.
+func principal(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		if chains := info.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+			return "cert:" + chains[0][0].Subject.CommonName
+		}
+	}
+
+	if p.Addr == nil {
+		return ""
+	}
+
+	if p.Addr.Network() == "bufconn" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			// HTTP clients can send their own values as metadata headers, so
+			// only the last value, which is added by the transcoder, is used.
+			if values := md.Get(PeerMetadataKey); len(values) > 0 {
+				return values[len(values)-1]
+			}
+			// The transcoder appends the address of its peer to any
+			// X-Forwarded-For header that the client sent.
+			if values := md.Get("x-forwarded-for"); len(values) > 0 {
+				addrs := strings.Split(values[len(values)-1], ",")
+				return "ip:" + strings.TrimSpace(addrs[len(addrs)-1])
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+var projectPattern = regexp.MustCompile(`^projects/([^/]+)`)
+
+// project returns the ID of the project of the resource that a request
+// refers to with its name or parent fields, or of the resource it contains.
+func project(req interface{}) string {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+	return projectOf(m.ProtoReflect(), true)
+}
+
+func projectOf(m protoreflect.Message, nested bool) string {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		switch {
+		case f.Kind() == protoreflect.StringKind && !f.IsList() && (f.Name() == "name" || f.Name() == "parent"):
+			if match := projectPattern.FindStringSubmatch(m.Get(f).String()); match != nil {
+				return match[1]
+			}
+		case f.Kind() == protoreflect.MessageKind && !f.IsList() && !f.IsMap() && nested && m.Has(f):
+			if id := projectOf(m.Get(f).Message(), false); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,215 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodClass(t *testing.T) {
+	tests := []struct {
+		method string
+		want   Class
+	}{
+		{"/google.cloud.apigeeregistry.v1.Registry/GetApi", Read},
+		{"/google.cloud.apigeeregistry.v1.Registry/ListApiSpecs", Read},
+		{"/google.cloud.apigeeregistry.v1.Registry/GetApiSpecContents", Contents},
+		{"/google.cloud.apigeeregistry.v1.Registry/GetArtifactContents", Contents},
+		{"/google.cloud.apigeeregistry.v1.Registry/CreateApi", Write},
+		{"/google.cloud.apigeeregistry.v1.Registry/RollbackApiSpec", Write},
+	}
+	for _, test := range tests {
+		if got := MethodClass(test.method); got != test.want {
+			t.Errorf("MethodClass(%q) returned %q, want %q", test.method, got, test.want)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		req  interface{}
+		want string
+	}{
+		{&rpc.GetApiRequest{Name: "projects/p1/locations/global/apis/a"}, "p1"},
+		{&rpc.ListApiSpecsRequest{Parent: "projects/p2/locations/global/apis/a/versions/v"}, "p2"},
+		{&rpc.UpdateApiRequest{Api: &rpc.Api{Name: "projects/p3/locations/global/apis/a"}}, "p3"},
+		{&rpc.ListProjectsRequest{}, ""},
+		{"not a message", ""},
+	}
+	for _, test := range tests {
+		if got := project(test.req); got != test.want {
+			t.Errorf("project(%+v) returned %q, want %q", test.req, got, test.want)
+		}
+	}
+}
+
+func TestReserve(t *testing.T) {
+	l := New(Config{
+		Read:  Limit{Rate: 1, Burst: 2},
+		Write: Limit{Rate: 0.5},
+	})
+	now := time.Now()
+	alice := key{principal: "ip:10.0.0.1", project: "p", class: Read}
+	bob := key{principal: "ip:10.0.0.2", project: "p", class: Read}
+
+	for i := 0; i < 2; i++ {
+		if delay := l.reserve(alice, now); delay != 0 {
+			t.Fatalf("reserve() call %d within burst returned delay %s", i, delay)
+		}
+	}
+	if delay := l.reserve(alice, now); delay != time.Second {
+		t.Errorf("reserve() beyond burst returned delay %s, want %s", delay, time.Second)
+	}
+	if delay := l.reserve(bob, now); delay != 0 {
+		t.Errorf("reserve() for another principal returned delay %s", delay)
+	}
+	if delay := l.reserve(alice, now.Add(time.Second)); delay != 0 {
+		t.Errorf("reserve() after refill returned delay %s", delay)
+	}
+
+	write := key{principal: "ip:10.0.0.1", project: "p", class: Write}
+	if delay := l.reserve(write, now); delay != 0 {
+		t.Errorf("reserve() for first write returned delay %s", delay)
+	}
+	if delay := l.reserve(write, now); delay != 2*time.Second {
+		t.Errorf("reserve() for second write returned delay %s, want %s", delay, 2*time.Second)
+	}
+
+	unlimited := key{principal: "ip:10.0.0.1", project: "p", class: Contents}
+	for i := 0; i < 100; i++ {
+		if delay := l.reserve(unlimited, now); delay != 0 {
+			t.Fatalf("reserve() for unlimited class returned delay %s", delay)
+		}
+	}
+
+	l.sweep(now.Add(time.Hour))
+	if len(l.buckets) != 0 {
+		t.Errorf("sweep() left %d idle buckets", len(l.buckets))
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	l := New(Config{Read: Limit{Rate: 1, Burst: 1}})
+	interceptor := l.UnaryInterceptor()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234},
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+	req := &rpc.GetApiRequest{Name: "projects/p/locations/global/apis/a"}
+	info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.apigeeregistry.v1.Registry/GetApi"}
+
+	if _, err := interceptor(ctx, req, info, handler); err != nil {
+		t.Fatalf("first call returned error: %s", err)
+	}
+
+	_, err := interceptor(ctx, req, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call returned status code %q, want %q: %v", status.Code(err), codes.ResourceExhausted, err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, d := range status.Convert(err).Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("second call returned error without retry delay: %v", status.Convert(err).Details())
+	}
+
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := interceptor(ctx, req, health, handler); err != nil {
+		t.Errorf("health check returned error: %s", err)
+	}
+}
+
+// bufconnAddr is the address of the in-memory connection used for transcoded calls.
+type bufconnAddr struct{}
+
+func (bufconnAddr) Network() string { return "bufconn" }
+func (bufconnAddr) String() string  { return "bufconn" }
+
+func TestPrincipalOfTranscodedCall(t *testing.T) {
+	tests := []struct {
+		desc     string
+		annotate bool
+		headers  map[string]string
+	}{
+		{
+			desc:     "no forwarding headers",
+			annotate: true,
+		},
+		{
+			desc:     "spoofed headers",
+			annotate: true,
+			headers: map[string]string{
+				"X-Forwarded-For":                  "10.9.9.9",
+				"Grpc-Metadata-X-Forwarded-For":    "10.8.8.8",
+				"Grpc-Metadata-" + PeerMetadataKey: "ip:10.7.7.7",
+			},
+		},
+		{
+			desc: "spoofed forwarding header without the transcoder's principal",
+			headers: map[string]string{
+				"X-Forwarded-For": "10.9.9.9",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			// Calls are annotated like they are by the transcoder of the server.
+			var opts []runtime.ServeMuxOption
+			if test.annotate {
+				opts = append(opts, runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+					return metadata.Pairs(PeerMetadataKey, HTTPPrincipal(req))
+				}))
+			}
+			mux := runtime.NewServeMux(opts...)
+			req := httptest.NewRequest(http.MethodGet, "/v1/projects/p", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+
+			ctx, err := runtime.AnnotateContext(context.Background(), mux, req, "/google.cloud.apigeeregistry.v1.Admin/GetProject")
+			if err != nil {
+				t.Fatalf("AnnotateContext() returned error: %s", err)
+			}
+			md, _ := metadata.FromOutgoingContext(ctx)
+			ctx = metadata.NewIncomingContext(context.Background(), md)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: bufconnAddr{}})
+
+			if got, want := principal(ctx), "ip:10.0.0.1"; got != want {
+				t.Errorf("principal() returned %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// OnDelete is the action taken on references to a resource when it is deleted.
+type OnDelete string
+
+const (
+	// OnDeleteIgnore leaves references to deleted resources unchanged.
+	OnDeleteIgnore OnDelete = ""
+	// OnDeleteBlock prevents the deletion of referenced resources.
+	OnDeleteBlock OnDelete = "block"
+	// OnDeleteClear clears references to deleted resources.
+	OnDeleteClear OnDelete = "clear"
+	// OnDeleteCascade deletes deployments that refer to deleted resources.
+	// References from APIs are cleared.
+	OnDeleteCascade OnDelete = "cascade"
+)
+
+// ReferencePolicy configures the checking of references to other resources
+// in the recommended_version and recommended_deployment fields of APIs and
+// the api_spec_revision field of deployments.
+type ReferencePolicy struct {
+	// Validate requires references to name existing resources in the same
+	// project when they are set.
+	Validate bool
+	// OnDelete is the action taken on references to deleted resources.
+	OnDelete OnDelete
+}
+
+const (
+	recommendedVersionField    = "recommended_version"
+	recommendedDeploymentField = "recommended_deployment"
+	apiSpecRevisionField       = "api_spec_revision"
+)
+
+// latestRevision is the revision ID that refers to the current revision of a
+// spec or deployment.
+const latestRevision = "latest"
+
+func (s *RegistryServer) getReferencePolicy() ReferencePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.references
+}
+
+// reference is a field of an API or deployment that names another resource.
+type reference struct {
+	field      string
+	value      string
+	api        *models.Api        // set for references from APIs
+	deployment *models.Deployment // set for references from deployments
+}
+
+// source returns the name of the resource containing the reference.
+func (r reference) source() string {
+	if r.api != nil {
+		return r.api.Name()
+	}
+	return r.deployment.Name()
+}
+
+// pinned returns true if the reference names a specific revision rather than
+// the current revision of a spec or deployment.
+func (r reference) pinned() bool {
+	i := strings.LastIndex(r.value, "@")
+	return i >= 0 && r.value[i+1:] != latestRevision
+}
+
+// within returns true if the reference names parent or one of its children.
+func (r reference) within(parent string) bool {
+	value, parent := strings.ToLower(r.value), strings.ToLower(parent)
+	return value == parent || strings.HasPrefix(value, parent+"/") || strings.HasPrefix(value, parent+"@")
+}
+
+// listReferences returns the references from the APIs and deployments of a project.
+func listReferences(ctx context.Context, db *storage.Client, project names.Project) ([]reference, error) {
+	apis, err := db.ListApisForProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := db.ListDeploymentsForProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []reference
+	for i := range apis {
+		api := &apis[i]
+		if api.RecommendedVersion != "" {
+			refs = append(refs, reference{field: recommendedVersionField, value: api.RecommendedVersion, api: api})
+		}
+		if api.RecommendedDeployment != "" {
+			refs = append(refs, reference{field: recommendedDeploymentField, value: api.RecommendedDeployment, api: api})
+		}
+	}
+	for i := range deployments {
+		deployment := &deployments[i]
+		if deployment.ApiSpecRevision != "" {
+			refs = append(refs, reference{field: apiSpecRevisionField, value: deployment.ApiSpecRevision, deployment: deployment})
+		}
+	}
+
+	return refs, nil
+}
+
+// resolveReference returns the name of the resource named by a reference
+// from a resource in project. Revision tags and the "latest" revision ID are
+// resolved, so references to specific revisions resolve to revision names
+// and references to the latest revision resolve to the name of the spec or
+// deployment.
+func resolveReference(ctx context.Context, db *storage.Client, project names.Project, field, value string) (string, error) {
+	inProject := func(projectID string) error {
+		if !strings.EqualFold(projectID, project.ProjectID) {
+			return status.Errorf(codes.InvalidArgument, "invalid %s %q: must be in project %q", field, value, project)
+		}
+		return nil
+	}
+	missing := func(err error) error {
+		if isNotFound(err) {
+			return status.Errorf(codes.FailedPrecondition, "invalid %s %q: resource does not exist", field, value)
+		}
+		return err
+	}
+
+	switch field {
+	case recommendedVersionField:
+		name, err := names.ParseVersion(value)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid %s %q: must be an API version", field, value)
+		} else if err := inProject(name.ProjectID); err != nil {
+			return "", err
+		}
+		if _, err := db.GetVersion(ctx, name); err != nil {
+			return "", missing(err)
+		}
+		return name.String(), nil
+
+	case recommendedDeploymentField:
+		if name, err := names.ParseDeployment(value); err == nil {
+			if err := inProject(name.ProjectID); err != nil {
+				return "", err
+			}
+			if _, err := db.GetDeployment(ctx, name); err != nil {
+				return "", missing(err)
+			}
+			return name.String(), nil
+		}
+
+		name, err := names.ParseDeploymentRevision(value)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid %s %q: must be an API deployment or revision", field, value)
+		} else if err := inProject(name.ProjectID); err != nil {
+			return "", err
+		}
+		if name.RevisionID == latestRevision {
+			if _, err := db.GetDeployment(ctx, name.Deployment()); err != nil {
+				return "", missing(err)
+			}
+			return name.Deployment().String(), nil
+		}
+		revision, err := db.GetDeploymentRevision(ctx, name)
+		if err != nil {
+			return "", missing(err)
+		}
+		return revision.RevisionName(), nil
+
+	case apiSpecRevisionField:
+		if name, err := names.ParseSpec(value); err == nil {
+			if err := inProject(name.ProjectID); err != nil {
+				return "", err
+			}
+			if _, err := db.GetSpec(ctx, name); err != nil {
+				return "", missing(err)
+			}
+			return name.String(), nil
+		}
+
+		name, err := names.ParseSpecRevision(value)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid %s %q: must be an API spec or revision", field, value)
+		} else if err := inProject(name.ProjectID); err != nil {
+			return "", err
+		}
+		if name.RevisionID == latestRevision {
+			if _, err := db.GetSpec(ctx, name.Spec()); err != nil {
+				return "", missing(err)
+			}
+			return name.Spec().String(), nil
+		}
+		revision, err := db.GetSpecRevision(ctx, name)
+		if err != nil {
+			return "", missing(err)
+		}
+		return revision.RevisionName(), nil
+	}
+
+	return "", status.Errorf(codes.Internal, "unknown reference field %q", field)
+}
+
+// checkReference returns an error if references must be valid and a changed
+// reference from a resource in project does not name an existing resource.
+func (s *RegistryServer) checkReference(ctx context.Context, db *storage.Client, project names.Project, field, old, value string) error {
+	if value == "" || value == old || !s.getReferencePolicy().Validate {
+		return nil
+	}
+
+	_, err := resolveReference(ctx, db, project, field, value)
+	return err
+}
+
+// applyDeletePolicy takes the on-delete action of the reference policy on
+// references to a resource that is about to be deleted. Revisions must be
+// named by their revision IDs, not by tags.
+func (s *RegistryServer) applyDeletePolicy(ctx context.Context, db *storage.Client, deleted names.Name) error {
+	action := s.getReferencePolicy().OnDelete
+	if action == OnDeleteIgnore {
+		return nil
+	}
+
+	// Deleting the only revision of a spec or deployment deletes it.
+	var project names.Project
+	switch name := deleted.(type) {
+	case names.SpecRevision:
+		listing, err := db.ListSpecRevisions(ctx, name.Spec(), storage.PageOptions{Size: 2})
+		if err != nil {
+			return err
+		} else if len(listing.Specs) < 2 {
+			deleted = name.Spec()
+		}
+		project = name.Spec().Project()
+	case names.DeploymentRevision:
+		listing, err := db.ListDeploymentRevisions(ctx, name.Deployment(), storage.PageOptions{Size: 2})
+		if err != nil {
+			return err
+		} else if len(listing.Deployments) < 2 {
+			deleted = name.Deployment()
+		}
+		project = name.Deployment().Project()
+	case names.Api:
+		project = name.Project()
+	case names.Version:
+		project = name.Project()
+	case names.Spec:
+		project = name.Project()
+	case names.Deployment:
+		project = name.Project()
+	default:
+		return status.Errorf(codes.Internal, "unsupported resource %q", deleted)
+	}
+
+	refs, err := listReferences(ctx, db, project)
+	if err != nil {
+		return err
+	}
+
+	var affected []reference
+	for _, ref := range refs {
+		switch deleted.(type) {
+		case names.SpecRevision, names.DeploymentRevision:
+			if !ref.pinned() {
+				continue
+			}
+			// References that no longer resolve aren't affected by the deletion.
+			if resolved, err := resolveReference(ctx, db, project, ref.field, ref.value); err == nil && resolved == deleted.String() {
+				affected = append(affected, ref)
+			}
+		default:
+			// References from resources that are also being deleted are ignored.
+			source, name := strings.ToLower(ref.source()), strings.ToLower(deleted.String())
+			if ref.within(deleted.String()) && source != name && !strings.HasPrefix(source, name+"/") {
+				affected = append(affected, ref)
+			}
+		}
+	}
+
+	if len(affected) == 0 {
+		return nil
+	}
+
+	if action == OnDeleteBlock {
+		sources := make([]string, len(affected))
+		for i, ref := range affected {
+			sources[i] = fmt.Sprintf("%s (%s)", ref.source(), ref.field)
+		}
+		return status.Errorf(codes.FailedPrecondition, "%q is referenced by %s", deleted, strings.Join(sources, ", "))
+	}
+
+	for _, ref := range affected {
+		if err := s.clearReference(ctx, db, ref, action == OnDeleteCascade); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clearReference clears a reference to a deleted resource. If cascade is
+// true, deployments containing the reference are deleted instead.
+func (s *RegistryServer) clearReference(ctx context.Context, db *storage.Client, ref reference, cascade bool) error {
+	if api := ref.api; api != nil {
+		switch ref.field {
+		case recommendedVersionField:
+			api.RecommendedVersion = ""
+		case recommendedDeploymentField:
+			api.RecommendedDeployment = ""
+		}
+		api.UpdateTime = time.Now().Round(time.Microsecond)
+		if err := db.SaveApi(ctx, api); err != nil {
+			return err
+		}
+		s.notify(ctx, rpc.Notification_UPDATED, api.Name())
+		return nil
+	}
+
+	deployment := ref.deployment
+	if cascade {
+		name, err := names.ParseDeployment(deployment.Name())
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := db.DeleteDeployment(ctx, name); err != nil {
+			return err
+		}
+		s.notify(ctx, rpc.Notification_DELETED, name.String())
+		return nil
+	}
+
+	// Changing the spec of a deployment creates a new revision.
+	mask := &fieldmaskpb.FieldMask{Paths: []string{apiSpecRevisionField}}
+	if err := deployment.Update(&rpc.ApiDeployment{}, mask); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := db.SaveDeploymentRevision(ctx, deployment); err != nil {
+		return err
+	}
+	s.notify(ctx, rpc.Notification_UPDATED, deployment.RevisionName())
+	return nil
+}
+
+// pinnedRevisions returns the names of the spec and deployment revisions of
+// a project that are named by references. It returns nil if references
+// aren't checked.
+func (s *RegistryServer) pinnedRevisions(ctx context.Context, db *storage.Client, project names.Project) (map[string]bool, error) {
+	if s.getReferencePolicy() == (ReferencePolicy{}) {
+		return nil, nil
+	}
+
+	refs, err := listReferences(ctx, db, project)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.field == recommendedVersionField || !ref.pinned() {
+			continue
+		}
+		if resolved, err := resolveReference(ctx, db, project, ref.field, ref.value); err == nil {
+			pinned[resolved] = true
+		}
+	}
+
+	return pinned, nil
+}
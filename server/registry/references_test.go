@@ -0,0 +1,356 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	referencesApi        = "projects/my-project/locations/global/apis/my-api"
+	referencesVersion    = referencesApi + "/versions/v1"
+	referencesSpec       = referencesVersion + "/specs/my-spec"
+	referencesDeployment = referencesApi + "/deployments/my-deployment"
+)
+
+// seedReferences creates a spec with two revisions, the second tagged
+// "stable", and a deployment. It returns the spec revision names.
+func seedReferences(ctx context.Context, t *testing.T, server *RegistryServer) []string {
+	t.Helper()
+	seed := []seeder.RegistryResource{
+		&rpc.ApiSpec{Name: referencesSpec},
+		&rpc.ApiDeployment{Name: referencesDeployment},
+	}
+	if err := seeder.SeedRegistry(ctx, server, seed...); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	first, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: referencesSpec})
+	if err != nil {
+		t.Fatalf("Setup: GetApiSpec returned error: %s", err)
+	}
+	second, err := server.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+		ApiSpec: &rpc.ApiSpec{Name: referencesSpec, Contents: []byte("second")},
+	})
+	if err != nil {
+		t.Fatalf("Setup: UpdateApiSpec returned error: %s", err)
+	}
+
+	revisions := []string{
+		fmt.Sprintf("%s@%s", referencesSpec, first.GetRevisionId()),
+		fmt.Sprintf("%s@%s", referencesSpec, second.GetRevisionId()),
+	}
+	if _, err := server.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{Name: revisions[1], Tag: "stable"}); err != nil {
+		t.Fatalf("Setup: TagApiSpecRevision returned error: %s", err)
+	}
+	return revisions
+}
+
+func TestReferenceValidation(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.Reload(Config{References: ReferencePolicy{Validate: true}})
+	revisions := seedReferences(ctx, t, server)
+
+	tests := []struct {
+		desc       string
+		api        *rpc.Api
+		deployment *rpc.ApiDeployment
+		want       codes.Code
+	}{
+		{
+			desc: "existing version",
+			api:  &rpc.Api{RecommendedVersion: referencesVersion},
+			want: codes.OK,
+		},
+		{
+			desc: "missing version",
+			api:  &rpc.Api{RecommendedVersion: referencesApi + "/versions/v2"},
+			want: codes.FailedPrecondition,
+		},
+		{
+			desc: "version in another project",
+			api:  &rpc.Api{RecommendedVersion: "projects/other-project/locations/global/apis/my-api/versions/v1"},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "spec as version",
+			api:  &rpc.Api{RecommendedVersion: referencesSpec},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "existing deployment",
+			api:  &rpc.Api{RecommendedDeployment: referencesDeployment},
+			want: codes.OK,
+		},
+		{
+			desc: "latest deployment revision",
+			api:  &rpc.Api{RecommendedDeployment: referencesDeployment + "@latest"},
+			want: codes.OK,
+		},
+		{
+			desc: "missing deployment revision",
+			api:  &rpc.Api{RecommendedDeployment: referencesDeployment + "@missing"},
+			want: codes.FailedPrecondition,
+		},
+		{
+			desc:       "existing spec",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: referencesSpec},
+			want:       codes.OK,
+		},
+		{
+			desc:       "spec revision",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: revisions[0]},
+			want:       codes.OK,
+		},
+		{
+			desc:       "tagged spec revision",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: referencesSpec + "@stable"},
+			want:       codes.OK,
+		},
+		{
+			desc:       "latest spec revision",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: referencesSpec + "@latest"},
+			want:       codes.OK,
+		},
+		{
+			desc:       "missing spec",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: referencesVersion + "/specs/other-spec"},
+			want:       codes.FailedPrecondition,
+		},
+		{
+			desc:       "free text",
+			deployment: &rpc.ApiDeployment{ApiSpecRevision: "some-revision"},
+			want:       codes.InvalidArgument,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.api != nil {
+				req := &rpc.CreateApiRequest{
+					Parent: "projects/my-project/locations/global",
+					ApiId:  fmt.Sprintf("api-%d", i),
+					Api:    test.api,
+				}
+				if _, err := server.CreateApi(ctx, req); status.Code(err) != test.want {
+					t.Errorf("CreateApi(%+v) returned status code %q, want %q: %v", req, status.Code(err), test.want, err)
+				}
+
+				update := &rpc.UpdateApiRequest{
+					Api: &rpc.Api{
+						Name:                  referencesApi,
+						RecommendedVersion:    test.api.GetRecommendedVersion(),
+						RecommendedDeployment: test.api.GetRecommendedDeployment(),
+					},
+				}
+				if _, err := server.UpdateApi(ctx, update); status.Code(err) != test.want {
+					t.Errorf("UpdateApi(%+v) returned status code %q, want %q: %v", update, status.Code(err), test.want, err)
+				}
+			}
+
+			if test.deployment != nil {
+				req := &rpc.CreateApiDeploymentRequest{
+					Parent:          referencesApi,
+					ApiDeploymentId: fmt.Sprintf("deployment-%d", i),
+					ApiDeployment:   test.deployment,
+				}
+				if _, err := server.CreateApiDeployment(ctx, req); status.Code(err) != test.want {
+					t.Errorf("CreateApiDeployment(%+v) returned status code %q, want %q: %v", req, status.Code(err), test.want, err)
+				}
+
+				update := &rpc.UpdateApiDeploymentRequest{
+					ApiDeployment: &rpc.ApiDeployment{
+						Name:            referencesDeployment,
+						ApiSpecRevision: test.deployment.GetApiSpecRevision(),
+					},
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"api_spec_revision"}},
+				}
+				if _, err := server.UpdateApiDeployment(ctx, update); status.Code(err) != test.want {
+					t.Errorf("UpdateApiDeployment(%+v) returned status code %q, want %q: %v", update, status.Code(err), test.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReferencesOnDelete(t *testing.T) {
+	tests := []struct {
+		action OnDelete
+		// Whether the deletion should succeed.
+		deleted bool
+		// Expected references after the deletion. Cascading deletes the deployment.
+		recommendedVersion, apiSpecRevision string
+	}{
+		{
+			action:             OnDeleteIgnore,
+			deleted:            true,
+			recommendedVersion: referencesVersion,
+			apiSpecRevision:    referencesSpec + "@stable",
+		},
+		{
+			action:             OnDeleteBlock,
+			recommendedVersion: referencesVersion,
+			apiSpecRevision:    referencesSpec + "@stable",
+		},
+		{
+			action:             OnDeleteClear,
+			deleted:            true,
+			recommendedVersion: "",
+			apiSpecRevision:    "",
+		},
+		{
+			action:  OnDeleteCascade,
+			deleted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q", test.action), func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			server.Reload(Config{References: ReferencePolicy{OnDelete: test.action}})
+			seedReferences(ctx, t, server)
+
+			if _, err := server.UpdateApi(ctx, &rpc.UpdateApiRequest{
+				Api: &rpc.Api{Name: referencesApi, RecommendedVersion: referencesVersion},
+			}); err != nil {
+				t.Fatalf("Setup: UpdateApi returned error: %s", err)
+			}
+			if _, err := server.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+				ApiDeployment: &rpc.ApiDeployment{Name: referencesDeployment, ApiSpecRevision: referencesSpec + "@stable"},
+			}); err != nil {
+				t.Fatalf("Setup: UpdateApiDeployment returned error: %s", err)
+			}
+
+			req := &rpc.DeleteApiVersionRequest{Name: referencesVersion}
+			if _, err := server.DeleteApiVersion(ctx, req); test.deleted && err != nil {
+				t.Fatalf("DeleteApiVersion(%+v) returned error: %s", req, err)
+			} else if !test.deleted && status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("DeleteApiVersion(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.FailedPrecondition, err)
+			}
+
+			api, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: referencesApi})
+			if err != nil {
+				t.Fatalf("GetApi returned error: %s", err)
+			}
+			if got := api.GetRecommendedVersion(); got != test.recommendedVersion {
+				t.Errorf("GetApi returned recommended_version %q, want %q", got, test.recommendedVersion)
+			}
+
+			deployment, err := server.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: referencesDeployment})
+			if test.action == OnDeleteCascade {
+				if !isNotFound(err) {
+					t.Errorf("GetApiDeployment returned status code %q, want %q: %v", status.Code(err), codes.NotFound, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("GetApiDeployment returned error: %s", err)
+			}
+			if got := deployment.GetApiSpecRevision(); got != test.apiSpecRevision {
+				t.Errorf("GetApiDeployment returned api_spec_revision %q, want %q", got, test.apiSpecRevision)
+			}
+		})
+
+		// References from the deleted resource to its own children don't affect its deletion.
+		t.Run(fmt.Sprintf("%q of a self-reference", test.action), func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			server.Reload(Config{References: ReferencePolicy{OnDelete: test.action}})
+			seedReferences(ctx, t, server)
+
+			if _, err := server.UpdateApi(ctx, &rpc.UpdateApiRequest{
+				Api: &rpc.Api{Name: referencesApi, RecommendedVersion: referencesVersion},
+			}); err != nil {
+				t.Fatalf("Setup: UpdateApi returned error: %s", err)
+			}
+
+			req := &rpc.DeleteApiRequest{Name: referencesApi}
+			if _, err := server.DeleteApi(ctx, req); err != nil {
+				t.Fatalf("DeleteApi(%+v) returned error: %s", req, err)
+			}
+			if _, err := server.GetApi(ctx, &rpc.GetApiRequest{Name: referencesApi}); !isNotFound(err) {
+				t.Errorf("GetApi returned status code %q, want %q: %v", status.Code(err), codes.NotFound, err)
+			}
+		})
+	}
+}
+
+func TestReferencesOnDeleteRevision(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.Reload(Config{References: ReferencePolicy{OnDelete: OnDeleteBlock}})
+	revisions := seedReferences(ctx, t, server)
+
+	if _, err := server.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+		ApiDeployment: &rpc.ApiDeployment{Name: referencesDeployment, ApiSpecRevision: referencesSpec + "@stable"},
+	}); err != nil {
+		t.Fatalf("Setup: UpdateApiDeployment returned error: %s", err)
+	}
+
+	// The tagged revision is referenced, but the other revision isn't.
+	req := &rpc.DeleteApiSpecRevisionRequest{Name: revisions[1]}
+	if _, err := server.DeleteApiSpecRevision(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteApiSpecRevision(%+v) returned status code %q, want %q: %v", req, status.Code(err), codes.FailedPrecondition, err)
+	}
+
+	req = &rpc.DeleteApiSpecRevisionRequest{Name: revisions[0]}
+	if _, err := server.DeleteApiSpecRevision(ctx, req); err != nil {
+		t.Errorf("DeleteApiSpecRevision(%+v) returned error: %s", req, err)
+	}
+}
+
+func TestPruneKeepsReferencedRevisions(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	server.Reload(Config{References: ReferencePolicy{Validate: true}})
+	revisions := seedReferences(ctx, t, server)
+
+	if _, err := server.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+		ApiDeployment: &rpc.ApiDeployment{Name: referencesDeployment, ApiSpecRevision: revisions[0]},
+	}); err != nil {
+		t.Fatalf("Setup: UpdateApiDeployment returned error: %s", err)
+	}
+
+	update := &rpc.UpdateProjectRequest{
+		Project: &rpc.Project{
+			Name: "projects/my-project",
+			RetentionPolicies: []*rpc.RetentionPolicy{
+				{KeepLast: 1},
+			},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies"}},
+	}
+	if _, err := server.UpdateProject(ctx, update); err != nil {
+		t.Fatalf("Setup: UpdateProject(%+v) returned error: %s", update, err)
+	}
+
+	req := &rpc.PruneRevisionsRequest{Name: "projects/my-project"}
+	if _, err := server.PruneRevisions(ctx, req); err != nil {
+		t.Fatalf("PruneRevisions(%+v) returned error: %s", req, err)
+	}
+
+	if _, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: revisions[0]}); err != nil {
+		t.Errorf("GetApiSpec(%q) returned error: %s", revisions[0], err)
+	}
+}
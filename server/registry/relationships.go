@@ -0,0 +1,189 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/internal/storage/models"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DependsOn is the kind of relationship that must not form cycles.
+const DependsOn = "depends-on"
+
+var kindRegexp = regexp.MustCompile("^[a-z0-9-]{1,63}$")
+
+// checkRelationship returns an error if a relationship is not valid. Valid
+// sources and targets are replaced with their resolved names so that
+// relationships can be found by the exact names of the resources they relate.
+func checkRelationship(ctx context.Context, db *storage.Client, name names.Relationship, relationship *models.Relationship) error {
+	if !kindRegexp.MatchString(relationship.Kind) {
+		return status.Errorf(codes.InvalidArgument, "invalid kind %q: must contain only lowercase letters, digits, and dashes", relationship.Kind)
+	}
+
+	if relationship.Target == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid target %q: must be provided", relationship.Target)
+	}
+
+	if relationship.Source != "" {
+		source, api, err := resolveRelated(ctx, db, name.Project(), "source", relationship.Source)
+		if err != nil {
+			return err
+		} else if !strings.EqualFold(api.String(), name.Api().String()) {
+			return status.Errorf(codes.InvalidArgument, "invalid source %q: must be in API %q", relationship.Source, name.Api())
+		}
+		relationship.Source = source
+	}
+
+	target, _, err := resolveRelated(ctx, db, name.Project(), "target", relationship.Target)
+	if err != nil {
+		return err
+	}
+	relationship.Target = target
+
+	if relationship.Kind == DependsOn {
+		return checkDependencyCycle(ctx, db, name, relationship)
+	}
+
+	return nil
+}
+
+// resolveRelated returns the name of a related resource and of the API
+// containing it. Revision tags and the "latest" revision ID are resolved
+// as they are in references.
+func resolveRelated(ctx context.Context, db *storage.Client, project names.Project, field, value string) (string, names.Api, error) {
+	var (
+		api     names.Api
+		resolve func() (string, error)
+	)
+
+	if name, err := names.ParseApi(value); err == nil {
+		api = name
+		resolve = func() (string, error) {
+			_, err := db.GetApi(ctx, name)
+			return name.String(), err
+		}
+	} else if name, err := names.ParseVersion(value); err == nil {
+		api = name.Api()
+		resolve = func() (string, error) {
+			_, err := db.GetVersion(ctx, name)
+			return name.String(), err
+		}
+	} else if name, err := names.ParseSpec(value); err == nil {
+		api = name.Api()
+	} else if name, err := names.ParseSpecRevision(value); err == nil {
+		api = name.Spec().Api()
+	} else if name, err := names.ParseDeployment(value); err == nil {
+		api = name.Api()
+	} else if name, err := names.ParseDeploymentRevision(value); err == nil {
+		api = name.Deployment().Api()
+	} else {
+		return "", names.Api{}, status.Errorf(codes.InvalidArgument, "invalid %s %q: must be an API, version, spec, or deployment", field, value)
+	}
+
+	if !strings.EqualFold(api.ProjectID, project.ProjectID) {
+		return "", names.Api{}, status.Errorf(codes.InvalidArgument, "invalid %s %q: must be in project %q", field, value, project)
+	}
+
+	if resolve == nil {
+		referenceField := apiSpecRevisionField
+		if strings.Contains(value, "/deployments/") {
+			referenceField = recommendedDeploymentField
+		}
+		resolve = func() (string, error) {
+			return resolveReference(ctx, db, project, referenceField, value)
+		}
+	}
+
+	resolved, err := resolve()
+	if isNotFound(err) || status.Code(err) == codes.FailedPrecondition {
+		return "", names.Api{}, status.Errorf(codes.FailedPrecondition, "invalid %s %q: resource does not exist", field, value)
+	} else if err != nil {
+		return "", names.Api{}, err
+	}
+
+	return resolved, api, nil
+}
+
+// dependencyNode returns the node of the dependency graph for a resource.
+// Dependencies are between APIs, so each resource is the API containing it.
+// Dependencies between resources of the same API are not edges of the graph.
+func dependencyNode(name string) string {
+	segments := strings.Split(name, "/")
+	for i := 0; i+1 < len(segments); i += 2 {
+		if segments[i] == "apis" {
+			segments = segments[:i+2]
+			break
+		}
+	}
+	return strings.ToLower(strings.Join(segments, "/"))
+}
+
+// checkDependencyCycle returns an error if adding a "depends-on"
+// relationship to a project would create a cycle of dependencies.
+func checkDependencyCycle(ctx context.Context, db *storage.Client, name names.Relationship, relationship *models.Relationship) error {
+	relationships, err := db.ListRelationshipsForProject(ctx, name.Project())
+	if err != nil {
+		return err
+	}
+
+	edges := make(map[string][]string)
+	for _, r := range relationships {
+		if r.Kind != DependsOn || strings.EqualFold(r.Name(), name.String()) {
+			continue
+		}
+		from, to := dependencyNode(r.SourceName()), dependencyNode(r.Target)
+		if from != to {
+			edges[from] = append(edges[from], to)
+		}
+	}
+
+	source, target := dependencyNode(relationship.SourceName()), dependencyNode(relationship.Target)
+	if source == target && !strings.EqualFold(relationship.SourceName(), relationship.Target) {
+		return nil
+	}
+	path := dependencyPath(edges, target, source, map[string]bool{})
+	if path == nil {
+		return nil
+	}
+
+	return status.Errorf(codes.FailedPrecondition, "invalid relationship %q: %s would create a cycle: %s",
+		name, DependsOn, strings.Join(append([]string{source}, path...), " -> "))
+}
+
+// dependencyPath returns a path of edges from one node to another, or nil if
+// there is none.
+func dependencyPath(edges map[string][]string, from, to string, visited map[string]bool) []string {
+	if from == to {
+		return []string{to}
+	} else if visited[from] {
+		return nil
+	}
+	visited[from] = true
+
+	for _, next := range edges[from] {
+		if path := dependencyPath(edges, next, to, visited); path != nil {
+			return append([]string{from}, path...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	relatedApiA = "projects/my-project/locations/global/apis/a"
+	relatedApiB = "projects/my-project/locations/global/apis/b"
+	relatedApiC = "projects/my-project/locations/global/apis/c"
+)
+
+func seedRelatedApis(ctx context.Context, t *testing.T, server *RegistryServer) {
+	t.Helper()
+	if err := seeder.SeedRegistry(ctx, server,
+		&rpc.ApiDeployment{Name: relatedApiB + "/deployments/prod"},
+		&rpc.ApiDeployment{Name: relatedApiC + "/deployments/prod"},
+		&rpc.ApiVersion{Name: relatedApiA + "/versions/v1"},
+		&rpc.ApiVersion{Name: "projects/other-project/locations/global/apis/d/versions/v1"},
+	); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+}
+
+func createRelationship(ctx context.Context, server *RegistryServer, parent, id string, relationship *rpc.Relationship) (*rpc.Relationship, error) {
+	return server.CreateRelationship(ctx, &rpc.CreateRelationshipRequest{
+		Parent:         parent,
+		RelationshipId: id,
+		Relationship:   relationship,
+	})
+}
+
+func TestCreateRelationship(t *testing.T) {
+	tests := []struct {
+		desc         string
+		parent       string
+		relationship *rpc.Relationship
+		want         codes.Code
+	}{
+		{
+			desc:         "dependency on a deployment",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Target: relatedApiB + "/deployments/prod", Kind: "depends-on"},
+			want:         codes.OK,
+		},
+		{
+			desc:         "source is a version of the parent",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Source: relatedApiA + "/versions/v1", Target: relatedApiB, Kind: "implements"},
+			want:         codes.OK,
+		},
+		{
+			desc:         "source in another API",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Source: relatedApiB, Target: relatedApiC, Kind: "wraps"},
+			want:         codes.InvalidArgument,
+		},
+		{
+			desc:         "missing target",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Kind: "depends-on"},
+			want:         codes.InvalidArgument,
+		},
+		{
+			desc:         "target that does not exist",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Target: relatedApiB + "/deployments/staging", Kind: "depends-on"},
+			want:         codes.FailedPrecondition,
+		},
+		{
+			desc:         "target in another project",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Target: "projects/other-project/locations/global/apis/d", Kind: "depends-on"},
+			want:         codes.InvalidArgument,
+		},
+		{
+			desc:         "target that is not an API resource",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Target: "projects/my-project", Kind: "depends-on"},
+			want:         codes.InvalidArgument,
+		},
+		{
+			desc:         "invalid kind",
+			parent:       relatedApiA,
+			relationship: &rpc.Relationship{Target: relatedApiB, Kind: "Depends On"},
+			want:         codes.InvalidArgument,
+		},
+		{
+			desc:         "missing parent",
+			parent:       "projects/my-project/locations/global/apis/missing",
+			relationship: &rpc.Relationship{Target: relatedApiB, Kind: "depends-on"},
+			want:         codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			seedRelatedApis(ctx, t, server)
+
+			got, err := createRelationship(ctx, server, test.parent, "r", test.relationship)
+			if status.Code(err) != test.want {
+				t.Fatalf("CreateRelationship(%+v) returned status code %q, want %q: %v", test.relationship, status.Code(err), test.want, err)
+			} else if err != nil {
+				return
+			}
+
+			want := &rpc.Relationship{
+				Name:   test.parent + "/relationships/r",
+				Source: test.relationship.GetSource(),
+				Target: test.relationship.GetTarget(),
+				Kind:   test.relationship.GetKind(),
+			}
+			if want.Source == "" {
+				want.Source = test.parent
+			}
+			opts := protocmp.IgnoreFields(new(rpc.Relationship), "create_time", "update_time")
+			if diff := cmp.Diff(want, got, protocmp.Transform(), opts); diff != "" {
+				t.Errorf("CreateRelationship(%+v) returned unexpected diff (-want +got):\n%s", test.relationship, diff)
+			}
+
+			if _, err := createRelationship(ctx, server, test.parent, "r", test.relationship); status.Code(err) != codes.AlreadyExists {
+				t.Errorf("CreateRelationship(%+v) again returned status code %q, want %q", test.relationship, status.Code(err), codes.AlreadyExists)
+			}
+		})
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedRelatedApis(ctx, t, server)
+
+	if _, err := createRelationship(ctx, server, relatedApiA, "uses-b", &rpc.Relationship{Target: relatedApiB, Kind: "depends-on"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+	if _, err := createRelationship(ctx, server, relatedApiB, "uses-c", &rpc.Relationship{Target: relatedApiC, Kind: "depends-on"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+
+	// Other kinds of relationships can form cycles.
+	if _, err := createRelationship(ctx, server, relatedApiC, "replaces-a", &rpc.Relationship{Target: relatedApiA, Kind: "replaces"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+
+	_, err := createRelationship(ctx, server, relatedApiC, "uses-a", &rpc.Relationship{Target: relatedApiA, Kind: "depends-on"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("CreateRelationship() of a cycle returned status code %q, want %q: %v", status.Code(err), codes.FailedPrecondition, err)
+	}
+	if want := strings.Join([]string{relatedApiC, relatedApiA, relatedApiB, relatedApiC}, " -> "); !strings.Contains(err.Error(), want) {
+		t.Errorf("CreateRelationship() of a cycle returned %q, want it to describe the cycle %q", err, want)
+	}
+
+	if _, err := createRelationship(ctx, server, relatedApiA, "self", &rpc.Relationship{Target: relatedApiA, Kind: "depends-on"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CreateRelationship() of a self-dependency returned status code %q, want %q: %v", status.Code(err), codes.FailedPrecondition, err)
+	}
+
+	// Updates that change a relationship into a dependency are also checked.
+	_, err = server.UpdateRelationship(ctx, &rpc.UpdateRelationshipRequest{
+		Relationship: &rpc.Relationship{Name: relatedApiC + "/relationships/replaces-a", Kind: "depends-on"},
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"kind"}},
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("UpdateRelationship() of a cycle returned status code %q, want %q: %v", status.Code(err), codes.FailedPrecondition, err)
+	}
+
+	// Retargeting a dependency is not a cycle with its previous target.
+	if _, err := server.UpdateRelationship(ctx, &rpc.UpdateRelationshipRequest{
+		Relationship: &rpc.Relationship{Name: relatedApiB + "/relationships/uses-c", Target: relatedApiC + "/deployments/prod"},
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"target"}},
+	}); err != nil {
+		t.Errorf("UpdateRelationship() returned error: %s", err)
+	}
+}
+
+func TestDependencyCyclesBetweenApis(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedRelatedApis(ctx, t, server)
+
+	// Resources depend on each other through the APIs that contain them.
+	if _, err := createRelationship(ctx, server, relatedApiA, "uses-b", &rpc.Relationship{
+		Source: relatedApiA + "/versions/v1",
+		Target: relatedApiB,
+		Kind:   "depends-on",
+	}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+
+	_, err := createRelationship(ctx, server, relatedApiB, "uses-a", &rpc.Relationship{
+		Source: relatedApiB + "/deployments/prod",
+		Target: relatedApiA + "/versions/v1",
+		Kind:   "depends-on",
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("CreateRelationship() of a cycle returned status code %q, want %q: %v", status.Code(err), codes.FailedPrecondition, err)
+	}
+	if want := strings.Join([]string{relatedApiB, relatedApiA, relatedApiB}, " -> "); !strings.Contains(err.Error(), want) {
+		t.Errorf("CreateRelationship() of a cycle returned %q, want it to describe the cycle %q", err, want)
+	}
+}
+
+func TestDependenciesWithinApis(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedRelatedApis(ctx, t, server)
+
+	// Resources of an API can depend on each other without forming a cycle.
+	if _, err := createRelationship(ctx, server, relatedApiB, "uses-b", &rpc.Relationship{Target: relatedApiB + "/deployments/prod", Kind: "depends-on"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+	if _, err := createRelationship(ctx, server, relatedApiB, "uses-self", &rpc.Relationship{
+		Source: relatedApiB + "/deployments/prod",
+		Target: relatedApiB,
+		Kind:   "depends-on",
+	}); err != nil {
+		t.Errorf("CreateRelationship() of a dependency within an API returned error: %s", err)
+	}
+
+	// Dependencies within an API don't close cycles between APIs.
+	if _, err := createRelationship(ctx, server, relatedApiA, "uses-b", &rpc.Relationship{Target: relatedApiB + "/deployments/prod", Kind: "depends-on"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+	if _, err := createRelationship(ctx, server, relatedApiB, "uses-c", &rpc.Relationship{Target: relatedApiC, Kind: "depends-on"}); err != nil {
+		t.Errorf("CreateRelationship() returned error: %s", err)
+	}
+}
+
+func TestRelationshipLookup(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	seedRelatedApis(ctx, t, server)
+
+	const deployment = relatedApiB + "/deployments/prod"
+	for _, parent := range []string{relatedApiA, relatedApiC} {
+		if _, err := createRelationship(ctx, server, parent, "uses-b", &rpc.Relationship{Target: deployment, Kind: "depends-on"}); err != nil {
+			t.Fatalf("CreateRelationship() returned error: %s", err)
+		}
+	}
+	if _, err := createRelationship(ctx, server, relatedApiA, "wraps-b", &rpc.Relationship{Target: relatedApiB, Kind: "wraps"}); err != nil {
+		t.Fatalf("CreateRelationship() returned error: %s", err)
+	}
+
+	listing, err := server.ListRelationships(ctx, &rpc.ListRelationshipsRequest{
+		Parent: "projects/my-project/locations/global/apis/-",
+		Filter: "target == '" + deployment + "'",
+	})
+	if err != nil {
+		t.Fatalf("ListRelationships() returned error: %s", err)
+	}
+
+	var got []string
+	for _, r := range listing.GetRelationships() {
+		got = append(got, r.GetName())
+	}
+	want := []string{relatedApiA + "/relationships/uses-b", relatedApiC + "/relationships/uses-b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListRelationships() returned unexpected dependents of %q (-want +got):\n%s", deployment, diff)
+	}
+
+	// Relationships are deleted with their API.
+	if _, err := server.DeleteApi(ctx, &rpc.DeleteApiRequest{Name: relatedApiC}); err != nil {
+		t.Fatalf("DeleteApi() returned error: %s", err)
+	}
+	if _, err := server.GetRelationship(ctx, &rpc.GetRelationshipRequest{Name: relatedApiC + "/relationships/uses-b"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetRelationship() after DeleteApi() returned status code %q, want %q", status.Code(err), codes.NotFound)
+	}
+
+	if _, err := server.DeleteRelationship(ctx, &rpc.DeleteRelationshipRequest{Name: relatedApiA + "/relationships/wraps-b"}); err != nil {
+		t.Fatalf("DeleteRelationship() returned error: %s", err)
+	}
+	if _, err := server.DeleteRelationship(ctx, &rpc.DeleteRelationshipRequest{Name: relatedApiA + "/relationships/wraps-b"}); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteRelationship() of a deleted relationship returned status code %q, want %q", status.Code(err), codes.NotFound)
+	}
+
+	// Updates can create missing relationships.
+	if _, err := server.UpdateRelationship(ctx, &rpc.UpdateRelationshipRequest{
+		Relationship: &rpc.Relationship{Name: relatedApiA + "/relationships/wraps-b", Target: relatedApiB, Kind: "wraps"},
+		AllowMissing: true,
+	}); err != nil {
+		t.Errorf("UpdateRelationship(allow_missing) returned error: %s", err)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apigee/registry/log"
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/storage"
+	"github.com/apigee/registry/server/registry/names"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PruneRevisions handles the corresponding API request.
+func (s *RegistryServer) PruneRevisions(ctx context.Context, req *rpc.PruneRevisionsRequest) (*rpc.PruneRevisionsResponse, error) {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer db.Close()
+
+	name, err := names.ParseProject(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.pruneRevisions(ctx, db, name, req.GetValidateOnly())
+}
+
+// validateRetentionPolicies returns an error if the retention policies of a project are invalid.
+func validateRetentionPolicies(project names.Project, policies []*rpc.RetentionPolicy) error {
+	seen := make(map[string]bool, len(policies))
+	for _, p := range policies {
+		if p.GetApi() != "" {
+			api, err := names.ParseApi(p.GetApi())
+			if err != nil {
+				return fmt.Errorf("invalid retention policy: %s", err)
+			} else if api.ProjectID != project.ProjectID {
+				return fmt.Errorf("invalid retention policy for %q: API must be in project %q", p.GetApi(), project)
+			} else if api.LocationID == "-" || api.ApiID == "-" {
+				return fmt.Errorf("invalid retention policy for %q: API must not contain wildcards", p.GetApi())
+			}
+		}
+
+		if seen[p.GetApi()] {
+			return fmt.Errorf("invalid retention policy for %q: only one policy is allowed for each API", p.GetApi())
+		}
+		seen[p.GetApi()] = true
+
+		if p.GetKeepLast() < 0 {
+			return fmt.Errorf("invalid retention policy for %q: keep_last must not be negative", p.GetApi())
+		}
+
+		if d := p.GetKeepNewerThan(); d != nil {
+			if err := d.CheckValid(); err != nil {
+				return fmt.Errorf("invalid retention policy for %q: %s", p.GetApi(), err)
+			} else if d.AsDuration() <= 0 {
+				return fmt.Errorf("invalid retention policy for %q: keep_newer_than must be positive", p.GetApi())
+			}
+		}
+
+		if p.GetKeepLast() == 0 && p.GetKeepNewerThan() == nil {
+			return fmt.Errorf("invalid retention policy for %q: keep_last or keep_newer_than must be set", p.GetApi())
+		}
+	}
+
+	return nil
+}
+
+// prunable describes a spec or deployment revision that can be pruned.
+type prunable struct {
+	name fmt.Stringer // names.SpecRevision or names.DeploymentRevision
+	size int64
+}
+
+// revisionSet collects the revisions of a project and selects the ones
+// that are not kept by its retention policies.
+type revisionSet struct {
+	policies map[string]*rpc.RetentionPolicy // keyed by API name; "" is the project default
+	now      time.Time
+	counts   map[string]int32 // number of revisions seen for each spec or deployment
+	prunable []prunable
+}
+
+func newRevisionSet(policies []*rpc.RetentionPolicy, now time.Time) *revisionSet {
+	set := &revisionSet{
+		policies: make(map[string]*rpc.RetentionPolicy, len(policies)),
+		now:      now,
+		counts:   make(map[string]int32),
+	}
+	for _, p := range policies {
+		set.policies[p.GetApi()] = p
+	}
+	return set
+}
+
+// add considers a revision for pruning. Revisions of each spec or deployment
+// must be added in order from the most recently created.
+func (set *revisionSet) add(revision prunable, parent string, api names.Api, created time.Time, tagged bool) {
+	index := set.counts[parent]
+	set.counts[parent]++
+
+	// The current revision is always kept.
+	if index == 0 {
+		return
+	}
+
+	policy, ok := set.policies[api.String()]
+	if !ok {
+		policy, ok = set.policies[""]
+	}
+	if !ok {
+		return
+	}
+
+	switch {
+	case policy.GetKeepLast() > 0 && index < policy.GetKeepLast():
+		return
+	case policy.GetKeepNewerThan() != nil && set.now.Sub(created) < policy.GetKeepNewerThan().AsDuration():
+		return
+	case policy.GetKeepTagged() && tagged:
+		return
+	}
+
+	set.prunable = append(set.prunable, revision)
+}
+
+// pruneRevisions deletes the revisions of a project that are not kept by its
+// retention policies. If validateOnly is true, the revisions are reported but
+// not deleted.
+func (s *RegistryServer) pruneRevisions(ctx context.Context, db *storage.Client, name names.Project, validateOnly bool) (*rpc.PruneRevisionsResponse, error) {
+	project, err := db.GetProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := project.Policies()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &rpc.PruneRevisionsResponse{}
+	if len(policies) == 0 {
+		return response, nil
+	}
+
+	set := newRevisionSet(policies, time.Now())
+
+	specs, err := db.ListSpecRevisionsForProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	specTags, err := db.ListSpecRevisionTagsForProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := make(map[string]bool, len(specTags))
+	for _, tag := range specTags {
+		tagged[names.SpecRevision{
+			ProjectID:  tag.ProjectID,
+			LocationID: tag.LocationID,
+			ApiID:      tag.ApiID,
+			VersionID:  tag.VersionID,
+			SpecID:     tag.SpecID,
+			RevisionID: tag.RevisionID,
+		}.String()] = true
+	}
+
+	for _, spec := range specs {
+		revision := names.SpecRevision{
+			ProjectID:  spec.ProjectID,
+			LocationID: spec.LocationID,
+			ApiID:      spec.ApiID,
+			VersionID:  spec.VersionID,
+			SpecID:     spec.SpecID,
+			RevisionID: spec.RevisionID,
+		}
+		set.add(prunable{name: revision, size: int64(spec.SizeInBytes)},
+			spec.Name(), revision.Spec().Api(), spec.RevisionCreateTime, tagged[revision.String()])
+	}
+
+	deployments, err := db.ListDeploymentRevisionsForProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentTags, err := db.ListDeploymentRevisionTagsForProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged = make(map[string]bool, len(deploymentTags))
+	for _, tag := range deploymentTags {
+		tagged[names.DeploymentRevision{
+			ProjectID:    tag.ProjectID,
+			LocationID:   tag.LocationID,
+			ApiID:        tag.ApiID,
+			DeploymentID: tag.DeploymentID,
+			RevisionID:   tag.RevisionID,
+		}.String()] = true
+	}
+
+	for _, deployment := range deployments {
+		revision := names.DeploymentRevision{
+			ProjectID:    deployment.ProjectID,
+			LocationID:   deployment.LocationID,
+			ApiID:        deployment.ApiID,
+			DeploymentID: deployment.DeploymentID,
+			RevisionID:   deployment.RevisionID,
+		}
+		set.add(prunable{name: revision},
+			deployment.Name(), revision.Deployment().Api(), deployment.RevisionCreateTime, tagged[revision.String()])
+	}
+
+	// Revisions named by references are kept so that the references remain valid.
+	pinned, err := s.pinnedRevisions(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range set.prunable {
+		if pinned[revision.name.String()] {
+			continue
+		}
+		if !validateOnly {
+			switch name := revision.name.(type) {
+			case names.SpecRevision:
+				err = db.DeleteSpecRevision(ctx, name)
+			case names.DeploymentRevision:
+				err = db.DeleteDeploymentRevision(ctx, name)
+			}
+			if err != nil {
+				return nil, err
+			}
+			s.notify(ctx, rpc.Notification_DELETED, revision.name.String())
+		}
+
+		response.Revisions = append(response.Revisions, revision.name.String())
+		response.SizeBytes += revision.size
+	}
+
+	return response, nil
+}
+
+// pruneAllRevisions prunes the revisions of every project.
+func (s *RegistryServer) pruneAllRevisions(ctx context.Context) error {
+	db, err := s.getStorageClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var projects []names.Project
+	opts := storage.PageOptions{Size: 1000}
+	for {
+		listing, err := db.ListProjects(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, p := range listing.Projects {
+			projects = append(projects, names.Project{ProjectID: p.ProjectID})
+		}
+		if listing.Token == "" {
+			break
+		}
+		opts.Token = listing.Token
+	}
+
+	logger := log.FromContext(ctx)
+	for _, project := range projects {
+		response, err := s.pruneRevisions(ctx, db, project, false)
+		if err != nil {
+			logger.WithError(err).Errorf("Failed to prune revisions of %s", project)
+			continue
+		}
+		if n := len(response.GetRevisions()); n > 0 {
+			logger.Infof("Pruned %d revisions (%d bytes) of %s", n, response.GetSizeBytes(), project)
+		}
+	}
+
+	return nil
+}
+
+// pruner runs pruneAllRevisions periodically until it is stopped.
+type pruner struct {
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func (s *RegistryServer) startPruner(interval time.Duration) *pruner {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pruner{
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.pruneAllRevisions(ctx); err != nil && ctx.Err() == nil {
+					log.FromContext(ctx).WithError(err).Error("Failed to prune revisions")
+				}
+			}
+		}
+	}()
+
+	return p
+}
+
+// stop stops the pruner and waits for a pruning run in progress to end.
+func (p *pruner) stop() {
+	p.cancel()
+	<-p.done
+}
@@ -0,0 +1,488 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apigee/registry/rpc"
+	"github.com/apigee/registry/server/registry/internal/test/seeder"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// seedRevisions creates a spec and a deployment with four revisions in each
+// API and returns their revision names from the oldest to the newest.
+// The second oldest revision of each is tagged.
+func seedRevisions(ctx context.Context, t *testing.T, server *RegistryServer, apis ...string) (specs, deployments [][]string) {
+	t.Helper()
+	var seed []seeder.RegistryResource
+	for _, api := range apis {
+		seed = append(seed,
+			&rpc.ApiSpec{Name: api + "/versions/v1/specs/my-spec"},
+			&rpc.ApiDeployment{Name: api + "/deployments/my-deployment"})
+	}
+	if err := seeder.SeedRegistry(ctx, server, seed...); err != nil {
+		t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+	}
+
+	for _, api := range apis {
+		s, d := seedApiRevisions(ctx, t, server, api)
+		specs = append(specs, s)
+		deployments = append(deployments, d)
+	}
+	return specs, deployments
+}
+
+func seedApiRevisions(ctx context.Context, t *testing.T, server *RegistryServer, api string) (specs, deployments []string) {
+	t.Helper()
+	spec := api + "/versions/v1/specs/my-spec"
+	deployment := api + "/deployments/my-deployment"
+
+	// Seeding creates the first revision of each resource.
+	s, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: spec})
+	if err != nil {
+		t.Fatalf("Setup: GetApiSpec returned error: %s", err)
+	}
+	specs = append(specs, fmt.Sprintf("%s@%s", s.GetName(), s.GetRevisionId()))
+
+	d, err := server.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: deployment})
+	if err != nil {
+		t.Fatalf("Setup: GetApiDeployment returned error: %s", err)
+	}
+	deployments = append(deployments, fmt.Sprintf("%s@%s", d.GetName(), d.GetRevisionId()))
+
+	for i := 1; i < 4; i++ {
+		s, err = server.UpdateApiSpec(ctx, &rpc.UpdateApiSpecRequest{
+			ApiSpec: &rpc.ApiSpec{
+				Name:     spec,
+				Contents: []byte(fmt.Sprintf("contents %d", i)),
+			},
+		})
+		if err != nil {
+			t.Fatalf("Setup: UpdateApiSpec returned error: %s", err)
+		}
+		specs = append(specs, fmt.Sprintf("%s@%s", s.GetName(), s.GetRevisionId()))
+
+		d, err = server.UpdateApiDeployment(ctx, &rpc.UpdateApiDeploymentRequest{
+			ApiDeployment: &rpc.ApiDeployment{
+				Name:        deployment,
+				EndpointUri: fmt.Sprintf("https://%d.example.com", i),
+			},
+		})
+		if err != nil {
+			t.Fatalf("Setup: UpdateApiDeployment returned error: %s", err)
+		}
+		deployments = append(deployments, fmt.Sprintf("%s@%s", d.GetName(), d.GetRevisionId()))
+	}
+
+	if _, err := server.TagApiSpecRevision(ctx, &rpc.TagApiSpecRevisionRequest{Name: specs[1], Tag: "stable"}); err != nil {
+		t.Fatalf("Setup: TagApiSpecRevision returned error: %s", err)
+	}
+	if _, err := server.TagApiDeploymentRevision(ctx, &rpc.TagApiDeploymentRevisionRequest{Name: deployments[1], Tag: "stable"}); err != nil {
+		t.Fatalf("Setup: TagApiDeploymentRevision returned error: %s", err)
+	}
+
+	return specs, deployments
+}
+
+func TestPruneRevisions(t *testing.T) {
+	const (
+		project  = "projects/my-project"
+		myApi    = "projects/my-project/locations/global/apis/my-api"
+		otherApi = "projects/my-project/locations/us-central1/apis/other-api"
+	)
+
+	tests := []struct {
+		desc     string
+		policies []*rpc.RetentionPolicy
+		// Indexes of the revisions of each API that should be pruned.
+		myApi, otherApi []int
+	}{
+		{
+			desc: "no policies",
+		},
+		{
+			desc: "keep last",
+			policies: []*rpc.RetentionPolicy{
+				{KeepLast: 2},
+			},
+			myApi:    []int{0, 1},
+			otherApi: []int{0, 1},
+		},
+		{
+			desc: "keep last and tagged",
+			policies: []*rpc.RetentionPolicy{
+				{KeepLast: 2, KeepTagged: true},
+			},
+			myApi:    []int{0},
+			otherApi: []int{0},
+		},
+		{
+			desc: "keep newer than",
+			policies: []*rpc.RetentionPolicy{
+				{KeepNewerThan: durationpb.New(time.Hour)},
+			},
+		},
+		{
+			desc: "keep only current",
+			policies: []*rpc.RetentionPolicy{
+				{KeepLast: 1},
+			},
+			myApi:    []int{0, 1, 2},
+			otherApi: []int{0, 1, 2},
+		},
+		{
+			desc: "api policy",
+			policies: []*rpc.RetentionPolicy{
+				{Api: myApi, KeepLast: 3},
+			},
+			myApi: []int{0},
+		},
+		{
+			desc: "api policy overrides project policy",
+			policies: []*rpc.RetentionPolicy{
+				{KeepLast: 1},
+				{Api: myApi, KeepLast: 3},
+			},
+			myApi:    []int{0},
+			otherApi: []int{0, 1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			specs, deployments := seedRevisions(ctx, t, server, myApi, otherApi)
+
+			update := &rpc.UpdateProjectRequest{
+				Project: &rpc.Project{
+					Name:              project,
+					RetentionPolicies: test.policies,
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies"}},
+			}
+			if _, err := server.UpdateProject(ctx, update); err != nil {
+				t.Fatalf("Setup: UpdateProject(%+v) returned error: %s", update, err)
+			}
+
+			var pruned, kept []string
+			for _, revisions := range []struct {
+				names  []string
+				pruned []int
+			}{
+				{specs[0], test.myApi},
+				{deployments[0], test.myApi},
+				{specs[1], test.otherApi},
+				{deployments[1], test.otherApi},
+			} {
+				for i, name := range revisions.names {
+					if contains(revisions.pruned, i) {
+						pruned = append(pruned, name)
+					} else {
+						kept = append(kept, name)
+					}
+				}
+			}
+
+			opts := cmp.Options{
+				protocmp.Transform(),
+				protocmp.SortRepeated(func(a, b string) bool { return a < b }),
+				cmpopts.EquateEmpty(),
+			}
+
+			t.Run("validate only", func(t *testing.T) {
+				req := &rpc.PruneRevisionsRequest{Name: project, ValidateOnly: true}
+				got, err := server.PruneRevisions(ctx, req)
+				if err != nil {
+					t.Fatalf("PruneRevisions(%+v) returned error: %s", req, err)
+				}
+
+				if want := (&rpc.PruneRevisionsResponse{Revisions: pruned}); !cmp.Equal(want, got, opts, protocmp.IgnoreFields(want, "size_bytes")) {
+					t.Errorf("PruneRevisions(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts, protocmp.IgnoreFields(want, "size_bytes")))
+				}
+
+				for _, name := range append(pruned, kept...) {
+					if err := getRevision(ctx, server, name); err != nil {
+						t.Errorf("Revision %q was deleted by a validate only request: %s", name, err)
+					}
+				}
+			})
+
+			t.Run("prune", func(t *testing.T) {
+				req := &rpc.PruneRevisionsRequest{Name: project}
+				got, err := server.PruneRevisions(ctx, req)
+				if err != nil {
+					t.Fatalf("PruneRevisions(%+v) returned error: %s", req, err)
+				}
+
+				if want := (&rpc.PruneRevisionsResponse{Revisions: pruned}); !cmp.Equal(want, got, opts, protocmp.IgnoreFields(want, "size_bytes")) {
+					t.Errorf("PruneRevisions(%+v) returned unexpected diff (-want +got):\n%s", req, cmp.Diff(want, got, opts, protocmp.IgnoreFields(want, "size_bytes")))
+				}
+
+				for _, name := range pruned {
+					if err := getRevision(ctx, server, name); status.Code(err) != codes.NotFound {
+						t.Errorf("Get(%q) returned status code %q, want %q: %v", name, status.Code(err), codes.NotFound, err)
+					}
+				}
+
+				for _, name := range kept {
+					if err := getRevision(ctx, server, name); err != nil {
+						t.Errorf("Get(%q) returned error: %s", name, err)
+					}
+				}
+			})
+
+			t.Run("prune again", func(t *testing.T) {
+				req := &rpc.PruneRevisionsRequest{Name: project}
+				got, err := server.PruneRevisions(ctx, req)
+				if err != nil {
+					t.Fatalf("PruneRevisions(%+v) returned error: %s", req, err)
+				}
+
+				if len(got.GetRevisions()) > 0 {
+					t.Errorf("PruneRevisions(%+v) pruned %v, want no revisions", req, got.GetRevisions())
+				}
+			})
+		})
+	}
+}
+
+func TestPruneRevisionsSize(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	specs, _ := seedRevisions(ctx, t, server, "projects/my-project/locations/global/apis/my-api")
+	revision := specs[0][0]
+
+	update := &rpc.UpdateProjectRequest{
+		Project: &rpc.Project{
+			Name:              "projects/my-project",
+			RetentionPolicies: []*rpc.RetentionPolicy{{KeepLast: 3}},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies"}},
+	}
+	if _, err := server.UpdateProject(ctx, update); err != nil {
+		t.Fatalf("Setup: UpdateProject(%+v) returned error: %s", update, err)
+	}
+
+	spec, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: revision})
+	if err != nil {
+		t.Fatalf("Setup: GetApiSpec(%q) returned error: %s", revision, err)
+	}
+
+	req := &rpc.PruneRevisionsRequest{Name: "projects/my-project", ValidateOnly: true}
+	got, err := server.PruneRevisions(ctx, req)
+	if err != nil {
+		t.Fatalf("PruneRevisions(%+v) returned error: %s", req, err)
+	}
+
+	if want := int64(spec.GetSizeBytes()); got.GetSizeBytes() != want {
+		t.Errorf("PruneRevisions(%+v) returned size_bytes %d, want %d", req, got.GetSizeBytes(), want)
+	}
+}
+
+func TestPruneRevisionsResponseCodes(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *rpc.PruneRevisionsRequest
+		want codes.Code
+	}{
+		{
+			desc: "invalid name",
+			req:  &rpc.PruneRevisionsRequest{Name: "projects/my-project/locations/global"},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc: "missing project",
+			req:  &rpc.PruneRevisionsRequest{Name: "projects/other-project"},
+			want: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+			if _, err := server.PruneRevisions(ctx, test.req); status.Code(err) != test.want {
+				t.Errorf("PruneRevisions(%+v) returned status code %q, want %q: %v", test.req, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicyValidation(t *testing.T) {
+	tests := []struct {
+		desc     string
+		policies []*rpc.RetentionPolicy
+		want     codes.Code
+	}{
+		{
+			desc: "valid",
+			policies: []*rpc.RetentionPolicy{
+				{KeepLast: 10, KeepTagged: true},
+				{Api: "projects/my-project/locations/global/apis/my-api", KeepNewerThan: durationpb.New(24 * time.Hour)},
+			},
+			want: codes.OK,
+		},
+		{
+			desc:     "no rules",
+			policies: []*rpc.RetentionPolicy{{KeepTagged: true}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "negative keep_last",
+			policies: []*rpc.RetentionPolicy{{KeepLast: -1}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "negative keep_newer_than",
+			policies: []*rpc.RetentionPolicy{{KeepNewerThan: durationpb.New(-time.Hour)}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "duplicate project policies",
+			policies: []*rpc.RetentionPolicy{{KeepLast: 1}, {KeepLast: 2}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc: "duplicate api policies",
+			policies: []*rpc.RetentionPolicy{
+				{Api: "projects/my-project/locations/global/apis/my-api", KeepLast: 1},
+				{Api: "projects/my-project/locations/global/apis/my-api", KeepLast: 2},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			desc:     "invalid api",
+			policies: []*rpc.RetentionPolicy{{Api: "my-api", KeepLast: 1}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "api in other project",
+			policies: []*rpc.RetentionPolicy{{Api: "projects/other-project/locations/global/apis/my-api", KeepLast: 1}},
+			want:     codes.InvalidArgument,
+		},
+		{
+			desc:     "api wildcard",
+			policies: []*rpc.RetentionPolicy{{Api: "projects/my-project/locations/-/apis/my-api", KeepLast: 1}},
+			want:     codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			server := defaultTestServer(t)
+
+			create := &rpc.CreateProjectRequest{
+				ProjectId: "my-project",
+				Project:   &rpc.Project{RetentionPolicies: test.policies},
+			}
+			created, err := server.CreateProject(ctx, create)
+			if status.Code(err) != test.want {
+				t.Fatalf("CreateProject(%+v) returned status code %q, want %q: %v", create, status.Code(err), test.want, err)
+			}
+
+			if test.want == codes.OK {
+				opts := cmp.Options{protocmp.Transform()}
+				if !cmp.Equal(test.policies, created.GetRetentionPolicies(), opts) {
+					t.Errorf("CreateProject(%+v) returned unexpected diff (-want +got):\n%s", create, cmp.Diff(test.policies, created.GetRetentionPolicies(), opts))
+				}
+				return
+			}
+
+			if err := seeder.SeedProjects(ctx, server, &rpc.Project{Name: "projects/my-project"}); err != nil {
+				t.Fatalf("Setup/Seeding: Failed to seed registry: %s", err)
+			}
+
+			update := &rpc.UpdateProjectRequest{
+				Project: &rpc.Project{
+					Name:              "projects/my-project",
+					RetentionPolicies: test.policies,
+				},
+			}
+			if _, err := server.UpdateProject(ctx, update); status.Code(err) != test.want {
+				t.Errorf("UpdateProject(%+v) returned status code %q, want %q: %v", update, status.Code(err), test.want, err)
+			}
+		})
+	}
+}
+
+func TestBackgroundPruning(t *testing.T) {
+	ctx := context.Background()
+	server := defaultTestServer(t)
+	s, d := seedRevisions(ctx, t, server, "projects/my-project/locations/global/apis/my-api")
+	specs, deployments := s[0], d[0]
+
+	update := &rpc.UpdateProjectRequest{
+		Project: &rpc.Project{
+			Name:              "projects/my-project",
+			RetentionPolicies: []*rpc.RetentionPolicy{{KeepLast: 1}},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"retention_policies"}},
+	}
+	if _, err := server.UpdateProject(ctx, update); err != nil {
+		t.Fatalf("Setup: UpdateProject(%+v) returned error: %s", update, err)
+	}
+
+	server.Reload(Config{PruneInterval: 10 * time.Millisecond})
+	defer server.Close()
+
+	var pruned []string
+	pruned = append(pruned, specs[:3]...)
+	pruned = append(pruned, deployments[:3]...)
+	deadline := time.Now().Add(10 * time.Second)
+	for _, name := range pruned {
+		for getRevision(ctx, server, name) == nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("Revision %q was not pruned in the background", name)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, name := range []string{specs[3], deployments[3]} {
+		if err := getRevision(ctx, server, name); err != nil {
+			t.Errorf("Get(%q) returned error: %s", name, err)
+		}
+	}
+}
+
+// getRevision returns an error if a spec or deployment revision can't be read.
+func getRevision(ctx context.Context, server *RegistryServer, name string) error {
+	if _, err := server.GetApiSpec(ctx, &rpc.GetApiSpecRequest{Name: name}); status.Code(err) != codes.InvalidArgument {
+		return err
+	}
+	_, err := server.GetApiDeployment(ctx, &rpc.GetApiDeploymentRequest{Name: name})
+	return err
+}
+
+func contains(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -16,12 +16,15 @@ package registry
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/apigee/registry/rpc"
 	"github.com/apigee/registry/server/registry/internal/storage"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Config configures the registry server.
@@ -32,14 +35,35 @@ type Config struct {
 	LogFormat string
 	Notify    bool
 	ProjectID string
+	Quotas    Quotas
+	// Locations that resources can be created in.
+	// If empty, resources can be created in any location.
+	Locations []string
+	// Interval between background runs that prune revisions according to
+	// the retention policies of each project. If zero, revisions are only
+	// pruned by PruneRevisions.
+	PruneInterval time.Duration
+	// References configures the checking of references between resources.
+	References ReferencePolicy
+	// StrictMimeTypes rejects specs with MIME types that can't be parsed.
+	// Otherwise they are stored as they are, without a normal form.
+	StrictMimeTypes bool
 }
 
 // RegistryServer implements a Registry server.
 type RegistryServer struct {
-	database      string
-	dbConfig      string
-	notifyEnabled bool
-	projectID     string
+	database  string
+	dbConfig  string
+	startTime time.Time
+
+	mu         sync.RWMutex
+	notifier   *notifier // nil if notifications are disabled
+	quotas     Quotas
+	locations  []string
+	pruner     *pruner // nil if background pruning is disabled
+	references ReferencePolicy
+	strictMime bool
+	lastReload *rpc.ReloadStatus
 
 	rpc.UnimplementedRegistryServer
 	rpc.UnimplementedAdminServer
@@ -47,10 +71,16 @@ type RegistryServer struct {
 
 func New(config Config) (*RegistryServer, error) {
 	s := &RegistryServer{
-		database:      config.Database,
-		dbConfig:      config.DBConfig,
-		notifyEnabled: config.Notify,
-		projectID:     config.ProjectID,
+		database:   config.Database,
+		dbConfig:   config.DBConfig,
+		startTime:  time.Now(),
+		quotas:     config.Quotas,
+		locations:  config.Locations,
+		references: config.References,
+		strictMime: config.StrictMimeTypes,
+	}
+	if config.Notify {
+		s.notifier = newNotifier(config.ProjectID)
 	}
 
 	if s.database == "" {
@@ -66,9 +96,87 @@ func New(config Config) (*RegistryServer, error) {
 	if err := db.EnsureTables(); err != nil {
 		return nil, err
 	}
+	if err := db.SeedHistory(context.Background()); err != nil {
+		return nil, err
+	}
+
+	if config.PruneInterval > 0 {
+		s.pruner = s.startPruner(config.PruneInterval)
+	}
 	return s, nil
 }
 
+// Reload applies the parts of config that can be changed while the server is
+// running. Currently these are the notification settings, quotas, locations,
+// prune interval, reference policy, and MIME type checking; other fields are
+// ignored.
+func (s *RegistryServer) Reload(config Config) {
+	s.mu.Lock()
+	s.quotas = config.Quotas
+	s.locations = config.Locations
+	s.references = config.References
+	s.strictMime = config.StrictMimeTypes
+	if p := s.pruner; p == nil || p.interval != config.PruneInterval {
+		s.pruner = nil
+		if config.PruneInterval > 0 {
+			s.pruner = s.startPruner(config.PruneInterval)
+		}
+		if p != nil {
+			// Stopping may wait for a pruning run to end, so don't hold the lock.
+			defer p.stop()
+		}
+	}
+	old := s.notifier
+	if old != nil && config.Notify && old.projectID == config.ProjectID {
+		s.mu.Unlock()
+		return
+	}
+	s.notifier = nil
+	if config.Notify {
+		s.notifier = newNotifier(config.ProjectID)
+	}
+	s.mu.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+}
+
+// RecordReload records the result of a reload of the server configuration
+// so that it can be reported by GetStatus.
+func (s *RegistryServer) RecordReload(err error) {
+	r := &rpc.ReloadStatus{
+		Time:    timestamppb.Now(),
+		Success: err == nil,
+		Message: "configuration reloaded",
+	}
+	if err != nil {
+		r.Message = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastReload = r
+}
+
+// Close sends pending notifications and releases resources held by the server.
+// It should be called after the server has stopped handling requests.
+// Storage clients are opened for each request and closed when it completes,
+// so none remain open once requests have finished.
+func (s *RegistryServer) Close() {
+	s.mu.Lock()
+	n, p := s.notifier, s.pruner
+	s.notifier, s.pruner = nil, nil
+	s.mu.Unlock()
+
+	if p != nil {
+		p.stop()
+	}
+	if n != nil {
+		n.close()
+	}
+}
+
 func (s *RegistryServer) getStorageClient(ctx context.Context) (*storage.Client, error) {
 	return storage.NewClient(ctx, s.database, s.dbConfig)
 }